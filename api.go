@@ -1,3 +1,6 @@
+//go:build windows
+// +build windows
+
 package wlanapi
 
 import (
@@ -13,13 +16,12 @@ var (
 	dwClientVersion = uintptr(2)
 )
 
+//The WlanFreeMemory function frees memory. Any memory returned from Native Wifi functions must be freed.
+//https://docs.microsoft.com/en-us/windows/win32/api/wlanapi/nf-wlanapi-wlanfreememory
 func WlanFreeMemory(pMemory PVOID) (err error) {
-	r1, _, _ := procWlanOpenHandle.Call(
+	procWlanFreeMemory.Call(
 		uintptr(pMemory),
 	)
-	if r1 != S_OK {
-		err = syscall.Errno(r1)
-	}
 	return
 }
 
@@ -52,19 +54,15 @@ func WlanCloseHandle(handle windows.Handle) (err error) {
 }
 
 func WlanEnumInterfaces(handle windows.Handle) (interfaceInfoList *WLAN_INTERFACE_INFO_LIST, err error) {
-	var iil *WLAN_INTERFACE_INFO_LIST
-	r1, _, err := procWlanEnumInterfaces.Call(
+	r1, _, _ := procWlanEnumInterfaces.Call(
 		uintptr(handle),
 		pReserved,
-		uintptr(unsafe.Pointer(&iil)),
+		uintptr(unsafe.Pointer(&interfaceInfoList)),
 	)
-	log.Println(err)
 	if r1 != S_OK {
 		err = syscall.Errno(r1)
 	}
-	return iil, nil
-
-	//TODO: Need to rework to cleanup unmanaged memory WlanFreeMemory(uintptr(unsafe.Pointer(iil)))
+	return
 }
 
 //The WlanScan function requests a scan for available networks on the indicated interface.
@@ -110,7 +108,10 @@ func WlanConnect(handle windows.Handle, wlanConnectionParameters *WLAN_CONNECTIO
 		uintptr(unsafe.Pointer(wlanConnectionParameters)),
 		pReserved,
 	)
-	return syscall.Errno(r1)
+	if r1 != S_OK {
+		return syscall.Errno(r1)
+	}
+	return nil
 }
 
 //The WlanDisconnect function disconnects an interface from its current network.
@@ -121,7 +122,10 @@ func WlanDisconnect(handle windows.Handle, pInterfaceGuid *windows.GUID) error {
 		uintptr(unsafe.Pointer(pInterfaceGuid)),
 		pReserved,
 	)
-	return syscall.Errno(r1)
+	if r1 != S_OK {
+		return syscall.Errno(r1)
+	}
+	return nil
 }
 
 //The WlanDeleteProfile function deletes a wireless profile for a wireless interface on the local computer.
@@ -138,7 +142,10 @@ func WlanDeleteProfile(handle windows.Handle, pInterfaceGuid *windows.GUID, strP
 		uintptr(unsafe.Pointer(pProfileName)),
 		pReserved,
 	)
-	return syscall.Errno(r1)
+	if r1 != S_OK {
+		return syscall.Errno(r1)
+	}
+	return nil
 }
 
 //The WlanGetAvailableNetworkList function retrieves the list of available networks on a wireless LAN interface.
@@ -186,7 +193,8 @@ func WlanGetFilterList(handle windows.Handle, wlanFilterListType WLAN_FILTER_LIS
 		uintptr(handle),
 		uintptr(wlanFilterListType),
 		pReserved,
-		uintptr(unsafe.Pointer(ppNetworkList)),
+		// out
+		uintptr(unsafe.Pointer(&ppNetworkList)),
 	)
 	if r1 != S_OK {
 		err = syscall.Errno(r1)
@@ -201,9 +209,12 @@ func WlanGetInterfaceCapability(handle windows.Handle, pInterfaceGuid *windows.G
 		uintptr(handle),
 		uintptr(unsafe.Pointer(pInterfaceGuid)),
 		pReserved,
-		uintptr(unsafe.Pointer(ppCapability)),
+		// out
+		uintptr(unsafe.Pointer(&ppCapability)),
 	)
-	err = syscall.Errno(r1)
+	if r1 != S_OK {
+		err = syscall.Errno(r1)
+	}
 	return
 }
 
@@ -222,18 +233,22 @@ func WlanGetNetworkBssList(handle windows.Handle,
 		uintptr(bSecurityEnabled),
 		pReserved,
 		// out
-		uintptr(unsafe.Pointer(ppWlanBssList)),
+		uintptr(unsafe.Pointer(&ppWlanBssList)),
 	)
-	err = syscall.Errno(r1)
+	if r1 != S_OK {
+		err = syscall.Errno(r1)
+	}
 	return
 }
 
 //The WlanGetProfile function retrieves all information about a specified wireless profile.
 //https://docs.microsoft.com/zh-cn/windows/win32/api/wlanapi/nf-wlanapi-wlangetprofile
+//pdwFlags passes WLAN_PROFILE_GET_PLAINTEXT_KEY in and receives the WLAN_PROFILE_* flags of the profile.
 func WlanGetProfile(
 	handle windows.Handle,
 	pInterfaceGuid *windows.GUID,
-	strProfileName string) (pstrProfileXml string, pdwFlags *DWORD, pdwGrantedAccess *DWORD, err error) {
+	strProfileName string,
+	pdwFlags *DWORD) (pstrProfileXml string, pdwGrantedAccess DWORD, err error) {
 	pProfileName, err := syscall.UTF16PtrFromString(strProfileName)
 	if err != nil {
 		log.Println(err)
@@ -246,12 +261,16 @@ func WlanGetProfile(
 		uintptr(unsafe.Pointer(pProfileName)),
 		pReserved,
 		// out
-		uintptr(unsafe.Pointer(pstrProfile)),
+		uintptr(unsafe.Pointer(&pstrProfile)),
 		uintptr(unsafe.Pointer(pdwFlags)),
-		uintptr(unsafe.Pointer(pdwGrantedAccess)),
+		uintptr(unsafe.Pointer(&pdwGrantedAccess)),
 	)
-	err = syscall.Errno(r1)
-	//syscall.UTF16ToString(pstrProfile)
+	if r1 != S_OK {
+		err = syscall.Errno(r1)
+		return
+	}
+	defer WlanFreeMemory(PVOID(unsafe.Pointer(pstrProfile)))
+	pstrProfileXml = windows.UTF16PtrToString(pstrProfile)
 	return
 }
 
@@ -287,9 +306,11 @@ func WlanGetProfileList(handle windows.Handle, pInterfaceGuid *windows.GUID) (pp
 		uintptr(unsafe.Pointer(pInterfaceGuid)),
 		pReserved,
 		// out
-		uintptr(unsafe.Pointer(ppProfileList)),
+		uintptr(unsafe.Pointer(&ppProfileList)),
 	)
-	err = syscall.Errno(r1)
+	if r1 != S_OK {
+		err = syscall.Errno(r1)
+	}
 	return
 }
 
@@ -322,7 +343,39 @@ func WlanGetSupportedDeviceServices(handle windows.Handle, pInterfaceGuid *windo
 		// out
 		uintptr(unsafe.Pointer(&ppDevSvcGuidList)),
 	)
-	err = syscall.Errno(r1)
+	if r1 != S_OK {
+		err = syscall.Errno(r1)
+	}
+	return
+}
+
+//WlanDeviceServiceCommand Allows an OEM or IHV component to communicate with a device service on a particular wireless LAN interface.
+//https://docs.microsoft.com/en-us/windows/win32/api/wlanapi/nf-wlanapi-wlandeviceservicecommand
+func WlanDeviceServiceCommand(
+	handle windows.Handle,
+	pInterfaceGuid *windows.GUID,
+	pDeviceServiceGuid *windows.GUID,
+	dwOpCode DWORD,
+	dwInBufferSize DWORD,
+	pInBuffer *BYTE,
+	dwOutBufferSize DWORD,
+	pOutBuffer *BYTE) (dwBytesReturned DWORD, err error) {
+
+	r1, _, _ := wlanDeviceServiceCommand.Call(
+		uintptr(handle),
+		uintptr(unsafe.Pointer(pInterfaceGuid)),
+		uintptr(unsafe.Pointer(pDeviceServiceGuid)),
+		uintptr(dwOpCode),
+		uintptr(dwInBufferSize),
+		uintptr(unsafe.Pointer(pInBuffer)),
+		uintptr(dwOutBufferSize),
+		uintptr(unsafe.Pointer(pOutBuffer)),
+		// out
+		uintptr(unsafe.Pointer(&dwBytesReturned)),
+	)
+	if r1 != S_OK {
+		err = syscall.Errno(r1)
+	}
 	return
 }
 
@@ -370,16 +423,20 @@ func WlanHostedNetworkInitSettings(handle windows.Handle) (pFailReason *WLAN_HOS
 func WlanHostedNetworkQueryProperty(handle windows.Handle, OpCode WLAN_HOSTED_NETWORK_OPCODE) (
 	pdwDataSize *DWORD, ppvData *PVOID, pWlanOpcodeValueType *WLAN_OPCODE_VALUE_TYPE, err error) {
 
+	pdwDataSize = new(DWORD)
+	pWlanOpcodeValueType = new(WLAN_OPCODE_VALUE_TYPE)
 	r1, _, _ := wlanHostedNetworkQueryProperty.Call(
 		uintptr(handle),
 		uintptr(OpCode),
 		// out
-		uintptr(unsafe.Pointer(&pdwDataSize)),
+		uintptr(unsafe.Pointer(pdwDataSize)),
 		uintptr(unsafe.Pointer(&ppvData)),
-		uintptr(unsafe.Pointer(&pWlanOpcodeValueType)),
+		uintptr(unsafe.Pointer(pWlanOpcodeValueType)),
 		pReserved,
 	)
-	err = syscall.Errno(r1)
+	if r1 != S_OK {
+		err = syscall.Errno(r1)
+	}
 	return
 }
 
@@ -411,7 +468,9 @@ func WlanHostedNetworkQueryStatus(handle windows.Handle) (ppWlanHostedNetworkSta
 		uintptr(unsafe.Pointer(&ppWlanHostedNetworkStatus)),
 		pReserved,
 	)
-	err = syscall.Errno(r1)
+	if r1 != S_OK {
+		err = syscall.Errno(r1)
+	}
 	return
 }
 
@@ -436,16 +495,19 @@ func WlanHostedNetworkSetProperty(
 	dwDataSize DWORD,
 	pvData PVOID) (pFailReason *WLAN_HOSTED_NETWORK_REASON, err error) {
 
+	pFailReason = new(WLAN_HOSTED_NETWORK_REASON)
 	r1, _, _ := wlanHostedNetworkSetProperty.Call(
 		uintptr(handle),
 		uintptr(OpCode),
 		uintptr(dwDataSize),
 		uintptr(pvData),
 		// out
-		uintptr(unsafe.Pointer(&pFailReason)),
+		uintptr(unsafe.Pointer(pFailReason)),
 		pReserved,
 	)
-	err = syscall.Errno(r1)
+	if r1 != S_OK {
+		err = syscall.Errno(r1)
+	}
 	return
 }
 
@@ -481,7 +543,9 @@ func WlanHostedNetworkStartUsing(handle windows.Handle) (pFailReason WLAN_HOSTED
 		uintptr(unsafe.Pointer(&pFailReason)),
 		pReserved,
 	)
-	err = syscall.Errno(r1)
+	if r1 != S_OK {
+		err = syscall.Errno(r1)
+	}
 	return
 }
 
@@ -495,7 +559,9 @@ func WlanHostedNetworkStopUsing(handle windows.Handle) (pFailReason WLAN_HOSTED_
 		uintptr(unsafe.Pointer(&pFailReason)),
 		pReserved,
 	)
-	err = syscall.Errno(r1)
+	if r1 != S_OK {
+		err = syscall.Errno(r1)
+	}
 	return
 }
 
@@ -506,21 +572,24 @@ func WlanIhvControl(
 	pInterfaceGuid *windows.GUID,
 	Type WLAN_IHV_CONTROL_TYPE,
 	dwInBufferSize DWORD,
-	pInBuffer PVOID,
-	dwOutBufferSize DWORD) (pOutBuffer *PVOID, pdwBytesReturned *DWORD, err error) {
+	pInBuffer *BYTE,
+	dwOutBufferSize DWORD,
+	pOutBuffer *BYTE) (dwBytesReturned DWORD, err error) {
 
 	r1, _, _ := wlanIhvControl.Call(
 		uintptr(handle),
 		uintptr(unsafe.Pointer(pInterfaceGuid)),
 		uintptr(Type),
 		uintptr(dwInBufferSize),
-		uintptr(pInBuffer),
+		uintptr(unsafe.Pointer(pInBuffer)),
 		uintptr(dwOutBufferSize),
+		uintptr(unsafe.Pointer(pOutBuffer)),
 		// out
-		uintptr(unsafe.Pointer(&pOutBuffer)),
-		uintptr(unsafe.Pointer(&pdwBytesReturned)),
+		uintptr(unsafe.Pointer(&dwBytesReturned)),
 	)
-	err = syscall.Errno(r1)
+	if r1 != S_OK {
+		err = syscall.Errno(r1)
+	}
 	return
 }
 
@@ -547,6 +616,8 @@ func WlanQueryAutoConfigParameter(handle windows.Handle, OpCode WLAN_AUTOCONF_OP
 func WlanQueryInterface(handle windows.Handle, pInterfaceGuid *windows.GUID, OpCode WLAN_INTF_OPCODE) (
 	pdwDataSize *DWORD, ppData *PVOID, pWlanOpcodeValueType *WLAN_OPCODE_VALUE_TYPE, err error) {
 
+	pdwDataSize = new(DWORD)
+	pWlanOpcodeValueType = new(WLAN_OPCODE_VALUE_TYPE)
 	r1, _, _ := procWlanQueryInterface.Call(
 		uintptr(handle),
 		uintptr(unsafe.Pointer(pInterfaceGuid)),
@@ -578,31 +649,55 @@ func WlanReasonCodeToString(dwReasonCode DWORD, dwBufferSize DWORD, pStringBuffe
 
 //WlanRegisterDeviceServiceNotification Allows user mode clients with admin privileges, or User-Mode Driver Framework (UMDF) drivers, to register for unsolicited notifications corresponding to device services that they're interested in.
 //https://docs.microsoft.com/zh-cn/windows/win32/api/wlanapi/nf-wlanapi-wlanregisterdeviceservicenotification
-func WlanRegisterDeviceServiceNotification(handle windows.Handle, pDevSvcGuidList WLAN_DEVICE_SERVICE_GUID_LIST) (err error) {
-	r1, _, _ := wlanIhvControl.Call(
+//A nil pDevSvcGuidList unregisters all device service notifications of the handle.
+func WlanRegisterDeviceServiceNotification(handle windows.Handle, pDevSvcGuidList *WLAN_DEVICE_SERVICE_GUID_LIST) (err error) {
+	r1, _, _ := wlanRegisterDeviceServiceNotification.Call(
 		uintptr(handle),
-		uintptr(unsafe.Pointer(&pDevSvcGuidList)),
+		uintptr(unsafe.Pointer(pDevSvcGuidList)),
 	)
-	err = syscall.Errno(r1)
+	if r1 != S_OK {
+		err = syscall.Errno(r1)
+	}
 	return
 }
 
 //The WlanRegisterNotification function is used to register and unregister notifications on all wireless interfaces.
 //https://docs.microsoft.com/zh-cn/windows/win32/api/wlanapi/nf-wlanapi-wlanregisternotification
-// TODO:
-func WlanRegisterNotification(handle windows.Handle, dwNotifSource DWORD, bIgnoreDuplicate BOOL) (err error) {
+//funcCallback is a function pointer created with windows.NewCallback and receives a *WLAN_NOTIFICATION_DATA and pCallbackContext.
+func WlanRegisterNotification(
+	handle windows.Handle,
+	dwNotifSource DWORD,
+	bIgnoreDuplicate BOOL,
+	funcCallback uintptr,
+	pCallbackContext PVOID) (dwPrevNotifSource DWORD, err error) {
 
+	r1, _, _ := wlanRegisterNotification.Call(
+		uintptr(handle),
+		uintptr(dwNotifSource),
+		uintptr(bIgnoreDuplicate),
+		funcCallback,
+		uintptr(pCallbackContext),
+		pReserved,
+		// out
+		uintptr(unsafe.Pointer(&dwPrevNotifSource)),
+	)
+	if r1 != S_OK {
+		err = syscall.Errno(r1)
+	}
 	return
 }
 
 //The WlanRegisterVirtualStationNotification function is used to register and unregister notifications on a virtual station.
 //https://docs.microsoft.com/zh-cn/windows/win32/api/wlanapi/nf-wlanapi-wlanregistervirtualstationnotification
 func WlanRegisterVirtualStationNotification(handle windows.Handle, bRegister BOOL) (err error) {
-	r1, _, _ := wlanIhvControl.Call(
+	r1, _, _ := wlanRegisterVirtualStationNotification.Call(
 		uintptr(handle),
 		uintptr(bRegister),
+		pReserved,
 	)
-	err = syscall.Errno(r1)
+	if r1 != S_OK {
+		err = syscall.Errno(r1)
+	}
 	return
 }
 
@@ -688,47 +783,56 @@ func WlanSetFilterList(handle windows.Handle, wlanFilterListType WLAN_FILTER_LIS
 //The WlanSetInterface function sets user-configurable parameters for a specified interface.
 //https://docs.microsoft.com/zh-cn/windows/win32/api/wlanapi/nf-wlanapi-wlansetinterface
 func WlanSetInterface(handle windows.Handle,
-	pInterfaceGuid *windows.GUID, OpCode WLAN_INTF_OPCODE, dwDataSize DWORD, pData PVOID) (err error) {
-	r1, _, _ := wlanIhvControl.Call(
+	pInterfaceGuid *windows.GUID, OpCode WLAN_INTF_OPCODE, dwDataSize DWORD, pData *BYTE) (err error) {
+	r1, _, _ := wlanSetInterface.Call(
 		uintptr(handle),
 		uintptr(unsafe.Pointer(pInterfaceGuid)),
 		uintptr(OpCode),
 		uintptr(dwDataSize),
-		uintptr(pData),
+		uintptr(unsafe.Pointer(pData)),
 		pReserved,
 	)
-	err = syscall.Errno(r1)
+	if r1 != S_OK {
+		err = syscall.Errno(r1)
+	}
 	return
 }
 
 //The WlanSetProfile function sets the content of a specific profile.
 //https://docs.microsoft.com/zh-cn/windows/win32/api/wlanapi/nf-wlanapi-wlansetprofile
+//An empty strAllUserProfileSecurity gives the profile the default security.
 func WlanSetProfile(handle windows.Handle,
 	pInterfaceGuid *windows.GUID,
 	dwFlags DWORD,
 	strProfileXml, strAllUserProfileSecurity string,
 	bOverwrite BOOL) (pdwReasonCode *DWORD, err error) {
-	profileXml, err := syscall.UTF16FromString(strProfileXml)
+	profileXml, err := syscall.UTF16PtrFromString(strProfileXml)
 	if err != nil {
 		log.Println(err)
 		return
 	}
-	allUserProfileSecurity, err := syscall.UTF16FromString(strAllUserProfileSecurity)
-	if err != nil {
-		log.Println(err)
-		return
+	var allUserProfileSecurity *uint16
+	if strAllUserProfileSecurity != "" {
+		allUserProfileSecurity, err = syscall.UTF16PtrFromString(strAllUserProfileSecurity)
+		if err != nil {
+			log.Println(err)
+			return
+		}
 	}
-	r1, _, _ := wlanIhvControl.Call(
+	pdwReasonCode = new(DWORD)
+	r1, _, _ := wlanSetProfile.Call(
 		uintptr(handle),
 		uintptr(unsafe.Pointer(pInterfaceGuid)),
 		uintptr(dwFlags),
-		uintptr(unsafe.Pointer(&profileXml)),
-		uintptr(unsafe.Pointer(&allUserProfileSecurity)),
+		uintptr(unsafe.Pointer(profileXml)),
+		uintptr(unsafe.Pointer(allUserProfileSecurity)),
 		uintptr(bOverwrite),
 		pReserved,
-		uintptr(unsafe.Pointer(&pdwReasonCode)),
+		uintptr(unsafe.Pointer(pdwReasonCode)),
 	)
-	err = syscall.Errno(r1)
+	if r1 != S_OK {
+		err = syscall.Errno(r1)
+	}
 	return
 }
 
@@ -866,19 +970,22 @@ func WlanSetProfilePosition(
 
 //The WlanSetPsdIEDataList function sets the proximity service discovery (PSD) information element (IE) data list.
 //https://docs.microsoft.com/en-us/windows/win32/api/wlanapi/nf-wlanapi-wlansetpsdiedatalist
+//A nil pPsdIEDataList clears the PSD IE data list of the format.
 func WlanSetPsdIEDataList(handle windows.Handle, strFormat string, pPsdIEDataList *WLAN_RAW_DATA_LIST) (err error) {
-	format, err := syscall.UTF16FromString(strFormat)
+	format, err := syscall.UTF16PtrFromString(strFormat)
 	if err != nil {
 		log.Println(err)
 		return
 	}
-	r1, _, _ := wlanIhvControl.Call(
+	r1, _, _ := wlanSetPsdIEDataList.Call(
 		uintptr(handle),
-		uintptr(unsafe.Pointer(&format)),
-		uintptr(unsafe.Pointer(&pPsdIEDataList)),
+		uintptr(unsafe.Pointer(format)),
+		uintptr(unsafe.Pointer(pPsdIEDataList)),
 		pReserved,
 	)
-	err = syscall.Errno(r1)
+	if r1 != S_OK {
+		err = syscall.Errno(r1)
+	}
 	return
 }
 
@@ -927,11 +1034,30 @@ func WlanUIEditProfile(
 	return
 }
 
+//defaultInterface returns the first connected interface, or the interface at dwIndex when none is connected.
+//Use Client.SelectInterfaces to choose among several adapters.
 func defaultInterface(handle windows.Handle) (wii WLAN_INTERFACE_INFO, err error) {
 	iil, err := WlanEnumInterfaces(handle)
-	log.Printf("dwIndex:%d dwNumberOfItems:%d", iil.dwIndex, iil.dwNumberOfItems)
 	if err != nil {
 		return
 	}
-	return iil.InterfaceInfo[iil.dwIndex], nil
+	defer WlanFreeMemory(PVOID(unsafe.Pointer(iil)))
+	infos, err := iil.Decode()
+	if err != nil {
+		return
+	}
+	if int(iil.dwIndex) >= len(infos) {
+		return wii, windows.ERROR_NOT_FOUND
+	}
+	info := infos[iil.dwIndex]
+	for _, candidate := range infos {
+		if WLAN_INTERFACE_STATE(candidate.State) == wlan_interface_state_connected {
+			info = candidate
+			break
+		}
+	}
+	wii.InterfaceGuid = windows.GUID(info.InterfaceGuid)
+	copy(wii.strInterfaceDescription[:len(wii.strInterfaceDescription)-1], windows.StringToUTF16(info.Description))
+	wii.isState = info.State
+	return wii, nil
 }
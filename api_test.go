@@ -1,9 +1,15 @@
+//go:build windows
+// +build windows
+
 package wlanapi
 
 import (
 	"golang.org/x/sys/windows"
 	"log"
 	"testing"
+	"unsafe"
+
+	"wlanapi/binary"
 )
 
 func handleSession() (handle windows.Handle) {
@@ -52,10 +58,120 @@ func TestWlanGetAvailableNetworkList(t *testing.T) {
 		log.Println(err)
 		return
 	}
+	defer WlanFreeMemory(PVOID(unsafe.Pointer(ppAvailableNetworkList)))
 	log.Printf("dwIndex:%d dwNumberOfItems:%d", ppAvailableNetworkList.dwIndex, ppAvailableNetworkList.dwNumberOfItems)
-	for i := ppAvailableNetworkList.dwIndex; i < ppAvailableNetworkList.dwNumberOfItems; i++ {
-		availableNetwork := ppAvailableNetworkList.Network[i]
-		t.Log(availableNetwork.GetStrProfileName())
+	networks, err := ppAvailableNetworkList.Decode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, network := range networks {
+		t.Log(network.ProfileName, string(network.SSID), network.SignalQuality)
+	}
+}
+
+func TestWlanGetNetworkBssList(t *testing.T) {
+	session := handleSession()
+	defer WlanCloseHandle(session)
+	wii, err := defaultInterface(session)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	ppWlanBssList, err := WlanGetNetworkBssList(session, &wii.InterfaceGuid, nil, Dot11BssTypeAny, FALSE)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	defer WlanFreeMemory(PVOID(unsafe.Pointer(ppWlanBssList)))
+	entries, err := ppWlanBssList.Decode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, entry := range entries {
+		t.Logf("%x %q %d dBm %d kHz %d bytes of IEs", entry.BSSID, entry.SSID, entry.RSSI, entry.ChCenterFrequency, len(entry.IEs))
+	}
+}
+
+func TestWlanGetProfileList(t *testing.T) {
+	session := handleSession()
+	defer WlanCloseHandle(session)
+	wii, err := defaultInterface(session)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	list, err := WlanGetProfileList(session, &wii.InterfaceGuid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer WlanFreeMemory(PVOID(unsafe.Pointer(list)))
+	log.Printf("dwIndex:%d dwNumberOfItems:%d", list.dwIndex, list.dwNumberOfItems)
+	var flags DWORD
+	if _, _, err := WlanGetProfile(session, &wii.InterfaceGuid, "wlanapi test profile that does not exist", &flags); err != windows.ERROR_NOT_FOUND {
+		t.Errorf("missing profile: %v", err)
+	}
+}
+
+//TestWlanSetProfile sets a profile that is not valid, which WlanSetProfile rejects with a reason code.
+func TestWlanSetProfile(t *testing.T) {
+	session := handleSession()
+	defer WlanCloseHandle(session)
+	wii, err := defaultInterface(session)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	reason, err := WlanSetProfile(session, &wii.InterfaceGuid, 0, "<WLANProfile/>", "", FALSE)
+	if err == nil {
+		t.Fatal("WlanSetProfile accepted a profile that is not valid")
+	}
+	if reason == nil || *reason == 0 {
+		t.Errorf("no reason code for %v", err)
+	}
+}
+
+func TestWlanHostedNetworkQueryProperty(t *testing.T) {
+	session := handleSession()
+	defer WlanCloseHandle(session)
+	size, data, valueType, err := WlanHostedNetworkQueryProperty(session, wlan_hosted_network_opcode_enable)
+	if err != nil {
+		//Adapters without hosted network support fail the query.
+		log.Println(err)
+		return
+	}
+	defer WlanFreeMemory(PVOID(unsafe.Pointer(data)))
+	if *size != 4 {
+		t.Errorf("enable property of %d bytes", *size)
+	}
+	log.Println(*valueType)
+}
+
+//TestWLANListLayouts checks the Go declarations of the list structures against the binary layouts.
+//WLAN_BSS_ENTRY is left out: Go aligns its uint64 fields to 4 bytes on 386, unlike MSVC.
+func TestWLANListLayouts(t *testing.T) {
+	abi := binary.NativeABI()
+	for name, offset := range map[string]uintptr{
+		"WLAN_INTERFACE_INFO_LIST":    unsafe.Offsetof(WLAN_INTERFACE_INFO_LIST{}.InterfaceInfo),
+		"WLAN_AVAILABLE_NETWORK_LIST": unsafe.Offsetof(WLAN_AVAILABLE_NETWORK_LIST{}.Network),
+		"WLAN_BSS_LIST":               unsafe.Offsetof(WLAN_BSS_LIST{}.wlanBssEntries),
+		"DOT11_NETWORK_LIST":          unsafe.Offsetof(DOT11_NETWORK_LIST{}.Network),
+		"WLAN_HOSTED_NETWORK_STATUS":  unsafe.Offsetof(WLAN_HOSTED_NETWORK_STATUS{}.PeerList),
+		"WLAN_PROFILE_INFO_LIST":      unsafe.Offsetof(WLAN_PROFILE_INFO_LIST{}.ProfileInfo),
+	} {
+		if got := binary.ListSize(abi, name, 0); uintptr(got) != offset {
+			t.Errorf("%s: elements at %d, Go declaration has %d", name, got, offset)
+		}
+	}
+	for name, size := range map[string]uintptr{
+		"WLAN_INTERFACE_INFO":    unsafe.Sizeof(WLAN_INTERFACE_INFO{}),
+		"WLAN_AVAILABLE_NETWORK": unsafe.Sizeof(WLAN_AVAILABLE_NETWORK{}),
+		"DOT11_NETWORK":          unsafe.Sizeof(DOT11_NETWORK{}),
+		"WLAN_PROFILE_INFO":      unsafe.Sizeof(WLAN_PROFILE_INFO{}),
+		"WLAN_NOTIFICATION_DATA": unsafe.Sizeof(WLAN_NOTIFICATION_DATA{}),
+	} {
+		if got := binary.LayoutOf(abi, name).Size; uintptr(got) != size {
+			t.Errorf("%s: %d bytes, Go declaration has %d", name, got, size)
+		}
 	}
 }
 
@@ -76,6 +192,101 @@ func TestWlanQueryInterface(t *testing.T) {
 	log.Println(*queryInterface, *data, *valueType)
 }
 
-func TestWlanGetProfile(t *testing.T) {
+func TestInterfaceReport(t *testing.T) {
+	client, err := NewClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+	interfaces, err := client.Interfaces()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, i := range interfaces {
+		report, err := i.Report()
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		log.Println(report)
+	}
+}
 
+func TestInterfaceDeviceServices(t *testing.T) {
+	client, err := NewClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+	interfaces, err := client.Interfaces()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, i := range interfaces {
+		services, err := i.DeviceServices()
+		if err != nil {
+			log.Println(err)
+			continue
+		}
+		for _, svc := range services {
+			log.Println(i.Description, svc.String())
+		}
+	}
+}
+
+func TestInterfaceSecondaryStations(t *testing.T) {
+	client, err := NewClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+	interfaces, err := client.Interfaces()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, i := range interfaces {
+		stations, err := i.SecondaryStations()
+		if err != nil {
+			log.Println(err)
+			continue
+		}
+		synchronized, err := i.SynchronizedConnections()
+		log.Println(i.Description, stations, synchronized, err)
+	}
+}
+
+func TestWLANRawDataListLayout(t *testing.T) {
+	var l WLAN_RAW_DATA_LIST
+	if unsafe.Offsetof(l.DataList) != rawDataListHeaderSize || unsafe.Sizeof(l.DataList[0]) != rawDataListEntrySize {
+		t.Errorf("WLAN_RAW_DATA_LIST DataList at %d with entries of %d bytes",
+			unsafe.Offsetof(l.DataList), unsafe.Sizeof(l.DataList[0]))
+	}
+}
+
+func TestWlanGetProfile(t *testing.T) {
+	client, err := NewClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+	interfaces, err := client.Interfaces()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, i := range interfaces {
+		profiles, err := i.Profiles()
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		for _, p := range profiles {
+			xml, err := i.ProfileXML(p.Name, false)
+			log.Println(i.Description, p.Name, p.Flags, len(xml), err)
+		}
+		if _, err := i.ProfileXML("wlanapi test profile that does not exist", false); err != ErrProfileNotFound {
+			t.Errorf("missing profile: %v", err)
+		}
+		c, err := i.Connection()
+		log.Println(i.Description, c, err)
+	}
 }
@@ -0,0 +1,27 @@
+package wlanapi
+
+import (
+	"errors"
+
+	"wlanapi/binary"
+)
+
+//Backend is the WLAN service behind a Client.
+//The native backend calls wlanapi.dll; Sim is an in-memory backend for tests.
+type Backend interface {
+	//Interfaces enumerates the wireless LAN interfaces, as WlanEnumInterfaces.
+	Interfaces() ([]binary.InterfaceInfo, error)
+	//Scan requests a scan on the interface, as WlanScan.
+	Scan(iface GUID) error
+	//AvailableNetworks retrieves the networks visible to the interface, as WlanGetAvailableNetworkList.
+	AvailableNetworks(iface GUID) ([]binary.AvailableNetwork, error)
+	//BSSList retrieves the BSS entries visible to the interface, as WlanGetNetworkBssList.
+	BSSList(iface GUID) ([]binary.BSSEntry, error)
+	//Notifications subscribes to the notifications of all sources.
+	//The returned function cancels the subscription and closes the channel.
+	Notifications() (<-chan Notification, func(), error)
+	Close() error
+}
+
+//ErrNotSupported is returned by NewClient on platforms without wlanapi.dll.
+var ErrNotSupported = errors.New("wlanapi: Native Wifi is not supported on this platform")
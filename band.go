@@ -0,0 +1,122 @@
+package wlanapi
+
+import "strings"
+
+//Band is a frequency band used by 802.11.
+type Band uint8
+
+const (
+	Band2_4GHz Band = 1 << iota
+	Band5GHz
+	Band6GHz
+	Band60GHz
+)
+
+//BandOf returns the band of a channel center frequency in kHz, as in WLAN_BSS_ENTRY.ulChCenterFrequency, or 0 if it is outside all bands.
+func BandOf(frequencyKHz uint32) Band {
+	mhz := frequencyKHz / 1000
+	switch {
+	case mhz >= 2400 && mhz < 2500:
+		return Band2_4GHz
+	case mhz >= 5150 && mhz < 5925:
+		return Band5GHz
+	case mhz >= 5925 && mhz <= 7125:
+		return Band6GHz
+	case mhz >= 57000 && mhz <= 71000:
+		return Band60GHz
+	}
+	return 0
+}
+
+//ChannelOf returns the channel number of a channel center frequency in kHz, or 0 if it is outside all bands.
+func ChannelOf(frequencyKHz uint32) int {
+	mhz := int(frequencyKHz / 1000)
+	switch BandOf(frequencyKHz) {
+	case Band2_4GHz:
+		if mhz == 2484 {
+			return 14
+		}
+		return (mhz - 2407) / 5
+	case Band5GHz:
+		return (mhz - 5000) / 5
+	case Band6GHz:
+		if mhz == 5935 {
+			return 2
+		}
+		//Below channel 1, the band has only channel 2.
+		if mhz < 5950 {
+			return 0
+		}
+		return (mhz - 5950) / 5
+	case Band60GHz:
+		return (mhz - 56160) / 2160
+	}
+	return 0
+}
+
+//FrequencyOf returns the center frequency in kHz of a channel of a band, or 0 if the band has no such channel.
+func FrequencyOf(band Band, channel int) uint32 {
+	var mhz int
+	switch band {
+	case Band2_4GHz:
+		mhz = 2407 + 5*channel
+		if channel == 14 {
+			mhz = 2484
+		}
+	case Band5GHz:
+		mhz = 5000 + 5*channel
+	case Band6GHz:
+		mhz = 5950 + 5*channel
+		if channel == 2 {
+			mhz = 5935
+		}
+	case Band60GHz:
+		mhz = 56160 + 2160*channel
+	}
+	khz := uint32(mhz) * 1000
+	if channel <= 0 || BandOf(khz) != band || ChannelOf(khz) != channel {
+		return 0
+	}
+	return khz
+}
+
+func (b Band) String() string {
+	switch b {
+	case Band2_4GHz:
+		return "2.4GHz"
+	case Band5GHz:
+		return "5GHz"
+	case Band6GHz:
+		return "6GHz"
+	case Band60GHz:
+		return "60GHz"
+	}
+	return BandSet(b).String()
+}
+
+//BandSet is a set of bands.
+type BandSet uint8
+
+//Has reports whether the set contains band b.
+func (s BandSet) Has(b Band) bool {
+	return s&BandSet(b) != 0
+}
+
+//Bands returns the bands of the set from low to high frequency.
+func (s BandSet) Bands() []Band {
+	var bands []Band
+	for b := Band2_4GHz; b <= Band60GHz; b <<= 1 {
+		if s.Has(b) {
+			bands = append(bands, b)
+		}
+	}
+	return bands
+}
+
+func (s BandSet) String() string {
+	var names []string
+	for _, b := range s.Bands() {
+		names = append(names, b.String())
+	}
+	return strings.Join(names, ",")
+}
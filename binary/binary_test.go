@@ -0,0 +1,430 @@
+package binary
+
+import (
+	"bytes"
+	"testing"
+	"unicode/utf16"
+)
+
+//golden offsets were taken from the wlanapi.h declarations compiled with MSVC and clang
+//for each target; only WLAN_NOTIFICATION_DATA contains a pointer and differs between ABIs.
+var golden = []struct {
+	name   string
+	size   map[ABI]int
+	fields map[string]int
+}{
+	{"GUID", all(16), map[string]int{"Data1": 0, "Data2": 4, "Data3": 6, "Data4": 8}},
+	{"DOT11_SSID", all(36), map[string]int{"uSSIDLength": 0, "ucSSID": 4}},
+	{"WLAN_INTERFACE_INFO", all(532), map[string]int{"InterfaceGuid": 0, "strInterfaceDescription": 16, "isState": 528}},
+	{"WLAN_INTERFACE_INFO_LIST", all(540), map[string]int{"dwNumberOfItems": 0, "dwIndex": 4, "InterfaceInfo": 8}},
+	{"WLAN_AVAILABLE_NETWORK", all(628), map[string]int{
+		"strProfileName":              0,
+		"dot11Ssid":                   512,
+		"dot11BssType":                548,
+		"uNumberOfBssids":             552,
+		"bNetworkConnectable":         556,
+		"wlanNotConnectableReason":    560,
+		"uNumberOfPhyTypes":           564,
+		"dot11PhyTypes":               568,
+		"bMorePhyTypes":               600,
+		"wlanSignalQuality":           604,
+		"bSecurityEnabled":            608,
+		"dot11DefaultAuthAlgorithm":   612,
+		"dot11DefaultCipherAlgorithm": 616,
+		"dwFlags":                     620,
+		"dwReserved":                  624,
+	}},
+	{"WLAN_AVAILABLE_NETWORK_LIST", all(636), map[string]int{"dwNumberOfItems": 0, "dwIndex": 4, "Network": 8}},
+	{"WLAN_RATE_SET", all(256), map[string]int{"uRateSetLength": 0, "usRateSet": 4}},
+	{"WLAN_BSS_ENTRY", all(360), map[string]int{
+		"dot11Ssid":               0,
+		"uPhyId":                  36,
+		"dot11Bssid":              40,
+		"dot11BssType":            48,
+		"dot11BssPhyType":         52,
+		"lRssi":                   56,
+		"uLinkQuality":            60,
+		"bInRegDomain":            64,
+		"usBeaconPeriod":          66,
+		"ullTimestamp":            72,
+		"ullHostTimestamp":        80,
+		"usCapabilityInformation": 88,
+		"ulChCenterFrequency":     92,
+		"wlanRateSet":             96,
+		"ulIeOffset":              352,
+		"ulIeSize":                356,
+	}},
+	{"WLAN_BSS_LIST", all(368), map[string]int{"dwTotalSize": 0, "dwNumberOfItems": 4, "wlanBssEntries": 8}},
+	{"DOT11_NETWORK", all(40), map[string]int{"dot11Ssid": 0, "dot11BssType": 36}},
+	{"DOT11_NETWORK_LIST", all(48), map[string]int{"dwNumberOfItems": 0, "dwIndex": 4, "Network": 8}},
+	{"WLAN_HOSTED_NETWORK_PEER_STATE", all(12), map[string]int{"PeerMacAddress": 0, "PeerAuthState": 8}},
+	{"WLAN_HOSTED_NETWORK_STATUS", all(52), map[string]int{
+		"HostedNetworkState":     0,
+		"IPDeviceID":             4,
+		"wlanHostedNetworkBSSID": 20,
+		"dot11PhyType":           28,
+		"ulChannelFrequency":     32,
+		"dwNumberOfPeers":        36,
+		"PeerList":               40,
+	}},
+	{"WLAN_PROFILE_INFO", all(516), map[string]int{"strProfileName": 0, "dwFlags": 512}},
+	{"WLAN_PROFILE_INFO_LIST", all(524), map[string]int{"dwNumberOfItems": 0, "dwIndex": 4, "ProfileInfo": 8}},
+	{"WLAN_ASSOCIATION_ATTRIBUTES", all(68), map[string]int{
+		"dot11Ssid":         0,
+		"dot11BssType":      36,
+		"dot11Bssid":        40,
+		"dot11PhyType":      48,
+		"uDot11PhyIndex":    52,
+		"wlanSignalQuality": 56,
+		"ulRxRate":          60,
+		"ulTxRate":          64,
+	}},
+	{"WLAN_CONNECTION_ATTRIBUTES", all(604), map[string]int{
+		"isState":                   0,
+		"wlanConnectionMode":        4,
+		"strProfileName":            8,
+		"wlanAssociationAttributes": 520,
+		"wlanSecurityAttributes":    588,
+	}},
+	{"WLAN_MAC_FRAME_STATISTICS", all(96), map[string]int{"ullTransmittedFrameCount": 0, "ullDecryptFailureCount": 88}},
+	{"WLAN_PHY_FRAME_STATISTICS", all(144), map[string]int{"ullTransmittedFrameCount": 0, "ullFCSErrorCount": 136}},
+	{"WLAN_STATISTICS", all(368), map[string]int{
+		"ullFourWayHandshakeFailures":   0,
+		"ullTKIPCounterMeasuresInvoked": 8,
+		"MacUcastCounters":              24,
+		"MacMcastCounters":              120,
+		"dwNumberOfPhys":                216,
+		"PhyCounters":                   224,
+	}},
+	{"WLAN_NOTIFICATION_DATA", map[ABI]int{X86: 32, AMD64: 40, ARM64: 40}, map[string]int{
+		"NotificationSource": 0,
+		"NotificationCode":   4,
+		"InterfaceGuid":      8,
+		"dwDataSize":         24,
+	}},
+}
+
+func all(size int) map[ABI]int {
+	return map[ABI]int{X86: size, AMD64: size, ARM64: size}
+}
+
+func TestLayouts(t *testing.T) {
+	for _, g := range golden {
+		for _, abi := range ABIs {
+			l := LayoutOf(abi, g.name)
+			if l == nil {
+				t.Errorf("%s/%s: no layout", abi, g.name)
+				continue
+			}
+			if l.Size != g.size[abi] {
+				t.Errorf("%s/%s: size %d, want %d", abi, g.name, l.Size, g.size[abi])
+			}
+			for name, offset := range g.fields {
+				if got := l.Offset(name); got != offset {
+					t.Errorf("%s/%s.%s: offset %d, want %d", abi, g.name, name, got, offset)
+				}
+			}
+		}
+	}
+
+	pData := map[ABI]int{X86: 28, AMD64: 32, ARM64: 32}
+	for _, abi := range ABIs {
+		if got := LayoutOf(abi, "WLAN_NOTIFICATION_DATA").Offset("pData"); got != pData[abi] {
+			t.Errorf("%s/WLAN_NOTIFICATION_DATA.pData: offset %d, want %d", abi, got, pData[abi])
+		}
+	}
+}
+
+func TestListSize(t *testing.T) {
+	if got := ListSize(AMD64, "WLAN_INTERFACE_INFO_LIST", 0); got != 8 {
+		t.Errorf("empty interface list: %d bytes, want 8", got)
+	}
+	if got := ListSize(AMD64, "WLAN_BSS_LIST", 2); got != 8+2*360 {
+		t.Errorf("BSS list with 2 entries: %d bytes, want %d", got, 8+2*360)
+	}
+	if got := ListSize(X86, "WLAN_HOSTED_NETWORK_STATUS", 3); got != 40+3*12 {
+		t.Errorf("hosted network status with 3 peers: %d bytes, want %d", got, 40+3*12)
+	}
+}
+
+func put16(b []byte, off int, v uint16) {
+	b[off], b[off+1] = byte(v), byte(v>>8)
+}
+
+func put32(b []byte, off int, v uint32) {
+	put16(b, off, uint16(v))
+	put16(b, off+2, uint16(v>>16))
+}
+
+func put64(b []byte, off int, v uint64) {
+	put32(b, off, uint32(v))
+	put32(b, off+4, uint32(v>>32))
+}
+
+func putString(b []byte, off int, s string) {
+	for i, c := range utf16.Encode([]rune(s)) {
+		put16(b, off+2*i, c)
+	}
+}
+
+func putSSID(b []byte, off int, ssid string) {
+	put32(b, off, uint32(len(ssid)))
+	copy(b[off+4:], ssid)
+}
+
+func TestDecodeInterfaceInfoList(t *testing.T) {
+	b := make([]byte, ListSize(AMD64, "WLAN_INTERFACE_INFO_LIST", 2))
+	put32(b, 0, 2)
+	put32(b, 8, 0x01020304)
+	put16(b, 12, 0x0506)
+	put16(b, 14, 0x0708)
+	copy(b[16:24], []byte{9, 10, 11, 12, 13, 14, 15, 16})
+	putString(b, 8+16, "Intel(R) Wi-Fi 6 AX201 160MHz")
+	put32(b, 8+528, 1)
+	putString(b, 8+532+16, "Realtek USB")
+	put32(b, 8+532+528, 4)
+
+	infos, err := DecodeInterfaceInfoList(AMD64, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(infos) != 2 {
+		t.Fatalf("decoded %d interfaces, want 2", len(infos))
+	}
+	if got, want := infos[0].InterfaceGuid.String(), "{01020304-0506-0708-090A-0B0C0D0E0F10}"; got != want {
+		t.Errorf("GUID %s, want %s", got, want)
+	}
+	if infos[0].Description != "Intel(R) Wi-Fi 6 AX201 160MHz" || infos[0].State != 1 {
+		t.Errorf("interface 0: %+v", infos[0])
+	}
+	if infos[1].Description != "Realtek USB" || infos[1].State != 4 {
+		t.Errorf("interface 1: %+v", infos[1])
+	}
+
+	if _, err := DecodeInterfaceInfoList(AMD64, b[:len(b)-1]); err == nil {
+		t.Error("decoded a truncated list")
+	}
+	if _, err := DecodeInterfaceInfoList(AMD64, b[:4]); err == nil {
+		t.Error("decoded a truncated header")
+	}
+}
+
+func TestDecodeAvailableNetworkList(t *testing.T) {
+	b := make([]byte, ListSize(X86, "WLAN_AVAILABLE_NETWORK_LIST", 1))
+	put32(b, 0, 1)
+	n := 8
+	putString(b, n, "home")
+	putSSID(b, n+512, "home")
+	put32(b, n+548, 1)
+	put32(b, n+552, 3)
+	put32(b, n+556, 1)
+	put32(b, n+564, 2)
+	put32(b, n+568, 7)
+	put32(b, n+572, 8)
+	put32(b, n+604, 87)
+	put32(b, n+608, 1)
+	put32(b, n+612, 7)
+	put32(b, n+616, 4)
+	put32(b, n+620, 3)
+
+	networks, err := DecodeAvailableNetworkList(X86, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := networks[0]
+	if got.ProfileName != "home" || string(got.SSID) != "home" || got.BssType != 1 || got.NumberOfBssids != 3 ||
+		!got.NetworkConnectable || got.SignalQuality != 87 || !got.SecurityEnabled ||
+		got.DefaultAuthAlgorithm != 7 || got.DefaultCipherAlgorithm != 4 || got.Flags != 3 {
+		t.Errorf("decoded %+v", got)
+	}
+	if len(got.PhyTypes) != 2 || got.PhyTypes[0] != 7 || got.PhyTypes[1] != 8 {
+		t.Errorf("PHY types %v, want [7 8]", got.PhyTypes)
+	}
+}
+
+func TestDecodeBSSList(t *testing.T) {
+	ies := [][]byte{
+		{0, 4, 'c', 'a', 'f', 'e', 3, 1, 6},
+		{0, 0},
+	}
+	size := ListSize(AMD64, "WLAN_BSS_LIST", 2)
+	b := make([]byte, size+len(ies[0])+len(ies[1]))
+	put32(b, 0, uint32(len(b)))
+	put32(b, 4, 2)
+
+	offset := size
+	for i, ie := range ies {
+		e := 8 + 360*i
+		putSSID(b, e, "cafe")
+		put32(b, e+36, uint32(i))
+		copy(b[e+40:], []byte{0x00, 0x11, 0x22, 0x33, 0x44, byte(i)})
+		put32(b, e+48, 1)
+		put32(b, e+52, 7)
+		put32(b, e+56, uint32(-60+int32(-i)))
+		put32(b, e+60, 80)
+		b[e+64] = 1
+		put16(b, e+66, 100)
+		put64(b, e+72, 0x0102030405060708)
+		put64(b, e+80, 0x1112131415161718)
+		put16(b, e+88, 0x0431)
+		put32(b, e+92, 2437000)
+		put32(b, e+96, 4)
+		put16(b, e+100, 0x8002)
+		put16(b, e+102, 0x8004)
+		put32(b, e+352, uint32(offset-e))
+		put32(b, e+356, uint32(len(ie)))
+		copy(b[offset:], ie)
+		offset += len(ie)
+	}
+
+	entries, err := DecodeBSSList(AMD64, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("decoded %d entries, want 2", len(entries))
+	}
+	for i, e := range entries {
+		if string(e.SSID) != "cafe" || e.PhyID != uint32(i) || e.BSSID != [6]byte{0x00, 0x11, 0x22, 0x33, 0x44, byte(i)} ||
+			e.BssType != 1 || e.PhyType != 7 || e.RSSI != int32(-60-i) || e.LinkQuality != 80 || !e.InRegDomain ||
+			e.BeaconPeriod != 100 || e.Timestamp != 0x0102030405060708 || e.HostTimestamp != 0x1112131415161718 ||
+			e.CapabilityInformation != 0x0431 || e.ChCenterFrequency != 2437000 {
+			t.Errorf("entry %d: %+v", i, e)
+		}
+		if len(e.Rates) != 2 || e.Rates[0] != 0x8002 || e.Rates[1] != 0x8004 {
+			t.Errorf("entry %d: rates %x", i, e.Rates)
+		}
+		if !bytes.Equal(e.IEs, ies[i]) {
+			t.Errorf("entry %d: IEs %x, want %x", i, e.IEs, ies[i])
+		}
+	}
+
+	put32(b, 8+360+356, 100)
+	if _, err := DecodeBSSList(AMD64, b); err == nil {
+		t.Error("decoded IEs past dwTotalSize")
+	}
+	put32(b, 0, uint32(len(b)+1))
+	if _, err := DecodeBSSList(AMD64, b); err == nil {
+		t.Error("decoded a list shorter than dwTotalSize")
+	}
+}
+
+func TestDecodeNetworkList(t *testing.T) {
+	b := make([]byte, ListSize(ARM64, "DOT11_NETWORK_LIST", 2))
+	put32(b, 0, 2)
+	putSSID(b, 8, "a")
+	put32(b, 8+36, 1)
+	putSSID(b, 8+40, "bb")
+	put32(b, 8+40+36, 2)
+
+	networks, err := DecodeNetworkList(ARM64, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(networks) != 2 || string(networks[0].SSID) != "a" || networks[0].BssType != 1 ||
+		string(networks[1].SSID) != "bb" || networks[1].BssType != 2 {
+		t.Errorf("decoded %+v", networks)
+	}
+}
+
+func TestDecodeHostedNetworkStatus(t *testing.T) {
+	b := make([]byte, ListSize(X86, "WLAN_HOSTED_NETWORK_STATUS", 2))
+	put32(b, 0, 2)
+	put32(b, 4, 0xdeadbeef)
+	copy(b[20:], []byte{2, 0, 0, 0, 0, 1})
+	put32(b, 28, 7)
+	put32(b, 32, 2412000)
+	put32(b, 36, 2)
+	copy(b[40:], []byte{0xa, 0xb, 0xc, 0xd, 0xe, 0xf})
+	put32(b, 48, 1)
+	copy(b[52:], []byte{1, 2, 3, 4, 5, 6})
+
+	s, err := DecodeHostedNetworkStatus(X86, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.State != 2 || s.IPDeviceID.Data1 != 0xdeadbeef || s.BSSID != [6]byte{2, 0, 0, 0, 0, 1} ||
+		s.PhyType != 7 || s.ChannelFrequency != 2412000 {
+		t.Errorf("decoded %+v", s)
+	}
+	if len(s.Peers) != 2 || s.Peers[0].MacAddress != [6]byte{0xa, 0xb, 0xc, 0xd, 0xe, 0xf} ||
+		s.Peers[0].AuthState != 1 || s.Peers[1].MacAddress != [6]byte{1, 2, 3, 4, 5, 6} || s.Peers[1].AuthState != 0 {
+		t.Errorf("decoded peers %+v", s.Peers)
+	}
+}
+
+func TestDecodeProfileInfoList(t *testing.T) {
+	b := make([]byte, ListSize(ARM64, "WLAN_PROFILE_INFO_LIST", 2))
+	put32(b, 0, 2)
+	putString(b, 8, "corp")
+	put32(b, 8+512, 1)
+	putString(b, 8+516, "café")
+	put32(b, 8+516+512, 2)
+
+	profiles, err := DecodeProfileInfoList(ARM64, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(profiles) != 2 || profiles[0] != (ProfileInfo{"corp", 1}) || profiles[1] != (ProfileInfo{"café", 2}) {
+		t.Errorf("decoded %+v", profiles)
+	}
+	if _, err := DecodeProfileInfoList(ARM64, b[:len(b)-4]); err == nil {
+		t.Error("decoded a truncated list")
+	}
+}
+
+func TestDecodeConnectionAttributes(t *testing.T) {
+	b := make([]byte, LayoutOf(AMD64, "WLAN_CONNECTION_ATTRIBUTES").Size)
+	put32(b, 0, 1)
+	put32(b, 4, 0)
+	putString(b, 8, "corp")
+	putSSID(b, 520, "corp")
+	put32(b, 520+36, 1)
+	copy(b[520+40:], []byte{0, 0x1a, 0x1e, 0, 0, 1})
+	put32(b, 520+48, 8)
+	put32(b, 520+52, 1)
+	put32(b, 520+56, 90)
+	put32(b, 520+60, 866700)
+	put32(b, 520+64, 585000)
+	put32(b, 588, 1)
+	put32(b, 588+8, 7)
+	put32(b, 588+12, 4)
+
+	c, err := DecodeConnectionAttributes(AMD64, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.State != 1 || c.ProfileName != "corp" || string(c.SSID) != "corp" || c.BssType != 1 ||
+		c.BSSID != [6]byte{0, 0x1a, 0x1e, 0, 0, 1} || c.PhyType != 8 || c.PhyIndex != 1 || c.SignalQuality != 90 ||
+		c.RxRate != 866700 || c.TxRate != 585000 || !c.SecurityEnabled || c.OneXEnabled || c.AuthAlgorithm != 7 || c.CipherAlgorithm != 4 {
+		t.Errorf("decoded %+v", c)
+	}
+	if _, err := DecodeConnectionAttributes(AMD64, b[:600]); err == nil {
+		t.Error("decoded truncated attributes")
+	}
+}
+
+func TestDecodeStatistics(t *testing.T) {
+	b := make([]byte, ListSize(X86, "WLAN_STATISTICS", 2))
+	put64(b, 0, 3)
+	put64(b, 24, 1000)
+	put64(b, 120+8, 200)
+	put32(b, 216, 2)
+	put64(b, 224+24, 5)
+	put64(b, 224+144+136, 1<<40)
+
+	s, err := DecodeStatistics(X86, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.FourWayHandshakeFailures != 3 || s.MacUcastCounters.TransmittedFrameCount != 1000 ||
+		s.MacMcastCounters.ReceivedFrameCount != 200 {
+		t.Errorf("decoded %+v", s)
+	}
+	if len(s.PhyCounters) != 2 || s.PhyCounters[0].RetryCount != 5 || s.PhyCounters[1].FCSErrorCount != 1<<40 {
+		t.Errorf("decoded PHY counters %+v", s.PhyCounters)
+	}
+	if _, err := DecodeStatistics(X86, b[:len(b)-8]); err == nil {
+		t.Error("decoded truncated statistics")
+	}
+}
@@ -0,0 +1,546 @@
+package binary
+
+import (
+	"fmt"
+	"unicode/utf16"
+)
+
+//GUID has the layout of windows.GUID so both convert to each other.
+type GUID struct {
+	Data1 uint32
+	Data2 uint16
+	Data3 uint16
+	Data4 [8]byte
+}
+
+func (g GUID) String() string {
+	return fmt.Sprintf("{%08X-%04X-%04X-%02X%02X-%02X%02X%02X%02X%02X%02X}",
+		g.Data1, g.Data2, g.Data3, g.Data4[0], g.Data4[1],
+		g.Data4[2], g.Data4[3], g.Data4[4], g.Data4[5], g.Data4[6], g.Data4[7])
+}
+
+//InterfaceInfo is a decoded WLAN_INTERFACE_INFO.
+type InterfaceInfo struct {
+	InterfaceGuid GUID
+	Description   string
+	State         uint32
+}
+
+//AvailableNetwork is a decoded WLAN_AVAILABLE_NETWORK.
+type AvailableNetwork struct {
+	ProfileName            string
+	SSID                   []byte
+	BssType                uint32
+	NumberOfBssids         uint32
+	NetworkConnectable     bool
+	NotConnectableReason   uint32
+	PhyTypes               []uint32
+	MorePhyTypes           bool
+	SignalQuality          uint32
+	SecurityEnabled        bool
+	DefaultAuthAlgorithm   uint32
+	DefaultCipherAlgorithm uint32
+	Flags                  uint32
+}
+
+//BSSEntry is a decoded WLAN_BSS_ENTRY. IEs holds the information elements found at ulIeOffset.
+type BSSEntry struct {
+	SSID                  []byte
+	PhyID                 uint32
+	BSSID                 [6]byte
+	BssType               uint32
+	PhyType               uint32
+	RSSI                  int32
+	LinkQuality           uint32
+	InRegDomain           bool
+	BeaconPeriod          uint16
+	Timestamp             uint64
+	HostTimestamp         uint64
+	CapabilityInformation uint16
+	ChCenterFrequency     uint32
+	Rates                 []uint16
+	IEs                   []byte
+}
+
+//Network is a decoded DOT11_NETWORK.
+type Network struct {
+	SSID    []byte
+	BssType uint32
+}
+
+//HostedNetworkPeer is a decoded WLAN_HOSTED_NETWORK_PEER_STATE.
+type HostedNetworkPeer struct {
+	MacAddress [6]byte
+	AuthState  uint32
+}
+
+//HostedNetworkStatus is a decoded WLAN_HOSTED_NETWORK_STATUS.
+type HostedNetworkStatus struct {
+	State            uint32
+	IPDeviceID       GUID
+	BSSID            [6]byte
+	PhyType          uint32
+	ChannelFrequency uint32
+	Peers            []HostedNetworkPeer
+}
+
+//ProfileInfo is a decoded WLAN_PROFILE_INFO.
+type ProfileInfo struct {
+	Name  string
+	Flags uint32
+}
+
+//ConnectionAttributes is a decoded WLAN_CONNECTION_ATTRIBUTES.
+type ConnectionAttributes struct {
+	State           uint32
+	Mode            uint32
+	ProfileName     string
+	SSID            []byte
+	BssType         uint32
+	BSSID           [6]byte
+	PhyType         uint32
+	PhyIndex        uint32
+	SignalQuality   uint32
+	RxRate          uint32
+	TxRate          uint32
+	SecurityEnabled bool
+	OneXEnabled     bool
+	AuthAlgorithm   uint32
+	CipherAlgorithm uint32
+}
+
+//MacFrameStatistics is a decoded WLAN_MAC_FRAME_STATISTICS.
+type MacFrameStatistics struct {
+	TransmittedFrameCount uint64
+	ReceivedFrameCount    uint64
+	WEPExcludedCount      uint64
+	TKIPLocalMICFailures  uint64
+	TKIPReplays           uint64
+	TKIPICVErrorCount     uint64
+	CCMPReplays           uint64
+	CCMPDecryptErrors     uint64
+	WEPUndecryptableCount uint64
+	WEPICVErrorCount      uint64
+	DecryptSuccessCount   uint64
+	DecryptFailureCount   uint64
+}
+
+//PhyFrameStatistics is a decoded WLAN_PHY_FRAME_STATISTICS.
+type PhyFrameStatistics struct {
+	TransmittedFrameCount            uint64
+	MulticastTransmittedFrameCount   uint64
+	FailedCount                      uint64
+	RetryCount                       uint64
+	MultipleRetryCount               uint64
+	MaxTXLifetimeExceededCount       uint64
+	TransmittedFragmentCount         uint64
+	RTSSuccessCount                  uint64
+	RTSFailureCount                  uint64
+	ACKFailureCount                  uint64
+	ReceivedFrameCount               uint64
+	MulticastReceivedFrameCount      uint64
+	PromiscuousReceivedFrameCount    uint64
+	MaxRXLifetimeExceededCount       uint64
+	FrameDuplicateCount              uint64
+	ReceivedFragmentCount            uint64
+	PromiscuousReceivedFragmentCount uint64
+	FCSErrorCount                    uint64
+}
+
+//Statistics is a decoded WLAN_STATISTICS. PhyCounters has an element for each PHY of the interface,
+//indexed like the uDot11PhyIndex of the connection.
+type Statistics struct {
+	FourWayHandshakeFailures   uint64
+	TKIPCounterMeasuresInvoked uint64
+	MacUcastCounters           MacFrameStatistics
+	MacMcastCounters           MacFrameStatistics
+	PhyCounters                []PhyFrameStatistics
+}
+
+//decoder reads the fields of one structure at base in b.
+type decoder struct {
+	b      []byte
+	base   int
+	layout *Layout
+}
+
+func (d decoder) field(name string) []byte {
+	f := d.layout.Field(name)
+	off := d.base + f.Offset
+	return d.b[off : off+f.Size*f.Count]
+}
+
+func (d decoder) nested(name string, layout *Layout) decoder {
+	return decoder{b: d.b, base: d.base + d.layout.Offset(name), layout: layout}
+}
+
+func (d decoder) u8(name string) uint8 {
+	return d.field(name)[0]
+}
+
+func (d decoder) u16(name string) uint16 {
+	return le16(d.field(name))
+}
+
+func (d decoder) u32(name string) uint32 {
+	return le32(d.field(name))
+}
+
+func (d decoder) u64(name string) uint64 {
+	b := d.field(name)
+	return uint64(le32(b)) | uint64(le32(b[4:]))<<32
+}
+
+func (d decoder) bool(name string) bool {
+	return d.u32(name) != 0
+}
+
+func (d decoder) guid(name string, abi ABI) GUID {
+	g := d.nested(name, LayoutOf(abi, "GUID"))
+	var guid GUID
+	guid.Data1 = g.u32("Data1")
+	guid.Data2 = g.u16("Data2")
+	guid.Data3 = g.u16("Data3")
+	copy(guid.Data4[:], g.field("Data4"))
+	return guid
+}
+
+func (d decoder) ssid(name string, abi ABI) []byte {
+	s := d.nested(name, LayoutOf(abi, "DOT11_SSID"))
+	n := s.u32("uSSIDLength")
+	if n > 32 {
+		n = 32
+	}
+	return append([]byte{}, s.field("ucSSID")[:n]...)
+}
+
+func (d decoder) wstring(name string) string {
+	b := d.field(name)
+	u := make([]uint16, 0, len(b)/2)
+	for i := 0; i+1 < len(b); i += 2 {
+		c := le16(b[i:])
+		if c == 0 {
+			break
+		}
+		u = append(u, c)
+	}
+	return string(utf16.Decode(u))
+}
+
+func le16(b []byte) uint16 {
+	return uint16(b[0]) | uint16(b[1])<<8
+}
+
+func le32(b []byte) uint32 {
+	return uint32(b[0]) | uint32(b[1])<<8 | uint32(b[2])<<16 | uint32(b[3])<<24
+}
+
+//list checks that b holds the list structure name with count elements and returns
+//a decoder for each element.
+func list(abi ABI, b []byte, name, items string, count uint32) ([]decoder, error) {
+	l := LayoutOf(abi, name)
+	f := l.Field(items)
+	if uint64(count) > uint64(len(b)) {
+		return nil, fmt.Errorf("binary: %s has %d items in %d bytes", name, count, len(b))
+	}
+	if need := ListSize(abi, name, int(count)); len(b) < need {
+		return nil, fmt.Errorf("binary: %s with %d items needs %d bytes, got %d", name, count, need, len(b))
+	}
+	item := LayoutOf(abi, itemStruct[name])
+	elements := make([]decoder, count)
+	for i := range elements {
+		elements[i] = decoder{b: b, base: f.Offset + f.Size*i, layout: item}
+	}
+	return elements, nil
+}
+
+//itemStruct maps a list structure to the structure of its elements.
+var itemStruct = map[string]string{
+	"WLAN_INTERFACE_INFO_LIST":    "WLAN_INTERFACE_INFO",
+	"WLAN_AVAILABLE_NETWORK_LIST": "WLAN_AVAILABLE_NETWORK",
+	"WLAN_BSS_LIST":               "WLAN_BSS_ENTRY",
+	"DOT11_NETWORK_LIST":          "DOT11_NETWORK",
+	"WLAN_HOSTED_NETWORK_STATUS":  "WLAN_HOSTED_NETWORK_PEER_STATE",
+	"WLAN_PROFILE_INFO_LIST":      "WLAN_PROFILE_INFO",
+	"WLAN_STATISTICS":             "WLAN_PHY_FRAME_STATISTICS",
+}
+
+//header returns a decoder for the fixed part of a list structure, if b is large enough for it.
+func header(abi ABI, b []byte, name string) (decoder, error) {
+	l := LayoutOf(abi, name)
+	if len(b) < ListSize(abi, name, 0) {
+		return decoder{}, fmt.Errorf("binary: %s needs at least %d bytes, got %d", name, ListSize(abi, name, 0), len(b))
+	}
+	return decoder{b: b, layout: l}, nil
+}
+
+//DecodeInterfaceInfoList decodes a WLAN_INTERFACE_INFO_LIST.
+func DecodeInterfaceInfoList(abi ABI, b []byte) ([]InterfaceInfo, error) {
+	h, err := header(abi, b, "WLAN_INTERFACE_INFO_LIST")
+	if err != nil {
+		return nil, err
+	}
+	elements, err := list(abi, b, "WLAN_INTERFACE_INFO_LIST", "InterfaceInfo", h.u32("dwNumberOfItems"))
+	if err != nil {
+		return nil, err
+	}
+	infos := make([]InterfaceInfo, len(elements))
+	for i, e := range elements {
+		infos[i] = InterfaceInfo{
+			InterfaceGuid: e.guid("InterfaceGuid", abi),
+			Description:   e.wstring("strInterfaceDescription"),
+			State:         e.u32("isState"),
+		}
+	}
+	return infos, nil
+}
+
+//DecodeAvailableNetworkList decodes a WLAN_AVAILABLE_NETWORK_LIST.
+func DecodeAvailableNetworkList(abi ABI, b []byte) ([]AvailableNetwork, error) {
+	h, err := header(abi, b, "WLAN_AVAILABLE_NETWORK_LIST")
+	if err != nil {
+		return nil, err
+	}
+	elements, err := list(abi, b, "WLAN_AVAILABLE_NETWORK_LIST", "Network", h.u32("dwNumberOfItems"))
+	if err != nil {
+		return nil, err
+	}
+	networks := make([]AvailableNetwork, len(elements))
+	for i, e := range elements {
+		n := AvailableNetwork{
+			ProfileName:            e.wstring("strProfileName"),
+			SSID:                   e.ssid("dot11Ssid", abi),
+			BssType:                e.u32("dot11BssType"),
+			NumberOfBssids:         e.u32("uNumberOfBssids"),
+			NetworkConnectable:     e.bool("bNetworkConnectable"),
+			NotConnectableReason:   e.u32("wlanNotConnectableReason"),
+			MorePhyTypes:           e.bool("bMorePhyTypes"),
+			SignalQuality:          e.u32("wlanSignalQuality"),
+			SecurityEnabled:        e.bool("bSecurityEnabled"),
+			DefaultAuthAlgorithm:   e.u32("dot11DefaultAuthAlgorithm"),
+			DefaultCipherAlgorithm: e.u32("dot11DefaultCipherAlgorithm"),
+			Flags:                  e.u32("dwFlags"),
+		}
+		phys := e.field("dot11PhyTypes")
+		count := int(e.u32("uNumberOfPhyTypes"))
+		if count > len(phys)/4 {
+			count = len(phys) / 4
+		}
+		n.PhyTypes = make([]uint32, count)
+		for j := range n.PhyTypes {
+			n.PhyTypes[j] = le32(phys[4*j:])
+		}
+		networks[i] = n
+	}
+	return networks, nil
+}
+
+//DecodeBSSList decodes a WLAN_BSS_LIST. b must hold dwTotalSize bytes, since the
+//information elements of each entry are stored after the entries.
+func DecodeBSSList(abi ABI, b []byte) ([]BSSEntry, error) {
+	h, err := header(abi, b, "WLAN_BSS_LIST")
+	if err != nil {
+		return nil, err
+	}
+	total := h.u32("dwTotalSize")
+	if uint64(total) > uint64(len(b)) {
+		return nil, fmt.Errorf("binary: WLAN_BSS_LIST dwTotalSize %d exceeds %d bytes", total, len(b))
+	}
+	b = b[:total]
+	elements, err := list(abi, b, "WLAN_BSS_LIST", "wlanBssEntries", h.u32("dwNumberOfItems"))
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]BSSEntry, len(elements))
+	for i, e := range elements {
+		entry := BSSEntry{
+			SSID:                  e.ssid("dot11Ssid", abi),
+			PhyID:                 e.u32("uPhyId"),
+			BssType:               e.u32("dot11BssType"),
+			PhyType:               e.u32("dot11BssPhyType"),
+			RSSI:                  int32(e.u32("lRssi")),
+			LinkQuality:           e.u32("uLinkQuality"),
+			InRegDomain:           e.u8("bInRegDomain") != 0,
+			BeaconPeriod:          e.u16("usBeaconPeriod"),
+			Timestamp:             e.u64("ullTimestamp"),
+			HostTimestamp:         e.u64("ullHostTimestamp"),
+			CapabilityInformation: e.u16("usCapabilityInformation"),
+			ChCenterFrequency:     e.u32("ulChCenterFrequency"),
+		}
+		copy(entry.BSSID[:], e.field("dot11Bssid"))
+
+		rates := e.nested("wlanRateSet", LayoutOf(abi, "WLAN_RATE_SET"))
+		rateBytes := rates.field("usRateSet")
+		n := int(rates.u32("uRateSetLength")) / 2
+		if n > len(rateBytes)/2 {
+			n = len(rateBytes) / 2
+		}
+		entry.Rates = make([]uint16, n)
+		for j := range entry.Rates {
+			entry.Rates[j] = le16(rateBytes[2*j:])
+		}
+
+		start := uint64(e.base) + uint64(e.u32("ulIeOffset"))
+		end := start + uint64(e.u32("ulIeSize"))
+		if end > uint64(len(b)) {
+			return nil, fmt.Errorf("binary: WLAN_BSS_ENTRY %d IEs at %d..%d exceed dwTotalSize %d", i, start, end, total)
+		}
+		entry.IEs = append([]byte{}, b[start:end]...)
+		entries[i] = entry
+	}
+	return entries, nil
+}
+
+//DecodeNetworkList decodes a DOT11_NETWORK_LIST.
+func DecodeNetworkList(abi ABI, b []byte) ([]Network, error) {
+	h, err := header(abi, b, "DOT11_NETWORK_LIST")
+	if err != nil {
+		return nil, err
+	}
+	elements, err := list(abi, b, "DOT11_NETWORK_LIST", "Network", h.u32("dwNumberOfItems"))
+	if err != nil {
+		return nil, err
+	}
+	networks := make([]Network, len(elements))
+	for i, e := range elements {
+		networks[i] = Network{
+			SSID:    e.ssid("dot11Ssid", abi),
+			BssType: e.u32("dot11BssType"),
+		}
+	}
+	return networks, nil
+}
+
+//DecodeHostedNetworkStatus decodes a WLAN_HOSTED_NETWORK_STATUS.
+func DecodeHostedNetworkStatus(abi ABI, b []byte) (*HostedNetworkStatus, error) {
+	h, err := header(abi, b, "WLAN_HOSTED_NETWORK_STATUS")
+	if err != nil {
+		return nil, err
+	}
+	elements, err := list(abi, b, "WLAN_HOSTED_NETWORK_STATUS", "PeerList", h.u32("dwNumberOfPeers"))
+	if err != nil {
+		return nil, err
+	}
+	status := &HostedNetworkStatus{
+		State:            h.u32("HostedNetworkState"),
+		IPDeviceID:       h.guid("IPDeviceID", abi),
+		PhyType:          h.u32("dot11PhyType"),
+		ChannelFrequency: h.u32("ulChannelFrequency"),
+		Peers:            make([]HostedNetworkPeer, len(elements)),
+	}
+	copy(status.BSSID[:], h.field("wlanHostedNetworkBSSID"))
+	for i, e := range elements {
+		copy(status.Peers[i].MacAddress[:], e.field("PeerMacAddress"))
+		status.Peers[i].AuthState = e.u32("PeerAuthState")
+	}
+	return status, nil
+}
+
+//DecodeProfileInfoList decodes a WLAN_PROFILE_INFO_LIST.
+func DecodeProfileInfoList(abi ABI, b []byte) ([]ProfileInfo, error) {
+	h, err := header(abi, b, "WLAN_PROFILE_INFO_LIST")
+	if err != nil {
+		return nil, err
+	}
+	elements, err := list(abi, b, "WLAN_PROFILE_INFO_LIST", "ProfileInfo", h.u32("dwNumberOfItems"))
+	if err != nil {
+		return nil, err
+	}
+	profiles := make([]ProfileInfo, len(elements))
+	for i, e := range elements {
+		profiles[i] = ProfileInfo{
+			Name:  e.wstring("strProfileName"),
+			Flags: e.u32("dwFlags"),
+		}
+	}
+	return profiles, nil
+}
+
+//DecodeConnectionAttributes decodes a WLAN_CONNECTION_ATTRIBUTES.
+func DecodeConnectionAttributes(abi ABI, b []byte) (*ConnectionAttributes, error) {
+	l := LayoutOf(abi, "WLAN_CONNECTION_ATTRIBUTES")
+	if len(b) < l.Size {
+		return nil, fmt.Errorf("binary: WLAN_CONNECTION_ATTRIBUTES needs %d bytes, got %d", l.Size, len(b))
+	}
+	d := decoder{b: b, layout: l}
+	a := d.nested("wlanAssociationAttributes", LayoutOf(abi, "WLAN_ASSOCIATION_ATTRIBUTES"))
+	s := d.nested("wlanSecurityAttributes", LayoutOf(abi, "WLAN_SECURITY_ATTRIBUTES"))
+	c := &ConnectionAttributes{
+		State:           d.u32("isState"),
+		Mode:            d.u32("wlanConnectionMode"),
+		ProfileName:     d.wstring("strProfileName"),
+		SSID:            a.ssid("dot11Ssid", abi),
+		BssType:         a.u32("dot11BssType"),
+		PhyType:         a.u32("dot11PhyType"),
+		PhyIndex:        a.u32("uDot11PhyIndex"),
+		SignalQuality:   a.u32("wlanSignalQuality"),
+		RxRate:          a.u32("ulRxRate"),
+		TxRate:          a.u32("ulTxRate"),
+		SecurityEnabled: s.bool("bSecurityEnabled"),
+		OneXEnabled:     s.bool("bOneXEnabled"),
+		AuthAlgorithm:   s.u32("dot11AuthAlgorithm"),
+		CipherAlgorithm: s.u32("dot11CipherAlgorithm"),
+	}
+	copy(c.BSSID[:], a.field("dot11Bssid"))
+	return c, nil
+}
+
+func decodeMacFrameStatistics(d decoder) MacFrameStatistics {
+	return MacFrameStatistics{
+		TransmittedFrameCount: d.u64("ullTransmittedFrameCount"),
+		ReceivedFrameCount:    d.u64("ullReceivedFrameCount"),
+		WEPExcludedCount:      d.u64("ullWEPExcludedCount"),
+		TKIPLocalMICFailures:  d.u64("ullTKIPLocalMICFailures"),
+		TKIPReplays:           d.u64("ullTKIPReplays"),
+		TKIPICVErrorCount:     d.u64("ullTKIPICVErrorCount"),
+		CCMPReplays:           d.u64("ullCCMPReplays"),
+		CCMPDecryptErrors:     d.u64("ullCCMPDecryptErrors"),
+		WEPUndecryptableCount: d.u64("ullWEPUndecryptableCount"),
+		WEPICVErrorCount:      d.u64("ullWEPICVErrorCount"),
+		DecryptSuccessCount:   d.u64("ullDecryptSuccessCount"),
+		DecryptFailureCount:   d.u64("ullDecryptFailureCount"),
+	}
+}
+
+//DecodeStatistics decodes a WLAN_STATISTICS.
+func DecodeStatistics(abi ABI, b []byte) (*Statistics, error) {
+	h, err := header(abi, b, "WLAN_STATISTICS")
+	if err != nil {
+		return nil, err
+	}
+	elements, err := list(abi, b, "WLAN_STATISTICS", "PhyCounters", h.u32("dwNumberOfPhys"))
+	if err != nil {
+		return nil, err
+	}
+	mac := LayoutOf(abi, "WLAN_MAC_FRAME_STATISTICS")
+	s := &Statistics{
+		FourWayHandshakeFailures:   h.u64("ullFourWayHandshakeFailures"),
+		TKIPCounterMeasuresInvoked: h.u64("ullTKIPCounterMeasuresInvoked"),
+		MacUcastCounters:           decodeMacFrameStatistics(h.nested("MacUcastCounters", mac)),
+		MacMcastCounters:           decodeMacFrameStatistics(h.nested("MacMcastCounters", mac)),
+		PhyCounters:                make([]PhyFrameStatistics, len(elements)),
+	}
+	for i, e := range elements {
+		s.PhyCounters[i] = PhyFrameStatistics{
+			TransmittedFrameCount:            e.u64("ullTransmittedFrameCount"),
+			MulticastTransmittedFrameCount:   e.u64("ullMulticastTransmittedFrameCount"),
+			FailedCount:                      e.u64("ullFailedCount"),
+			RetryCount:                       e.u64("ullRetryCount"),
+			MultipleRetryCount:               e.u64("ullMultipleRetryCount"),
+			MaxTXLifetimeExceededCount:       e.u64("ullMaxTXLifetimeExceededCount"),
+			TransmittedFragmentCount:         e.u64("ullTransmittedFragmentCount"),
+			RTSSuccessCount:                  e.u64("ullRTSSuccessCount"),
+			RTSFailureCount:                  e.u64("ullRTSFailureCount"),
+			ACKFailureCount:                  e.u64("ullACKFailureCount"),
+			ReceivedFrameCount:               e.u64("ullReceivedFrameCount"),
+			MulticastReceivedFrameCount:      e.u64("ullMulticastReceivedFrameCount"),
+			PromiscuousReceivedFrameCount:    e.u64("ullPromiscuousReceivedFrameCount"),
+			MaxRXLifetimeExceededCount:       e.u64("ullMaxRXLifetimeExceededCount"),
+			FrameDuplicateCount:              e.u64("ullFrameDuplicateCount"),
+			ReceivedFragmentCount:            e.u64("ullReceivedFragmentCount"),
+			PromiscuousReceivedFragmentCount: e.u64("ullPromiscuousReceivedFragmentCount"),
+			FCSErrorCount:                    e.u64("ullFCSErrorCount"),
+		}
+	}
+	return s, nil
+}
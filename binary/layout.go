@@ -0,0 +1,353 @@
+//Package binary decodes Native Wifi structures from raw bytes.
+//
+//The list structures returned by wlanapi.dll end in a variable length array. Instead of casting
+//native memory to Go structs with fixed size arrays, the structures are copied into a []byte and
+//decoded field by field at the offsets of an explicit Layout for the Windows ABI that produced them.
+package binary
+
+import (
+	"fmt"
+	"runtime"
+)
+
+//ABI is a Windows data model whose alignment rules determine the structure layouts.
+//All of them align scalars to their size, including 8-byte scalars on x86; they differ in pointer size.
+type ABI int
+
+const (
+	X86 ABI = iota
+	AMD64
+	ARM64
+)
+
+//ABIs lists the supported ABIs.
+var ABIs = []ABI{X86, AMD64, ARM64}
+
+func (abi ABI) String() string {
+	switch abi {
+	case X86:
+		return "x86"
+	case AMD64:
+		return "amd64"
+	case ARM64:
+		return "arm64"
+	}
+	return fmt.Sprintf("ABI(%d)", int(abi))
+}
+
+//PointerSize returns the size of a pointer in bytes.
+func (abi ABI) PointerSize() int {
+	if abi == X86 {
+		return 4
+	}
+	return 8
+}
+
+//NativeABI returns the ABI of the running program.
+func NativeABI() ABI {
+	switch runtime.GOARCH {
+	case "386":
+		return X86
+	case "arm64":
+		return ARM64
+	}
+	return AMD64
+}
+
+//Field is a member of a Layout. Arrays have Count elements of Size bytes each.
+type Field struct {
+	Name   string
+	Offset int
+	Size   int
+	Count  int
+}
+
+//Layout is the memory layout of a structure.
+//For the list structures the last field is the first element of the variable length array,
+//as in the C declaration; the following elements are Field.Size bytes apart.
+type Layout struct {
+	Name   string
+	Size   int
+	Align  int
+	Fields []Field
+}
+
+//Field returns the named field. It panics if the structure has no such field.
+func (l *Layout) Field(name string) Field {
+	for _, f := range l.Fields {
+		if f.Name == name {
+			return f
+		}
+	}
+	panic("binary: " + l.Name + " has no field " + name)
+}
+
+//Offset returns the offset of the named field.
+func (l *Layout) Offset(name string) int {
+	return l.Field(name).Offset
+}
+
+//member describes a structure member before its offset is known.
+//A size of 0 is a pointer, whose size depends on the ABI.
+type member struct {
+	name   string
+	size   int
+	align  int
+	count  int
+	nested *Layout
+}
+
+func scalar(name string, size int) member {
+	return member{name: name, size: size, align: size, count: 1}
+}
+
+func array(name string, size, count int) member {
+	return member{name: name, size: size, align: size, count: count}
+}
+
+func pointer(name string) member {
+	return member{name: name, count: 1}
+}
+
+func nested(name string, l *Layout, count int) member {
+	return member{name: name, size: l.Size, align: l.Align, count: count, nested: l}
+}
+
+func alignUp(n, align int) int {
+	return (n + align - 1) / align * align
+}
+
+func newLayout(abi ABI, name string, members ...member) *Layout {
+	l := &Layout{Name: name, Align: 1}
+	offset := 0
+	for _, m := range members {
+		size, align := m.size, m.align
+		if size == 0 {
+			size, align = abi.PointerSize(), abi.PointerSize()
+		}
+		offset = alignUp(offset, align)
+		l.Fields = append(l.Fields, Field{Name: m.name, Offset: offset, Size: size, Count: m.count})
+		offset += size * m.count
+		if align > l.Align {
+			l.Align = align
+		}
+	}
+	l.Size = alignUp(offset, l.Align)
+	return l
+}
+
+//layouts holds the layouts of every ABI by structure name.
+var layouts = map[ABI]map[string]*Layout{}
+
+func init() {
+	for _, abi := range ABIs {
+		layouts[abi] = buildLayouts(abi)
+	}
+}
+
+func buildLayouts(abi ABI) map[string]*Layout {
+	m := map[string]*Layout{}
+	add := func(l *Layout) *Layout {
+		m[l.Name] = l
+		return l
+	}
+
+	guid := add(newLayout(abi, "GUID",
+		scalar("Data1", 4),
+		scalar("Data2", 2),
+		scalar("Data3", 2),
+		array("Data4", 1, 8),
+	))
+	ssid := add(newLayout(abi, "DOT11_SSID",
+		scalar("uSSIDLength", 4),
+		array("ucSSID", 1, 32),
+	))
+
+	interfaceInfo := add(newLayout(abi, "WLAN_INTERFACE_INFO",
+		nested("InterfaceGuid", guid, 1),
+		array("strInterfaceDescription", 2, 256),
+		scalar("isState", 4),
+	))
+	add(newLayout(abi, "WLAN_INTERFACE_INFO_LIST",
+		scalar("dwNumberOfItems", 4),
+		scalar("dwIndex", 4),
+		nested("InterfaceInfo", interfaceInfo, 1),
+	))
+
+	availableNetwork := add(newLayout(abi, "WLAN_AVAILABLE_NETWORK",
+		array("strProfileName", 2, 256),
+		nested("dot11Ssid", ssid, 1),
+		scalar("dot11BssType", 4),
+		scalar("uNumberOfBssids", 4),
+		scalar("bNetworkConnectable", 4),
+		scalar("wlanNotConnectableReason", 4),
+		scalar("uNumberOfPhyTypes", 4),
+		array("dot11PhyTypes", 4, 8),
+		scalar("bMorePhyTypes", 4),
+		scalar("wlanSignalQuality", 4),
+		scalar("bSecurityEnabled", 4),
+		scalar("dot11DefaultAuthAlgorithm", 4),
+		scalar("dot11DefaultCipherAlgorithm", 4),
+		scalar("dwFlags", 4),
+		scalar("dwReserved", 4),
+	))
+	add(newLayout(abi, "WLAN_AVAILABLE_NETWORK_LIST",
+		scalar("dwNumberOfItems", 4),
+		scalar("dwIndex", 4),
+		nested("Network", availableNetwork, 1),
+	))
+
+	rateSet := add(newLayout(abi, "WLAN_RATE_SET",
+		scalar("uRateSetLength", 4),
+		array("usRateSet", 2, 126),
+	))
+	bssEntry := add(newLayout(abi, "WLAN_BSS_ENTRY",
+		nested("dot11Ssid", ssid, 1),
+		scalar("uPhyId", 4),
+		array("dot11Bssid", 1, 6),
+		scalar("dot11BssType", 4),
+		scalar("dot11BssPhyType", 4),
+		scalar("lRssi", 4),
+		scalar("uLinkQuality", 4),
+		scalar("bInRegDomain", 1),
+		scalar("usBeaconPeriod", 2),
+		scalar("ullTimestamp", 8),
+		scalar("ullHostTimestamp", 8),
+		scalar("usCapabilityInformation", 2),
+		scalar("ulChCenterFrequency", 4),
+		nested("wlanRateSet", rateSet, 1),
+		scalar("ulIeOffset", 4),
+		scalar("ulIeSize", 4),
+	))
+	add(newLayout(abi, "WLAN_BSS_LIST",
+		scalar("dwTotalSize", 4),
+		scalar("dwNumberOfItems", 4),
+		nested("wlanBssEntries", bssEntry, 1),
+	))
+
+	network := add(newLayout(abi, "DOT11_NETWORK",
+		nested("dot11Ssid", ssid, 1),
+		scalar("dot11BssType", 4),
+	))
+	add(newLayout(abi, "DOT11_NETWORK_LIST",
+		scalar("dwNumberOfItems", 4),
+		scalar("dwIndex", 4),
+		nested("Network", network, 1),
+	))
+
+	peer := add(newLayout(abi, "WLAN_HOSTED_NETWORK_PEER_STATE",
+		array("PeerMacAddress", 1, 6),
+		scalar("PeerAuthState", 4),
+	))
+	add(newLayout(abi, "WLAN_HOSTED_NETWORK_STATUS",
+		scalar("HostedNetworkState", 4),
+		nested("IPDeviceID", guid, 1),
+		array("wlanHostedNetworkBSSID", 1, 6),
+		scalar("dot11PhyType", 4),
+		scalar("ulChannelFrequency", 4),
+		scalar("dwNumberOfPeers", 4),
+		nested("PeerList", peer, 1),
+	))
+
+	profileInfo := add(newLayout(abi, "WLAN_PROFILE_INFO",
+		array("strProfileName", 2, 256),
+		scalar("dwFlags", 4),
+	))
+	add(newLayout(abi, "WLAN_PROFILE_INFO_LIST",
+		scalar("dwNumberOfItems", 4),
+		scalar("dwIndex", 4),
+		nested("ProfileInfo", profileInfo, 1),
+	))
+
+	association := add(newLayout(abi, "WLAN_ASSOCIATION_ATTRIBUTES",
+		nested("dot11Ssid", ssid, 1),
+		scalar("dot11BssType", 4),
+		array("dot11Bssid", 1, 6),
+		scalar("dot11PhyType", 4),
+		scalar("uDot11PhyIndex", 4),
+		scalar("wlanSignalQuality", 4),
+		scalar("ulRxRate", 4),
+		scalar("ulTxRate", 4),
+	))
+	security := add(newLayout(abi, "WLAN_SECURITY_ATTRIBUTES",
+		scalar("bSecurityEnabled", 4),
+		scalar("bOneXEnabled", 4),
+		scalar("dot11AuthAlgorithm", 4),
+		scalar("dot11CipherAlgorithm", 4),
+	))
+	add(newLayout(abi, "WLAN_CONNECTION_ATTRIBUTES",
+		scalar("isState", 4),
+		scalar("wlanConnectionMode", 4),
+		array("strProfileName", 2, 256),
+		nested("wlanAssociationAttributes", association, 1),
+		nested("wlanSecurityAttributes", security, 1),
+	))
+
+	macFrameStatistics := add(newLayout(abi, "WLAN_MAC_FRAME_STATISTICS",
+		scalar("ullTransmittedFrameCount", 8),
+		scalar("ullReceivedFrameCount", 8),
+		scalar("ullWEPExcludedCount", 8),
+		scalar("ullTKIPLocalMICFailures", 8),
+		scalar("ullTKIPReplays", 8),
+		scalar("ullTKIPICVErrorCount", 8),
+		scalar("ullCCMPReplays", 8),
+		scalar("ullCCMPDecryptErrors", 8),
+		scalar("ullWEPUndecryptableCount", 8),
+		scalar("ullWEPICVErrorCount", 8),
+		scalar("ullDecryptSuccessCount", 8),
+		scalar("ullDecryptFailureCount", 8),
+	))
+	phyFrameStatistics := add(newLayout(abi, "WLAN_PHY_FRAME_STATISTICS",
+		scalar("ullTransmittedFrameCount", 8),
+		scalar("ullMulticastTransmittedFrameCount", 8),
+		scalar("ullFailedCount", 8),
+		scalar("ullRetryCount", 8),
+		scalar("ullMultipleRetryCount", 8),
+		scalar("ullMaxTXLifetimeExceededCount", 8),
+		scalar("ullTransmittedFragmentCount", 8),
+		scalar("ullRTSSuccessCount", 8),
+		scalar("ullRTSFailureCount", 8),
+		scalar("ullACKFailureCount", 8),
+		scalar("ullReceivedFrameCount", 8),
+		scalar("ullMulticastReceivedFrameCount", 8),
+		scalar("ullPromiscuousReceivedFrameCount", 8),
+		scalar("ullMaxRXLifetimeExceededCount", 8),
+		scalar("ullFrameDuplicateCount", 8),
+		scalar("ullReceivedFragmentCount", 8),
+		scalar("ullPromiscuousReceivedFragmentCount", 8),
+		scalar("ullFCSErrorCount", 8),
+	))
+	add(newLayout(abi, "WLAN_STATISTICS",
+		scalar("ullFourWayHandshakeFailures", 8),
+		scalar("ullTKIPCounterMeasuresInvoked", 8),
+		scalar("ullReserved", 8),
+		nested("MacUcastCounters", macFrameStatistics, 1),
+		nested("MacMcastCounters", macFrameStatistics, 1),
+		scalar("dwNumberOfPhys", 4),
+		nested("PhyCounters", phyFrameStatistics, 1),
+	))
+
+	add(newLayout(abi, "WLAN_NOTIFICATION_DATA",
+		scalar("NotificationSource", 4),
+		scalar("NotificationCode", 4),
+		nested("InterfaceGuid", guid, 1),
+		scalar("dwDataSize", 4),
+		pointer("pData"),
+	))
+	return m
+}
+
+//LayoutOf returns the layout of the named structure under abi, or nil if it is unknown.
+func LayoutOf(abi ABI, name string) *Layout {
+	return layouts[abi][name]
+}
+
+//ListSize returns the size in bytes of the list structure name with count elements.
+func ListSize(abi ABI, name string, count int) int {
+	l := LayoutOf(abi, name)
+	if l == nil {
+		panic("binary: unknown structure " + name)
+	}
+	items := l.Fields[len(l.Fields)-1]
+	return items.Offset + items.Size*count
+}
@@ -0,0 +1,43 @@
+//go:build windows
+// +build windows
+
+package wlanapi
+
+import (
+	"golang.org/x/sys/windows"
+)
+
+//growBuffer calls fn with an output buffer of initial bytes, and grows the buffer while fn
+//reports ERROR_MORE_DATA or ERROR_INSUFFICIENT_BUFFER, up to max bytes.
+//fn returns the number of bytes written, or the number of bytes required when the buffer is too small.
+func growBuffer(initial, max int, fn func(out []byte) (int, error)) ([]byte, error) {
+	size := initial
+	if size > max {
+		size = max
+	}
+	for {
+		out := make([]byte, size)
+		n, err := fn(out)
+		switch err {
+		case nil:
+			if n > len(out) {
+				n = len(out)
+			}
+			return out[:n], nil
+		case windows.ERROR_MORE_DATA, windows.ERROR_INSUFFICIENT_BUFFER:
+			if size >= max {
+				return nil, err
+			}
+			if n > size {
+				size = n
+			} else {
+				size *= 2
+			}
+			if size > max {
+				size = max
+			}
+		default:
+			return nil, err
+		}
+	}
+}
@@ -0,0 +1,144 @@
+//go:build windows
+// +build windows
+
+package wlanapi
+
+import (
+	"fmt"
+	"strings"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+//Capability describes what an interface supports, as reported by WlanGetInterfaceCapability.
+type Capability struct {
+	InterfaceType           WLAN_INTERFACE_TYPE
+	Dot11DSupported         bool
+	MaxDesiredSSIDListSize  uint32
+	MaxDesiredBSSIDListSize uint32
+	PhyTypes                []DOT11_PHY_TYPE
+}
+
+//Capability retrieves the capabilities of the interface.
+func (i *Interface) Capability() (*Capability, error) {
+	pc, err := WlanGetInterfaceCapability(i.client.handle, &i.GUID)
+	if err != nil {
+		return nil, err
+	}
+	defer WlanFreeMemory(PVOID(unsafe.Pointer(pc)))
+
+	n := pc.dwNumberOfSupportedPhys
+	if n > WLAN_MAX_PHY_INDEX {
+		n = WLAN_MAX_PHY_INDEX
+	}
+	c := &Capability{
+		InterfaceType:           pc.interfaceType,
+		Dot11DSupported:         pc.bDot11DSupported != FALSE,
+		MaxDesiredSSIDListSize:  uint32(pc.dwMaxDesiredSsidListSize),
+		MaxDesiredBSSIDListSize: uint32(pc.dwMaxDesiredBssidListSize),
+		PhyTypes:                make([]DOT11_PHY_TYPE, n),
+	}
+	copy(c.PhyTypes, pc.dot11PhyTypes[:n])
+	return c, nil
+}
+
+//AdapterReport combines the capability of an interface with the auth/cipher pairs and
+//country or region strings it supports.
+type AdapterReport struct {
+	Interface                     *Interface
+	Capability                    *Capability
+	InfrastructureAuthCipherPairs []DOT11_AUTH_CIPHER_PAIR
+	AdhocAuthCipherPairs          []DOT11_AUTH_CIPHER_PAIR
+	CountryOrRegionStrings        []string
+}
+
+//Report builds an AdapterReport for the interface.
+//Ad hoc pairs and country or region strings are left empty when the driver does not support querying them.
+func (i *Interface) Report() (*AdapterReport, error) {
+	c, err := i.Capability()
+	if err != nil {
+		return nil, err
+	}
+	infra, err := i.authCipherPairs(WlanIntfOpcodeSupportedInfrastructureAuthCipherPairs)
+	if err != nil {
+		return nil, err
+	}
+	adhoc, err := i.authCipherPairs(WlanIntfOpcodeSupportedAdhocAuthCipherPairs)
+	if err != nil && err != windows.ERROR_NOT_SUPPORTED {
+		return nil, err
+	}
+	countries, err := i.countryOrRegionStrings()
+	if err != nil && err != windows.ERROR_NOT_SUPPORTED {
+		return nil, err
+	}
+	return &AdapterReport{
+		Interface:                     i,
+		Capability:                    c,
+		InfrastructureAuthCipherPairs: infra,
+		AdhocAuthCipherPairs:          adhoc,
+		CountryOrRegionStrings:        countries,
+	}, nil
+}
+
+func (i *Interface) authCipherPairs(opCode WLAN_INTF_OPCODE) ([]DOT11_AUTH_CIPHER_PAIR, error) {
+	_, ppData, _, err := WlanQueryInterface(i.client.handle, &i.GUID, opCode)
+	if err != nil {
+		return nil, err
+	}
+	defer WlanFreeMemory(PVOID(unsafe.Pointer(ppData)))
+
+	list := (*WLAN_AUTH_CIPHER_PAIR_LIST)(unsafe.Pointer(ppData))
+	pairs := make([]DOT11_AUTH_CIPHER_PAIR, list.dwNumberOfItems)
+	if len(pairs) > 0 {
+		copy(pairs, unsafe.Slice(&list.pAuthCipherPairList[0], len(pairs)))
+	}
+	return pairs, nil
+}
+
+func (i *Interface) countryOrRegionStrings() ([]string, error) {
+	_, ppData, _, err := WlanQueryInterface(i.client.handle, &i.GUID, WlanIntfOpcodeSupportedCountryOrRegionStringList)
+	if err != nil {
+		return nil, err
+	}
+	defer WlanFreeMemory(PVOID(unsafe.Pointer(ppData)))
+
+	list := (*WLAN_COUNTRY_OR_REGION_STRING_LIST)(unsafe.Pointer(ppData))
+	countries := make([]string, 0, list.dwNumberOfItems)
+	if list.dwNumberOfItems > 0 {
+		for _, s := range unsafe.Slice(&list.pCountryOrRegionStringList[0], list.dwNumberOfItems) {
+			countries = append(countries, strings.TrimRight(string(s[:]), " \x00"))
+		}
+	}
+	return countries, nil
+}
+
+func (r *AdapterReport) String() string {
+	var b strings.Builder
+	if r.Interface != nil {
+		fmt.Fprintf(&b, "Interface:        %s\n", r.Interface.Description)
+		fmt.Fprintf(&b, "GUID:             %s\n", r.Interface.GUID.String())
+		fmt.Fprintf(&b, "State:            %s\n", r.Interface.State)
+	}
+	if c := r.Capability; c != nil {
+		fmt.Fprintf(&b, "Type:             %s\n", c.InterfaceType)
+		fmt.Fprintf(&b, "802.11d:          %t\n", c.Dot11DSupported)
+		fmt.Fprintf(&b, "Max SSID list:    %d\n", c.MaxDesiredSSIDListSize)
+		fmt.Fprintf(&b, "Max BSSID list:   %d\n", c.MaxDesiredBSSIDListSize)
+		phys := make([]string, len(c.PhyTypes))
+		for i, t := range c.PhyTypes {
+			phys[i] = t.String()
+		}
+		fmt.Fprintf(&b, "PHY types:        %s\n", strings.Join(phys, ", "))
+	}
+	writePairs := func(name string, pairs []DOT11_AUTH_CIPHER_PAIR) {
+		fmt.Fprintf(&b, "%s\n", name)
+		for _, p := range pairs {
+			fmt.Fprintf(&b, "    %-16s %s\n", p.AuthAlgoId, p.CipherAlgoId)
+		}
+	}
+	writePairs("Infrastructure auth/cipher pairs:", r.InfrastructureAuthCipherPairs)
+	writePairs("Ad hoc auth/cipher pairs:", r.AdhocAuthCipherPairs)
+	fmt.Fprintf(&b, "Country or region: %s\n", strings.Join(r.CountryOrRegionStrings, ", "))
+	return b.String()
+}
@@ -0,0 +1,250 @@
+//Package channel analyzes the occupancy of 802.11 channels from BSS lists and recommends the least congested ones.
+package channel
+
+import (
+	"fmt"
+	"sort"
+
+	"wlanapi"
+	"wlanapi/binary"
+	"wlanapi/ie"
+)
+
+//DefaultUtilization is the channel utilization assumed for BSSes that do not advertise a BSS Load element.
+const DefaultUtilization = 0.25
+
+//BSS is a BSS with the channels it occupies.
+type BSS struct {
+	BSSID [6]byte
+	SSID  []byte
+	RSSI  int32
+	Band  wlanapi.Band
+	ie.OperatingChannel
+	//Load is nil when the BSS does not advertise a BSS Load element.
+	Load *ie.BSSLoadElement
+}
+
+//NewBSS returns the channels and load of a BSS entry.
+func NewBSS(e binary.BSSEntry) BSS {
+	b := BSS{BSSID: e.BSSID, SSID: e.SSID, RSSI: e.RSSI, Band: wlanapi.BandOf(e.ChCenterFrequency)}
+	es, _ := ie.Parse(e.IEs)
+	b.OperatingChannel = es.OperatingChannel(wlanapi.ChannelOf(e.ChCenterFrequency), b.Band == wlanapi.Band6GHz)
+	if l, ok := es.Find(ie.BSSLoad); ok {
+		b.Load, _ = ie.ParseBSSLoad(l.Data)
+	}
+	return b
+}
+
+//weight scales the interference of a BSS with its signal: 1 at -50 dBm and above, falling linearly to 0 at -95 dBm.
+func (b *BSS) weight() float64 {
+	w := float64(b.RSSI+95) / 45
+	if w > 1 {
+		return 1
+	}
+	if w < 0 {
+		return 0
+	}
+	return w
+}
+
+//cost is the interference of the BSS on a channel it fully overlaps.
+func (b *BSS) cost() float64 {
+	u := DefaultUtilization
+	if b.Load != nil {
+		u = b.Load.Utilization()
+	}
+	return b.weight() * (1 + u)
+}
+
+//Channel is the occupancy of a 20 MHz channel.
+type Channel struct {
+	Band   wlanapi.Band
+	Number int
+	//Primary are the BSSes with this primary channel, Bonded those using it as a secondary channel,
+	//and Overlapping the 2.4 GHz BSSes on channels close enough to overlap it.
+	Primary     []*BSS
+	Bonded      []*BSS
+	Overlapping []*BSS
+	//Utilization is the highest channel utilization advertised by the BSSes of the channel, or -1 if none advertises it.
+	Utilization float64
+	//Stations is the number of stations associated to the BSSes of the channel that advertise it.
+	Stations int
+	//Score estimates the interference on the channel: lower is better, 0 is a free channel.
+	Score float64
+}
+
+func (c *Channel) String() string {
+	return fmt.Sprintf("%v channel %d", c.Band, c.Number)
+}
+
+//Candidate 20 MHz channels of each band.
+var channelNumbers = map[wlanapi.Band][]int{
+	wlanapi.Band2_4GHz: span(1, 13, 1),
+	wlanapi.Band5GHz:   append(append(span(36, 64, 4), span(100, 144, 4)...), span(149, 165, 4)...),
+	wlanapi.Band6GHz:   span(1, 233, 4),
+}
+
+func span(first, last, step int) []int {
+	var s []int
+	for n := first; n <= last; n += step {
+		s = append(s, n)
+	}
+	return s
+}
+
+//Report is the occupancy of the channels of the 2.4, 5 and 6 GHz bands.
+type Report struct {
+	BSSes []BSS
+	//Channels are ordered by band and number.
+	Channels []*Channel
+	index    map[wlanapi.Band]map[int]*Channel
+}
+
+//Analyze builds the occupancy of the channels from a BSS list.
+//BSSes outside the 2.4, 5 and 6 GHz bands are ignored.
+func Analyze(entries []binary.BSSEntry) *Report {
+	r := &Report{index: map[wlanapi.Band]map[int]*Channel{}}
+	for _, band := range []wlanapi.Band{wlanapi.Band2_4GHz, wlanapi.Band5GHz, wlanapi.Band6GHz} {
+		r.index[band] = map[int]*Channel{}
+		for _, n := range channelNumbers[band] {
+			c := &Channel{Band: band, Number: n, Utilization: -1}
+			r.Channels = append(r.Channels, c)
+			r.index[band][n] = c
+		}
+	}
+
+	for _, e := range entries {
+		if b := NewBSS(e); r.index[b.Band] != nil && b.Primary != 0 {
+			r.BSSes = append(r.BSSes, b)
+		}
+	}
+	for n := range r.BSSes {
+		b := &r.BSSes[n]
+		for _, number := range b.Channels() {
+			c := r.channel(b.Band, number)
+			if number == b.Primary {
+				c.Primary = append(c.Primary, b)
+			} else {
+				c.Bonded = append(c.Bonded, b)
+			}
+			c.Score += b.cost()
+			if b.Load != nil {
+				if u := b.Load.Utilization(); u > c.Utilization {
+					c.Utilization = u
+				}
+				if number == b.Primary {
+					c.Stations += int(b.Load.StationCount)
+				}
+			}
+			if b.Band != wlanapi.Band2_4GHz {
+				continue
+			}
+			//2.4 GHz channels are 5 MHz apart but 20 MHz wide, so a BSS overlaps the 4 channels on each side
+			//less and less.
+			for d := -4; d <= 4; d++ {
+				if o := r.index[b.Band][number+d]; o != nil && d != 0 && !contains(b.Channels(), number+d) {
+					if len(o.Overlapping) == 0 || o.Overlapping[len(o.Overlapping)-1] != b {
+						o.Overlapping = append(o.Overlapping, b)
+					}
+					o.Score += b.cost() * float64(5-abs(d)) / 5
+				}
+			}
+		}
+	}
+	return r
+}
+
+//channel returns the channel, adding it when the BSS uses a channel missing from the candidates.
+func (r *Report) channel(band wlanapi.Band, number int) *Channel {
+	c := r.index[band][number]
+	if c == nil {
+		c = &Channel{Band: band, Number: number, Utilization: -1}
+		r.index[band][number] = c
+		r.Channels = append(r.Channels, c)
+		sort.SliceStable(r.Channels, func(i, j int) bool {
+			a, b := r.Channels[i], r.Channels[j]
+			return a.Band < b.Band || a.Band == b.Band && a.Number < b.Number
+		})
+	}
+	return c
+}
+
+//Channel returns the occupancy of a 20 MHz channel, or nil if the report does not cover it.
+func (r *Report) Channel(band wlanapi.Band, number int) *Channel {
+	return r.index[band][number]
+}
+
+//Recommendation is a channel for a new BSS.
+type Recommendation struct {
+	Band wlanapi.Band
+	ie.OperatingChannel
+	//Score is the sum of the scores of the 20 MHz channels the BSS would occupy.
+	Score float64
+}
+
+func (r Recommendation) String() string {
+	return fmt.Sprintf("%v channel %d/%d MHz (score %.2f)", r.Band, r.Primary, r.Width, r.Score)
+}
+
+//Recommend returns the channels of a band for a new BSS of width MHz, from the least congested.
+//In 2.4 GHz only the non-overlapping channels 1, 6 and 11 are recommended for 20 MHz,
+//and 40 MHz BSSes are placed above channels 1 to 9.
+func (r *Report) Recommend(band wlanapi.Band, width int) []Recommendation {
+	var recommendations []Recommendation
+	add := func(o ie.OperatingChannel) {
+		rec := Recommendation{Band: band, OperatingChannel: o}
+		for _, n := range o.Channels() {
+			c := r.Channel(band, n)
+			if c == nil {
+				return
+			}
+			rec.Score += c.Score
+		}
+		recommendations = append(recommendations, rec)
+	}
+
+	switch {
+	case band == wlanapi.Band2_4GHz && width == 20:
+		for _, n := range []int{1, 6, 11} {
+			add(ie.OperatingChannel{Primary: n, Width: 20, Center: n})
+		}
+	case band == wlanapi.Band2_4GHz && width == 40:
+		for n := 1; n <= 9; n++ {
+			add(ie.OperatingChannel{Primary: n, Width: 40, Center: n + 2})
+		}
+	case band == wlanapi.Band5GHz || band == wlanapi.Band6GHz:
+		count := width / 20
+		if width%20 != 0 || count < 1 || count > 8 || count&(count-1) != 0 {
+			return nil
+		}
+		//Bonded channels are aligned on blocks of their width: 36-48 is an 80 MHz channel, 40-52 is not.
+		base := 36
+		if band == wlanapi.Band6GHz {
+			base = 1
+		}
+		for _, n := range channelNumbers[band] {
+			if (n-base)%(4*count) != 0 && !(band == wlanapi.Band5GHz && n >= 149 && (n-149)%(4*count) == 0) {
+				continue
+			}
+			add(ie.OperatingChannel{Primary: n, Width: width, Center: n + 2*(count-1)})
+		}
+	}
+	sort.SliceStable(recommendations, func(i, j int) bool { return recommendations[i].Score < recommendations[j].Score })
+	return recommendations
+}
+
+func contains(channels []int, n int) bool {
+	for _, c := range channels {
+		if c == n {
+			return true
+		}
+	}
+	return false
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
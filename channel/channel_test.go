@@ -0,0 +1,87 @@
+package channel
+
+import (
+	"testing"
+
+	"wlanapi"
+	"wlanapi/binary"
+)
+
+func ht40(primary, offset byte) []byte {
+	e := make([]byte, 24)
+	e[0], e[1], e[2], e[3] = 61, 22, primary, offset|0x04
+	return e
+}
+
+func bssLoad(stations uint16, utilization byte) []byte {
+	return []byte{11, 5, byte(stations), byte(stations >> 8), utilization, 0, 0}
+}
+
+func entry(id byte, mhz uint32, rssi int32, ies ...[]byte) binary.BSSEntry {
+	e := binary.BSSEntry{BSSID: [6]byte{2, 0, 0, 0, 0, id}, SSID: []byte{'a' + id}, RSSI: rssi, ChCenterFrequency: mhz * 1000}
+	for _, ie := range ies {
+		e.IEs = append(e.IEs, ie...)
+	}
+	return e
+}
+
+func TestAnalyze(t *testing.T) {
+	r := Analyze([]binary.BSSEntry{
+		entry(1, 2437, -45, bssLoad(20, 204)),
+		entry(2, 2437, -80),
+		entry(3, 2412, -50, ht40(1, 1), bssLoad(3, 51)),
+		entry(4, 5180, -60, ht40(36, 1), []byte{192, 5, 1, 42, 0, 0xfc, 0xff}),
+		entry(5, 5745, -95),
+		entry(6, 60480, -40),
+	})
+	if len(r.BSSes) != 5 {
+		t.Fatalf("%d BSSes, want 5 without the 60 GHz one", len(r.BSSes))
+	}
+
+	c6 := r.Channel(wlanapi.Band2_4GHz, 6)
+	if len(c6.Primary) != 2 || len(c6.Bonded) != 0 || c6.Stations != 20 || c6.Utilization != 0.8 {
+		t.Errorf("channel 6: %d primary, %d bonded, %d stations, utilization %v", len(c6.Primary), len(c6.Bonded), c6.Stations, c6.Utilization)
+	}
+	//The 40 MHz BSS on 1+5 overlaps channel 6 from channel 5, and channels 2 to 4 from both of its channels.
+	if len(c6.Overlapping) != 1 || c6.Overlapping[0].BSSID[5] != 3 {
+		t.Errorf("channel 6 overlapped by %d BSSes", len(c6.Overlapping))
+	}
+	c5 := r.Channel(wlanapi.Band2_4GHz, 5)
+	if len(c5.Bonded) != 1 || len(c5.Primary) != 0 || len(c5.Overlapping) != 2 {
+		t.Errorf("channel 5: %d primary, %d bonded, %d overlapping", len(c5.Primary), len(c5.Bonded), len(c5.Overlapping))
+	}
+	if c11 := r.Channel(wlanapi.Band2_4GHz, 11); c11.Score != 0 || c11.Utilization != -1 {
+		t.Errorf("channel 11 score %v, utilization %v", c11.Score, c11.Utilization)
+	}
+	for _, n := range []int{36, 40, 44, 48} {
+		if c := r.Channel(wlanapi.Band5GHz, n); c.Score == 0 || len(c.Primary)+len(c.Bonded) != 1 {
+			t.Errorf("channel %d is not occupied by the 80 MHz BSS", n)
+		}
+	}
+	if c := r.Channel(wlanapi.Band5GHz, 149); len(c.Primary) != 1 || c.Score != 0 {
+		t.Errorf("a BSS at -95 dBm scores %v", c.Score)
+	}
+
+	recommendations := r.Recommend(wlanapi.Band2_4GHz, 20)
+	if len(recommendations) != 3 || recommendations[0].Primary != 11 || recommendations[2].Primary != 6 {
+		t.Errorf("2.4 GHz recommendations %v", recommendations)
+	}
+	recommendations = r.Recommend(wlanapi.Band5GHz, 80)
+	if len(recommendations) != 6 || recommendations[0].Primary != 52 || recommendations[len(recommendations)-1].Primary != 36 {
+		t.Errorf("5 GHz 80 MHz recommendations %v", recommendations)
+	}
+	for _, rec := range recommendations {
+		if rec.Primary == 149 && rec.Center != 155 {
+			t.Errorf("149 centered on %d", rec.Center)
+		}
+	}
+	if n := len(r.Recommend(wlanapi.Band5GHz, 160)); n != 2 {
+		t.Errorf("%d 160 MHz channels, want 2", n)
+	}
+	if n := len(r.Recommend(wlanapi.Band6GHz, 20)); n != 59 {
+		t.Errorf("%d 6 GHz channels, want 59", n)
+	}
+	if n := len(r.Recommend(wlanapi.Band5GHz, 30)); n != 0 {
+		t.Errorf("%d 30 MHz channels", n)
+	}
+}
@@ -0,0 +1,92 @@
+package wlanapi
+
+import (
+	"sync"
+
+	"wlanapi/binary"
+)
+
+//DefaultParallelism is the number of interfaces a Client works on at the same time.
+const DefaultParallelism = 4
+
+//Client is a session with the WLAN AutoConfig service.
+//The methods that call wlanapi.dll directly, such as Subscribe and Capability, need a client opened by NewClient.
+type Client struct {
+	backend Backend
+
+	parallelismMu sync.Mutex
+	parallelism   int
+
+	clientSys
+}
+
+//NewClientWithBackend returns a Client using backend.
+func NewClientWithBackend(backend Backend) *Client {
+	return &Client{backend: backend, parallelism: DefaultParallelism}
+}
+
+//Backend returns the backend of the client.
+func (c *Client) Backend() Backend {
+	return c.backend
+}
+
+//SetParallelism sets the number of interfaces ForEachInterface works on at the same time.
+//Values below 1 select DefaultParallelism.
+func (c *Client) SetParallelism(n int) {
+	if n < 1 {
+		n = DefaultParallelism
+	}
+	c.parallelismMu.Lock()
+	c.parallelism = n
+	c.parallelismMu.Unlock()
+}
+
+//Close closes the backend and all notification channels.
+func (c *Client) Close() error {
+	err := c.backend.Close()
+	c.closeSys()
+	return err
+}
+
+//Interfaces enumerates the wireless LAN interfaces on the local computer.
+func (c *Client) Interfaces() ([]*Interface, error) {
+	infos, err := c.backend.Interfaces()
+	if err != nil {
+		return nil, err
+	}
+	interfaces := make([]*Interface, len(infos))
+	for i, info := range infos {
+		interfaces[i] = &Interface{
+			GUID:        GUID(info.InterfaceGuid),
+			Description: info.Description,
+			State:       WLAN_INTERFACE_STATE(info.State),
+			client:      c,
+		}
+	}
+	return interfaces, nil
+}
+
+//Interface is a wireless LAN interface returned by Client.Interfaces.
+type Interface struct {
+	GUID        GUID
+	Description string
+	State       WLAN_INTERFACE_STATE
+
+	client *Client
+}
+
+//Scan requests a scan for available networks on the interface.
+//The scan completes asynchronously; the results show up in AvailableNetworks and BSSList within a few seconds.
+func (i *Interface) Scan() error {
+	return i.client.backend.Scan(i.GUID)
+}
+
+//AvailableNetworks retrieves the networks visible to the interface.
+func (i *Interface) AvailableNetworks() ([]binary.AvailableNetwork, error) {
+	return i.client.backend.AvailableNetworks(i.GUID)
+}
+
+//BSSList retrieves the BSS entries visible to the interface.
+func (i *Interface) BSSList() ([]binary.BSSEntry, error) {
+	return i.client.backend.BSSList(i.GUID)
+}
@@ -0,0 +1,14 @@
+//go:build !windows
+// +build !windows
+
+package wlanapi
+
+//clientSys is empty where there is no wlanapi.dll session.
+type clientSys struct{}
+
+//NewClient returns ErrNotSupported; use NewClientWithBackend.
+func NewClient() (*Client, error) {
+	return nil, ErrNotSupported
+}
+
+func (c *Client) closeSys() {}
@@ -0,0 +1,62 @@
+//go:build windows
+// +build windows
+
+package wlanapi
+
+import (
+	"sync"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+//clientSys holds the wlanapi.dll session of a Client opened by NewClient.
+type clientSys struct {
+	handle windows.Handle
+
+	id            uintptr
+	registerMu    sync.Mutex
+	sources       DWORD
+	mu            sync.Mutex
+	subscriptions map[*subscription]struct{}
+	services      map[windows.GUID]int
+	//virtualStations counts the subscriptions of VirtualStationNotifications.
+	virtualStations int
+}
+
+//NewClient opens a new session with the WLAN AutoConfig service.
+func NewClient() (*Client, error) {
+	handle, err := WlanOpenHandle()
+	if err != nil {
+		return nil, err
+	}
+	backend := &nativeBackend{handle: handle}
+	c := NewClientWithBackend(backend)
+	c.handle = handle
+	backend.client = c
+	return c, nil
+}
+
+//Handle returns the client handle used for the raw Wlan* functions.
+//It is 0 for a client created by NewClientWithBackend.
+func (c *Client) Handle() windows.Handle {
+	return c.handle
+}
+
+//closeSys closes all notification channels after the backend is closed.
+func (c *Client) closeSys() {
+	c.closeSubscriptions()
+}
+
+//SetPSDIEList sets the proximity service discovery IE data list of l.Format.
+//A list without IEs clears the data list of the format.
+func (c *Client) SetPSDIEList(l *PSDIEList) error {
+	if len(l.IEs) == 0 {
+		return WlanSetPsdIEDataList(c.handle, l.Format, nil)
+	}
+	b, err := l.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	return WlanSetPsdIEDataList(c.handle, l.Format, (*WLAN_RAW_DATA_LIST)(unsafe.Pointer(&b[0])))
+}
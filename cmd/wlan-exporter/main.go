@@ -0,0 +1,99 @@
+//Command wlan-exporter serves the metrics of the wireless LAN interfaces to Prometheus: the state and
+//connection of each interface, the RSSI and link quality of the visible BSSes, the MAC and PHY frame counters
+//and the peers of the wireless Hosted Network.
+//
+//Usage:
+//
+//	wlan-exporter [flags]
+//
+//The flags are:
+//
+//	--listen address                   the address to listen on (default :9776)
+//	--path path                        the path of the metrics (default /metrics)
+//	--backend native|sim:fixture.json  the WLAN service, or a Sim loaded from a JSON fixture (default native)
+//	-i, --interface selector           the interfaces to export: all, connected, a GUID or part of a description
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+
+	"wlanapi"
+	"wlanapi/exporter"
+	"wlanapi/simfile"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stderr))
+}
+
+//config holds the flags.
+type config struct {
+	listen, path, backend, iface string
+}
+
+func run(args []string, stderr io.Writer) int {
+	fs := flag.NewFlagSet("wlan-exporter", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	var cfg config
+	fs.StringVar(&cfg.listen, "listen", ":9776", "`address` to listen on")
+	fs.StringVar(&cfg.path, "path", "/metrics", "`path` of the metrics")
+	fs.StringVar(&cfg.backend, "backend", "native", "`backend`: native, or sim:fixture.json")
+	fs.StringVar(&cfg.iface, "i", "", "interface `selector`: all, connected, a GUID or part of a description")
+	fs.StringVar(&cfg.iface, "interface", "", "interface `selector`: all, connected, a GUID or part of a description")
+	if err := fs.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return 0
+		}
+		return 2
+	}
+	if fs.NArg() > 0 {
+		fmt.Fprintf(stderr, "wlan-exporter: unexpected arguments %q\n", fs.Args())
+		return 2
+	}
+	handler, err := newHandler(cfg)
+	if err != nil {
+		fmt.Fprintf(stderr, "wlan-exporter: %v\n", err)
+		if _, ok := err.(usageError); ok {
+			return 2
+		}
+		return 1
+	}
+	if err := http.ListenAndServe(cfg.listen, handler); err != nil {
+		fmt.Fprintf(stderr, "wlan-exporter: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+//usageError is a flag error; wlan-exporter exits with 2 after it, and with 1 after other errors.
+type usageError string
+
+func (e usageError) Error() string {
+	return string(e)
+}
+
+//newHandler opens the backend and returns the handler serving its metrics at the path.
+func newHandler(cfg config) (http.Handler, error) {
+	sel, err := wlanapi.ParseInterfaceSelector(cfg.iface)
+	if err != nil {
+		return nil, usageError(err.Error())
+	}
+	c, err := simfile.Backend(cfg.backend)
+	var unknown simfile.UnknownBackendError
+	if errors.As(err, &unknown) {
+		return nil, usageError(err.Error())
+	}
+	if err != nil {
+		return nil, err
+	}
+	e := exporter.New(c)
+	e.Select = sel
+	mux := http.NewServeMux()
+	mux.Handle(cfg.path, e)
+	return mux, nil
+}
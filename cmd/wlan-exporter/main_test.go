@@ -0,0 +1,47 @@
+package main
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"wlanapi/simfile/simfiletest"
+)
+
+func TestHandler(t *testing.T) {
+	h, err := newHandler(config{path: "/metrics", backend: "sim:" + simfiletest.Path, iface: "realtek"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "/metrics", nil))
+	body := w.Body.String()
+	if w.Code != http.StatusOK || !strings.Contains(body, `state="disconnected"} 4`) || strings.Contains(body, "Intel") {
+		t.Errorf("metrics of the selected interface: %d\n%s", w.Code, body)
+	}
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("GET /: %d", w.Code)
+	}
+}
+
+func TestUsage(t *testing.T) {
+	for _, args := range [][]string{
+		{"--frobnicate"},
+		{"metrics"},
+		{"--backend", "dbus"},
+		{"-i", "{not-a-guid}"},
+	} {
+		var stderr bytes.Buffer
+		if code := run(args, &stderr); code != 2 || stderr.Len() == 0 {
+			t.Errorf("wlan-exporter %q: exit %d, %q", args, code, stderr.String())
+		}
+	}
+	var stderr bytes.Buffer
+	if code := run([]string{"--backend", "sim:missing.json"}, &stderr); code != 1 {
+		t.Errorf("missing fixture: exit %d, %q", code, stderr.String())
+	}
+}
@@ -0,0 +1,525 @@
+package main
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"wlanapi"
+	"wlanapi/binary"
+	"wlanapi/ie"
+)
+
+//defaultTimeout bounds how long scan and connect wait for their completion notifications.
+const defaultTimeout = 10 * time.Second
+
+type interfaceView struct {
+	GUID        string          `json:"guid"`
+	Description string          `json:"description"`
+	State       string          `json:"state"`
+	Connection  *connectionView `json:"connection,omitempty"`
+}
+
+type connectionView struct {
+	Mode            string  `json:"mode"`
+	Profile         string  `json:"profile"`
+	SSID            string  `json:"ssid"`
+	BSSID           string  `json:"bssid"`
+	BssType         string  `json:"bssType"`
+	PhyType         string  `json:"phyType"`
+	SignalQuality   uint32  `json:"signalQuality"`
+	RxRateMbps      float64 `json:"rxRateMbps"`
+	TxRateMbps      float64 `json:"txRateMbps"`
+	SecurityEnabled bool    `json:"securityEnabled"`
+	OneXEnabled     bool    `json:"oneXEnabled"`
+	AuthAlgorithm   string  `json:"authAlgorithm"`
+	CipherAlgorithm string  `json:"cipherAlgorithm"`
+}
+
+func newConnectionView(c *binary.ConnectionAttributes) *connectionView {
+	return &connectionView{
+		Mode:            wlanapi.WLAN_CONNECTION_MODE(c.Mode).String(),
+		Profile:         c.ProfileName,
+		SSID:            string(c.SSID),
+		BSSID:           net.HardwareAddr(c.BSSID[:]).String(),
+		BssType:         wlanapi.DOT11_BSS_TYPE(c.BssType).String(),
+		PhyType:         wlanapi.DOT11_PHY_TYPE(c.PhyType).String(),
+		SignalQuality:   c.SignalQuality,
+		RxRateMbps:      float64(c.RxRate) / 1000,
+		TxRateMbps:      float64(c.TxRate) / 1000,
+		SecurityEnabled: c.SecurityEnabled,
+		OneXEnabled:     c.OneXEnabled,
+		AuthAlgorithm:   wlanapi.DOT11_AUTH_ALGORITHM(c.AuthAlgorithm).String(),
+		CipherAlgorithm: wlanapi.DOT11_CIPHER_ALGORITHM(c.CipherAlgorithm).String(),
+	}
+}
+
+func (e *env) interfaces(args []string) error {
+	fs := e.flags("interfaces")
+	args, err := e.parse(fs, args, 0, 0)
+	if err != nil {
+		return err
+	}
+	_, interfaces, err := e.selected()
+	if err != nil {
+		return err
+	}
+	var failed wlanapi.InterfaceErrors
+	views := []interfaceView{}
+	v := view{header: []string{"GUID", "DESCRIPTION", "STATE", "SSID", "BSSID", "SIGNAL", "PROFILE"}}
+	for _, i := range interfaces {
+		iv := interfaceView{GUID: i.GUID.String(), Description: i.Description, State: i.State.String()}
+		row := []string{iv.GUID, iv.Description, iv.State, "", "", "", ""}
+		c, err := i.Connection()
+		switch {
+		case err == nil:
+			iv.Connection = newConnectionView(c)
+			row[3], row[4], row[5], row[6] = iv.Connection.SSID, iv.Connection.BSSID, fmt.Sprintf("%d%%", c.SignalQuality), c.ProfileName
+		case err != wlanapi.ErrNotConnected && err != wlanapi.ErrBackendNotSupported:
+			failed = append(failed, &wlanapi.InterfaceError{Interface: i, Err: err})
+		}
+		views = append(views, iv)
+		v.rows = append(v.rows, row)
+	}
+	v.value = views
+	return e.finish(v, failed)
+}
+
+//finish writes the view of a command and returns the errors of the interfaces it failed on.
+func (e *env) finish(v view, failed wlanapi.InterfaceErrors) error {
+	if err := e.write(v); err != nil {
+		return err
+	}
+	if len(failed) > 0 {
+		return failed
+	}
+	return nil
+}
+
+type resultView struct {
+	Interface string `json:"interface"`
+	Result    string `json:"result"`
+}
+
+//await waits up to timeout for the ACM notification that ends an operation on each pending interface,
+//and stores the result outcome returns for it. Interfaces without such a notification time out.
+func await(notifications <-chan wlanapi.Notification, timeout time.Duration, pending map[wlanapi.GUID]*string,
+	outcome func(wlanapi.Notification) (string, bool)) {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+wait:
+	for len(pending) > 0 {
+		select {
+		case n, ok := <-notifications:
+			if !ok {
+				break wait
+			}
+			if result := pending[n.InterfaceGuid]; result != nil {
+				if s, done := outcome(n); done {
+					*result = s
+					delete(pending, n.InterfaceGuid)
+				}
+			}
+		case <-timer.C:
+			break wait
+		}
+	}
+	for guid, result := range pending {
+		*result = "timed out"
+		delete(pending, guid)
+	}
+}
+
+//failure formats the outcome of a failed operation with the reason of its notification.
+func failure(n wlanapi.Notification) string {
+	if r := n.Reason(); r != wlanapi.WLAN_REASON_CODE_SUCCESS {
+		return "failed: " + r.String()
+	}
+	return "failed"
+}
+
+//results returns the view of the results of an operation on interfaces, with the errors of those that did not succeed.
+func results(interfaces []*wlanapi.Interface, outcomes []string, succeeded ...string) (view, wlanapi.InterfaceErrors) {
+	var failed wlanapi.InterfaceErrors
+	views := make([]resultView, len(interfaces))
+	v := view{header: []string{"INTERFACE", "RESULT"}}
+	for n, i := range interfaces {
+		views[n] = resultView{Interface: i.GUID.String(), Result: outcomes[n]}
+		v.rows = append(v.rows, []string{i.Description, outcomes[n]})
+		ok := false
+		for _, s := range succeeded {
+			ok = ok || outcomes[n] == s
+		}
+		if !ok && !strings.HasPrefix(outcomes[n], "error: ") {
+			failed = append(failed, &wlanapi.InterfaceError{Interface: i, Err: errors.New(outcomes[n])})
+		}
+	}
+	v.value = views
+	return v, failed
+}
+
+//subscribe subscribes to the notifications of the backend when wait is set.
+func subscribe(c *wlanapi.Client, wait bool) (<-chan wlanapi.Notification, func(), error) {
+	if !wait {
+		return nil, func() {}, nil
+	}
+	return c.Backend().Notifications()
+}
+
+func (e *env) scan(args []string) error {
+	fs := e.flags("scan")
+	wait := fs.Bool("wait", false, "wait for the scans to complete")
+	timeout := fs.Duration("timeout", defaultTimeout, "how long to wait for the scans")
+	args, err := e.parse(fs, args, 0, 0)
+	if err != nil {
+		return err
+	}
+	c, interfaces, err := e.selected()
+	if err != nil {
+		return err
+	}
+	notifications, cancel, err := subscribe(c, *wait)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+
+	outcomes := make([]string, len(interfaces))
+	pending := map[wlanapi.GUID]*string{}
+	var failed wlanapi.InterfaceErrors
+	for n, i := range interfaces {
+		if err := i.Scan(); err != nil {
+			outcomes[n] = "error: " + err.Error()
+			failed = append(failed, &wlanapi.InterfaceError{Interface: i, Err: err})
+			continue
+		}
+		outcomes[n] = "requested"
+		pending[i.GUID] = &outcomes[n]
+	}
+	if *wait {
+		await(notifications, *timeout, pending, func(n wlanapi.Notification) (string, bool) {
+			code, ok := n.ACM()
+			if !ok {
+				return "", false
+			}
+			switch code {
+			case wlanapi.WlanNotificationAcmScanComplete:
+				return "complete", true
+			case wlanapi.WlanNotificationAcmScanFail:
+				return failure(n), true
+			}
+			return "", false
+		})
+	}
+	v, unfinished := results(interfaces, outcomes, "requested", "complete")
+	return e.finish(v, append(failed, unfinished...))
+}
+
+type networkView struct {
+	Interface            string   `json:"interface"`
+	SSID                 string   `json:"ssid"`
+	Profile              string   `json:"profile,omitempty"`
+	BssType              string   `json:"bssType"`
+	NumberOfBssids       uint32   `json:"numberOfBssids"`
+	Connectable          bool     `json:"connectable"`
+	NotConnectableReason string   `json:"notConnectableReason,omitempty"`
+	PhyTypes             []string `json:"phyTypes"`
+	SignalQuality        uint32   `json:"signalQuality"`
+	SecurityEnabled      bool     `json:"securityEnabled"`
+	AuthAlgorithm        string   `json:"authAlgorithm"`
+	CipherAlgorithm      string   `json:"cipherAlgorithm"`
+	Connected            bool     `json:"connected"`
+}
+
+func (e *env) networks(args []string) error {
+	fs := e.flags("networks")
+	args, err := e.parse(fs, args, 0, 0)
+	if err != nil {
+		return err
+	}
+	c, interfaces, err := e.selected()
+	if err != nil {
+		return err
+	}
+	networks, err := c.NetworksAll(context.Background(), selector(interfaces))
+	failed, err := interfaceErrors(err)
+	if err != nil {
+		return err
+	}
+	views := []networkView{}
+	v := view{header: []string{"INTERFACE", "SSID", "SIGNAL", "AUTH", "CIPHER", "BSSIDS", "CONNECTABLE", "PROFILE", "CONNECTED"}}
+	for _, n := range networks {
+		nv := networkView{
+			Interface:       n.Interface.GUID.String(),
+			SSID:            string(n.Network.SSID),
+			Profile:         n.Network.ProfileName,
+			BssType:         wlanapi.DOT11_BSS_TYPE(n.Network.BssType).String(),
+			NumberOfBssids:  n.Network.NumberOfBssids,
+			Connectable:     n.Network.NetworkConnectable,
+			PhyTypes:        []string{},
+			SignalQuality:   n.Network.SignalQuality,
+			SecurityEnabled: n.Network.SecurityEnabled,
+			AuthAlgorithm:   wlanapi.DOT11_AUTH_ALGORITHM(n.Network.DefaultAuthAlgorithm).String(),
+			CipherAlgorithm: wlanapi.DOT11_CIPHER_ALGORITHM(n.Network.DefaultCipherAlgorithm).String(),
+			Connected:       n.Network.Flags&wlanapi.WLAN_AVAILABLE_NETWORK_CONNECTED != 0,
+		}
+		if !nv.Connectable {
+			nv.NotConnectableReason = wlanapi.WLAN_REASON_CODE(n.Network.NotConnectableReason).String()
+		}
+		for _, p := range n.Network.PhyTypes {
+			nv.PhyTypes = append(nv.PhyTypes, wlanapi.DOT11_PHY_TYPE(p).String())
+		}
+		views = append(views, nv)
+		v.rows = append(v.rows, []string{n.Interface.Description, nv.SSID, fmt.Sprintf("%d%%", nv.SignalQuality), nv.AuthAlgorithm,
+			nv.CipherAlgorithm, strconv.Itoa(int(nv.NumberOfBssids)), yesNo(nv.Connectable), nv.Profile, yesNo(nv.Connected)})
+	}
+	v.value = views
+	return e.finish(v, failed)
+}
+
+//selector selects the interfaces of a list.
+func selector(interfaces []*wlanapi.Interface) wlanapi.InterfaceSelector {
+	guids := map[wlanapi.GUID]bool{}
+	for _, i := range interfaces {
+		guids[i.GUID] = true
+	}
+	return func(i *wlanapi.Interface) bool { return guids[i.GUID] }
+}
+
+//interfaceErrors splits the error of a fan-out into the errors of some interfaces and an error of the whole operation.
+func interfaceErrors(err error) (wlanapi.InterfaceErrors, error) {
+	var failed wlanapi.InterfaceErrors
+	if errors.As(err, &failed) {
+		return failed, nil
+	}
+	return nil, err
+}
+
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
+
+type bssView struct {
+	Interface    string    `json:"interface"`
+	BSSID        string    `json:"bssid"`
+	Manufacturer string    `json:"manufacturer,omitempty"`
+	SSID         string    `json:"ssid"`
+	BssType      string    `json:"bssType"`
+	PhyType      string    `json:"phyType"`
+	RSSI         int32     `json:"rssi"`
+	LinkQuality  uint32    `json:"linkQuality"`
+	FrequencyKHz uint32    `json:"frequencyKHz"`
+	Band         string    `json:"band"`
+	Channel      int       `json:"channel"`
+	Security     string    `json:"security"`
+	BeaconPeriod uint16    `json:"beaconPeriod"`
+	RatesMbps    []float64 `json:"ratesMbps"`
+	IEs          []ieView  `json:"ies,omitempty"`
+}
+
+type ieView struct {
+	ID     uint8  `json:"id"`
+	Name   string `json:"name"`
+	Length int    `json:"length"`
+	Data   string `json:"data"`
+}
+
+//ieName names an element, with the decoder name or the OUI and type of vendor specific elements.
+func ieName(el ie.Element) string {
+	if oui, t, ok := el.Vendor(); ok {
+		if d, ok := ie.LookupVendorDecoder(oui, t); ok {
+			return fmt.Sprintf("%v %s", el.ID, d.Name())
+		}
+		return fmt.Sprintf("%v %v/%d", el.ID, oui, t)
+	}
+	return el.ID.String()
+}
+
+func (e *env) bss(args []string) error {
+	fs := e.flags("bss")
+	withIEs := fs.Bool("ie", false, "decode the information elements")
+	args, err := e.parse(fs, args, 0, 0)
+	if err != nil {
+		return err
+	}
+	c, interfaces, err := e.selected()
+	if err != nil {
+		return err
+	}
+	entries, err := c.BSSesAll(context.Background(), selector(interfaces))
+	failed, err := interfaceErrors(err)
+	if err != nil {
+		return err
+	}
+	views := []bssView{}
+	v := view{header: []string{"INTERFACE", "BSSID", "MANUFACTURER", "SSID", "RSSI", "QUALITY", "BAND", "CHANNEL", "PHY", "SECURITY"}}
+	if *withIEs {
+		v.header = append(v.header, "IES")
+	}
+	for _, b := range entries {
+		entry := b.BSS
+		bv := bssView{
+			Interface:    b.Interface.GUID.String(),
+			BSSID:        net.HardwareAddr(entry.BSSID[:]).String(),
+			SSID:         string(entry.SSID),
+			BssType:      wlanapi.DOT11_BSS_TYPE(entry.BssType).String(),
+			PhyType:      wlanapi.DOT11_PHY_TYPE(entry.PhyType).String(),
+			RSSI:         entry.RSSI,
+			LinkQuality:  entry.LinkQuality,
+			FrequencyKHz: entry.ChCenterFrequency,
+			Band:         wlanapi.BandOf(entry.ChCenterFrequency).String(),
+			Channel:      wlanapi.ChannelOf(entry.ChCenterFrequency),
+			BeaconPeriod: entry.BeaconPeriod,
+			RatesMbps:    []float64{},
+		}
+		bv.Manufacturer, _ = ie.Manufacturer(entry.BSSID[:])
+		for _, r := range entry.Rates {
+			bv.RatesMbps = append(bv.RatesMbps, float64(r&0x7fff)/2)
+		}
+		//Elements after a truncated one are left out, and the security is read from those before it.
+		es, _ := ie.Parse(entry.IEs)
+		if s, err := ie.ParseSecurity(entry.CapabilityInformation, es); err == nil {
+			bv.Security = s.String()
+		} else {
+			bv.Security = "invalid: " + err.Error()
+		}
+		row := []string{b.Interface.Description, bv.BSSID, bv.Manufacturer, bv.SSID, strconv.Itoa(int(bv.RSSI)), strconv.Itoa(int(bv.LinkQuality)),
+			bv.Band, strconv.Itoa(bv.Channel), bv.PhyType, bv.Security}
+		if *withIEs {
+			names := make([]string, len(es))
+			for n, el := range es {
+				bv.IEs = append(bv.IEs, ieView{ID: uint8(el.ID), Name: ieName(el), Length: len(el.Data), Data: hex.EncodeToString(el.Data)})
+				names[n] = ieName(el)
+			}
+			row = append(row, strings.Join(names, ", "))
+		}
+		views = append(views, bv)
+		v.rows = append(v.rows, row)
+	}
+	v.value = views
+	return e.finish(v, failed)
+}
+
+//bssidList is a repeatable flag of BSSIDs.
+type bssidList [][6]byte
+
+func (l *bssidList) String() string {
+	s := make([]string, len(*l))
+	for n, b := range *l {
+		s[n] = net.HardwareAddr(b[:]).String()
+	}
+	return strings.Join(s, ",")
+}
+
+func (l *bssidList) Set(s string) error {
+	mac, err := net.ParseMAC(s)
+	if err != nil || len(mac) != 6 {
+		return fmt.Errorf("invalid BSSID %q", s)
+	}
+	var b [6]byte
+	copy(b[:], mac)
+	*l = append(*l, b)
+	return nil
+}
+
+func (e *env) connect(args []string) error {
+	fs := e.flags("connect")
+	xmlFile := fs.String("xml", "", "connect with the temporary profile of an XML `file`")
+	ssid := fs.String("ssid", "", "the `SSID` of the profile to connect to")
+	var bssids bssidList
+	fs.Var(&bssids, "bssid", "a `BSSID` to connect to; repeat it to allow several")
+	wait := fs.Bool("wait", false, "wait for the connections to complete")
+	timeout := fs.Duration("timeout", defaultTimeout, "how long to wait for the connections")
+	args, err := e.parse(fs, args, 0, 1)
+	if err != nil {
+		return err
+	}
+	p := wlanapi.ConnectionParameters{SSID: []byte(*ssid), BSSIDs: bssids}
+	if len(args) > 0 {
+		p.Profile = args[0]
+	}
+	switch {
+	case *xmlFile != "":
+		b, err := os.ReadFile(*xmlFile)
+		if err != nil {
+			return err
+		}
+		p.ProfileXML = string(b)
+	case p.Profile == "":
+		return usageError("connect: a profile name or --xml is needed")
+	}
+	c, interfaces, err := e.selected()
+	if err != nil {
+		return err
+	}
+	notifications, cancel, err := subscribe(c, *wait)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+
+	outcomes := make([]string, len(interfaces))
+	pending := map[wlanapi.GUID]*string{}
+	var failed wlanapi.InterfaceErrors
+	for n, i := range interfaces {
+		if err := i.Connect(p); err != nil {
+			outcomes[n] = "error: " + err.Error()
+			failed = append(failed, &wlanapi.InterfaceError{Interface: i, Err: err})
+			continue
+		}
+		outcomes[n] = "requested"
+		pending[i.GUID] = &outcomes[n]
+	}
+	if *wait {
+		await(notifications, *timeout, pending, func(n wlanapi.Notification) (string, bool) {
+			code, ok := n.ACM()
+			if !ok {
+				return "", false
+			}
+			switch code {
+			case wlanapi.WlanNotificationAcmConnectionComplete:
+				if n.Reason() == wlanapi.WLAN_REASON_CODE_SUCCESS {
+					return "connected", true
+				}
+				return failure(n), true
+			case wlanapi.WlanNotificationAcmConnectionAttemptFail:
+				return failure(n), true
+			}
+			return "", false
+		})
+	}
+	v, unfinished := results(interfaces, outcomes, "requested", "connected")
+	return e.finish(v, append(failed, unfinished...))
+}
+
+func (e *env) disconnect(args []string) error {
+	fs := e.flags("disconnect")
+	args, err := e.parse(fs, args, 0, 0)
+	if err != nil {
+		return err
+	}
+	_, interfaces, err := e.selected()
+	if err != nil {
+		return err
+	}
+	outcomes := make([]string, len(interfaces))
+	var failed wlanapi.InterfaceErrors
+	for n, i := range interfaces {
+		outcomes[n] = "disconnected"
+		if err := i.Disconnect(); err != nil {
+			outcomes[n] = "error: " + err.Error()
+			failed = append(failed, &wlanapi.InterfaceError{Interface: i, Err: err})
+		}
+	}
+	v, _ := results(interfaces, outcomes, "disconnected")
+	return e.finish(v, failed)
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+
+	"wlanapi"
+)
+
+func (e *env) hostedNetwork(args []string) error {
+	if len(args) == 0 {
+		return usageError("hostednetwork: missing subcommand: status, start or stop")
+	}
+	switch args[0] {
+	case "status":
+		return e.hostedNetworkStatus(args[1:])
+	case "start":
+		return e.hostedNetworkStart(args[1:])
+	case "stop":
+		return e.hostedNetworkStop(args[1:])
+	}
+	return usageError(fmt.Sprintf("hostednetwork: unknown subcommand %q", args[0]))
+}
+
+type hostedNetworkView struct {
+	State        string     `json:"state"`
+	SSID         string     `json:"ssid"`
+	MaxPeers     uint32     `json:"maxPeers"`
+	BSSID        string     `json:"bssid,omitempty"`
+	PhyType      string     `json:"phyType,omitempty"`
+	FrequencyKHz uint32     `json:"frequencyKHz,omitempty"`
+	Channel      int        `json:"channel,omitempty"`
+	Peers        []peerView `json:"peers"`
+}
+
+type peerView struct {
+	MAC       string `json:"mac"`
+	AuthState string `json:"authState"`
+}
+
+//writeHostedNetwork writes the status and settings of the Hosted Network.
+func (e *env) writeHostedNetwork(c *wlanapi.Client) error {
+	status, err := c.HostedNetworkStatus()
+	if err != nil {
+		return err
+	}
+	settings, err := c.HostedNetworkSettings()
+	if err != nil {
+		return err
+	}
+	hv := hostedNetworkView{
+		State:    wlanapi.WLAN_HOSTED_NETWORK_STATE(status.State).String(),
+		SSID:     string(settings.SSID),
+		MaxPeers: settings.MaxPeers,
+		Peers:    []peerView{},
+	}
+	//The BSS of the Hosted Network is only defined while it runs.
+	if hv.State == "active" {
+		hv.BSSID = net.HardwareAddr(status.BSSID[:]).String()
+		hv.PhyType = wlanapi.DOT11_PHY_TYPE(status.PhyType).String()
+		hv.FrequencyKHz = status.ChannelFrequency
+		hv.Channel = wlanapi.ChannelOf(status.ChannelFrequency)
+	}
+	for _, p := range status.Peers {
+		hv.Peers = append(hv.Peers, peerView{
+			MAC:       net.HardwareAddr(p.MacAddress[:]).String(),
+			AuthState: wlanapi.WLAN_HOSTED_NETWORK_PEER_AUTH_STATE(p.AuthState).String(),
+		})
+	}
+	v := view{
+		value:  hv,
+		header: []string{"STATE", "SSID", "MAX PEERS", "BSSID", "CHANNEL", "PEERS"},
+		rows:   [][]string{{hv.State, hv.SSID, strconv.Itoa(int(hv.MaxPeers)), hv.BSSID, "", strconv.Itoa(len(hv.Peers))}},
+	}
+	if hv.Channel != 0 {
+		v.rows[0][4] = strconv.Itoa(hv.Channel)
+	}
+	return e.write(v)
+}
+
+func (e *env) hostedNetworkStatus(args []string) error {
+	fs := e.flags("hostednetwork status")
+	args, err := e.parse(fs, args, 0, 0)
+	if err != nil {
+		return err
+	}
+	c, err := e.open()
+	if err != nil {
+		return err
+	}
+	return e.writeHostedNetwork(c)
+}
+
+func (e *env) hostedNetworkStart(args []string) error {
+	fs := e.flags("hostednetwork start")
+	ssid := fs.String("ssid", "", "set the `SSID` of the Hosted Network")
+	maxPeers := fs.Uint("max-peers", 0, "set the maximum `number` of peers")
+	args, err := e.parse(fs, args, 0, 0)
+	if err != nil {
+		return err
+	}
+	c, err := e.open()
+	if err != nil {
+		return err
+	}
+	if *ssid != "" || *maxPeers != 0 {
+		settings, err := c.HostedNetworkSettings()
+		if err != nil {
+			return err
+		}
+		if *ssid != "" {
+			settings.SSID = []byte(*ssid)
+		}
+		if *maxPeers != 0 {
+			settings.MaxPeers = uint32(*maxPeers)
+		}
+		if err := c.SetHostedNetworkSettings(*settings); err != nil {
+			return err
+		}
+	}
+	if err := c.StartHostedNetwork(); err != nil {
+		return err
+	}
+	return e.writeHostedNetwork(c)
+}
+
+func (e *env) hostedNetworkStop(args []string) error {
+	fs := e.flags("hostednetwork stop")
+	args, err := e.parse(fs, args, 0, 0)
+	if err != nil {
+		return err
+	}
+	c, err := e.open()
+	if err != nil {
+		return err
+	}
+	if err := c.StopHostedNetwork(); err != nil {
+		return err
+	}
+	return e.writeHostedNetwork(c)
+}
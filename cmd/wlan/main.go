@@ -0,0 +1,227 @@
+//Command wlan lists and manages the wireless LAN interfaces of the computer: it scans, shows the
+//available networks and BSSes, manages profiles, connects and runs the wireless Hosted Network.
+//
+//Usage:
+//
+//	wlan [flags] command [arguments]
+//
+//The flags can come before or after the command:
+//
+//	--backend native|sim:fixture.json  the WLAN service, or a Sim loaded from a JSON fixture (default native)
+//	-o, --output table|json|yaml       the output format (default table)
+//	-i, --interface selector           the interfaces to work on: all, connected, a GUID or part of a description
+//
+//The commands are:
+//
+//	interfaces                                list the interfaces and their connection
+//	scan [--wait] [--timeout d]               request a scan, and wait for it to complete
+//	networks                                  list the available networks
+//	bss [--ie]                                list the BSSes, with their information elements
+//	profile list                              list the profiles
+//	profile show [--key] name                 show the XML of a profile, with its key in plain text
+//	profile export [--folder dir] [--key] [name...]
+//	                                          write the profiles to XML files
+//	profile import [--overwrite] file...      add the profiles of XML files
+//	profile delete name...                    delete profiles
+//	connect [--xml file] [--ssid s] [--bssid mac...] [--wait] [--timeout d] [name]
+//	                                          connect with a profile, or with the temporary profile of a file
+//	disconnect                                disconnect the interfaces
+//	hostednetwork status                      show the wireless Hosted Network
+//	hostednetwork start [--ssid s] [--max-peers n]
+//	                                          start the Hosted Network, setting its SSID and maximum peers
+//	hostednetwork stop                        stop the Hosted Network
+//
+//wlan exits with 1 when a command fails on any interface and 2 on usage errors.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"wlanapi"
+	"wlanapi/simfile"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+const usage = `usage: wlan [--backend native|sim:fixture.json] [-o table|json|yaml] [-i interface] command [arguments]
+
+commands:
+  interfaces
+  scan [--wait] [--timeout d]
+  networks
+  bss [--ie]
+  profile list|show|export|import|delete
+  connect [--xml file] [--ssid s] [--bssid mac...] [--wait] [--timeout d] [name]
+  disconnect
+  hostednetwork status|start|stop
+`
+
+//command runs a command with its arguments after the command name.
+type command func(e *env, args []string) error
+
+var commands = map[string]command{
+	"interfaces":    (*env).interfaces,
+	"scan":          (*env).scan,
+	"networks":      (*env).networks,
+	"bss":           (*env).bss,
+	"profile":       (*env).profile,
+	"connect":       (*env).connect,
+	"disconnect":    (*env).disconnect,
+	"hostednetwork": (*env).hostedNetwork,
+}
+
+//usageError is a command line error; wlan exits with 2 after it.
+type usageError string
+
+func (e usageError) Error() string {
+	return string(e)
+}
+
+//errFlags is returned for the flag errors the flag sets have already reported.
+var errFlags = errors.New("invalid flags")
+
+//env is the state shared by the commands: the global flags and the client they open.
+type env struct {
+	stdout, stderr io.Writer
+
+	backend string
+	output  string
+	iface   string
+
+	client *wlanapi.Client
+}
+
+func run(args []string, stdout, stderr io.Writer) int {
+	e := &env{stdout: stdout, stderr: stderr, backend: "native", output: "table"}
+	defer func() {
+		if e.client != nil {
+			e.client.Close()
+		}
+	}()
+
+	fs := e.flags("wlan")
+	fs.Usage = func() { fmt.Fprint(stderr, usage) }
+	if err := fs.Parse(args); err != nil {
+		if err != flag.ErrHelp {
+			err = errFlags
+		}
+		return exitCode(err)
+	}
+	if fs.NArg() == 0 {
+		fmt.Fprint(stderr, usage)
+		return 2
+	}
+	cmd, ok := commands[fs.Arg(0)]
+	if !ok {
+		fmt.Fprintf(stderr, "wlan: unknown command %q\n%s", fs.Arg(0), usage)
+		return 2
+	}
+	err := cmd(e, fs.Args()[1:])
+	if err != nil && err != flag.ErrHelp && err != errFlags {
+		fmt.Fprintf(stderr, "wlan: %v\n", err)
+	}
+	return exitCode(err)
+}
+
+func exitCode(err error) int {
+	var usage usageError
+	switch {
+	case err == nil, err == flag.ErrHelp:
+		return 0
+	case err == errFlags, errors.As(err, &usage):
+		return 2
+	}
+	return 1
+}
+
+//flags returns the flag set of a command, with the global flags.
+func (e *env) flags(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(e.stderr)
+	fs.StringVar(&e.backend, "backend", e.backend, "`backend`: native, or sim:fixture.json")
+	fs.StringVar(&e.output, "o", e.output, "output `format`: table, json or yaml")
+	fs.StringVar(&e.output, "output", e.output, "output `format`: table, json or yaml")
+	fs.StringVar(&e.iface, "i", e.iface, "interface `selector`: all, connected, a GUID or part of a description")
+	fs.StringVar(&e.iface, "interface", e.iface, "interface `selector`: all, connected, a GUID or part of a description")
+	return fs
+}
+
+//parse parses the arguments of a command, with flags before, between or after its positional arguments,
+//and returns the positional arguments after checking their number; max < 0 allows any number.
+func (e *env) parse(fs *flag.FlagSet, args []string, min, max int) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			if err != flag.ErrHelp {
+				err = errFlags
+			}
+			return nil, err
+		}
+		//Everything after a -- terminator is positional.
+		if n := len(args) - fs.NArg(); n > 0 && args[n-1] == "--" {
+			positional = append(positional, fs.Args()...)
+			break
+		}
+		if fs.NArg() == 0 {
+			break
+		}
+		positional = append(positional, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+	switch {
+	case len(positional) < min:
+		return nil, usageError(fmt.Sprintf("%s: missing arguments", fs.Name()))
+	case max >= 0 && len(positional) > max:
+		return nil, usageError(fmt.Sprintf("%s: unexpected arguments %q", fs.Name(), positional[max:]))
+	}
+	switch e.output {
+	case "table", "json", "yaml":
+	default:
+		return nil, usageError(fmt.Sprintf("unknown output format %q", e.output))
+	}
+	return positional, nil
+}
+
+//open opens the client of the backend flag.
+func (e *env) open() (*wlanapi.Client, error) {
+	if e.client != nil {
+		return e.client, nil
+	}
+	c, err := simfile.Backend(e.backend)
+	var unknown simfile.UnknownBackendError
+	if errors.As(err, &unknown) {
+		return nil, usageError(err.Error())
+	}
+	if err != nil {
+		return nil, err
+	}
+	e.client = c
+	return e.client, nil
+}
+
+//selected opens the client and returns the interfaces chosen by the interface flag.
+//A selector that matches no interface is an error.
+func (e *env) selected() (*wlanapi.Client, []*wlanapi.Interface, error) {
+	sel, err := wlanapi.ParseInterfaceSelector(e.iface)
+	if err != nil {
+		return nil, nil, usageError(err.Error())
+	}
+	c, err := e.open()
+	if err != nil {
+		return nil, nil, err
+	}
+	interfaces, err := c.SelectInterfaces(sel)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(interfaces) == 0 && e.iface != "" {
+		return nil, nil, fmt.Errorf("no interface matches %q", e.iface)
+	}
+	return c, interfaces, nil
+}
@@ -0,0 +1,207 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"wlanapi/simfile/simfiletest"
+)
+
+var fixture = "--backend=sim:" + simfiletest.Path
+
+//wlan runs the command with the fixture backend and returns its output and exit code.
+func wlan(t *testing.T, args ...string) (string, int) {
+	t.Helper()
+	var stdout, stderr bytes.Buffer
+	code := run(append([]string{fixture}, args...), &stdout, &stderr)
+	if code != 0 {
+		t.Logf("wlan %s: exit %d: %s", strings.Join(args, " "), code, stderr.String())
+	}
+	return stdout.String(), code
+}
+
+func TestInterfaces(t *testing.T) {
+	out, code := wlan(t, "interfaces")
+	want := `GUID                                    DESCRIPTION                    STATE         SSID  BSSID              SIGNAL  PROFILE
+{11111111-2222-3333-4444-555555555555}  Intel(R) Wi-Fi 6 AX201 160MHz  connected     corp  00:1a:1e:00:00:01  90%     corp
+{AAAAAAAA-BBBB-CCCC-DDDD-EEEEEEEEEEEE}  Realtek RTL8812BU USB          disconnected
+`
+	if code != 0 || out != want {
+		t.Errorf("interfaces: exit %d\n%s\nwant\n%s", code, out, want)
+	}
+
+	out, code = wlan(t, "-o", "json", "interfaces", "-i", "connected")
+	var interfaces []interfaceView
+	if err := json.Unmarshal([]byte(out), &interfaces); err != nil || code != 0 {
+		t.Fatalf("interfaces -o json: exit %d, %v\n%s", code, err, out)
+	}
+	if len(interfaces) != 1 || interfaces[0].Connection == nil || interfaces[0].Connection.TxRateMbps != 54 ||
+		interfaces[0].Connection.AuthAlgorithm != "WPA2-Personal" {
+		t.Errorf("connected interfaces %+v", interfaces)
+	}
+}
+
+func TestNetworksAndBSS(t *testing.T) {
+	out, code := wlan(t, "networks", "-i", "intel")
+	if code != 0 || !strings.Contains(out, "corp   90%     WPA2-Personal  CCMP    3       yes          corp     yes") {
+		t.Errorf("networks: exit %d\n%s", code, out)
+	}
+
+	out, code = wlan(t, "bss", "--ie", "-o", "json")
+	var bsses []bssView
+	if err := json.Unmarshal([]byte(out), &bsses); err != nil || code != 0 {
+		t.Fatalf("bss -o json: exit %d, %v\n%s", code, err, out)
+	}
+	if len(bsses) != 4 {
+		t.Fatalf("%d BSSes", len(bsses))
+	}
+	b := bsses[1]
+	if b.Band != "5GHz" || b.Channel != 36 || b.Security != "WPA2-Personal" || len(b.IEs) != 5 || b.IEs[1].Name != "RSN" ||
+		b.RatesMbps[0] != 6 || b.Manufacturer != "Aruba, a Hewlett Packard Enterprise Company" {
+		t.Errorf("BSS %+v", b)
+	}
+	if bsses[3].Security != "Open" {
+		t.Errorf("security of an open BSS %q", bsses[3].Security)
+	}
+}
+
+func TestScanWait(t *testing.T) {
+	out, code := wlan(t, "scan", "--wait", "-o", "yaml")
+	want := `- interface: "{11111111-2222-3333-4444-555555555555}"
+  result: complete
+- interface: "{AAAAAAAA-BBBB-CCCC-DDDD-EEEEEEEEEEEE}"
+  result: complete
+`
+	if code != 0 || out != want {
+		t.Errorf("scan --wait: exit %d\n%s\nwant\n%s", code, out, want)
+	}
+	if _, code := wlan(t, "scan", "-i", "nothing"); code != 1 {
+		t.Errorf("scan without interfaces: exit %d", code)
+	}
+}
+
+func TestProfiles(t *testing.T) {
+	dir := t.TempDir()
+	out, code := wlan(t, "profile", "export", "--folder", dir, "--key")
+	file := filepath.Join(dir, "corp.xml")
+	if code != 0 || !strings.Contains(out, file) {
+		t.Fatalf("profile export: exit %d\n%s", code, out)
+	}
+	xml, err := os.ReadFile(file)
+	if err != nil || !strings.Contains(string(xml), "<keyMaterial>correct horse</keyMaterial>") {
+		t.Fatalf("exported profile %q, %v", xml, err)
+	}
+
+	out, code = wlan(t, "profile", "import", "-i", "realtek", file)
+	if code != 0 || !strings.Contains(out, "Realtek RTL8812BU USB  corp") {
+		t.Errorf("profile import: exit %d\n%s", code, out)
+	}
+	if _, code := wlan(t, "profile", "import", "-i", "intel", file); code != 1 {
+		t.Errorf("importing an existing profile: exit %d", code)
+	}
+	if _, code := wlan(t, "profile", "import", "-i", "intel", "--overwrite", file); code != 0 {
+		t.Errorf("profile import --overwrite: exit %d", code)
+	}
+
+	out, code = wlan(t, "profile", "show", "corp")
+	if code != 0 || out != string(xml)+"\n" {
+		t.Errorf("profile show: exit %d\n%s", code, out)
+	}
+	if _, code := wlan(t, "profile", "show", "home"); code != 1 {
+		t.Errorf("showing a missing profile: exit %d", code)
+	}
+	if out, code := wlan(t, "profile", "delete", "corp"); code != 0 || !strings.Contains(out, "corp") {
+		t.Errorf("profile delete: exit %d\n%s", code, out)
+	}
+}
+
+func TestConnect(t *testing.T) {
+	out, code := wlan(t, "connect", "-i", "intel", "corp", "--wait", "--bssid", "00:1a:1e:00:00:02")
+	if code != 0 || !strings.Contains(out, "connected") {
+		t.Errorf("connect: exit %d\n%s", code, out)
+	}
+	out, code = wlan(t, "connect", "-i", "intel", "corp", "--wait", "--bssid", "00:1a:1e:00:00:09")
+	if code != 1 || !strings.Contains(out, "failed") {
+		t.Errorf("connect to a missing BSSID: exit %d\n%s", code, out)
+	}
+	out, code = wlan(t, "connect", "-i", "intel", "--xml", "missing.xml")
+	if code != 1 {
+		t.Errorf("connect with a missing file: exit %d\n%s", code, out)
+	}
+	if out, code := wlan(t, "disconnect", "-i", "connected"); code != 0 || !strings.Contains(out, "disconnected") {
+		t.Errorf("disconnect: exit %d\n%s", code, out)
+	}
+}
+
+func TestHostedNetwork(t *testing.T) {
+	out, code := wlan(t, "hostednetwork", "start", "--ssid", "lobby", "--max-peers", "4", "-o", "yaml")
+	want := `state: active
+ssid: lobby
+maxPeers: 4
+bssid: "02:00:00:00:00:01"
+phyType: "802.11n"
+frequencyKHz: 2437000
+channel: 6
+peers: []
+`
+	if code != 0 || out != want {
+		t.Errorf("hostednetwork start: exit %d\n%s\nwant\n%s", code, out, want)
+	}
+	out, code = wlan(t, "hostednetwork", "status")
+	want = `STATE  SSID   MAX PEERS  BSSID  CHANNEL  PEERS
+idle   kiosk  8                          0
+`
+	if code != 0 || out != want {
+		t.Errorf("hostednetwork status: exit %d\n%s\nwant\n%s", code, out, want)
+	}
+	if _, code := wlan(t, "hostednetwork", "stop"); code != 1 {
+		t.Errorf("stopping an idle hosted network: exit %d", code)
+	}
+}
+
+func TestUsage(t *testing.T) {
+	for _, args := range [][]string{
+		{},
+		{"frobnicate"},
+		{"scan", "--frobnicate"},
+		{"-o", "xml", "interfaces"},
+		{"profile"},
+		{"profile", "show"},
+		{"connect"},
+		{"hostednetwork", "restart"},
+		{"--backend", "dbus", "interfaces"},
+		{"-i", "{not-a-guid}", "interfaces"},
+	} {
+		var stdout, stderr bytes.Buffer
+		if code := run(args, &stdout, &stderr); code != 2 || stderr.Len() == 0 {
+			t.Errorf("wlan %q: exit %d, %q", args, code, stderr.String())
+		}
+	}
+}
+
+func TestYAML(t *testing.T) {
+	var b bytes.Buffer
+	doc := `{"a":[{"b":[1,[true,null]],"c":{}},"on"],"d":{"e f":"x: y","g":[]},"":"1.5"}`
+	if err := writeYAML(&b, []byte(doc)); err != nil {
+		t.Fatal(err)
+	}
+	want := `a:
+- b:
+  - 1
+  - - true
+    - null
+  c: {}
+- "on"
+d:
+  e f: "x: y"
+  g: []
+"": "1.5"
+`
+	if b.String() != want {
+		t.Errorf("YAML\n%s\nwant\n%s", b.String(), want)
+	}
+}
@@ -0,0 +1,182 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+//view is the result of a command: value is written as JSON or YAML, and the header and rows as a table.
+type view struct {
+	value  interface{}
+	header []string
+	rows   [][]string
+	//text replaces the table when set, as for the XML of profiles.
+	text string
+}
+
+//write writes v in the output format.
+func (e *env) write(v view) error {
+	switch e.output {
+	case "json":
+		b, err := json.MarshalIndent(v.value, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(e.stdout, "%s\n", b)
+		return err
+	case "yaml":
+		b, err := json.Marshal(v.value)
+		if err != nil {
+			return err
+		}
+		return writeYAML(e.stdout, b)
+	}
+	if v.text != "" {
+		_, err := io.WriteString(e.stdout, v.text)
+		return err
+	}
+	var b bytes.Buffer
+	w := tabwriter.NewWriter(&b, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, strings.Join(v.header, "\t"))
+	for _, row := range v.rows {
+		fmt.Fprintln(w, strings.Join(row, "\t"))
+	}
+	w.Flush()
+	//Empty cells at the end of rows leave padding behind.
+	for _, line := range strings.Split(strings.TrimSuffix(b.String(), "\n"), "\n") {
+		if _, err := fmt.Fprintln(e.stdout, strings.TrimRight(line, " ")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//yamlNode is a JSON value in the order of the document, since decoding into maps loses the order of the keys.
+type yamlNode struct {
+	//kind is '{' for objects, '[' for arrays and 0 for scalars.
+	kind   byte
+	keys   []string
+	values []*yamlNode
+	scalar string
+}
+
+//writeYAML writes the JSON document b as block style YAML.
+func writeYAML(w io.Writer, b []byte) error {
+	d := json.NewDecoder(bytes.NewReader(b))
+	d.UseNumber()
+	n, err := decodeYAMLNode(d)
+	if err != nil {
+		return err
+	}
+	var out bytes.Buffer
+	if n.inline() {
+		out.WriteString(n.text() + "\n")
+	} else {
+		n.write(&out, "", "")
+	}
+	_, err = w.Write(out.Bytes())
+	return err
+}
+
+func decodeYAMLNode(d *json.Decoder) (*yamlNode, error) {
+	t, err := d.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch t := t.(type) {
+	case json.Delim:
+		n := &yamlNode{kind: byte(t)}
+		for d.More() {
+			if n.kind == '{' {
+				key, err := d.Token()
+				if err != nil {
+					return nil, err
+				}
+				n.keys = append(n.keys, key.(string))
+			}
+			value, err := decodeYAMLNode(d)
+			if err != nil {
+				return nil, err
+			}
+			n.values = append(n.values, value)
+		}
+		_, err := d.Token()
+		return n, err
+	case string:
+		return &yamlNode{scalar: yamlString(t)}, nil
+	case json.Number:
+		return &yamlNode{scalar: t.String()}, nil
+	case bool:
+		return &yamlNode{scalar: strconv.FormatBool(t)}, nil
+	}
+	return &yamlNode{scalar: "null"}, nil
+}
+
+//inline reports whether the node is written on the line of its key or dash: scalars and empty collections.
+func (n *yamlNode) inline() bool {
+	return n.kind == 0 || len(n.values) == 0
+}
+
+func (n *yamlNode) text() string {
+	switch n.kind {
+	case '{':
+		return "{}"
+	case '[':
+		return "[]"
+	}
+	return n.scalar
+}
+
+//write writes a collection whose lines are indented by indent; first replaces the indentation of the first line,
+//so the first key of a mapping in a sequence follows its dash.
+func (n *yamlNode) write(out *bytes.Buffer, indent, first string) {
+	for k, value := range n.values {
+		prefix := indent
+		if k == 0 {
+			prefix = first
+		}
+		if n.kind == '[' {
+			if value.inline() {
+				out.WriteString(prefix + "- " + value.text() + "\n")
+			} else {
+				value.write(out, indent+"  ", prefix+"- ")
+			}
+			continue
+		}
+		out.WriteString(prefix + yamlString(n.keys[k]) + ":")
+		switch {
+		case value.inline():
+			out.WriteString(" " + value.text() + "\n")
+		case value.kind == '[':
+			out.WriteString("\n")
+			value.write(out, indent, indent)
+		default:
+			out.WriteString("\n")
+			value.write(out, indent+"  ", indent+"  ")
+		}
+	}
+}
+
+//yamlString returns s as a plain scalar when YAML reads it back as the same string, and double quoted otherwise.
+func yamlString(s string) string {
+	switch strings.ToLower(s) {
+	case "", "true", "false", "yes", "no", "on", "off", "y", "n", "null", "~":
+		return strconv.Quote(s)
+	}
+	if s[0] == ' ' || s[len(s)-1] == ' ' || strings.Contains(s, ": ") || strings.Contains(s, " #") {
+		return strconv.Quote(s)
+	}
+	for n, r := range s {
+		letter := r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r == '_'
+		other := r >= '0' && r <= '9' || strings.ContainsRune(" ./()@+-", r)
+		if !letter && (n == 0 || !other) {
+			return strconv.Quote(s)
+		}
+	}
+	return s
+}
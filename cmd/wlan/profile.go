@@ -0,0 +1,285 @@
+package main
+
+import (
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"wlanapi"
+)
+
+func (e *env) profile(args []string) error {
+	if len(args) == 0 {
+		return usageError("profile: missing subcommand: list, show, export, import or delete")
+	}
+	switch args[0] {
+	case "list":
+		return e.profileList(args[1:])
+	case "show":
+		return e.profileShow(args[1:])
+	case "export":
+		return e.profileExport(args[1:])
+	case "import":
+		return e.profileImport(args[1:])
+	case "delete":
+		return e.profileDelete(args[1:])
+	}
+	return usageError(fmt.Sprintf("profile: unknown subcommand %q", args[0]))
+}
+
+type profileView struct {
+	Interface string `json:"interface"`
+	Name      string `json:"name"`
+	Type      string `json:"type,omitempty"`
+	XML       string `json:"xml,omitempty"`
+	File      string `json:"file,omitempty"`
+}
+
+//profileType names the type of a profile after its WLAN_PROFILE_INFO flags.
+func profileType(flags uint32) string {
+	switch {
+	case flags&wlanapi.WLAN_PROFILE_GROUP_POLICY != 0:
+		return "group policy"
+	case flags&wlanapi.WLAN_PROFILE_USER != 0:
+		return "user"
+	}
+	return "all users"
+}
+
+func (e *env) profileList(args []string) error {
+	fs := e.flags("profile list")
+	args, err := e.parse(fs, args, 0, 0)
+	if err != nil {
+		return err
+	}
+	_, interfaces, err := e.selected()
+	if err != nil {
+		return err
+	}
+	var failed wlanapi.InterfaceErrors
+	views := []profileView{}
+	v := view{header: []string{"INTERFACE", "NAME", "TYPE"}}
+	for _, i := range interfaces {
+		profiles, err := i.Profiles()
+		if err != nil {
+			failed = append(failed, &wlanapi.InterfaceError{Interface: i, Err: err})
+			continue
+		}
+		for _, p := range profiles {
+			pv := profileView{Interface: i.GUID.String(), Name: p.Name, Type: profileType(p.Flags)}
+			views = append(views, pv)
+			v.rows = append(v.rows, []string{i.Description, pv.Name, pv.Type})
+		}
+	}
+	v.value = views
+	return e.finish(v, failed)
+}
+
+func (e *env) profileShow(args []string) error {
+	fs := e.flags("profile show")
+	key := fs.Bool("key", false, "show the key in plain text, which needs administrator rights")
+	args, err := e.parse(fs, args, 1, 1)
+	if err != nil {
+		return err
+	}
+	_, interfaces, err := e.selected()
+	if err != nil {
+		return err
+	}
+	var failed wlanapi.InterfaceErrors
+	views := []profileView{}
+	var v view
+	for _, i := range interfaces {
+		x, err := i.ProfileXML(args[0], *key)
+		if err == wlanapi.ErrProfileNotFound {
+			continue
+		}
+		if err != nil {
+			failed = append(failed, &wlanapi.InterfaceError{Interface: i, Err: err})
+			continue
+		}
+		views = append(views, profileView{Interface: i.GUID.String(), Name: args[0], XML: x})
+		v.text += strings.TrimSuffix(x, "\n") + "\n"
+	}
+	if len(views) == 0 && len(failed) == 0 {
+		return fmt.Errorf("profile %q not found", args[0])
+	}
+	v.value = views
+	return e.finish(v, failed)
+}
+
+//fileName returns the name of the file of a profile, replacing the characters Windows does not allow in names.
+func fileName(profile string) string {
+	return strings.Map(func(r rune) rune {
+		if r < ' ' || strings.ContainsRune(`<>:"/\|?*`, r) {
+			return '_'
+		}
+		return r
+	}, profile) + ".xml"
+}
+
+func (e *env) profileExport(args []string) error {
+	fs := e.flags("profile export")
+	folder := fs.String("folder", ".", "the `folder` to write the profiles to")
+	key := fs.Bool("key", false, "export the keys in plain text, which needs administrator rights")
+	args, err := e.parse(fs, args, 0, -1)
+	if err != nil {
+		return err
+	}
+	_, interfaces, err := e.selected()
+	if err != nil {
+		return err
+	}
+	type export struct {
+		i         *wlanapi.Interface
+		name, xml string
+	}
+	var exports []export
+	var failed wlanapi.InterfaceErrors
+	interfacesOf := map[string]int{}
+	for _, i := range interfaces {
+		names := args
+		if len(names) == 0 {
+			profiles, err := i.Profiles()
+			if err != nil {
+				failed = append(failed, &wlanapi.InterfaceError{Interface: i, Err: err})
+				continue
+			}
+			for _, p := range profiles {
+				names = append(names, p.Name)
+			}
+		}
+		for _, name := range names {
+			x, err := i.ProfileXML(name, *key)
+			if err == wlanapi.ErrProfileNotFound && len(args) > 0 {
+				continue
+			}
+			if err != nil {
+				failed = append(failed, &wlanapi.InterfaceError{Interface: i, Err: fmt.Errorf("profile %q: %v", name, err)})
+				continue
+			}
+			exports = append(exports, export{i, name, x})
+			interfacesOf[name]++
+		}
+	}
+	for _, name := range args {
+		if interfacesOf[name] == 0 && len(failed) == 0 {
+			return fmt.Errorf("profile %q not found", name)
+		}
+	}
+
+	views := []profileView{}
+	v := view{header: []string{"INTERFACE", "NAME", "FILE"}}
+	for _, x := range exports {
+		//The profiles of the same name on several interfaces are told apart by the GUID of their interface.
+		file := fileName(x.name)
+		if interfacesOf[x.name] > 1 {
+			file = x.i.GUID.String() + "-" + file
+		}
+		file = filepath.Join(*folder, file)
+		if err := os.WriteFile(file, []byte(x.xml), 0600); err != nil {
+			return err
+		}
+		views = append(views, profileView{Interface: x.i.GUID.String(), Name: x.name, File: file})
+		v.rows = append(v.rows, []string{x.i.Description, x.name, file})
+	}
+	v.value = views
+	return e.finish(v, failed)
+}
+
+//profileName returns the name of the profile of an XML document.
+func profileName(doc []byte) (string, error) {
+	var p struct {
+		Name string `xml:"name"`
+	}
+	if err := xml.Unmarshal(doc, &p); err != nil {
+		return "", err
+	}
+	if p.Name == "" {
+		return "", fmt.Errorf("profile has no name")
+	}
+	return p.Name, nil
+}
+
+func (e *env) profileImport(args []string) error {
+	fs := e.flags("profile import")
+	overwrite := fs.Bool("overwrite", false, "replace the profiles of the same names")
+	args, err := e.parse(fs, args, 1, -1)
+	if err != nil {
+		return err
+	}
+	docs := make([][]byte, len(args))
+	names := make([]string, len(args))
+	for n, file := range args {
+		doc, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		if names[n], err = profileName(doc); err != nil {
+			return fmt.Errorf("%s: %v", file, err)
+		}
+		docs[n] = doc
+	}
+	_, interfaces, err := e.selected()
+	if err != nil {
+		return err
+	}
+	var failed wlanapi.InterfaceErrors
+	views := []profileView{}
+	v := view{header: []string{"INTERFACE", "NAME", "FILE"}}
+	for _, i := range interfaces {
+		for n, file := range args {
+			if err := i.SetProfile(string(docs[n]), *overwrite); err != nil {
+				failed = append(failed, &wlanapi.InterfaceError{Interface: i, Err: fmt.Errorf("%s: %v", file, err)})
+				continue
+			}
+			views = append(views, profileView{Interface: i.GUID.String(), Name: names[n], File: file})
+			v.rows = append(v.rows, []string{i.Description, names[n], file})
+		}
+	}
+	v.value = views
+	return e.finish(v, failed)
+}
+
+func (e *env) profileDelete(args []string) error {
+	fs := e.flags("profile delete")
+	args, err := e.parse(fs, args, 1, -1)
+	if err != nil {
+		return err
+	}
+	_, interfaces, err := e.selected()
+	if err != nil {
+		return err
+	}
+	var failed wlanapi.InterfaceErrors
+	views := []profileView{}
+	v := view{header: []string{"INTERFACE", "NAME"}}
+	deleted := map[string]bool{}
+	for _, i := range interfaces {
+		for _, name := range args {
+			err := i.DeleteProfile(name)
+			if err == wlanapi.ErrProfileNotFound {
+				continue
+			}
+			if err != nil {
+				failed = append(failed, &wlanapi.InterfaceError{Interface: i, Err: fmt.Errorf("profile %q: %v", name, err)})
+				continue
+			}
+			deleted[name] = true
+			views = append(views, profileView{Interface: i.GUID.String(), Name: name})
+			v.rows = append(v.rows, []string{i.Description, name})
+		}
+	}
+	v.value = views
+	if err := e.finish(v, failed); err != nil {
+		return err
+	}
+	for _, name := range args {
+		if !deleted[name] {
+			return fmt.Errorf("profile %q not found", name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package wlanapi
+
+import (
+	"errors"
+
+	"wlanapi/binary"
+)
+
+//ErrNotConnected is returned for the connection of an interface that is not connected.
+var ErrNotConnected = errors.New("wlanapi: interface is not connected")
+
+//ConnectionParameters selects the network an interface connects to, as WLAN_CONNECTION_PARAMETERS.
+type ConnectionParameters struct {
+	//Profile is the name of the profile to connect with.
+	Profile string
+	//ProfileXML connects with a temporary profile instead of a stored one; Profile is then ignored.
+	ProfileXML string
+	//SSID selects one of the SSIDs of the profile; empty uses the first one.
+	SSID []byte
+	//BSSIDs restricts the connection to these BSSes; empty allows any BSS of the network.
+	BSSIDs [][6]byte
+	//BssType is the BSS type of the network; 0 selects infrastructure.
+	BssType DOT11_BSS_TYPE
+}
+
+//mode returns the connection mode of the parameters.
+func (p *ConnectionParameters) mode() WLAN_CONNECTION_MODE {
+	if p.ProfileXML != "" {
+		return wlan_connection_mode_temporary_profile
+	}
+	return wlan_connection_mode_profile
+}
+
+func (p *ConnectionParameters) bssType() DOT11_BSS_TYPE {
+	if p.BssType == 0 {
+		return Dot11BssTypeInfrastructure
+	}
+	return p.BssType
+}
+
+//ConnectionBackend is implemented by backends that connect their interfaces.
+type ConnectionBackend interface {
+	//Connect starts connecting the interface, as WlanConnect.
+	//The connection completes asynchronously with a connection complete or connection attempt fail notification.
+	Connect(iface GUID, p ConnectionParameters) error
+	//Disconnect disconnects the interface, as WlanDisconnect.
+	Disconnect(iface GUID) error
+	//Connection retrieves the current connection of the interface, as WlanQueryInterface with
+	//wlan_intf_opcode_current_connection. It returns ErrNotConnected when the interface is not connected.
+	Connection(iface GUID) (*binary.ConnectionAttributes, error)
+}
+
+func (i *Interface) connectionBackend() (ConnectionBackend, error) {
+	if b, ok := i.client.backend.(ConnectionBackend); ok {
+		return b, nil
+	}
+	return nil, ErrBackendNotSupported
+}
+
+//Connect starts connecting the interface to the network selected by p.
+func (i *Interface) Connect(p ConnectionParameters) error {
+	b, err := i.connectionBackend()
+	if err != nil {
+		return err
+	}
+	return b.Connect(i.GUID, p)
+}
+
+//Disconnect disconnects the interface from its network.
+func (i *Interface) Disconnect() error {
+	b, err := i.connectionBackend()
+	if err != nil {
+		return err
+	}
+	return b.Disconnect(i.GUID)
+}
+
+//Connection retrieves the current connection of the interface, or ErrNotConnected.
+func (i *Interface) Connection() (*binary.ConnectionAttributes, error) {
+	b, err := i.connectionBackend()
+	if err != nil {
+		return nil, err
+	}
+	return b.Connection(i.GUID)
+}
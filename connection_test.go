@@ -0,0 +1,184 @@
+package wlanapi
+
+import (
+	"errors"
+	"testing"
+
+	"wlanapi/binary"
+)
+
+const corpProfile = `<?xml version="1.0"?>
+<WLANProfile xmlns="http://www.microsoft.com/networking/WLAN/profile/v1">
+	<name>corp</name>
+	<SSIDConfig><SSID><name>corp</name></SSID></SSIDConfig>
+	<connectionType>ESS</connectionType>
+	<MSM><security>
+		<authEncryption><authentication>WPA2PSK</authentication><encryption>AES</encryption><useOneX>false</useOneX></authEncryption>
+		<sharedKey><keyType>passPhrase</keyType><protected>false</protected><keyMaterial>correct horse</keyMaterial></sharedKey>
+	</security></MSM>
+</WLANProfile>`
+
+func TestProfilesAndConnection(t *testing.T) {
+	sim := newFanOutSim()
+	iface := GUID(simGUID(2))
+	sim.SetBSSList(iface, []binary.BSSEntry{
+		{SSID: []byte("corp"), BSSID: [6]byte{5: 1}, RSSI: -70, LinkQuality: 60, PhyType: 7},
+		{SSID: []byte("corp"), BSSID: [6]byte{5: 2}, RSSI: -50, LinkQuality: 100, PhyType: 8, Rates: []uint16{0x8002, 108}},
+	})
+	c := NewClientWithBackend(sim)
+	interfaces, err := c.Interfaces()
+	if err != nil {
+		t.Fatal(err)
+	}
+	i := interfaces[1]
+
+	if err := i.SetProfile(corpProfile, false); err != nil {
+		t.Fatal(err)
+	}
+	if err := i.SetProfile(corpProfile, false); err == nil {
+		t.Error("profile added twice")
+	}
+	if err := i.SetProfile("<WLANProfile/>", true); err == nil {
+		t.Error("profile without a name added")
+	}
+	profiles, err := i.Profiles()
+	if err != nil || len(profiles) != 1 || profiles[0].Name != "corp" {
+		t.Fatalf("profiles %+v, %v", profiles, err)
+	}
+	if xml, err := i.ProfileXML("corp", true); err != nil || xml != corpProfile {
+		t.Errorf("profile XML %q, %v", xml, err)
+	}
+	if _, err := i.ProfileXML("home", false); err != ErrProfileNotFound {
+		t.Errorf("missing profile: %v", err)
+	}
+
+	notifications, cancel, err := sim.Notifications()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cancel()
+	if _, err := i.Connection(); err != ErrNotConnected {
+		t.Errorf("connection before connecting: %v", err)
+	}
+	if err := i.Connect(ConnectionParameters{Profile: "corp"}); err != nil {
+		t.Fatal(err)
+	}
+	if n := <-notifications; n.Code != uint32(WlanNotificationAcmConnectionComplete) || n.InterfaceGuid != iface {
+		t.Errorf("notification %+v", n)
+	}
+	conn, err := i.Connection()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if conn.ProfileName != "corp" || conn.BSSID != [6]byte{5: 2} || conn.SignalQuality != 100 || conn.TxRate != 54000 ||
+		DOT11_AUTH_ALGORITHM(conn.AuthAlgorithm) != DOT11_AUTH_ALGO_RSNA_PSK || DOT11_CIPHER_ALGORITHM(conn.CipherAlgorithm) != DOT11_CIPHER_ALGO_CCMP ||
+		!conn.SecurityEnabled || WLAN_CONNECTION_MODE(conn.Mode) != wlan_connection_mode_profile {
+		t.Errorf("connection %+v", conn)
+	}
+	if interfaces, _ := c.Interfaces(); interfaces[1].State != wlan_interface_state_connected {
+		t.Errorf("state after connecting %v", interfaces[1].State)
+	}
+
+	if err := i.Connect(ConnectionParameters{Profile: "corp", BSSIDs: [][6]byte{{5: 9}}}); err != nil {
+		t.Fatal(err)
+	}
+	if n := <-notifications; n.Code != uint32(WlanNotificationAcmConnectionAttemptFail) {
+		t.Errorf("notification for a missing BSSID %+v", n)
+	}
+	if err := i.Disconnect(); err != nil {
+		t.Fatal(err)
+	}
+	if n := <-notifications; n.Code != uint32(WlanNotificationAcmDisconnected) {
+		t.Errorf("notification %+v", n)
+	}
+	if _, err := i.Connection(); err != ErrNotConnected {
+		t.Errorf("connection after disconnecting: %v", err)
+	}
+	if err := i.DeleteProfile("corp"); err != nil {
+		t.Fatal(err)
+	}
+	if err := i.Connect(ConnectionParameters{Profile: "corp"}); err != ErrProfileNotFound {
+		t.Errorf("connecting with a deleted profile: %v", err)
+	}
+}
+
+func TestHostedNetwork(t *testing.T) {
+	sim := NewSim()
+	c := NewClientWithBackend(sim)
+	if err := c.StartHostedNetwork(); !errors.Is(err, ErrHostedNetworkState) {
+		t.Errorf("started an unavailable hosted network: %v", err)
+	}
+	sim.SetHostedNetwork(binary.HostedNetworkStatus{State: uint32(wlan_hosted_network_idle)}, HostedNetworkSettings{})
+	if err := c.SetHostedNetworkSettings(HostedNetworkSettings{SSID: []byte("kiosk"), MaxPeers: 8}); err != nil {
+		t.Fatal(err)
+	}
+	if s, err := c.HostedNetworkSettings(); err != nil || string(s.SSID) != "kiosk" || s.MaxPeers != 8 {
+		t.Errorf("settings %+v, %v", s, err)
+	}
+	notifications, cancel, err := sim.Notifications()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cancel()
+	if err := c.StartHostedNetwork(); err != nil {
+		t.Fatal(err)
+	}
+	if v, ok := (<-notifications).VirtualStation(); !ok || v.Code != wlan_hosted_network_state_change ||
+		v.OldState != wlan_hosted_network_idle || v.NewState != wlan_hosted_network_active {
+		t.Errorf("state change notification %+v, %v", v, ok)
+	}
+	if s, err := c.HostedNetworkStatus(); err != nil || WLAN_HOSTED_NETWORK_STATE(s.State) != wlan_hosted_network_active {
+		t.Errorf("status %+v, %v", s, err)
+	}
+	if err := c.StopHostedNetwork(); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.StopHostedNetwork(); err == nil {
+		t.Error("stopped an idle hosted network")
+	}
+
+	//Embedding the interface hides the optional methods of the Sim.
+	other := NewClientWithBackend(struct{ Backend }{sim})
+	if _, err := other.HostedNetworkStatus(); err != ErrBackendNotSupported {
+		t.Errorf("backend without hosted network: %v", err)
+	}
+}
+
+func TestVirtualStationNotification(t *testing.T) {
+	peer := Notification{Source: WLAN_NOTIFICATION_SOURCE_HNWK, Code: uint32(wlan_hosted_network_peer_state_change), Data: []byte{
+		2, 0, 0, 0, 0, 1, 0, 0, 0, 0, 0, 0,
+		2, 0, 0, 0, 0, 1, 0, 0, 1, 0, 0, 0,
+		byte(wlan_hosted_network_reason_peer_arrived), 0, 0, 0,
+	}}
+	v, ok := peer.VirtualStation()
+	if !ok || v.NewPeer.MacAddress != [6]byte{2, 0, 0, 0, 0, 1} ||
+		WLAN_HOSTED_NETWORK_PEER_AUTH_STATE(v.NewPeer.AuthState) != wlan_hosted_network_peer_state_authenticated ||
+		v.Reason != wlan_hosted_network_reason_peer_arrived {
+		t.Errorf("peer state change %+v, %v", v, ok)
+	}
+	radio := Notification{Source: WLAN_NOTIFICATION_SOURCE_HNWK, Code: uint32(wlan_hosted_network_radio_state_change), Data: []byte{1, 0, 0, 0, 2, 0, 0, 0}}
+	if v, ok := radio.VirtualStation(); !ok || v.SoftwareRadioState != dot11_radio_state_on || v.HardwareRadioState != dot11_radio_state_off {
+		t.Errorf("radio state change %+v, %v", v, ok)
+	}
+	if _, ok := (Notification{Source: WLAN_NOTIFICATION_SOURCE_HNWK, Code: uint32(wlan_hosted_network_peer_state_change)}).VirtualStation(); ok {
+		t.Error("decoded a peer state change without data")
+	}
+	if _, ok := (Notification{Source: WLAN_NOTIFICATION_SOURCE_ACM, Code: uint32(wlan_hosted_network_state_change)}).VirtualStation(); ok {
+		t.Error("decoded an ACM notification")
+	}
+}
+
+func TestNotificationEvent(t *testing.T) {
+	guid, _ := ParseGUID("{11111111-2222-3333-4444-555555555555}")
+	e := Notification{Source: WLAN_NOTIFICATION_SOURCE_ACM, Code: uint32(WlanNotificationAcmScanFail), InterfaceGuid: guid,
+		Data: []byte{0x01, 0x80, 0x02, 0x00}}.Event()
+	if e.Source != "acm" || e.Interface != guid.String() || e.Notification != "scan fail" || e.Reason == "" {
+		t.Errorf("scan fail event %+v", e)
+	}
+	if e := (Notification{Source: WLAN_NOTIFICATION_SOURCE_HNWK, Code: 2}).Event(); e.Source != "hnwk" || e.Interface != "" || e.Notification != "" {
+		t.Errorf("hosted network event %+v", e)
+	}
+	if name := SourceName(0x1000); name != "0x1000" {
+		t.Errorf("unknown source %q", name)
+	}
+}
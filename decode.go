@@ -0,0 +1,60 @@
+//go:build windows
+// +build windows
+
+package wlanapi
+
+import (
+	"unsafe"
+
+	"wlanapi/binary"
+)
+
+//nativeBytes copies n bytes of native memory at p, so that they can be decoded after p is freed.
+func nativeBytes(p unsafe.Pointer, n int) []byte {
+	b := make([]byte, n)
+	if n > 0 {
+		copy(b, unsafe.Slice((*byte)(p), n))
+	}
+	return b
+}
+
+//nativeList copies the list structure name at p with count elements.
+func nativeList(p unsafe.Pointer, name string, count DWORD) []byte {
+	return nativeBytes(p, binary.ListSize(binary.NativeABI(), name, int(count)))
+}
+
+//Decode decodes the interfaces of a list returned by WlanEnumInterfaces.
+func (l *WLAN_INTERFACE_INFO_LIST) Decode() ([]binary.InterfaceInfo, error) {
+	b := nativeList(unsafe.Pointer(l), "WLAN_INTERFACE_INFO_LIST", DWORD(l.dwNumberOfItems))
+	return binary.DecodeInterfaceInfoList(binary.NativeABI(), b)
+}
+
+//Decode decodes the networks of a list returned by WlanGetAvailableNetworkList.
+func (l *WLAN_AVAILABLE_NETWORK_LIST) Decode() ([]binary.AvailableNetwork, error) {
+	b := nativeList(unsafe.Pointer(l), "WLAN_AVAILABLE_NETWORK_LIST", DWORD(l.dwNumberOfItems))
+	return binary.DecodeAvailableNetworkList(binary.NativeABI(), b)
+}
+
+//Decode decodes the entries of a list returned by WlanGetNetworkBssList, including their information elements.
+func (l *WLAN_BSS_LIST) Decode() ([]binary.BSSEntry, error) {
+	b := nativeBytes(unsafe.Pointer(l), int(l.dwTotalSize))
+	return binary.DecodeBSSList(binary.NativeABI(), b)
+}
+
+//Decode decodes the networks of a list returned by WlanGetFilterList.
+func (l *DOT11_NETWORK_LIST) Decode() ([]binary.Network, error) {
+	b := nativeList(unsafe.Pointer(l), "DOT11_NETWORK_LIST", l.dwNumberOfItems)
+	return binary.DecodeNetworkList(binary.NativeABI(), b)
+}
+
+//Decode decodes the status returned by WlanHostedNetworkQueryStatus.
+func (s *WLAN_HOSTED_NETWORK_STATUS) Decode() (*binary.HostedNetworkStatus, error) {
+	b := nativeList(unsafe.Pointer(s), "WLAN_HOSTED_NETWORK_STATUS", s.dwNumberOfPeers)
+	return binary.DecodeHostedNetworkStatus(binary.NativeABI(), b)
+}
+
+//Decode decodes the profiles of a list returned by WlanGetProfileList.
+func (l *WLAN_PROFILE_INFO_LIST) Decode() ([]binary.ProfileInfo, error) {
+	b := nativeList(unsafe.Pointer(l), "WLAN_PROFILE_INFO_LIST", DWORD(l.dwNumberOfItems))
+	return binary.DecodeProfileInfoList(binary.NativeABI(), b)
+}
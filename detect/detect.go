@@ -0,0 +1,326 @@
+//Package detect finds rogue and evil twin access points by comparing BSS lists
+//with an allowlist of the networks an organization operates.
+package detect
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"time"
+
+	"wlanapi"
+	"wlanapi/binary"
+	"wlanapi/ie"
+)
+
+//Severity ranks findings.
+type Severity int
+
+const (
+	Info Severity = iota
+	Low
+	Medium
+	High
+	Critical
+)
+
+func (s Severity) String() string {
+	switch s {
+	case Info:
+		return "info"
+	case Low:
+		return "low"
+	case Medium:
+		return "medium"
+	case High:
+		return "high"
+	case Critical:
+		return "critical"
+	}
+	return fmt.Sprintf("Severity(%d)", int(s))
+}
+
+//Kind is the kind of anomaly a Finding reports.
+type Kind int
+
+const (
+	//SecurityMismatch is a BSS advertising an allowlisted SSID with other security than expected.
+	SecurityMismatch Kind = iota
+	//UnknownOUI is a BSS advertising an allowlisted SSID from a BSSID of an unexpected vendor.
+	UnknownOUI
+	//UnexpectedChannel is a BSS advertising an allowlisted SSID on a channel the network does not use.
+	UnexpectedChannel
+	//DuplicateBSSID is a BSSID seen on several channels at once.
+	DuplicateBSSID
+	//TimestampReset is a BSS whose beacon timestamp went back since the previous list.
+	TimestampReset
+)
+
+func (k Kind) String() string {
+	switch k {
+	case SecurityMismatch:
+		return "security mismatch"
+	case UnknownOUI:
+		return "unknown OUI"
+	case UnexpectedChannel:
+		return "unexpected channel"
+	case DuplicateBSSID:
+		return "duplicate BSSID"
+	case TimestampReset:
+		return "timestamp reset"
+	}
+	return fmt.Sprintf("Kind(%d)", int(k))
+}
+
+//Network is a network of the allowlist.
+type Network struct {
+	SSID string
+	//OUIs are the expected first three bytes of the BSSIDs; empty accepts any vendor.
+	OUIs []ie.OUI
+	//Open expects BSSes without security. Otherwise a BSS must advertise at least one of AKMs and no other AKM.
+	Open bool
+	AKMs []ie.AKM
+	//Ciphers are the accepted pairwise ciphers; empty accepts any.
+	Ciphers []ie.Cipher
+	//Channels are the channels the network uses; empty accepts any.
+	Channels []int
+}
+
+//security returns the name of the expected security.
+func (n *Network) security() string {
+	if n.Open {
+		return "Open"
+	}
+	return ie.Security{RSN: &ie.RSNElement{AKMs: n.AKMs}}.String()
+}
+
+//Observation is what a BSS list showed of a BSS; findings keep them as evidence.
+type Observation struct {
+	Time    time.Time
+	BSSID   [6]byte
+	SSID    []byte
+	Channel int
+	RSSI    int32
+	//Security names the advertised security, as ie.Security.String.
+	Security string
+	//Timestamp is the beacon timestamp, the microseconds the BSS has been up.
+	Timestamp uint64
+	//Interface is the interface whose BSS list showed the BSS.
+	Interface wlanapi.GUID
+}
+
+//Finding is an anomaly of a BSS.
+type Finding struct {
+	Kind     Kind
+	Severity Severity
+	SSID     string
+	BSSID    [6]byte
+	Message  string
+	Evidence []Observation
+}
+
+func (f Finding) String() string {
+	return fmt.Sprintf("%v %v %s %q: %s", f.Severity, f.Kind, net.HardwareAddr(f.BSSID[:]), f.SSID, f.Message)
+}
+
+//List is the BSS list of an interface.
+type List struct {
+	Interface wlanapi.GUID
+	Entries   []binary.BSSEntry
+}
+
+//lastKey identifies the last observation of a BSS by an interface.
+type lastKey struct {
+	iface wlanapi.GUID
+	bssid [6]byte
+}
+
+//DefaultExpiry is the Expiry of a Detector that sets none.
+const DefaultExpiry = time.Hour
+
+//Detector checks successive BSS lists. It remembers the BSSes of the allowlisted networks
+//to detect beacon timestamp resets.
+type Detector struct {
+	//Expiry is how long a BSS is remembered after a list last showed it; zero is DefaultExpiry.
+	Expiry time.Duration
+
+	networks map[string]*Network
+	last     map[lastKey]Observation
+}
+
+//NewDetector returns a Detector of a copy of the networks. BSSes advertising other SSIDs are ignored.
+func NewDetector(allowlist []Network) *Detector {
+	allowlist = append([]Network(nil), allowlist...)
+	d := &Detector{networks: map[string]*Network{}, last: map[lastKey]Observation{}}
+	for n := range allowlist {
+		d.networks[allowlist[n].SSID] = &allowlist[n]
+	}
+	return d
+}
+
+//Check returns the findings of the BSS lists of interfaces taken at now. Beacon timestamps are only compared
+//with the previous lists of the same interface, since adapters receive the beacons of a BSS at different times.
+func (d *Detector) Check(now time.Time, lists ...List) []Finding {
+	d.expire(now)
+	var findings []Finding
+	report := func(kind Kind, severity Severity, o Observation, evidence []Observation, format string, a ...interface{}) {
+		findings = append(findings, Finding{Kind: kind, Severity: severity, SSID: string(o.SSID), BSSID: o.BSSID,
+			Message: fmt.Sprintf(format, a...), Evidence: evidence})
+	}
+
+	channels := map[[6]byte][]Observation{}
+	var order [][6]byte
+	var entries []binary.BSSEntry
+	var ifaces []wlanapi.GUID
+	for _, l := range lists {
+		for _, e := range l.Entries {
+			entries = append(entries, e)
+			ifaces = append(ifaces, l.Interface)
+		}
+	}
+	for n, e := range entries {
+		network := d.networks[string(e.SSID)]
+		if network == nil {
+			continue
+		}
+		es, _ := ie.Parse(e.IEs)
+		security, err := ie.ParseSecurity(e.CapabilityInformation, es)
+		o := Observation{Time: now, BSSID: e.BSSID, SSID: e.SSID, Channel: wlanapi.ChannelOf(e.ChCenterFrequency),
+			RSSI: e.RSSI, Security: security.String(), Timestamp: e.Timestamp, Interface: ifaces[n]}
+		if err != nil {
+			o.Security = "invalid"
+		}
+		evidence := []Observation{o}
+
+		switch {
+		case err != nil:
+			report(SecurityMismatch, High, o, evidence, "malformed security elements: %v", err)
+		case network.Open:
+			if !security.Open() {
+				report(SecurityMismatch, Low, o, evidence, "advertises %s, expected Open", o.Security)
+			}
+		case security.RSN == nil && security.WPA == nil:
+			report(SecurityMismatch, Critical, o, evidence, "advertises %s, expected %s", o.Security, network.security())
+		default:
+			if akm, ok := unexpectedAKM(network, security.AKMs()); !ok {
+				report(SecurityMismatch, High, o, evidence, "advertises %s (%v), expected %s", o.Security, akm, network.security())
+			} else if cipher, ok := unexpectedCipher(network, security.PairwiseCiphers()); !ok {
+				report(SecurityMismatch, High, o, evidence, "advertises pairwise cipher %v", cipher)
+			}
+		}
+
+		if len(network.OUIs) > 0 && !containsOUI(network.OUIs, ie.OUI{e.BSSID[0], e.BSSID[1], e.BSSID[2]}) {
+			//APs derive the BSSIDs of their additional SSIDs by setting the locally administered bit,
+			//which hides their OUI.
+			if e.BSSID[0]&0x02 != 0 {
+				report(UnknownOUI, Medium, o, evidence, "locally administered BSSID does not carry an expected OUI")
+			} else {
+				report(UnknownOUI, High, o, evidence, "OUI %v is not expected", ie.OUI{e.BSSID[0], e.BSSID[1], e.BSSID[2]})
+			}
+		}
+
+		if len(network.Channels) > 0 && !containsChannel(network.Channels, o.Channel) {
+			report(UnexpectedChannel, Medium, o, evidence, "channel %d is not used by the network", o.Channel)
+		}
+
+		key := lastKey{o.Interface, e.BSSID}
+		if last, ok := d.last[key]; ok && o.Timestamp < last.Timestamp {
+			report(TimestampReset, Medium, o, []Observation{last, o}, "beacon timestamp went back from %v to %v of uptime",
+				uptime(last.Timestamp), uptime(o.Timestamp))
+		}
+		d.last[key] = o
+
+		if _, ok := channels[e.BSSID]; !ok {
+			order = append(order, e.BSSID)
+		}
+		channels[e.BSSID] = append(channels[e.BSSID], o)
+	}
+
+	for _, bssid := range order {
+		observations := channels[bssid]
+		var seen []int
+		for _, o := range observations {
+			if !containsChannel(seen, o.Channel) {
+				seen = append(seen, o.Channel)
+			}
+		}
+		if len(seen) > 1 {
+			sort.Ints(seen)
+			report(DuplicateBSSID, High, observations[0], observations, "seen on channels %v at once", seen)
+		}
+	}
+	return findings
+}
+
+//expire forgets the BSSes that no list showed within the Expiry before now.
+func (d *Detector) expire(now time.Time) {
+	expiry := d.Expiry
+	if expiry == 0 {
+		expiry = DefaultExpiry
+	}
+	for key, o := range d.last {
+		if now.Sub(o.Time) > expiry {
+			delete(d.last, key)
+		}
+	}
+}
+
+//Forget drops what the Detector remembers of the BSSes, as after the access points were restarted on purpose.
+func (d *Detector) Forget() {
+	d.last = map[lastKey]Observation{}
+}
+
+func uptime(timestamp uint64) time.Duration {
+	return (time.Duration(timestamp) * time.Microsecond).Round(time.Millisecond)
+}
+
+//unexpectedAKM returns the first AKM that is not allowed, or false if none of akms is allowed.
+func unexpectedAKM(network *Network, akms []ie.AKM) (ie.AKM, bool) {
+	allowed := false
+	for _, a := range akms {
+		found := false
+		for _, b := range network.AKMs {
+			found = found || a == b
+		}
+		if !found {
+			return a, false
+		}
+		allowed = true
+	}
+	return 0, allowed
+}
+
+//unexpectedCipher returns the first cipher that is not allowed.
+func unexpectedCipher(network *Network, ciphers []ie.Cipher) (ie.Cipher, bool) {
+	if len(network.Ciphers) == 0 {
+		return 0, true
+	}
+	for _, c := range ciphers {
+		found := false
+		for _, d := range network.Ciphers {
+			found = found || c == d
+		}
+		if !found {
+			return c, false
+		}
+	}
+	return 0, true
+}
+
+func containsOUI(ouis []ie.OUI, oui ie.OUI) bool {
+	for _, o := range ouis {
+		if o == oui {
+			return true
+		}
+	}
+	return false
+}
+
+func containsChannel(channels []int, channel int) bool {
+	for _, c := range channels {
+		if c == channel {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,161 @@
+package detect
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"wlanapi"
+	"wlanapi/binary"
+	"wlanapi/ie"
+)
+
+var (
+	rsnEnterprise = []byte{48, 20, 1, 0, 0, 0x0f, 0xac, 4, 1, 0, 0, 0x0f, 0xac, 4, 1, 0, 0, 0x0f, 0xac, 1, 0, 0}
+	rsnPSK        = []byte{48, 20, 1, 0, 0, 0x0f, 0xac, 4, 1, 0, 0, 0x0f, 0xac, 4, 1, 0, 0, 0x0f, 0xac, 2, 0, 0}
+	rsnTKIP       = []byte{48, 20, 1, 0, 0, 0x0f, 0xac, 2, 1, 0, 0, 0x0f, 0xac, 2, 1, 0, 0, 0x0f, 0xac, 1, 0, 0}
+)
+
+var allowlist = []Network{
+	{SSID: "corp", OUIs: []ie.OUI{{0x00, 0x1a, 0x1e}}, AKMs: []ie.AKM{ie.AKM8021X, ie.AKMFT8021X},
+		Ciphers: []ie.Cipher{ie.CipherCCMP}, Channels: []int{1, 6, 11, 36, 40}},
+	{SSID: "guest", Open: true},
+}
+
+func entry(ssid string, bssid [6]byte, khz uint32, capability uint16, timestamp uint64, ies []byte) binary.BSSEntry {
+	return binary.BSSEntry{SSID: []byte(ssid), BSSID: bssid, BssType: 1, RSSI: -60, ChCenterFrequency: khz,
+		CapabilityInformation: capability, Timestamp: timestamp, IEs: append([]byte{0, byte(len(ssid))}, append([]byte(ssid), ies...)...)}
+}
+
+var (
+	corpAP  = [6]byte{0x00, 0x1a, 0x1e, 0x01, 0x02, 0x03}
+	corpAP2 = [6]byte{0x00, 0x1a, 0x1e, 0x01, 0x02, 0x04}
+)
+
+func TestCheck(t *testing.T) {
+	d := NewDetector(allowlist)
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	findings := d.Check(now, List{Entries: []binary.BSSEntry{
+		entry("corp", corpAP, 2437000, 0x0011, 5e9, rsnEnterprise),
+		entry("guest", [6]byte{0x00, 0x1a, 0x1e, 9, 9, 9}, 2437000, 0x0001, 5e9, nil),
+		entry("neighbour", [6]byte{0xde, 0xad, 0xbe, 0xef, 0, 1}, 2412000, 0x0001, 1, nil),
+	}})
+	if len(findings) != 0 {
+		t.Fatalf("findings on the expected networks: %v", findings)
+	}
+
+	tests := []struct {
+		entry    binary.BSSEntry
+		kind     Kind
+		severity Severity
+		message  string
+	}{
+		{entry("corp", corpAP2, 2437000, 0x0001, 1e6, nil), SecurityMismatch, Critical, "advertises Open, expected WPA2-Enterprise"},
+		{entry("corp", corpAP2, 2437000, 0x0011, 1e6, nil), SecurityMismatch, Critical, "advertises WEP"},
+		{entry("corp", corpAP2, 2437000, 0x0011, 1e6, rsnPSK), SecurityMismatch, High, "advertises WPA2-Personal (PSK)"},
+		{entry("corp", corpAP2, 2437000, 0x0011, 1e6, rsnTKIP), SecurityMismatch, High, "pairwise cipher TKIP"},
+		{entry("corp", corpAP2, 2437000, 0x0011, 1e6, []byte{48, 8, 1, 0, 0, 0x0f, 0xac, 4, 1, 0}), SecurityMismatch, High, "malformed"},
+		{entry("guest", corpAP2, 2437000, 0x0011, 1e6, rsnPSK), SecurityMismatch, Low, "expected Open"},
+		{entry("corp", [6]byte{0x02, 0x11, 0x22, 1, 2, 3}, 2437000, 0x0011, 1e6, rsnEnterprise), UnknownOUI, Medium, "locally administered"},
+		{entry("corp", [6]byte{0xb8, 0x27, 0xeb, 1, 2, 3}, 2437000, 0x0011, 1e6, rsnEnterprise), UnknownOUI, High, "b8:27:eb"},
+		{entry("corp", corpAP2, 2462000+5000*2, 0x0011, 1e6, rsnEnterprise), UnexpectedChannel, Medium, "channel 13"},
+	}
+	for _, tt := range tests {
+		findings := NewDetector(allowlist).Check(now, List{Entries: []binary.BSSEntry{tt.entry}})
+		if len(findings) != 1 {
+			t.Errorf("%s: %d findings %v", tt.message, len(findings), findings)
+			continue
+		}
+		f := findings[0]
+		if f.Kind != tt.kind || f.Severity != tt.severity || !strings.Contains(f.Message, tt.message) ||
+			f.BSSID != tt.entry.BSSID || len(f.Evidence) != 1 || f.Evidence[0].Time != now {
+			t.Errorf("%s: %v", tt.message, f)
+		}
+	}
+}
+
+func TestCheckDuplicateBSSID(t *testing.T) {
+	findings := NewDetector(allowlist).Check(time.Now(), List{Entries: []binary.BSSEntry{
+		entry("corp", corpAP, 2437000, 0x0011, 5e9, rsnEnterprise),
+		entry("corp", corpAP2, 2437000, 0x0011, 5e9, rsnEnterprise),
+		entry("corp", corpAP, 2412000, 0x0011, 7e9, rsnEnterprise),
+	}})
+	if len(findings) != 1 {
+		t.Fatalf("findings %v", findings)
+	}
+	f := findings[0]
+	if f.Kind != DuplicateBSSID || f.Severity != High || f.BSSID != corpAP || len(f.Evidence) != 2 ||
+		f.Evidence[0].Channel != 6 || f.Evidence[1].Channel != 1 || !strings.Contains(f.Message, "[1 6]") {
+		t.Errorf("%v %+v", f, f.Evidence)
+	}
+}
+
+func TestCheckTimestampReset(t *testing.T) {
+	d := NewDetector(allowlist)
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	if findings := d.Check(start, List{Entries: []binary.BSSEntry{entry("corp", corpAP, 2437000, 0x0011, 3600e6, rsnEnterprise)}}); len(findings) != 0 {
+		t.Fatalf("findings %v", findings)
+	}
+	if findings := d.Check(start.Add(time.Minute), List{Entries: []binary.BSSEntry{entry("corp", corpAP, 2437000, 0x0011, 3660e6, rsnEnterprise)}}); len(findings) != 0 {
+		t.Fatalf("findings %v", findings)
+	}
+	findings := d.Check(start.Add(2*time.Minute), List{Entries: []binary.BSSEntry{entry("corp", corpAP, 2437000, 0x0011, 1500e3, rsnEnterprise)}})
+	if len(findings) != 1 {
+		t.Fatalf("findings %v", findings)
+	}
+	f := findings[0]
+	if f.Kind != TimestampReset || len(f.Evidence) != 2 || f.Evidence[0].Timestamp != 3660e6 || f.Evidence[1].Timestamp != 1500e3 ||
+		!strings.Contains(f.Message, "1h1m0s to 1.5s") {
+		t.Errorf("%v %+v", f, f.Evidence)
+	}
+
+	d.Forget()
+	if findings := d.Check(start.Add(3*time.Minute), List{Entries: []binary.BSSEntry{entry("corp", corpAP, 2437000, 0x0011, 1e3, rsnEnterprise)}}); len(findings) != 0 {
+		t.Fatalf("findings after Forget %v", findings)
+	}
+}
+
+//TestCheckTimestampInterfaces checks that the timestamps that adapters saw at different times are not resets.
+func TestCheckTimestampInterfaces(t *testing.T) {
+	d := NewDetector(allowlist)
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	usb, internal := wlanapi.GUID{Data1: 1}, wlanapi.GUID{Data1: 2}
+	for n := uint64(0); n < 2; n++ {
+		findings := d.Check(start.Add(time.Duration(n)*time.Minute),
+			List{Interface: usb, Entries: []binary.BSSEntry{entry("corp", corpAP, 2437000, 0x0011, 3660e6+n*60e6, rsnEnterprise)}},
+			List{Interface: internal, Entries: []binary.BSSEntry{entry("corp", corpAP, 2437000, 0x0011, 3600e6+n*60e6, rsnEnterprise)}})
+		if len(findings) != 0 {
+			t.Fatalf("findings %v", findings)
+		}
+	}
+	findings := d.Check(start.Add(2*time.Minute),
+		List{Interface: internal, Entries: []binary.BSSEntry{entry("corp", corpAP, 2437000, 0x0011, 1500e3, rsnEnterprise)}})
+	if len(findings) != 1 || findings[0].Kind != TimestampReset || findings[0].Evidence[0].Interface != internal ||
+		findings[0].Evidence[0].Timestamp != 3660e6 {
+		t.Errorf("findings %v", findings)
+	}
+}
+
+func TestDetectorExpiry(t *testing.T) {
+	networks := append([]Network(nil), allowlist...)
+	d := NewDetector(networks)
+	d.Expiry = 10 * time.Minute
+	networks[0].SSID = "other"
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	d.Check(start, List{Entries: []binary.BSSEntry{
+		entry("corp", corpAP, 2437000, 0x0011, 3600e6, rsnEnterprise),
+		entry("corp", corpAP2, 2437000, 0x0011, 3600e6, rsnEnterprise),
+	}})
+	if len(d.last) != 2 {
+		t.Fatalf("remembered %d BSSes of the allowlist as given, want 2", len(d.last))
+	}
+	d.Check(start.Add(5*time.Minute), List{Entries: []binary.BSSEntry{entry("corp", corpAP, 2437000, 0x0011, 3900e6, rsnEnterprise)}})
+	d.Check(start.Add(12*time.Minute), List{})
+	if _, ok := d.last[lastKey{bssid: corpAP}]; len(d.last) != 1 || !ok {
+		t.Errorf("remembered %v after the expiry of the second BSS", d.last)
+	}
+	//A BSS seen again after it expired is new, so its timestamp is not compared.
+	if findings := d.Check(start.Add(30*time.Minute), List{Entries: []binary.BSSEntry{entry("corp", corpAP, 2437000, 0x0011, 1e3, rsnEnterprise)}}); len(findings) != 0 {
+		t.Errorf("findings of an expired BSS %v", findings)
+	}
+}
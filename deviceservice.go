@@ -0,0 +1,142 @@
+//go:build windows
+// +build windows
+
+package wlanapi
+
+import (
+	"runtime"
+	"syscall"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+const (
+	//deviceServiceBufferSize is the first output buffer size tried by DeviceServiceCommand.
+	deviceServiceBufferSize = 1024
+	//maxDeviceServiceBufferSize bounds the output buffer growth of DeviceServiceCommand.
+	maxDeviceServiceBufferSize = 1 << 20
+)
+
+//DeviceServices retrieves the device services supported by the interface.
+func (i *Interface) DeviceServices() ([]windows.GUID, error) {
+	list, err := WlanGetSupportedDeviceServices(i.client.handle, &i.GUID)
+	if err != nil {
+		return nil, err
+	}
+	defer WlanFreeMemory(PVOID(unsafe.Pointer(list)))
+
+	services := make([]windows.GUID, list.dwNumberOfItems)
+	for n := range services {
+		services[n] = windows.GUID(list.DeviceService[n])
+	}
+	return services, nil
+}
+
+//DeviceServiceCommand sends opcode with the in buffer to the device service svc and returns its response.
+//The output buffer is grown until the response fits.
+func (i *Interface) DeviceServiceCommand(svc windows.GUID, opcode uint32, in []byte) ([]byte, error) {
+	var pIn *BYTE
+	if len(in) > 0 {
+		pIn = (*BYTE)(unsafe.Pointer(&in[0]))
+	}
+	return growBuffer(deviceServiceBufferSize, maxDeviceServiceBufferSize, func(out []byte) (int, error) {
+		n, err := WlanDeviceServiceCommand(i.client.handle, &i.GUID, &svc, DWORD(opcode),
+			DWORD(len(in)), pIn, DWORD(len(out)), (*BYTE)(unsafe.Pointer(&out[0])))
+		runtime.KeepAlive(in)
+		runtime.KeepAlive(out)
+		return int(n), err
+	})
+}
+
+//DeviceServiceNotification is an unsolicited notification sent by a device service.
+type DeviceServiceNotification struct {
+	InterfaceGuid windows.GUID
+	DeviceService windows.GUID
+	OpCode        uint32
+	Data          []byte
+}
+
+//DeviceServiceNotifications subscribes to notifications of the given device services on all interfaces.
+//The returned function cancels the subscription and closes the channel.
+func (c *Client) DeviceServiceNotifications(services ...windows.GUID) (<-chan DeviceServiceNotification, func(), error) {
+	if err := c.addDeviceServices(services, 1); err != nil {
+		return nil, nil, err
+	}
+	notifications, cancel, err := c.Subscribe(WLAN_NOTIFICATION_SOURCE_DEVICE_SERVICE)
+	if err != nil {
+		c.addDeviceServices(services, -1)
+		return nil, nil, err
+	}
+
+	wanted := make(map[windows.GUID]bool, len(services))
+	for _, svc := range services {
+		wanted[svc] = true
+	}
+	ch := make(chan DeviceServiceNotification, notificationBuffer)
+	go func() {
+		defer close(ch)
+		for n := range notifications {
+			dsn, ok := parseDeviceServiceNotification(n)
+			if !ok || !wanted[dsn.DeviceService] {
+				continue
+			}
+			select {
+			case ch <- dsn:
+			default:
+			}
+		}
+	}()
+	return ch, func() {
+		cancel()
+		c.addDeviceServices(services, -1)
+	}, nil
+}
+
+//addDeviceServices adjusts the reference count of each service by delta and registers
+//the services that are still referenced with WlanRegisterDeviceServiceNotification.
+func (c *Client) addDeviceServices(services []windows.GUID, delta int) error {
+	c.registerMu.Lock()
+	defer c.registerMu.Unlock()
+	if c.services == nil {
+		c.services = map[windows.GUID]int{}
+	}
+	for _, svc := range services {
+		c.services[svc] += delta
+		if c.services[svc] <= 0 {
+			delete(c.services, svc)
+		}
+	}
+
+	if len(c.services) == 0 {
+		return WlanRegisterDeviceServiceNotification(c.handle, nil)
+	}
+	if len(c.services) > MAX_INDEX+1 {
+		return windows.ERROR_INVALID_PARAMETER
+	}
+	list := new(WLAN_DEVICE_SERVICE_GUID_LIST)
+	for svc := range c.services {
+		list.DeviceService[list.dwNumberOfItems] = syscall.GUID(svc)
+		list.dwNumberOfItems++
+	}
+	return WlanRegisterDeviceServiceNotification(c.handle, list)
+}
+
+func parseDeviceServiceNotification(n Notification) (dsn DeviceServiceNotification, ok bool) {
+	offset := int(unsafe.Offsetof(WLAN_DEVICE_SERVICE_NOTIFICATION_DATA{}.DataBlob))
+	if len(n.Data) < offset {
+		return
+	}
+	data := (*WLAN_DEVICE_SERVICE_NOTIFICATION_DATA)(unsafe.Pointer(&n.Data[0]))
+	end := offset + int(data.dwDataSize)
+	if end > len(n.Data) {
+		end = len(n.Data)
+	}
+	dsn = DeviceServiceNotification{
+		InterfaceGuid: n.InterfaceGuid,
+		DeviceService: windows.GUID(data.DeviceService),
+		OpCode:        uint32(data.dwOpCode),
+		Data:          append([]byte(nil), n.Data[offset:end]...),
+	}
+	return dsn, true
+}
@@ -1,5 +1,7 @@
 package wlanapi
 
+import "fmt"
+
 //The WFD_DISPLAY_SINK_NOTIFICATION_TYPE enumerated type defines the type of the notification passed to the WFD_DISPLAY_SINK_NOTIFICATION_CALLBACK function.
 //https://docs.microsoft.com/en-us/windows/win32/nativewifi/wfd-display-sink-notification-type
 type WFD_DISPLAY_SINK_NOTIFICATION_TYPE uint32
@@ -23,6 +25,10 @@ const (
 	DOT11_AUTH_ALGO_WPA_NONE         DOT11_AUTH_ALGORITHM = 5
 	DOT11_AUTH_ALGO_RSNA             DOT11_AUTH_ALGORITHM = 6
 	DOT11_AUTH_ALGO_RSNA_PSK         DOT11_AUTH_ALGORITHM = 7
+	DOT11_AUTH_ALGO_WPA3             DOT11_AUTH_ALGORITHM = 8
+	DOT11_AUTH_ALGO_WPA3_SAE         DOT11_AUTH_ALGORITHM = 9
+	DOT11_AUTH_ALGO_OWE              DOT11_AUTH_ALGORITHM = 10
+	DOT11_AUTH_ALGO_WPA3_ENT         DOT11_AUTH_ALGORITHM = 11
 	DOT11_AUTH_ALGO_IHV_START        DOT11_AUTH_ALGORITHM = 0x80000000
 	DOT11_AUTH_ALGO_IHV_END          DOT11_AUTH_ALGORITHM = 0xffffffff
 )
@@ -32,11 +38,54 @@ const (
 type DOT11_BSS_TYPE uint32
 
 const (
-	dot11_BSS_type_infrastructure DOT11_BSS_TYPE = 1
-	dot11_BSS_type_independent    DOT11_BSS_TYPE = 2
-	dot11_BSS_type_any            DOT11_BSS_TYPE = 3
+	Dot11BssTypeInfrastructure DOT11_BSS_TYPE = 1
+	Dot11BssTypeIndependent    DOT11_BSS_TYPE = 2
+	Dot11BssTypeAny            DOT11_BSS_TYPE = 3
 )
 
+func (t DOT11_BSS_TYPE) String() string {
+	switch t {
+	case Dot11BssTypeInfrastructure:
+		return "infrastructure"
+	case Dot11BssTypeIndependent:
+		return "independent"
+	case Dot11BssTypeAny:
+		return "any"
+	}
+	return fmt.Sprintf("DOT11_BSS_TYPE(%d)", uint32(t))
+}
+
+func (a DOT11_AUTH_ALGORITHM) String() string {
+	switch a {
+	case DOT11_AUTH_ALGO_80211_OPEN:
+		return "Open"
+	case DOT11_AUTH_ALGO_80211_SHARED_KEY:
+		return "Shared"
+	case DOT11_AUTH_ALGO_WPA:
+		return "WPA-Enterprise"
+	case DOT11_AUTH_ALGO_WPA_PSK:
+		return "WPA-Personal"
+	case DOT11_AUTH_ALGO_WPA_NONE:
+		return "WPA-None"
+	case DOT11_AUTH_ALGO_RSNA:
+		return "WPA2-Enterprise"
+	case DOT11_AUTH_ALGO_RSNA_PSK:
+		return "WPA2-Personal"
+	case DOT11_AUTH_ALGO_WPA3:
+		return "WPA3-Enterprise-192"
+	case DOT11_AUTH_ALGO_WPA3_SAE:
+		return "WPA3-Personal"
+	case DOT11_AUTH_ALGO_OWE:
+		return "OWE"
+	case DOT11_AUTH_ALGO_WPA3_ENT:
+		return "WPA3-Enterprise"
+	}
+	if a >= DOT11_AUTH_ALGO_IHV_START {
+		return fmt.Sprintf("IHV(0x%x)", uint32(a))
+	}
+	return fmt.Sprintf("DOT11_AUTH_ALGORITHM(%d)", uint32(a))
+}
+
 //The DOT11_CIPHER_ALGORITHM enumerated type defines a cipher algorithm for data encryption and decryption.
 //https://docs.microsoft.com/en-us/windows/win32/nativewifi/dot11-cipher-algorithm
 type DOT11_CIPHER_ALGORITHM uint32
@@ -47,6 +96,10 @@ const (
 	DOT11_CIPHER_ALGO_TKIP          DOT11_CIPHER_ALGORITHM = 0x02
 	DOT11_CIPHER_ALGO_CCMP          DOT11_CIPHER_ALGORITHM = 0x04
 	DOT11_CIPHER_ALGO_WEP104        DOT11_CIPHER_ALGORITHM = 0x05
+	DOT11_CIPHER_ALGO_BIP           DOT11_CIPHER_ALGORITHM = 0x06
+	DOT11_CIPHER_ALGO_GCMP          DOT11_CIPHER_ALGORITHM = 0x08
+	DOT11_CIPHER_ALGO_GCMP_256      DOT11_CIPHER_ALGORITHM = 0x09
+	DOT11_CIPHER_ALGO_CCMP_256      DOT11_CIPHER_ALGORITHM = 0x0a
 	DOT11_CIPHER_ALGO_WPA_USE_GROUP DOT11_CIPHER_ALGORITHM = 0x100
 	DOT11_CIPHER_ALGO_RSN_USE_GROUP DOT11_CIPHER_ALGORITHM = 0x100
 	DOT11_CIPHER_ALGO_WEP           DOT11_CIPHER_ALGORITHM = 0x101
@@ -54,6 +107,37 @@ const (
 	DOT11_CIPHER_ALGO_IHV_END       DOT11_CIPHER_ALGORITHM = 0xffffffff
 )
 
+func (c DOT11_CIPHER_ALGORITHM) String() string {
+	switch c {
+	case DOT11_CIPHER_ALGO_NONE:
+		return "None"
+	case DOT11_CIPHER_ALGO_WEP40:
+		return "WEP-40"
+	case DOT11_CIPHER_ALGO_TKIP:
+		return "TKIP"
+	case DOT11_CIPHER_ALGO_CCMP:
+		return "CCMP"
+	case DOT11_CIPHER_ALGO_WEP104:
+		return "WEP-104"
+	case DOT11_CIPHER_ALGO_BIP:
+		return "BIP"
+	case DOT11_CIPHER_ALGO_GCMP:
+		return "GCMP"
+	case DOT11_CIPHER_ALGO_GCMP_256:
+		return "GCMP-256"
+	case DOT11_CIPHER_ALGO_CCMP_256:
+		return "CCMP-256"
+	case DOT11_CIPHER_ALGO_WPA_USE_GROUP:
+		return "Use group key"
+	case DOT11_CIPHER_ALGO_WEP:
+		return "WEP"
+	}
+	if c >= DOT11_CIPHER_ALGO_IHV_START {
+		return fmt.Sprintf("IHV(0x%x)", uint32(c))
+	}
+	return fmt.Sprintf("DOT11_CIPHER_ALGORITHM(%d)", uint32(c))
+}
+
 //The DOT11_PHY_TYPE enumeration defines an 802.11 PHY and media type.
 //https://docs.microsoft.com/en-us/windows/win32/nativewifi/dot11-phy-type
 type DOT11_PHY_TYPE uint32
@@ -69,10 +153,46 @@ const (
 	dot11_phy_type_erp                   DOT11_PHY_TYPE = 6
 	dot11_phy_type_ht                    DOT11_PHY_TYPE = 7
 	dot11_phy_type_vht                   DOT11_PHY_TYPE = 8
+	dot11_phy_type_dmg                   DOT11_PHY_TYPE = 9
+	dot11_phy_type_he                    DOT11_PHY_TYPE = 10
+	dot11_phy_type_eht                   DOT11_PHY_TYPE = 11
 	dot11_phy_type_IHV_start             DOT11_PHY_TYPE = 0x80000000
 	dot11_phy_type_IHV_end               DOT11_PHY_TYPE = 0xffffffff
 )
 
+func (t DOT11_PHY_TYPE) String() string {
+	switch t {
+	case dot11_phy_type_unknownDOT11_PHY_TYPE:
+		return "unknown"
+	case dot11_phy_type_fhss:
+		return "FHSS"
+	case dot11_phy_type_dsss:
+		return "DSSS"
+	case dot11_phy_type_irbaseband:
+		return "IR baseband"
+	case dot11_phy_type_ofdm:
+		return "802.11a"
+	case dot11_phy_type_hrdsss:
+		return "802.11b"
+	case dot11_phy_type_erp:
+		return "802.11g"
+	case dot11_phy_type_ht:
+		return "802.11n"
+	case dot11_phy_type_vht:
+		return "802.11ac"
+	case dot11_phy_type_dmg:
+		return "802.11ad"
+	case dot11_phy_type_he:
+		return "802.11ax"
+	case dot11_phy_type_eht:
+		return "802.11be"
+	}
+	if t >= dot11_phy_type_IHV_start {
+		return fmt.Sprintf("IHV(0x%x)", uint32(t))
+	}
+	return fmt.Sprintf("DOT11_PHY_TYPE(%d)", uint32(t))
+}
+
 //The DOT11_RADIO_STATE enumeration specifies an 802.11 radio state.
 //https://docs.microsoft.com/en-us/windows/win32/api/wlanapi/ne-wlanapi-dot11_radio_state-r1
 type DOT11_RADIO_STATE uint32
@@ -106,6 +226,22 @@ const (
 	wlan_connection_mode_invalid
 )
 
+func (m WLAN_CONNECTION_MODE) String() string {
+	switch m {
+	case wlan_connection_mode_profile:
+		return "profile"
+	case wlan_connection_mode_temporary_profile:
+		return "temporary profile"
+	case wlan_connection_mode_discovery_secure:
+		return "discovery secure"
+	case wlan_connection_mode_discovery_unsecure:
+		return "discovery unsecure"
+	case wlan_connection_mode_auto:
+		return "auto"
+	}
+	return fmt.Sprintf("WLAN_CONNECTION_MODE(%d)", uint32(m))
+}
+
 //The WLAN_FILTER_LIST_TYPE enumerated type indicates types of filter lists.
 //https://docs.microsoft.com/en-us/windows/win32/api/wlanapi/ne-wlanapi-wlan_filter_list_type
 type WLAN_FILTER_LIST_TYPE uint32
@@ -121,12 +257,25 @@ const (
 //https://docs.microsoft.com/en-us/windows/win32/api/wlanapi/ne-wlanapi-wlan_hosted_network_notification_code
 type WLAN_HOSTED_NETWORK_NOTIFICATION_CODE uint32
 
+//The codes follow L2_NOTIFICATION_CODE_V2_BEGIN.
 const (
-	wlan_hosted_network_state_change WLAN_HOSTED_NETWORK_NOTIFICATION_CODE = iota
+	wlan_hosted_network_state_change WLAN_HOSTED_NETWORK_NOTIFICATION_CODE = 0x00001000 + iota
 	wlan_hosted_network_peer_state_change
 	wlan_hosted_network_radio_state_change
 )
 
+func (c WLAN_HOSTED_NETWORK_NOTIFICATION_CODE) String() string {
+	switch c {
+	case wlan_hosted_network_state_change:
+		return "state change"
+	case wlan_hosted_network_peer_state_change:
+		return "peer state change"
+	case wlan_hosted_network_radio_state_change:
+		return "radio state change"
+	}
+	return fmt.Sprintf("WLAN_HOSTED_NETWORK_NOTIFICATION_CODE(%d)", uint32(c))
+}
+
 //The WLAN_HOSTED_NETWORK_OPCODE enumerated type specifies the possible values of the operation code for the properties to query or set on the wireless Hosted Network.
 //https://docs.microsoft.com/en-us/windows/win32/api/wlanapi/ne-wlanapi-wlan_hosted_network_opcode
 type WLAN_HOSTED_NETWORK_OPCODE uint32
@@ -147,6 +296,16 @@ const (
 	wlan_hosted_network_peer_state_authenticated
 )
 
+func (s WLAN_HOSTED_NETWORK_PEER_AUTH_STATE) String() string {
+	switch s {
+	case wlan_hosted_network_peer_state_invalid:
+		return "invalid"
+	case wlan_hosted_network_peer_state_authenticated:
+		return "authenticated"
+	}
+	return fmt.Sprintf("WLAN_HOSTED_NETWORK_PEER_AUTH_STATE(%d)", uint32(s))
+}
+
 //The WLAN_HOSTED_NETWORK_REASON enumerated type specifies the possible values for the result of a wireless Hosted Network function call.
 //https://docs.microsoft.com/en-us/windows/win32/api/wlanapi/ne-wlanapi-wlan_hosted_network_reason
 type WLAN_HOSTED_NETWORK_REASON uint32
@@ -193,6 +352,18 @@ const (
 	wlan_hosted_network_active
 )
 
+func (s WLAN_HOSTED_NETWORK_STATE) String() string {
+	switch s {
+	case wlan_hosted_network_unavailable:
+		return "unavailable"
+	case wlan_hosted_network_idle:
+		return "idle"
+	case wlan_hosted_network_active:
+		return "active"
+	}
+	return fmt.Sprintf("WLAN_HOSTED_NETWORK_STATE(%d)", uint32(s))
+}
+
 //The WLAN_INTERFACE_TYPE enumeration specifies the wireless interface type.
 //https://docs.microsoft.com/en-us/windows/win32/api/wlanapi/ne-wlanapi-wlan_interface_type
 type WLAN_INTERFACE_TYPE uint32
@@ -203,6 +374,53 @@ const (
 	wlan_interface_type_invalid
 )
 
+func (t WLAN_INTERFACE_TYPE) String() string {
+	switch t {
+	case wlan_interface_type_emulated_802_11:
+		return "emulated 802.11"
+	case wlan_interface_type_native_802_11:
+		return "native 802.11"
+	}
+	return "invalid"
+}
+
+//The WLAN_INTERFACE_STATE enumerated type indicates the state of an interface.
+//https://docs.microsoft.com/en-us/windows/win32/api/wlanapi/ne-wlanapi-wlan_interface_state-r1
+type WLAN_INTERFACE_STATE uint32
+
+const (
+	wlan_interface_state_not_ready WLAN_INTERFACE_STATE = iota
+	wlan_interface_state_connected
+	wlan_interface_state_ad_hoc_network_formed
+	wlan_interface_state_disconnecting
+	wlan_interface_state_disconnected
+	wlan_interface_state_associating
+	wlan_interface_state_discovering
+	wlan_interface_state_authenticating
+)
+
+func (s WLAN_INTERFACE_STATE) String() string {
+	switch s {
+	case wlan_interface_state_not_ready:
+		return "not ready"
+	case wlan_interface_state_connected:
+		return "connected"
+	case wlan_interface_state_ad_hoc_network_formed:
+		return "ad hoc network formed"
+	case wlan_interface_state_disconnecting:
+		return "disconnecting"
+	case wlan_interface_state_disconnected:
+		return "disconnected"
+	case wlan_interface_state_associating:
+		return "associating"
+	case wlan_interface_state_discovering:
+		return "discovering"
+	case wlan_interface_state_authenticating:
+		return "authenticating"
+	}
+	return fmt.Sprintf("WLAN_INTERFACE_STATE(%d)", uint32(s))
+}
+
 //The WLAN_SECURABLE_OBJECT enumerated type defines the securable objects used by Native Wifi Functions.
 //https://docs.microsoft.com/en-us/windows/win32/api/wlanapi/ne-wlanapi-wlan_securable_object
 type WLAN_SECURABLE_OBJECT uint32
@@ -244,8 +462,8 @@ const (
 type WLAN_IHV_CONTROL_TYPE uint32
 
 const (
-	wlan_ihv_control_type_service WLAN_IHV_CONTROL_TYPE = iota
-	wlan_ihv_control_type_driver
+	WlanIhvControlTypeService WLAN_IHV_CONTROL_TYPE = iota
+	WlanIhvControlTypeDriver
 )
 
 //The WLAN_AUTOCONF_OPCODE enumerated type specifies an automatic configuration parameter.
@@ -287,13 +505,67 @@ const (
 	WlanIntfOpcodeManagementFrameProtectionCapable
 	WlanIntfOpcodeSecondaryStaInterfaces
 	WlanIntfOpcodeSecondaryStaSynchronizedConnections
-	WlanIntfOpcodeAutoconfEnd WLAN_INTF_OPCODE = 0x0fffffff
-	WlanIntfOpcodeMsmStart    WLAN_INTF_OPCODE = 0x10000100
-	WlanIntfOpcodeStatistics
-	WlanIntfOpcodeRssi
-	WlanIntfOpcodeMsmEnd        = 0x1fffffff
-	WlanIntfOpcodeSecurityStart = 0x20010000
-	WlanIntfOpcodeSecurityEnd   = 0x2fffffff
-	WlanIntfOpcodeIhvStart      = 0x30000000
-	WlanIntfOpcodeIhvEnd        = 0x3fffffff
+	WlanIntfOpcodeAutoconfEnd   WLAN_INTF_OPCODE = 0x0fffffff
+	WlanIntfOpcodeMsmStart      WLAN_INTF_OPCODE = 0x10000100
+	WlanIntfOpcodeStatistics    WLAN_INTF_OPCODE = 0x10000101
+	WlanIntfOpcodeRssi          WLAN_INTF_OPCODE = 0x10000102
+	WlanIntfOpcodeMsmEnd                         = 0x1fffffff
+	WlanIntfOpcodeSecurityStart                  = 0x20010000
+	WlanIntfOpcodeSecurityEnd                    = 0x2fffffff
+	WlanIntfOpcodeIhvStart                       = 0x30000000
+	WlanIntfOpcodeIhvEnd                         = 0x3fffffff
 )
+
+//The WLAN_NOTIFICATION_ACM enumerated type specifies the possible values of the NotificationCode member of
+//WLAN_NOTIFICATION_DATA for the Auto Configuration Module (WLAN_NOTIFICATION_SOURCE_ACM).
+//https://docs.microsoft.com/en-us/windows/win32/api/wlanapi/ne-wlanapi-wlan_notification_acm-r1
+type WLAN_NOTIFICATION_ACM uint32
+
+const (
+	wlanNotificationAcmStart WLAN_NOTIFICATION_ACM = iota
+	WlanNotificationAcmAutoconfEnabled
+	WlanNotificationAcmAutoconfDisabled
+	WlanNotificationAcmBackgroundScanEnabled
+	WlanNotificationAcmBackgroundScanDisabled
+	WlanNotificationAcmBssTypeChange
+	WlanNotificationAcmPowerSettingChange
+	WlanNotificationAcmScanComplete
+	WlanNotificationAcmScanFail
+	WlanNotificationAcmConnectionStart
+	WlanNotificationAcmConnectionComplete
+	WlanNotificationAcmConnectionAttemptFail
+	WlanNotificationAcmFilterListChange
+	WlanNotificationAcmInterfaceArrival
+	WlanNotificationAcmInterfaceRemoval
+	WlanNotificationAcmProfileChange
+	WlanNotificationAcmProfileNameChange
+	WlanNotificationAcmProfilesExhausted
+	WlanNotificationAcmNetworkNotAvailable
+	WlanNotificationAcmNetworkAvailable
+	WlanNotificationAcmDisconnecting
+	WlanNotificationAcmDisconnected
+	WlanNotificationAcmAdhocNetworkStateChange
+	WlanNotificationAcmProfileUnblocked
+	WlanNotificationAcmScreenPowerChange
+	WlanNotificationAcmProfileBlocked
+	WlanNotificationAcmScanListRefresh
+	WlanNotificationAcmOperationalStateChange
+	wlanNotificationAcmEnd
+)
+
+var acmNames = [...]string{
+	"autoconf enabled", "autoconf disabled", "background scan enabled", "background scan disabled",
+	"BSS type change", "power setting change", "scan complete", "scan fail", "connection start",
+	"connection complete", "connection attempt fail", "filter list change", "interface arrival",
+	"interface removal", "profile change", "profile name change", "profiles exhausted",
+	"network not available", "network available", "disconnecting", "disconnected",
+	"ad hoc network state change", "profile unblocked", "screen power change", "profile blocked",
+	"scan list refresh", "operational state change",
+}
+
+func (c WLAN_NOTIFICATION_ACM) String() string {
+	if c > wlanNotificationAcmStart && c < wlanNotificationAcmEnd {
+		return acmNames[c-1]
+	}
+	return fmt.Sprintf("WLAN_NOTIFICATION_ACM(%d)", uint32(c))
+}
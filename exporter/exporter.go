@@ -0,0 +1,339 @@
+//Package exporter collects metrics of the interfaces, connections, BSSes, frame counters and Hosted Network
+//of a Client and serves them to Prometheus in the text exposition format.
+//
+//Series are labelled with the GUID of their interface and, where they apply, the SSID, BSSID and band.
+//The frame counter families are generated from the fields of binary.Statistics.
+package exporter
+
+import (
+	"errors"
+	"net"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"wlanapi"
+	"wlanapi/binary"
+)
+
+//The types of metric families.
+const (
+	Gauge   = "gauge"
+	Counter = "counter"
+)
+
+//Label is a label of a sample.
+type Label struct {
+	Name, Value string
+}
+
+//Sample is a series of a family with its current value.
+type Sample struct {
+	Labels []Label
+	Value  float64
+}
+
+//Family is a metric family.
+type Family struct {
+	Name    string
+	Help    string
+	Type    string
+	Samples []Sample
+}
+
+//Exporter collects the metrics of a Client.
+type Exporter struct {
+	client *wlanapi.Client
+	//Select chooses the interfaces whose metrics are exported; nil exports every interface.
+	Select wlanapi.InterfaceSelector
+}
+
+//New returns an Exporter of the metrics of c.
+func New(c *wlanapi.Client) *Exporter {
+	return &Exporter{client: c}
+}
+
+//collector gathers the samples of a scrape into families in a fixed order.
+type collector struct {
+	families []*Family
+	byName   map[string]*Family
+}
+
+func (c *collector) family(name, typ, help string) *Family {
+	if c.byName == nil {
+		c.byName = map[string]*Family{}
+	}
+	f, ok := c.byName[name]
+	if !ok {
+		f = &Family{Name: name, Help: help, Type: typ}
+		c.byName[name] = f
+		c.families = append(c.families, f)
+	}
+	return f
+}
+
+func (c *collector) add(name, typ, help string, value float64, labels ...Label) {
+	f := c.family(name, typ, help)
+	f.Samples = append(f.Samples, Sample{Labels: labels, Value: value})
+}
+
+//declare creates the families in the order of the output, so that it does not depend on which interfaces
+//have samples of them.
+func (c *collector) declare() {
+	for _, d := range families {
+		c.family(d.name, d.typ, d.help)
+	}
+	for _, s := range statisticsFamilies {
+		c.family(s.name, Counter, s.help)
+	}
+	c.family(scrapeErrors.name, scrapeErrors.typ, scrapeErrors.help)
+}
+
+type declaration struct {
+	name, typ, help string
+}
+
+//families are the families that are not generated from the frame counters.
+var families = []declaration{
+	{"wlan_interface_state", Gauge, "WLAN_INTERFACE_STATE of the interface, with its name in the state label."},
+	{"wlan_signal_quality", Gauge, "Signal quality of the connection, from 0 to 100."},
+	{"wlan_rx_rate_mbps", Gauge, "Receive rate of the connection in Mbps."},
+	{"wlan_tx_rate_mbps", Gauge, "Transmit rate of the connection in Mbps."},
+	{"wlan_rssi_dbm", Gauge, "RSSI of a visible BSS in dBm."},
+	{"wlan_link_quality", Gauge, "Link quality of a visible BSS, from 0 to 100."},
+	{"wlan_bss_visible", Gauge, "Number of visible BSSes of an SSID in a band."},
+	{"wlan_hosted_network_peers", Gauge, "Number of peers of the wireless Hosted Network, with its state in the state label."},
+}
+
+//scrapeErrors is the last family.
+var scrapeErrors = declaration{"wlan_scrape_errors", Gauge, "Number of interfaces, and of the Hosted Network, whose metrics could not be collected in the scrape."}
+
+//statisticsFamily is a family generated from a frame counter of binary.Statistics.
+type statisticsFamily struct {
+	name, help string
+	//index is the path of the counter field from binary.Statistics, or from binary.PhyFrameStatistics
+	//for per-PHY counters.
+	index []int
+	//kind is "" for the fields of Statistics, "mac" for those of its unicast and multicast MAC counters,
+	//and "phy" for those of PhyFrameStatistics.
+	kind string
+}
+
+var statisticsFamilies = generateStatisticsFamilies()
+
+//generateStatisticsFamilies derives the frame counter families from the fields of binary.Statistics:
+//TKIPICVErrorCount of the MAC counters becomes wlan_mac_tkip_icv_error_total, labelled unicast or multicast,
+//and RetryCount of a PHY becomes wlan_phy_retry_total, labelled with the index of the PHY.
+func generateStatisticsFamilies() []statisticsFamily {
+	var result []statisticsFamily
+	counters := func(t reflect.Type, prefix, kind string, index []int, from string) {
+		for n := 0; n < t.NumField(); n++ {
+			f := t.Field(n)
+			if f.Type.Kind() != reflect.Uint64 {
+				continue
+			}
+			result = append(result, statisticsFamily{
+				name:  "wlan_" + prefix + metricName(f.Name) + "_total",
+				help:  "Counter " + f.Name + " of " + from + ".",
+				index: append(append([]int(nil), index...), n),
+				kind:  kind,
+			})
+		}
+	}
+	st := reflect.TypeOf(binary.Statistics{})
+	counters(st, "", "", nil, "WLAN_STATISTICS")
+	mac, _ := st.FieldByName("MacUcastCounters")
+	counters(mac.Type, "mac_", "mac", mac.Index, "WLAN_MAC_FRAME_STATISTICS")
+	counters(reflect.TypeOf(binary.PhyFrameStatistics{}), "phy_", "phy", nil, "WLAN_PHY_FRAME_STATISTICS")
+	return result
+}
+
+//acronyms are the upper case words in the names of the counter fields.
+var acronyms = []string{"TKIP", "CCMP", "WEP", "ICV", "MIC", "RTS", "ACK", "FCS", "TX", "RX"}
+
+//metricName converts the name of a counter field to snake case, without its Count suffix.
+func metricName(field string) string {
+	field = strings.TrimSuffix(field, "Count")
+	var words []string
+	for len(field) > 0 {
+		n := 1
+		for _, a := range acronyms {
+			if strings.HasPrefix(field, a) && (len(field) == len(a) || isUpper(field[len(a)])) {
+				n = len(a)
+				break
+			}
+		}
+		if n == 1 {
+			for n < len(field) && !isUpper(field[n]) {
+				n++
+			}
+		}
+		words = append(words, strings.ToLower(field[:n]))
+		field = field[n:]
+	}
+	return strings.Join(words, "_")
+}
+
+func isUpper(c byte) bool {
+	return c >= 'A' && c <= 'Z'
+}
+
+//bssLabels returns the labels of a BSS of an interface.
+func bssLabels(guid, ssid string, bssid [6]byte, frequency uint32) []Label {
+	band := ""
+	if b := wlanapi.BandOf(frequency); b != 0 {
+		band = b.String()
+	}
+	return []Label{{"guid", guid}, {"ssid", ssid}, {"bssid", net.HardwareAddr(bssid[:]).String()}, {"band", band}}
+}
+
+//ScrapeError is returned by Collect, with the metrics that could be collected, when the metrics of some
+//interfaces or of the Hosted Network could not.
+type ScrapeError struct {
+	Interfaces wlanapi.InterfaceErrors
+	//HostedNetwork is the error of the Hosted Network metrics, if any.
+	HostedNetwork error
+}
+
+func (e *ScrapeError) Error() string {
+	var msgs []string
+	if len(e.Interfaces) > 0 {
+		msgs = append(msgs, e.Interfaces.Error())
+	}
+	if e.HostedNetwork != nil {
+		msgs = append(msgs, "hosted network: "+e.HostedNetwork.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+//count returns the number of failures, as wlan_scrape_errors counts them.
+func (e *ScrapeError) count() int {
+	n := len(e.Interfaces)
+	if e.HostedNetwork != nil {
+		n++
+	}
+	return n
+}
+
+//Collect collects the metrics of the selected interfaces and of the Hosted Network. When the metrics of some
+//interfaces or of the Hosted Network cannot be collected, the others are returned with a ScrapeError.
+func (e *Exporter) Collect() ([]*Family, error) {
+	interfaces, err := e.client.SelectInterfaces(e.Select)
+	if err != nil {
+		return nil, err
+	}
+	c := &collector{}
+	c.declare()
+	errs := &ScrapeError{}
+	for _, i := range interfaces {
+		if err := e.collectInterface(c, i); err != nil {
+			errs.Interfaces = append(errs.Interfaces, &wlanapi.InterfaceError{Interface: i, Err: err})
+		}
+	}
+	errs.HostedNetwork = e.collectHostedNetwork(c)
+	c.add(scrapeErrors.name, scrapeErrors.typ, "", float64(errs.count()))
+
+	var result []*Family
+	for _, f := range c.families {
+		if len(f.Samples) > 0 {
+			result = append(result, f)
+		}
+	}
+	if errs.count() > 0 {
+		return result, errs
+	}
+	return result, nil
+}
+
+func (e *Exporter) collectInterface(c *collector, i *wlanapi.Interface) error {
+	guid := i.GUID.String()
+	c.add("wlan_interface_state", Gauge, "", float64(i.State),
+		Label{"guid", guid}, Label{"description", i.Description}, Label{"state", i.State.String()})
+
+	entries, err := i.BSSList()
+	if err != nil {
+		return err
+	}
+	type ssidBand struct {
+		ssid string
+		band wlanapi.Band
+	}
+	visible := map[ssidBand]int{}
+	var order []ssidBand
+	frequencies := map[[6]byte]uint32{}
+	for _, b := range entries {
+		labels := bssLabels(guid, string(b.SSID), b.BSSID, b.ChCenterFrequency)
+		c.add("wlan_rssi_dbm", Gauge, "", float64(b.RSSI), labels...)
+		c.add("wlan_link_quality", Gauge, "", float64(b.LinkQuality), labels...)
+		frequencies[b.BSSID] = b.ChCenterFrequency
+		k := ssidBand{string(b.SSID), wlanapi.BandOf(b.ChCenterFrequency)}
+		if _, ok := visible[k]; !ok {
+			order = append(order, k)
+		}
+		visible[k]++
+	}
+	for _, k := range order {
+		band := ""
+		if k.band != 0 {
+			band = k.band.String()
+		}
+		c.add("wlan_bss_visible", Gauge, "", float64(visible[k]), Label{"guid", guid}, Label{"ssid", k.ssid}, Label{"band", band})
+	}
+
+	conn, err := i.Connection()
+	switch {
+	case err == nil:
+		labels := bssLabels(guid, string(conn.SSID), conn.BSSID, frequencies[conn.BSSID])
+		c.add("wlan_signal_quality", Gauge, "", float64(conn.SignalQuality), labels...)
+		c.add("wlan_rx_rate_mbps", Gauge, "", float64(conn.RxRate)/1000, labels...)
+		c.add("wlan_tx_rate_mbps", Gauge, "", float64(conn.TxRate)/1000, labels...)
+	case !errors.Is(err, wlanapi.ErrNotConnected) && !errors.Is(err, wlanapi.ErrBackendNotSupported):
+		return err
+	}
+
+	s, err := i.Statistics()
+	switch {
+	case err == nil:
+		collectStatistics(c, guid, s)
+	case !errors.Is(err, wlanapi.ErrNotConnected) && !errors.Is(err, wlanapi.ErrBackendNotSupported):
+		return err
+	}
+	return nil
+}
+
+func collectStatistics(c *collector, guid string, s *binary.Statistics) {
+	v := reflect.ValueOf(s).Elem()
+	for _, f := range statisticsFamilies {
+		switch f.kind {
+		case "":
+			c.add(f.name, Counter, "", float64(v.FieldByIndex(f.index).Uint()), Label{"guid", guid})
+		case "mac":
+			c.add(f.name, Counter, "", float64(v.FieldByIndex(f.index).Uint()), Label{"guid", guid}, Label{"cast", "unicast"})
+			multicast := v.FieldByName("MacMcastCounters").Field(f.index[len(f.index)-1])
+			c.add(f.name, Counter, "", float64(multicast.Uint()), Label{"guid", guid}, Label{"cast", "multicast"})
+		case "phy":
+			for n, phy := range s.PhyCounters {
+				value := reflect.ValueOf(phy).FieldByIndex(f.index)
+				c.add(f.name, Counter, "", float64(value.Uint()), Label{"guid", guid}, Label{"phy", strconv.Itoa(n)})
+			}
+		}
+	}
+}
+
+func (e *Exporter) collectHostedNetwork(c *collector) error {
+	status, err := e.client.HostedNetworkStatus()
+	if errors.Is(err, wlanapi.ErrBackendNotSupported) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	settings, err := e.client.HostedNetworkSettings()
+	if err != nil {
+		return err
+	}
+	state := wlanapi.WLAN_HOSTED_NETWORK_STATE(status.State).String()
+	c.add("wlan_hosted_network_peers", Gauge, "", float64(len(status.Peers)),
+		Label{"ssid", string(settings.SSID)}, Label{"state", state})
+	return nil
+}
@@ -0,0 +1,153 @@
+package exporter
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"wlanapi"
+	"wlanapi/binary"
+	"wlanapi/simfile/simfiletest"
+)
+
+//scrape serves the metrics of the fixture and returns the response to a scrape of it.
+func scrape(t *testing.T, setup func(*wlanapi.Sim)) (*http.Response, string) {
+	t.Helper()
+	sim := simfiletest.Sim(t)
+	if setup != nil {
+		setup(sim)
+	}
+	server := httptest.NewServer(New(wlanapi.NewClientWithBackend(sim)))
+	defer server.Close()
+	resp, err := http.Get(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp, string(body)
+}
+
+var (
+	metadataLine = regexp.MustCompile(`^# (HELP|TYPE) ([a-z_]+) (.+)$`)
+	sampleLine   = regexp.MustCompile(`^([a-z_]+)(\{([a-z_]+="([^"\\]|\\.)*",?)*\})? -?[0-9.e+]+$`)
+)
+
+func TestScrape(t *testing.T) {
+	resp, body := scrape(t, func(sim *wlanapi.Sim) {
+		sim.SetHostedNetwork(binary.HostedNetworkStatus{State: 2, Peers: []binary.HostedNetworkPeer{{AuthState: 1}}},
+			wlanapi.HostedNetworkSettings{SSID: []byte("kiosk"), MaxPeers: 8})
+	})
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != ContentType {
+		t.Fatalf("scrape: %s, %q\n%s", resp.Status, resp.Header.Get("Content-Type"), body)
+	}
+
+	//Every line is valid, and the samples of a family follow its HELP and TYPE.
+	seen := map[string]bool{}
+	family := ""
+	for _, line := range strings.Split(strings.TrimSuffix(body, "\n"), "\n") {
+		if m := metadataLine.FindStringSubmatch(line); m != nil {
+			if m[1] == "HELP" {
+				if seen[m[2]] {
+					t.Errorf("family %s described twice", m[2])
+				}
+				seen[m[2]], family = true, m[2]
+			}
+			continue
+		}
+		m := sampleLine.FindStringSubmatch(line)
+		if m == nil {
+			t.Errorf("invalid line %q", line)
+		} else if m[1] != family {
+			t.Errorf("sample %q outside of its family %s", line, family)
+		}
+	}
+
+	const intel = `guid="` + simfiletest.Intel + `"`
+	for _, line := range []string{
+		`wlan_interface_state{` + intel + `,description="Intel(R) Wi-Fi 6 AX201 160MHz",state="connected"} 1`,
+		`wlan_signal_quality{` + intel + `,ssid="corp",bssid="00:1a:1e:00:00:01",band="2.4GHz"} 90`,
+		`wlan_tx_rate_mbps{` + intel + `,ssid="corp",bssid="00:1a:1e:00:00:01",band="2.4GHz"} 54`,
+		`wlan_rssi_dbm{` + intel + `,ssid="corp",bssid="00:1a:1e:00:00:03",band="5GHz"} -72`,
+		`wlan_link_quality{` + intel + `,ssid="guest",bssid="00:1a:1e:00:00:10",band="2.4GHz"} 60`,
+		`wlan_bss_visible{` + intel + `,ssid="corp",band="5GHz"} 2`,
+		`wlan_hosted_network_peers{ssid="kiosk",state="active"} 1`,
+		`wlan_four_way_handshake_failures_total{` + intel + `} 2`,
+		`wlan_mac_received_frame_total{` + intel + `,cast="multicast"} 400`,
+		`wlan_mac_tkip_icv_error_total{` + intel + `,cast="unicast"} 1`,
+		`wlan_phy_retry_total{` + intel + `,phy="0"} 35`,
+		`wlan_phy_max_tx_lifetime_exceeded_total{` + intel + `,phy="1"} 1`,
+		`wlan_phy_fcs_error_total{` + intel + `,phy="0"} 8`,
+		"# TYPE wlan_phy_rts_failure_total counter",
+		"wlan_scrape_errors 0",
+	} {
+		if !strings.Contains(body, line+"\n") {
+			t.Errorf("missing %s", line)
+		}
+	}
+	if strings.Contains(body, `wlan_signal_quality{guid="{AAAAAAAA`) {
+		t.Error("signal quality of a disconnected interface")
+	}
+}
+
+func TestScrapeInterfaceError(t *testing.T) {
+	realtek, _ := wlanapi.ParseGUID(simfiletest.Realtek)
+	resp, body := scrape(t, func(sim *wlanapi.Sim) { sim.SetError(realtek, errors.New("device removed")) })
+	if resp.StatusCode != http.StatusOK || !strings.Contains(body, "wlan_scrape_errors 1\n") ||
+		!strings.Contains(body, `wlan_phy_retry_total{guid="`+simfiletest.Intel+`",phy="0"} 35`) {
+		t.Errorf("scrape with a failing interface: %s\n%s", resp.Status, body)
+	}
+}
+
+func TestScrapeHostedNetworkError(t *testing.T) {
+	resp, body := scrape(t, func(sim *wlanapi.Sim) { sim.SetHostedNetworkError(errors.New("service stopped")) })
+	if resp.StatusCode != http.StatusOK || !strings.Contains(body, "wlan_scrape_errors 1\n") ||
+		strings.Contains(body, "wlan_hosted_network_peers") || !strings.Contains(body, `wlan_rssi_dbm{guid="`+simfiletest.Intel) {
+		t.Errorf("scrape with a failing Hosted Network: %s\n%s", resp.Status, body)
+	}
+}
+
+func TestMetricName(t *testing.T) {
+	for field, want := range map[string]string{
+		"TransmittedFrameCount":      "transmitted_frame",
+		"TKIPICVErrorCount":          "tkip_icv_error",
+		"TKIPLocalMICFailures":       "tkip_local_mic_failures",
+		"MaxTXLifetimeExceededCount": "max_tx_lifetime_exceeded",
+		"RTSSuccessCount":            "rts_success",
+		"FourWayHandshakeFailures":   "four_way_handshake_failures",
+	} {
+		if got := metricName(field); got != want {
+			t.Errorf("metricName(%q) = %q, want %q", field, got, want)
+		}
+	}
+}
+
+func TestWriteTextEscaping(t *testing.T) {
+	var b strings.Builder
+	err := WriteText(&b, []*Family{{Name: "m", Help: "a\\b\nc", Type: Gauge, Samples: []Sample{
+		{Labels: []Label{{"l", "x\"y\\z\n"}}, Value: 0.5},
+	}}})
+	want := "# HELP m a\\\\b\\nc\n# TYPE m gauge\nm{l=\"x\\\"y\\\\z\\n\"} 0.5\n"
+	if err != nil || b.String() != want {
+		t.Errorf("WriteText: %v\n%s\nwant\n%s", err, b.String(), want)
+	}
+}
+
+func TestWriteTextInvalidUTF8(t *testing.T) {
+	var b strings.Builder
+	err := WriteText(&b, []*Family{{Name: "m", Help: "h", Type: Gauge, Samples: []Sample{
+		{Labels: []Label{{"ssid", "caf\xe9\xff\"bar\""}}, Value: 1},
+	}}})
+	want := "# HELP m h\n# TYPE m gauge\nm{ssid=\"caf\uFFFD\\\"bar\\\"\"} 1\n"
+	if err != nil || b.String() != want || !utf8.ValidString(b.String()) {
+		t.Errorf("WriteText: %v\n%q\nwant\n%q", err, b.String(), want)
+	}
+}
@@ -0,0 +1,79 @@
+package exporter
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+//ContentType is the media type of the text exposition format.
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+var (
+	helpEscaper  = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+	labelEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+)
+
+//labelValue escapes a label value. SSIDs are arbitrary bytes, but the format is UTF-8, so invalid sequences
+//are replaced with U+FFFD.
+func labelValue(v string) string {
+	return labelEscaper.Replace(strings.ToValidUTF8(v, "\uFFFD"))
+}
+
+//formatValue formats a sample value as the text exposition format expects it.
+func formatValue(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+//WriteText writes families in the Prometheus text exposition format.
+func WriteText(w io.Writer, families []*Family) error {
+	b := bufio.NewWriter(w)
+	for _, f := range families {
+		b.WriteString("# HELP " + f.Name + " " + helpEscaper.Replace(f.Help) + "\n")
+		b.WriteString("# TYPE " + f.Name + " " + f.Type + "\n")
+		for _, s := range f.Samples {
+			b.WriteString(f.Name)
+			if len(s.Labels) > 0 {
+				b.WriteByte('{')
+				for n, l := range s.Labels {
+					if n > 0 {
+						b.WriteByte(',')
+					}
+					b.WriteString(l.Name + `="` + labelValue(l.Value) + `"`)
+				}
+				b.WriteByte('}')
+			}
+			b.WriteString(" " + formatValue(s.Value) + "\n")
+		}
+	}
+	return b.Flush()
+}
+
+//ServeHTTP collects the metrics and writes them in the text exposition format. The metrics that could be
+//collected are served even when those of some interfaces or of the Hosted Network fail, which wlan_scrape_errors
+//counts.
+func (e *Exporter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	families, err := e.Collect()
+	var errs *ScrapeError
+	if errors.As(err, &errs) {
+		log.Printf("exporter: %v", err)
+	} else if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", ContentType)
+	WriteText(w, families)
+}
@@ -0,0 +1,225 @@
+package wlanapi
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"wlanapi/binary"
+)
+
+//InterfaceError is the error of an operation on one interface.
+type InterfaceError struct {
+	Interface *Interface
+	Err       error
+}
+
+func (e *InterfaceError) Error() string {
+	return fmt.Sprintf("wlanapi: interface %s (%s): %v", e.Interface.GUID.String(), e.Interface.Description, e.Err)
+}
+
+func (e *InterfaceError) Unwrap() error {
+	return e.Err
+}
+
+//InterfaceErrors collects the errors of an operation that failed on some of the interfaces.
+//The results of the other interfaces are still returned alongside it.
+type InterfaceErrors []*InterfaceError
+
+func (e InterfaceErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+//InterfaceSelector reports whether an interface is selected.
+type InterfaceSelector func(*Interface) bool
+
+//ParseInterfaceSelector parses an interface selector:
+//"" or "all" selects every interface, "connected" the connected interfaces,
+//a GUID in the {xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx} form, with or without the braces, that interface,
+//and anything else the interfaces whose description contains it, ignoring case.
+func ParseInterfaceSelector(s string) (InterfaceSelector, error) {
+	s = strings.TrimSpace(s)
+	switch strings.ToLower(s) {
+	case "", "all":
+		return func(*Interface) bool { return true }, nil
+	case "connected":
+		return func(i *Interface) bool { return i.State == wlan_interface_state_connected }, nil
+	}
+	if len(s) == 36 && s[8] == '-' && s[13] == '-' && s[18] == '-' && s[23] == '-' {
+		s = "{" + s + "}"
+	}
+	if strings.HasPrefix(s, "{") {
+		guid, err := ParseGUID(s)
+		if err != nil {
+			return nil, err
+		}
+		return func(i *Interface) bool { return i.GUID == guid }, nil
+	}
+	substr := strings.ToLower(s)
+	return func(i *Interface) bool { return strings.Contains(strings.ToLower(i.Description), substr) }, nil
+}
+
+//ParseGUID parses a GUID in the {xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx} form, as printed by GUID.String.
+func ParseGUID(s string) (GUID, error) {
+	var guid GUID
+	if len(s) != 38 || s[0] != '{' || s[37] != '}' || s[9] != '-' || s[14] != '-' || s[19] != '-' || s[24] != '-' {
+		return guid, fmt.Errorf("wlanapi: invalid GUID %q", s)
+	}
+	hex := strings.ReplaceAll(s[1:37], "-", "")
+	var b [16]byte
+	for n := range b {
+		v, err := strconv.ParseUint(hex[2*n:2*n+2], 16, 8)
+		if err != nil {
+			return guid, fmt.Errorf("wlanapi: invalid GUID %q", s)
+		}
+		b[n] = byte(v)
+	}
+	guid.Data1 = uint32(b[0])<<24 | uint32(b[1])<<16 | uint32(b[2])<<8 | uint32(b[3])
+	guid.Data2 = uint16(b[4])<<8 | uint16(b[5])
+	guid.Data3 = uint16(b[6])<<8 | uint16(b[7])
+	copy(guid.Data4[:], b[8:])
+	return guid, nil
+}
+
+//SelectInterfaces returns the interfaces chosen by sel; a nil sel selects every interface.
+func (c *Client) SelectInterfaces(sel InterfaceSelector) ([]*Interface, error) {
+	interfaces, err := c.Interfaces()
+	if err != nil || sel == nil {
+		return interfaces, err
+	}
+	selected := interfaces[:0]
+	for _, i := range interfaces {
+		if sel(i) {
+			selected = append(selected, i)
+		}
+	}
+	return selected, nil
+}
+
+//ForEachInterface calls fn for every interface, working on up to the client parallelism at the same time.
+//Errors of fn are collected into InterfaceErrors; interfaces not started before ctx is done fail with ctx.Err().
+func (c *Client) ForEachInterface(ctx context.Context, fn func(context.Context, *Interface) error) error {
+	interfaces, err := c.Interfaces()
+	if err != nil {
+		return err
+	}
+	return c.forEach(ctx, interfaces, func(ctx context.Context, _ int, i *Interface) error {
+		return fn(ctx, i)
+	})
+}
+
+//forEach calls fn with the index of each interface, bounded by the client parallelism.
+func (c *Client) forEach(ctx context.Context, interfaces []*Interface, fn func(context.Context, int, *Interface) error) error {
+	c.parallelismMu.Lock()
+	parallelism := c.parallelism
+	c.parallelismMu.Unlock()
+
+	errs := make([]error, len(interfaces))
+	sem := make(chan struct{}, parallelism)
+	var wg sync.WaitGroup
+	for n, i := range interfaces {
+		acquired := false
+		select {
+		case sem <- struct{}{}:
+			acquired = true
+		case <-ctx.Done():
+		}
+		//The select may pick the semaphore when ctx is done too.
+		if err := ctx.Err(); err != nil {
+			if acquired {
+				<-sem
+			}
+			errs[n] = err
+			continue
+		}
+		wg.Add(1)
+		go func(n int, i *Interface) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			errs[n] = fn(ctx, n, i)
+		}(n, i)
+	}
+	wg.Wait()
+
+	var failed InterfaceErrors
+	for n, err := range errs {
+		if err != nil {
+			failed = append(failed, &InterfaceError{Interface: interfaces[n], Err: err})
+		}
+	}
+	if len(failed) > 0 {
+		return failed
+	}
+	return nil
+}
+
+//InterfaceNetwork is an available network seen by an interface.
+type InterfaceNetwork struct {
+	Interface *Interface
+	Network   binary.AvailableNetwork
+}
+
+//InterfaceBSS is a BSS entry seen by an interface.
+type InterfaceBSS struct {
+	Interface *Interface
+	BSS       binary.BSSEntry
+}
+
+//ScanAll requests a scan on the interfaces chosen by sel, or on all interfaces if sel is nil.
+func (c *Client) ScanAll(ctx context.Context, sel InterfaceSelector) error {
+	interfaces, err := c.SelectInterfaces(sel)
+	if err != nil {
+		return err
+	}
+	return c.forEach(ctx, interfaces, func(_ context.Context, _ int, i *Interface) error {
+		return i.Scan()
+	})
+}
+
+//NetworksAll retrieves the available networks of the interfaces chosen by sel, in interface order.
+//When some interfaces fail, the networks of the others are returned with InterfaceErrors.
+func (c *Client) NetworksAll(ctx context.Context, sel InterfaceSelector) ([]InterfaceNetwork, error) {
+	interfaces, err := c.SelectInterfaces(sel)
+	if err != nil {
+		return nil, err
+	}
+	results := make([][]binary.AvailableNetwork, len(interfaces))
+	err = c.forEach(ctx, interfaces, func(_ context.Context, n int, i *Interface) (err error) {
+		results[n], err = i.AvailableNetworks()
+		return err
+	})
+	var merged []InterfaceNetwork
+	for n, networks := range results {
+		for _, network := range networks {
+			merged = append(merged, InterfaceNetwork{Interface: interfaces[n], Network: network})
+		}
+	}
+	return merged, err
+}
+
+//BSSesAll retrieves the BSS entries of the interfaces chosen by sel, in interface order.
+//When some interfaces fail, the entries of the others are returned with InterfaceErrors.
+func (c *Client) BSSesAll(ctx context.Context, sel InterfaceSelector) ([]InterfaceBSS, error) {
+	interfaces, err := c.SelectInterfaces(sel)
+	if err != nil {
+		return nil, err
+	}
+	results := make([][]binary.BSSEntry, len(interfaces))
+	err = c.forEach(ctx, interfaces, func(_ context.Context, n int, i *Interface) (err error) {
+		results[n], err = i.BSSList()
+		return err
+	})
+	var merged []InterfaceBSS
+	for n, entries := range results {
+		for _, entry := range entries {
+			merged = append(merged, InterfaceBSS{Interface: interfaces[n], BSS: entry})
+		}
+	}
+	return merged, err
+}
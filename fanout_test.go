@@ -0,0 +1,174 @@
+package wlanapi
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"wlanapi/binary"
+)
+
+func simGUID(n byte) binary.GUID {
+	return binary.GUID{Data1: 0x1000 + uint32(n), Data4: [8]byte{7: n}}
+}
+
+func newFanOutSim() *Sim {
+	sim := NewSim()
+	sim.AddInterface(binary.InterfaceInfo{InterfaceGuid: simGUID(1), Description: "Intel(R) Wi-Fi 6 AX201 160MHz", State: 1})
+	sim.AddInterface(binary.InterfaceInfo{InterfaceGuid: simGUID(2), Description: "Realtek RTL8812BU Wireless LAN 802.11ac USB NIC", State: 4})
+	sim.AddInterface(binary.InterfaceInfo{InterfaceGuid: simGUID(3), Description: "Microsoft Wi-Fi Direct Virtual Adapter", State: 0})
+	for n := byte(1); n <= 3; n++ {
+		sim.SetNetworks(GUID(simGUID(n)), []binary.AvailableNetwork{{SSID: []byte("shared")}, {SSID: []byte{'n', '0' + n}}})
+		sim.SetBSSList(GUID(simGUID(n)), []binary.BSSEntry{{SSID: []byte("shared"), BSSID: [6]byte{5: n}}})
+	}
+	return sim
+}
+
+func TestParseInterfaceSelector(t *testing.T) {
+	c := NewClientWithBackend(newFanOutSim())
+	tests := []struct {
+		selector string
+		want     []byte
+	}{
+		{"", []byte{1, 2, 3}},
+		{"all", []byte{1, 2, 3}},
+		{"connected", []byte{1}},
+		{"usb", []byte{2}},
+		{"Wi-Fi", []byte{1, 3}},
+		{"{00001002-0000-0000-0000-000000000002}", []byte{2}},
+		{"{00001002-0000-0000-0000-000000000009}", nil},
+		{"00001002-0000-0000-0000-000000000002", []byte{2}},
+	}
+	for _, tt := range tests {
+		sel, err := ParseInterfaceSelector(tt.selector)
+		if err != nil {
+			t.Errorf("%q: %v", tt.selector, err)
+			continue
+		}
+		interfaces, err := c.SelectInterfaces(sel)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(interfaces) != len(tt.want) {
+			t.Errorf("%q selected %d interfaces, want %d", tt.selector, len(interfaces), len(tt.want))
+			continue
+		}
+		for n, i := range interfaces {
+			if i.GUID != GUID(simGUID(tt.want[n])) {
+				t.Errorf("%q selected %s, want %s", tt.selector, i.GUID.String(), GUID(simGUID(tt.want[n])).String())
+			}
+		}
+	}
+
+	for _, s := range []string{"{00001002-0000-0000-0000-00000000000}", "{0000100g-0000-0000-0000-000000000002}", "0000100g-0000-0000-0000-000000000002"} {
+		if _, err := ParseInterfaceSelector(s); err == nil {
+			t.Errorf("%q: parsed an invalid GUID", s)
+		}
+	}
+}
+
+func TestForEachInterfaceParallelism(t *testing.T) {
+	sim := NewSim()
+	for n := byte(0); n < 10; n++ {
+		sim.AddInterface(binary.InterfaceInfo{InterfaceGuid: simGUID(n)})
+	}
+	c := NewClientWithBackend(sim)
+	c.SetParallelism(3)
+
+	var mu sync.Mutex
+	running, peak, calls := 0, 0, 0
+	err := c.ForEachInterface(context.Background(), func(ctx context.Context, i *Interface) error {
+		mu.Lock()
+		running++
+		calls++
+		if running > peak {
+			peak = running
+		}
+		mu.Unlock()
+		time.Sleep(10 * time.Millisecond)
+		mu.Lock()
+		running--
+		mu.Unlock()
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if calls != 10 {
+		t.Errorf("fn called %d times, want 10", calls)
+	}
+	if peak > 3 {
+		t.Errorf("%d interfaces ran at the same time, want at most 3", peak)
+	}
+}
+
+func TestForEachInterfaceCanceled(t *testing.T) {
+	c := NewClientWithBackend(newFanOutSim())
+	c.SetParallelism(1)
+	ctx, cancel := context.WithCancel(context.Background())
+	err := c.ForEachInterface(ctx, func(ctx context.Context, i *Interface) error {
+		cancel()
+		return nil
+	})
+	errs, ok := err.(InterfaceErrors)
+	if !ok || len(errs) != 2 {
+		t.Fatalf("got %v, want 2 interface errors", err)
+	}
+	for _, e := range errs {
+		if !errors.Is(e, context.Canceled) {
+			t.Errorf("%v, want context.Canceled", e)
+		}
+	}
+}
+
+func TestNetworksAllPartial(t *testing.T) {
+	sim := newFanOutSim()
+	failure := errors.New("driver hung")
+	sim.SetError(GUID(simGUID(2)), failure)
+	c := NewClientWithBackend(sim)
+
+	networks, err := c.NetworksAll(context.Background(), nil)
+	errs, ok := err.(InterfaceErrors)
+	if !ok || len(errs) != 1 || errs[0].Interface.GUID != GUID(simGUID(2)) || !errors.Is(errs[0], failure) {
+		t.Fatalf("got %v, want the error of interface 2", err)
+	}
+	want := []struct {
+		iface byte
+		ssid  string
+	}{{1, "shared"}, {1, "n1"}, {3, "shared"}, {3, "n3"}}
+	if len(networks) != len(want) {
+		t.Fatalf("got %d networks, want %d", len(networks), len(want))
+	}
+	for n, w := range want {
+		if networks[n].Interface.GUID != GUID(simGUID(w.iface)) || string(networks[n].Network.SSID) != w.ssid {
+			t.Errorf("network %d: %s %q, want interface %d %q", n, networks[n].Interface.GUID.String(), networks[n].Network.SSID, w.iface, w.ssid)
+		}
+	}
+}
+
+func TestScanAllAndBSSesAll(t *testing.T) {
+	sim := newFanOutSim()
+	c := NewClientWithBackend(sim)
+	sel, _ := ParseInterfaceSelector("connected")
+	if err := c.ScanAll(context.Background(), sel); err != nil {
+		t.Fatal(err)
+	}
+	if sim.Scans(GUID(simGUID(1))) != 1 || sim.Scans(GUID(simGUID(2))) != 0 {
+		t.Errorf("scans %d/%d, want 1/0", sim.Scans(GUID(simGUID(1))), sim.Scans(GUID(simGUID(2))))
+	}
+
+	entries, err := c.BSSesAll(context.Background(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("got %d BSS entries, want 3", len(entries))
+	}
+	for n, e := range entries {
+		if e.Interface.GUID != GUID(simGUID(byte(n+1))) || e.BSS.BSSID[5] != byte(n+1) {
+			t.Errorf("entry %d attributed to %s", n, e.Interface.GUID.String())
+		}
+	}
+}
@@ -2,4 +2,15 @@ module wlanapi
 
 go 1.17
 
-require golang.org/x/sys v0.0.0-20220610221304-9f5ed59c137d
+require (
+	golang.org/x/sys v0.0.0-20220610221304-9f5ed59c137d
+	google.golang.org/grpc v1.50.0
+	google.golang.org/protobuf v1.27.1
+)
+
+require (
+	github.com/golang/protobuf v1.5.2 // indirect
+	golang.org/x/net v0.0.0-20201021035429-f5854403a974 // indirect
+	golang.org/x/text v0.3.3 // indirect
+	google.golang.org/genproto v0.0.0-20200526211855-cb27e3aa2013 // indirect
+)
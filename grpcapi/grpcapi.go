@@ -0,0 +1,210 @@
+//Package grpcapi serves a Client over gRPC with the WLAN service of wlanpb: its interfaces, scans, available
+//networks and BSSes with their decoded information elements, profiles, connections and wireless Hosted Network,
+//and its notifications as a server stream.
+//
+//Errors carry gRPC status codes: NotFound for unknown interfaces and profiles, FailedPrecondition for
+//disconnected interfaces and Hosted Network state changes, Unimplemented for backends without the feature and
+//InvalidArgument for invalid requests.
+package grpcapi
+
+//go:generate protoc -I wlanpb --go_out=paths=source_relative:wlanpb --go-grpc_out=paths=source_relative:wlanpb wlan.proto
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"wlanapi"
+	"wlanapi/grpcapi/wlanpb"
+	"wlanapi/internal/remote"
+)
+
+//Server implements the WLAN service with a Client.
+type Server struct {
+	wlanpb.UnimplementedWLANServer
+	client  *wlanapi.Client
+	service *remote.Service
+}
+
+//New returns a Server of c, to register with wlanpb.RegisterWLANServer.
+func New(c *wlanapi.Client) *Server {
+	return &Server{client: c, service: remote.New(c)}
+}
+
+//kindCode is the code of the status errors of each kind of the errors of the Service.
+var kindCode = map[remote.Kind]codes.Code{
+	remote.Internal:    codes.Internal,
+	remote.Invalid:     codes.InvalidArgument,
+	remote.NotFound:    codes.NotFound,
+	remote.Conflict:    codes.FailedPrecondition,
+	remote.Unsupported: codes.Unimplemented,
+}
+
+//statusError converts an error of the Service to a status error.
+func statusError(err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	return status.Error(kindCode[remote.KindOf(err)], err.Error())
+}
+
+//newInterfaces converts the interfaces described by the Service.
+func newInterfaces(interfaces []remote.Interface) *wlanpb.ListInterfacesResponse {
+	resp := &wlanpb.ListInterfacesResponse{}
+	for _, i := range interfaces {
+		resp.Interfaces = append(resp.Interfaces, newInterface(i.Interface, i.Connection))
+	}
+	return resp
+}
+
+//newProfiles converts the profiles read by the Service.
+func newProfiles(profiles []remote.Profile) *wlanpb.ListProfilesResponse {
+	resp := &wlanpb.ListProfilesResponse{}
+	for _, p := range profiles {
+		m := newProfile(p.Interface, p.Info)
+		m.Xml = p.XML
+		resp.Profiles = append(resp.Profiles, m)
+	}
+	return resp
+}
+
+func (s *Server) ListInterfaces(ctx context.Context, req *wlanpb.ListInterfacesRequest) (*wlanpb.ListInterfacesResponse, error) {
+	interfaces, err := s.service.Interfaces(req.Selector)
+	return newInterfaces(interfaces), statusError(err)
+}
+
+func (s *Server) Scan(ctx context.Context, req *wlanpb.ScanRequest) (*wlanpb.Interface, error) {
+	i, err := s.service.Scan(req.InterfaceGuid)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return newInterface(i.Interface, i.Connection), nil
+}
+
+func (s *Server) ListNetworks(ctx context.Context, req *wlanpb.ListNetworksRequest) (*wlanpb.ListNetworksResponse, error) {
+	networks, err := s.service.AvailableNetworks(req.InterfaceGuid)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	resp := &wlanpb.ListNetworksResponse{}
+	for _, n := range networks {
+		resp.Networks = append(resp.Networks, newNetwork(n))
+	}
+	return resp, nil
+}
+
+func (s *Server) ListBSS(ctx context.Context, req *wlanpb.ListBSSRequest) (*wlanpb.ListBSSResponse, error) {
+	entries, err := s.service.BSSList(req.InterfaceGuid)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	resp := &wlanpb.ListBSSResponse{}
+	for _, e := range entries {
+		resp.Bss = append(resp.Bss, newBSS(e))
+	}
+	return resp, nil
+}
+
+func (s *Server) ListProfiles(ctx context.Context, req *wlanpb.ListProfilesRequest) (*wlanpb.ListProfilesResponse, error) {
+	profiles, err := s.service.Profiles(req.Selector)
+	return newProfiles(profiles), statusError(err)
+}
+
+func (s *Server) GetProfile(ctx context.Context, req *wlanpb.GetProfileRequest) (*wlanpb.ListProfilesResponse, error) {
+	profiles, err := s.service.Profile(req.Selector, req.Name, req.PlaintextKey)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return newProfiles(profiles), nil
+}
+
+func (s *Server) SetProfile(ctx context.Context, req *wlanpb.SetProfileRequest) (*wlanpb.ListProfilesResponse, error) {
+	profiles, err := s.service.SetProfile(req.Selector, req.Xml, req.Overwrite)
+	return newProfiles(profiles), statusError(err)
+}
+
+func (s *Server) DeleteProfile(ctx context.Context, req *wlanpb.DeleteProfileRequest) (*wlanpb.DeleteProfileResponse, error) {
+	if err := s.service.DeleteProfile(req.Selector, req.Name); err != nil {
+		return nil, statusError(err)
+	}
+	return &wlanpb.DeleteProfileResponse{}, nil
+}
+
+func (s *Server) Connect(ctx context.Context, req *wlanpb.ConnectRequest) (*wlanpb.ListInterfacesResponse, error) {
+	interfaces, err := s.service.Connect(remote.ConnectRequest{
+		Selector:   req.Selector,
+		Profile:    req.Profile,
+		ProfileXML: req.ProfileXml,
+		SSID:       req.Ssid,
+		BSSIDs:     req.Bssids,
+		BssType:    req.BssType,
+	})
+	return newInterfaces(interfaces), statusError(err)
+}
+
+func (s *Server) Disconnect(ctx context.Context, req *wlanpb.DisconnectRequest) (*wlanpb.ListInterfacesResponse, error) {
+	interfaces, err := s.service.Disconnect(req.Selector)
+	return newInterfaces(interfaces), statusError(err)
+}
+
+func (s *Server) GetHostedNetwork(ctx context.Context, req *wlanpb.GetHostedNetworkRequest) (*wlanpb.HostedNetwork, error) {
+	h, err := s.service.HostedNetwork()
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return newHostedNetwork(h.Status, h.Settings), nil
+}
+
+func (s *Server) SetHostedNetwork(ctx context.Context, req *wlanpb.SetHostedNetworkRequest) (*wlanpb.HostedNetwork, error) {
+	h, err := s.service.SetHostedNetwork(remote.HostedNetworkRequest{Action: req.Action, SSID: req.Ssid, MaxPeers: req.MaxPeers})
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return newHostedNetwork(h.Status, h.Settings), nil
+}
+
+//WatchEvents streams the notifications of the requested sources until the client cancels the call.
+//Events are named as wlanapi.Event names them.
+func (s *Server) WatchEvents(req *wlanpb.WatchEventsRequest, stream wlanpb.WLAN_WatchEventsServer) error {
+	sources := map[string]bool{}
+	for _, name := range req.Sources {
+		sources[name] = true
+	}
+	ch, cancel, err := s.client.Backend().Notifications()
+	if err != nil {
+		return statusError(err)
+	}
+	defer cancel()
+	//Send the headers, so that the client knows the subscription is in place.
+	if err := stream.SendHeader(nil); err != nil {
+		return err
+	}
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case n, ok := <-ch:
+			if !ok {
+				return nil
+			}
+			e := n.Event()
+			if len(sources) > 0 && !sources[e.Source] {
+				continue
+			}
+			err := stream.Send(&wlanpb.Event{
+				InterfaceGuid: e.Interface,
+				Source:        e.Source,
+				Code:          e.Code,
+				Notification:  e.Notification,
+				Reason:        e.Reason,
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
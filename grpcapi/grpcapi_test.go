@@ -0,0 +1,210 @@
+package grpcapi
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"wlanapi/grpcapi/wlanpb"
+	"wlanapi/simfile/simfiletest"
+)
+
+//dial serves the fixture over a bufconn listener and returns a client of it.
+func dial(t *testing.T) wlanpb.WLANClient {
+	t.Helper()
+	lis := bufconn.Listen(1 << 20)
+	server := grpc.NewServer()
+	wlanpb.RegisterWLANServer(server, New(simfiletest.Client(t)))
+	go server.Serve(lis)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.DialContext(context.Background(), "bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return wlanpb.NewWLANClient(conn)
+}
+
+func wantCode(t *testing.T, what string, err error, code codes.Code) {
+	t.Helper()
+	if status.Code(err) != code {
+		t.Errorf("%s: %v, want %v", what, err, code)
+	}
+}
+
+func TestInterfaces(t *testing.T) {
+	c, ctx := dial(t), context.Background()
+	resp, err := c.ListInterfaces(ctx, &wlanpb.ListInterfacesRequest{})
+	if err != nil || len(resp.Interfaces) != 2 {
+		t.Fatalf("ListInterfaces: %v %v", resp, err)
+	}
+	i, conn := resp.Interfaces[0], resp.Interfaces[0].Connection
+	if i.Guid != simfiletest.Intel || i.State != "connected" || conn == nil || string(conn.Ssid) != "corp" || conn.AuthAlgorithm != "WPA2-Personal" ||
+		resp.Interfaces[1].Connection != nil {
+		t.Errorf("interfaces %v", resp.Interfaces)
+	}
+	resp, err = c.ListInterfaces(ctx, &wlanpb.ListInterfacesRequest{Selector: "realtek"})
+	if err != nil || len(resp.Interfaces) != 1 {
+		t.Errorf("selected interfaces: %v %v", resp, err)
+	}
+	_, err = c.ListInterfaces(ctx, &wlanpb.ListInterfacesRequest{Selector: "broadcom"})
+	wantCode(t, "no selected interface", err, codes.NotFound)
+}
+
+func TestNetworksAndBSS(t *testing.T) {
+	c, ctx := dial(t), context.Background()
+	networks, err := c.ListNetworks(ctx, &wlanpb.ListNetworksRequest{InterfaceGuid: simfiletest.Intel})
+	if err != nil || len(networks.Networks) != 2 || !networks.Networks[0].Connected || len(networks.Networks[0].PhyTypes) != 2 {
+		t.Errorf("ListNetworks: %v %v", networks, err)
+	}
+
+	resp, err := c.ListBSS(ctx, &wlanpb.ListBSSRequest{InterfaceGuid: "11111111-2222-3333-4444-555555555555"})
+	if err != nil || len(resp.Bss) != 4 {
+		t.Fatalf("ListBSS: %v %v", resp, err)
+	}
+	b := resp.Bss[1]
+	if b.Bssid != "00:1a:1e:00:00:02" || b.Band != "5GHz" || b.Channel != 36 || len(b.Elements) != 5 {
+		t.Fatalf("BSS %v", b)
+	}
+	if e := b.Elements[1]; e.Id != 48 || e.Name != "RSN" || len(e.Data) != 20 {
+		t.Errorf("RSN element %v", e)
+	}
+	if e := b.Elements[4]; e.VendorOui != "00:50:f2" || e.VendorType != 2 {
+		t.Errorf("vendor element %v", e)
+	}
+	if s := b.Security; s == nil || s.Summary != "WPA2-Personal" || s.GroupCipher != "CCMP-128" || len(s.PairwiseCiphers) != 1 {
+		t.Errorf("security %v", s)
+	}
+	if o := b.OperatingChannel; o == nil || o.WidthMhz != 40 || o.Center != 38 {
+		t.Errorf("operating channel %v", o)
+	}
+	if l := b.BssLoad; l == nil || l.StationCount != 3 || l.Utilization < 0.5 || l.Utilization > 0.51 {
+		t.Errorf("BSS load %v", l)
+	}
+	if s := resp.Bss[3].Security; s == nil || s.Summary != "Open" || resp.Bss[3].Elements != nil {
+		t.Errorf("BSS without IEs %v", resp.Bss[3])
+	}
+
+	_, err = c.ListBSS(ctx, &wlanpb.ListBSSRequest{InterfaceGuid: "{00000000-0000-0000-0000-000000000000}"})
+	wantCode(t, "unknown interface", err, codes.NotFound)
+	_, err = c.ListBSS(ctx, &wlanpb.ListBSSRequest{InterfaceGuid: "wlan0"})
+	wantCode(t, "invalid GUID", err, codes.InvalidArgument)
+}
+
+func TestProfiles(t *testing.T) {
+	c, ctx := dial(t), context.Background()
+	const home = "<WLANProfile><name>home</name><SSIDConfig><SSID><name>home</name></SSID></SSIDConfig></WLANProfile>"
+	set, err := c.SetProfile(ctx, &wlanpb.SetProfileRequest{Selector: "intel", Xml: home})
+	if err != nil || len(set.Profiles) != 1 || set.Profiles[0].Name != "home" || set.Profiles[0].InterfaceGuid != simfiletest.Intel {
+		t.Fatalf("SetProfile: %v %v", set, err)
+	}
+	list, err := c.ListProfiles(ctx, &wlanpb.ListProfilesRequest{})
+	if err != nil || len(list.Profiles) != 2 || list.Profiles[0].Name != "corp" || list.Profiles[0].Xml != "" {
+		t.Errorf("ListProfiles: %v %v", list, err)
+	}
+	get, err := c.GetProfile(ctx, &wlanpb.GetProfileRequest{Name: "home", PlaintextKey: true})
+	if err != nil || len(get.Profiles) != 1 || get.Profiles[0].Xml != home {
+		t.Errorf("GetProfile: %v %v", get, err)
+	}
+	_, err = c.SetProfile(ctx, &wlanpb.SetProfileRequest{Xml: "<WLANProfile/>"})
+	wantCode(t, "profile without a name", err, codes.InvalidArgument)
+	if _, err := c.DeleteProfile(ctx, &wlanpb.DeleteProfileRequest{Name: "home"}); err != nil {
+		t.Errorf("DeleteProfile: %v", err)
+	}
+	_, err = c.DeleteProfile(ctx, &wlanpb.DeleteProfileRequest{Name: "home"})
+	wantCode(t, "delete a deleted profile", err, codes.NotFound)
+	_, err = c.GetProfile(ctx, &wlanpb.GetProfileRequest{Name: "home"})
+	wantCode(t, "get a deleted profile", err, codes.NotFound)
+}
+
+func TestConnect(t *testing.T) {
+	c, ctx := dial(t), context.Background()
+	resp, err := c.Disconnect(ctx, &wlanpb.DisconnectRequest{Selector: "intel"})
+	if err != nil || len(resp.Interfaces) != 1 || resp.Interfaces[0].State != "disconnected" || resp.Interfaces[0].Connection != nil {
+		t.Fatalf("Disconnect: %v %v", resp, err)
+	}
+	resp, err = c.Connect(ctx, &wlanpb.ConnectRequest{Selector: simfiletest.Intel, Profile: "corp", Bssids: []string{"00:1a:1e:00:00:02"}})
+	if err != nil || len(resp.Interfaces) != 1 || resp.Interfaces[0].Connection.GetBssid() != "00:1a:1e:00:00:02" {
+		t.Fatalf("Connect: %v %v", resp, err)
+	}
+	for _, req := range []*wlanpb.ConnectRequest{
+		{Selector: simfiletest.Intel},
+		{Selector: simfiletest.Intel, Profile: "corp", Bssids: []string{"00:1a"}},
+		{Selector: simfiletest.Intel, Profile: "corp", BssType: "mesh"},
+	} {
+		_, err := c.Connect(ctx, req)
+		wantCode(t, "Connect "+req.String(), err, codes.InvalidArgument)
+	}
+	_, err = c.Connect(ctx, &wlanpb.ConnectRequest{Selector: "realtek", Profile: "corp"})
+	wantCode(t, "connect without the profile", err, codes.NotFound)
+}
+
+func TestHostedNetwork(t *testing.T) {
+	c, ctx := dial(t), context.Background()
+	h, err := c.GetHostedNetwork(ctx, &wlanpb.GetHostedNetworkRequest{})
+	if err != nil || h.State != "idle" || string(h.Ssid) != "kiosk" || h.MaxPeers != 8 {
+		t.Errorf("GetHostedNetwork: %v %v", h, err)
+	}
+	h, err = c.SetHostedNetwork(ctx, &wlanpb.SetHostedNetworkRequest{Action: "start", Ssid: []byte("lobby"), MaxPeers: 4})
+	if err != nil || h.State != "active" || string(h.Ssid) != "lobby" || h.MaxPeers != 4 {
+		t.Errorf("start: %v %v", h, err)
+	}
+	_, err = c.SetHostedNetwork(ctx, &wlanpb.SetHostedNetworkRequest{Action: "start"})
+	wantCode(t, "start an active hosted network", err, codes.FailedPrecondition)
+	_, err = c.SetHostedNetwork(ctx, &wlanpb.SetHostedNetworkRequest{Action: "restart"})
+	wantCode(t, "unknown action", err, codes.InvalidArgument)
+	h, err = c.SetHostedNetwork(ctx, &wlanpb.SetHostedNetworkRequest{MaxPeers: 6})
+	if err != nil || string(h.Ssid) != "lobby" || h.MaxPeers != 6 {
+		t.Errorf("set the maximum number of peers: %v %v", h, err)
+	}
+}
+
+func TestNonUTF8SSID(t *testing.T) {
+	c, ctx := dial(t), context.Background()
+	ssid := []byte{'c', 'a', 'f', 0xe9, 0xff}
+	h, err := c.SetHostedNetwork(ctx, &wlanpb.SetHostedNetworkRequest{Ssid: ssid, MaxPeers: 2})
+	if err != nil || !bytes.Equal(h.Ssid, ssid) {
+		t.Errorf("SetHostedNetwork: %v %v", h, err)
+	}
+	h, err = c.GetHostedNetwork(ctx, &wlanpb.GetHostedNetworkRequest{})
+	if err != nil || !bytes.Equal(h.Ssid, ssid) {
+		t.Errorf("GetHostedNetwork: %v %v", h, err)
+	}
+}
+
+func TestWatchEvents(t *testing.T) {
+	c := dial(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	stream, err := c.WatchEvents(ctx, &wlanpb.WatchEventsRequest{Sources: []string{"acm"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := stream.Header(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.SetHostedNetwork(ctx, &wlanpb.SetHostedNetworkRequest{Action: "start"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Scan(ctx, &wlanpb.ScanRequest{InterfaceGuid: simfiletest.Intel}); err != nil {
+		t.Fatal(err)
+	}
+	e, err := stream.Recv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e.InterfaceGuid != simfiletest.Intel || e.Source != "acm" || e.Code != 7 || e.Notification != "scan complete" || e.Reason != "" {
+		t.Errorf("event %v", e)
+	}
+}
@@ -0,0 +1,163 @@
+package grpcapi
+
+import (
+	"net"
+
+	"wlanapi"
+	"wlanapi/binary"
+	"wlanapi/grpcapi/wlanpb"
+	"wlanapi/ie"
+)
+
+func mac(addr [6]byte) string {
+	return net.HardwareAddr(addr[:]).String()
+}
+
+func newInterface(i *wlanapi.Interface, c *binary.ConnectionAttributes) *wlanpb.Interface {
+	m := &wlanpb.Interface{Guid: i.GUID.String(), Description: i.Description, State: i.State.String()}
+	if c != nil {
+		m.Connection = &wlanpb.Connection{
+			Mode:            wlanapi.WLAN_CONNECTION_MODE(c.Mode).String(),
+			Profile:         c.ProfileName,
+			Ssid:            c.SSID,
+			Bssid:           mac(c.BSSID),
+			BssType:         wlanapi.DOT11_BSS_TYPE(c.BssType).String(),
+			PhyType:         wlanapi.DOT11_PHY_TYPE(c.PhyType).String(),
+			SignalQuality:   c.SignalQuality,
+			RxRateKbps:      c.RxRate,
+			TxRateKbps:      c.TxRate,
+			SecurityEnabled: c.SecurityEnabled,
+			OneXEnabled:     c.OneXEnabled,
+			AuthAlgorithm:   wlanapi.DOT11_AUTH_ALGORITHM(c.AuthAlgorithm).String(),
+			CipherAlgorithm: wlanapi.DOT11_CIPHER_ALGORITHM(c.CipherAlgorithm).String(),
+		}
+	}
+	return m
+}
+
+func newNetwork(n binary.AvailableNetwork) *wlanpb.Network {
+	m := &wlanpb.Network{
+		Ssid:            n.SSID,
+		Profile:         n.ProfileName,
+		BssType:         wlanapi.DOT11_BSS_TYPE(n.BssType).String(),
+		BssCount:        n.NumberOfBssids,
+		Connectable:     n.NetworkConnectable,
+		SignalQuality:   n.SignalQuality,
+		SecurityEnabled: n.SecurityEnabled,
+		AuthAlgorithm:   wlanapi.DOT11_AUTH_ALGORITHM(n.DefaultAuthAlgorithm).String(),
+		CipherAlgorithm: wlanapi.DOT11_CIPHER_ALGORITHM(n.DefaultCipherAlgorithm).String(),
+		Connected:       n.Flags&wlanapi.WLAN_AVAILABLE_NETWORK_CONNECTED != 0,
+		HasProfile:      n.Flags&wlanapi.WLAN_AVAILABLE_NETWORK_HAS_PROFILE != 0,
+	}
+	if !n.NetworkConnectable {
+		m.NotConnectableReason = wlanapi.WLAN_REASON_CODE(n.NotConnectableReason).String()
+	}
+	for _, t := range n.PhyTypes {
+		m.PhyTypes = append(m.PhyTypes, wlanapi.DOT11_PHY_TYPE(t).String())
+	}
+	return m
+}
+
+//newBSS converts a BSS entry, decoding the information elements that parse. The elements before one that runs
+//past the end of the IEs are still listed.
+func newBSS(e binary.BSSEntry) *wlanpb.BSS {
+	m := &wlanpb.BSS{
+		Bssid:         mac(e.BSSID),
+		Ssid:          e.SSID,
+		PhyId:         e.PhyID,
+		BssType:       wlanapi.DOT11_BSS_TYPE(e.BssType).String(),
+		PhyType:       wlanapi.DOT11_PHY_TYPE(e.PhyType).String(),
+		Rssi:          e.RSSI,
+		LinkQuality:   e.LinkQuality,
+		InRegDomain:   e.InRegDomain,
+		BeaconPeriod:  uint32(e.BeaconPeriod),
+		Timestamp:     e.Timestamp,
+		HostTimestamp: e.HostTimestamp,
+		Capability:    uint32(e.CapabilityInformation),
+		FrequencyKhz:  e.ChCenterFrequency,
+		Ies:           e.IEs,
+	}
+	band := wlanapi.BandOf(e.ChCenterFrequency)
+	if band != 0 {
+		m.Band = band.String()
+		m.Channel = uint32(wlanapi.ChannelOf(e.ChCenterFrequency))
+	}
+	for _, r := range e.Rates {
+		m.Rates = append(m.Rates, uint32(r))
+	}
+
+	elements, _ := ie.Parse(e.IEs)
+	for _, el := range elements {
+		pe := &wlanpb.InformationElement{Id: uint32(el.ID), Name: el.ID.String(), Data: el.Data}
+		if el.ID == ie.Extension && len(el.Data) > 0 {
+			pe.ExtensionId = uint32(el.Data[0])
+		}
+		if oui, t, ok := el.Vendor(); ok {
+			pe.VendorOui, pe.VendorType = oui.String(), uint32(t)
+		}
+		m.Elements = append(m.Elements, pe)
+	}
+	if security, err := ie.ParseSecurity(e.CapabilityInformation, elements); err == nil {
+		ps := &wlanpb.Security{Summary: security.String(), Privacy: security.Privacy}
+		for _, a := range security.AKMs() {
+			ps.Akms = append(ps.Akms, a.String())
+		}
+		for _, c := range security.PairwiseCiphers() {
+			ps.PairwiseCiphers = append(ps.PairwiseCiphers, c.String())
+		}
+		if r := security.RSN; r != nil {
+			ps.GroupCipher = r.GroupCipher.String()
+		} else if r := security.WPA; r != nil {
+			ps.GroupCipher = r.GroupCipher.String()
+		}
+		m.Security = ps
+	}
+	if m.Channel != 0 {
+		o := elements.OperatingChannel(int(m.Channel), band == wlanapi.Band6GHz)
+		m.OperatingChannel = &wlanpb.OperatingChannel{
+			Primary:  uint32(o.Primary),
+			WidthMhz: uint32(o.Width),
+			Center:   uint32(o.Center),
+			Segment1: uint32(o.Segment1),
+		}
+	}
+	if el, ok := elements.Find(ie.BSSLoad); ok {
+		if l, err := ie.ParseBSSLoad(el.Data); err == nil {
+			m.BssLoad = &wlanpb.BSSLoad{
+				StationCount:               uint32(l.StationCount),
+				Utilization:                l.Utilization(),
+				AvailableAdmissionCapacity: uint32(l.AvailableAdmissionCapacity),
+			}
+		}
+	}
+	return m
+}
+
+func newProfile(i *wlanapi.Interface, p binary.ProfileInfo) *wlanpb.Profile {
+	return &wlanpb.Profile{
+		InterfaceGuid: i.GUID.String(),
+		Name:          p.Name,
+		GroupPolicy:   p.Flags&wlanapi.WLAN_PROFILE_GROUP_POLICY != 0,
+		User:          p.Flags&wlanapi.WLAN_PROFILE_USER != 0,
+	}
+}
+
+func newHostedNetwork(status *binary.HostedNetworkStatus, settings *wlanapi.HostedNetworkSettings) *wlanpb.HostedNetwork {
+	m := &wlanpb.HostedNetwork{
+		State:    wlanapi.WLAN_HOSTED_NETWORK_STATE(status.State).String(),
+		Ssid:     settings.SSID,
+		MaxPeers: settings.MaxPeers,
+	}
+	if status.BSSID != [6]byte{} {
+		m.Bssid = mac(status.BSSID)
+		m.PhyType = wlanapi.DOT11_PHY_TYPE(status.PhyType).String()
+		m.FrequencyKhz = status.ChannelFrequency
+	}
+	for _, p := range status.Peers {
+		m.Peers = append(m.Peers, &wlanpb.Peer{
+			Mac:       mac(p.MacAddress),
+			AuthState: wlanapi.WLAN_HOSTED_NETWORK_PEER_AUTH_STATE(p.AuthState).String(),
+		})
+	}
+	return m
+}
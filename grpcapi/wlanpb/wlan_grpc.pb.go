@@ -0,0 +1,591 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.2.0
+// - protoc             v3.5.1-go
+// source: wlan.proto
+
+package wlanpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// WLANClient is the client API for WLAN service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type WLANClient interface {
+	// ListInterfaces lists the selected interfaces and their connections.
+	ListInterfaces(ctx context.Context, in *ListInterfacesRequest, opts ...grpc.CallOption) (*ListInterfacesResponse, error)
+	// Scan requests a scan; its completion is reported by WatchEvents.
+	Scan(ctx context.Context, in *ScanRequest, opts ...grpc.CallOption) (*Interface, error)
+	// ListNetworks lists the networks available to an interface.
+	ListNetworks(ctx context.Context, in *ListNetworksRequest, opts ...grpc.CallOption) (*ListNetworksResponse, error)
+	// ListBSS lists the BSSes visible to an interface, with their decoded information elements.
+	ListBSS(ctx context.Context, in *ListBSSRequest, opts ...grpc.CallOption) (*ListBSSResponse, error)
+	// ListProfiles lists the profiles of the selected interfaces.
+	ListProfiles(ctx context.Context, in *ListProfilesRequest, opts ...grpc.CallOption) (*ListProfilesResponse, error)
+	// GetProfile returns a profile of the selected interfaces with its XML.
+	GetProfile(ctx context.Context, in *GetProfileRequest, opts ...grpc.CallOption) (*ListProfilesResponse, error)
+	// SetProfile adds or replaces a profile on the selected interfaces.
+	SetProfile(ctx context.Context, in *SetProfileRequest, opts ...grpc.CallOption) (*ListProfilesResponse, error)
+	// DeleteProfile deletes a profile from the selected interfaces.
+	DeleteProfile(ctx context.Context, in *DeleteProfileRequest, opts ...grpc.CallOption) (*DeleteProfileResponse, error)
+	// Connect connects the selected interfaces.
+	Connect(ctx context.Context, in *ConnectRequest, opts ...grpc.CallOption) (*ListInterfacesResponse, error)
+	// Disconnect disconnects the selected interfaces.
+	Disconnect(ctx context.Context, in *DisconnectRequest, opts ...grpc.CallOption) (*ListInterfacesResponse, error)
+	// GetHostedNetwork returns the status and settings of the wireless Hosted Network.
+	GetHostedNetwork(ctx context.Context, in *GetHostedNetworkRequest, opts ...grpc.CallOption) (*HostedNetwork, error)
+	// SetHostedNetwork changes the settings of the Hosted Network, then starts or stops it.
+	SetHostedNetwork(ctx context.Context, in *SetHostedNetworkRequest, opts ...grpc.CallOption) (*HostedNetwork, error)
+	// WatchEvents streams the notifications of the WLAN service until the call is cancelled.
+	WatchEvents(ctx context.Context, in *WatchEventsRequest, opts ...grpc.CallOption) (WLAN_WatchEventsClient, error)
+}
+
+type wLANClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewWLANClient(cc grpc.ClientConnInterface) WLANClient {
+	return &wLANClient{cc}
+}
+
+func (c *wLANClient) ListInterfaces(ctx context.Context, in *ListInterfacesRequest, opts ...grpc.CallOption) (*ListInterfacesResponse, error) {
+	out := new(ListInterfacesResponse)
+	err := c.cc.Invoke(ctx, "/wlanapi.v1.WLAN/ListInterfaces", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *wLANClient) Scan(ctx context.Context, in *ScanRequest, opts ...grpc.CallOption) (*Interface, error) {
+	out := new(Interface)
+	err := c.cc.Invoke(ctx, "/wlanapi.v1.WLAN/Scan", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *wLANClient) ListNetworks(ctx context.Context, in *ListNetworksRequest, opts ...grpc.CallOption) (*ListNetworksResponse, error) {
+	out := new(ListNetworksResponse)
+	err := c.cc.Invoke(ctx, "/wlanapi.v1.WLAN/ListNetworks", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *wLANClient) ListBSS(ctx context.Context, in *ListBSSRequest, opts ...grpc.CallOption) (*ListBSSResponse, error) {
+	out := new(ListBSSResponse)
+	err := c.cc.Invoke(ctx, "/wlanapi.v1.WLAN/ListBSS", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *wLANClient) ListProfiles(ctx context.Context, in *ListProfilesRequest, opts ...grpc.CallOption) (*ListProfilesResponse, error) {
+	out := new(ListProfilesResponse)
+	err := c.cc.Invoke(ctx, "/wlanapi.v1.WLAN/ListProfiles", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *wLANClient) GetProfile(ctx context.Context, in *GetProfileRequest, opts ...grpc.CallOption) (*ListProfilesResponse, error) {
+	out := new(ListProfilesResponse)
+	err := c.cc.Invoke(ctx, "/wlanapi.v1.WLAN/GetProfile", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *wLANClient) SetProfile(ctx context.Context, in *SetProfileRequest, opts ...grpc.CallOption) (*ListProfilesResponse, error) {
+	out := new(ListProfilesResponse)
+	err := c.cc.Invoke(ctx, "/wlanapi.v1.WLAN/SetProfile", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *wLANClient) DeleteProfile(ctx context.Context, in *DeleteProfileRequest, opts ...grpc.CallOption) (*DeleteProfileResponse, error) {
+	out := new(DeleteProfileResponse)
+	err := c.cc.Invoke(ctx, "/wlanapi.v1.WLAN/DeleteProfile", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *wLANClient) Connect(ctx context.Context, in *ConnectRequest, opts ...grpc.CallOption) (*ListInterfacesResponse, error) {
+	out := new(ListInterfacesResponse)
+	err := c.cc.Invoke(ctx, "/wlanapi.v1.WLAN/Connect", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *wLANClient) Disconnect(ctx context.Context, in *DisconnectRequest, opts ...grpc.CallOption) (*ListInterfacesResponse, error) {
+	out := new(ListInterfacesResponse)
+	err := c.cc.Invoke(ctx, "/wlanapi.v1.WLAN/Disconnect", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *wLANClient) GetHostedNetwork(ctx context.Context, in *GetHostedNetworkRequest, opts ...grpc.CallOption) (*HostedNetwork, error) {
+	out := new(HostedNetwork)
+	err := c.cc.Invoke(ctx, "/wlanapi.v1.WLAN/GetHostedNetwork", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *wLANClient) SetHostedNetwork(ctx context.Context, in *SetHostedNetworkRequest, opts ...grpc.CallOption) (*HostedNetwork, error) {
+	out := new(HostedNetwork)
+	err := c.cc.Invoke(ctx, "/wlanapi.v1.WLAN/SetHostedNetwork", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *wLANClient) WatchEvents(ctx context.Context, in *WatchEventsRequest, opts ...grpc.CallOption) (WLAN_WatchEventsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &WLAN_ServiceDesc.Streams[0], "/wlanapi.v1.WLAN/WatchEvents", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &wLANWatchEventsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type WLAN_WatchEventsClient interface {
+	Recv() (*Event, error)
+	grpc.ClientStream
+}
+
+type wLANWatchEventsClient struct {
+	grpc.ClientStream
+}
+
+func (x *wLANWatchEventsClient) Recv() (*Event, error) {
+	m := new(Event)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// WLANServer is the server API for WLAN service.
+// All implementations must embed UnimplementedWLANServer
+// for forward compatibility
+type WLANServer interface {
+	// ListInterfaces lists the selected interfaces and their connections.
+	ListInterfaces(context.Context, *ListInterfacesRequest) (*ListInterfacesResponse, error)
+	// Scan requests a scan; its completion is reported by WatchEvents.
+	Scan(context.Context, *ScanRequest) (*Interface, error)
+	// ListNetworks lists the networks available to an interface.
+	ListNetworks(context.Context, *ListNetworksRequest) (*ListNetworksResponse, error)
+	// ListBSS lists the BSSes visible to an interface, with their decoded information elements.
+	ListBSS(context.Context, *ListBSSRequest) (*ListBSSResponse, error)
+	// ListProfiles lists the profiles of the selected interfaces.
+	ListProfiles(context.Context, *ListProfilesRequest) (*ListProfilesResponse, error)
+	// GetProfile returns a profile of the selected interfaces with its XML.
+	GetProfile(context.Context, *GetProfileRequest) (*ListProfilesResponse, error)
+	// SetProfile adds or replaces a profile on the selected interfaces.
+	SetProfile(context.Context, *SetProfileRequest) (*ListProfilesResponse, error)
+	// DeleteProfile deletes a profile from the selected interfaces.
+	DeleteProfile(context.Context, *DeleteProfileRequest) (*DeleteProfileResponse, error)
+	// Connect connects the selected interfaces.
+	Connect(context.Context, *ConnectRequest) (*ListInterfacesResponse, error)
+	// Disconnect disconnects the selected interfaces.
+	Disconnect(context.Context, *DisconnectRequest) (*ListInterfacesResponse, error)
+	// GetHostedNetwork returns the status and settings of the wireless Hosted Network.
+	GetHostedNetwork(context.Context, *GetHostedNetworkRequest) (*HostedNetwork, error)
+	// SetHostedNetwork changes the settings of the Hosted Network, then starts or stops it.
+	SetHostedNetwork(context.Context, *SetHostedNetworkRequest) (*HostedNetwork, error)
+	// WatchEvents streams the notifications of the WLAN service until the call is cancelled.
+	WatchEvents(*WatchEventsRequest, WLAN_WatchEventsServer) error
+	mustEmbedUnimplementedWLANServer()
+}
+
+// UnimplementedWLANServer must be embedded to have forward compatible implementations.
+type UnimplementedWLANServer struct {
+}
+
+func (UnimplementedWLANServer) ListInterfaces(context.Context, *ListInterfacesRequest) (*ListInterfacesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListInterfaces not implemented")
+}
+func (UnimplementedWLANServer) Scan(context.Context, *ScanRequest) (*Interface, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Scan not implemented")
+}
+func (UnimplementedWLANServer) ListNetworks(context.Context, *ListNetworksRequest) (*ListNetworksResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListNetworks not implemented")
+}
+func (UnimplementedWLANServer) ListBSS(context.Context, *ListBSSRequest) (*ListBSSResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListBSS not implemented")
+}
+func (UnimplementedWLANServer) ListProfiles(context.Context, *ListProfilesRequest) (*ListProfilesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListProfiles not implemented")
+}
+func (UnimplementedWLANServer) GetProfile(context.Context, *GetProfileRequest) (*ListProfilesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetProfile not implemented")
+}
+func (UnimplementedWLANServer) SetProfile(context.Context, *SetProfileRequest) (*ListProfilesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetProfile not implemented")
+}
+func (UnimplementedWLANServer) DeleteProfile(context.Context, *DeleteProfileRequest) (*DeleteProfileResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteProfile not implemented")
+}
+func (UnimplementedWLANServer) Connect(context.Context, *ConnectRequest) (*ListInterfacesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Connect not implemented")
+}
+func (UnimplementedWLANServer) Disconnect(context.Context, *DisconnectRequest) (*ListInterfacesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Disconnect not implemented")
+}
+func (UnimplementedWLANServer) GetHostedNetwork(context.Context, *GetHostedNetworkRequest) (*HostedNetwork, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetHostedNetwork not implemented")
+}
+func (UnimplementedWLANServer) SetHostedNetwork(context.Context, *SetHostedNetworkRequest) (*HostedNetwork, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetHostedNetwork not implemented")
+}
+func (UnimplementedWLANServer) WatchEvents(*WatchEventsRequest, WLAN_WatchEventsServer) error {
+	return status.Errorf(codes.Unimplemented, "method WatchEvents not implemented")
+}
+func (UnimplementedWLANServer) mustEmbedUnimplementedWLANServer() {}
+
+// UnsafeWLANServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to WLANServer will
+// result in compilation errors.
+type UnsafeWLANServer interface {
+	mustEmbedUnimplementedWLANServer()
+}
+
+func RegisterWLANServer(s grpc.ServiceRegistrar, srv WLANServer) {
+	s.RegisterService(&WLAN_ServiceDesc, srv)
+}
+
+func _WLAN_ListInterfaces_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListInterfacesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WLANServer).ListInterfaces(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/wlanapi.v1.WLAN/ListInterfaces",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WLANServer).ListInterfaces(ctx, req.(*ListInterfacesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WLAN_Scan_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ScanRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WLANServer).Scan(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/wlanapi.v1.WLAN/Scan",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WLANServer).Scan(ctx, req.(*ScanRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WLAN_ListNetworks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListNetworksRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WLANServer).ListNetworks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/wlanapi.v1.WLAN/ListNetworks",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WLANServer).ListNetworks(ctx, req.(*ListNetworksRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WLAN_ListBSS_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListBSSRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WLANServer).ListBSS(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/wlanapi.v1.WLAN/ListBSS",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WLANServer).ListBSS(ctx, req.(*ListBSSRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WLAN_ListProfiles_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListProfilesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WLANServer).ListProfiles(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/wlanapi.v1.WLAN/ListProfiles",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WLANServer).ListProfiles(ctx, req.(*ListProfilesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WLAN_GetProfile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetProfileRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WLANServer).GetProfile(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/wlanapi.v1.WLAN/GetProfile",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WLANServer).GetProfile(ctx, req.(*GetProfileRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WLAN_SetProfile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetProfileRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WLANServer).SetProfile(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/wlanapi.v1.WLAN/SetProfile",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WLANServer).SetProfile(ctx, req.(*SetProfileRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WLAN_DeleteProfile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteProfileRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WLANServer).DeleteProfile(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/wlanapi.v1.WLAN/DeleteProfile",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WLANServer).DeleteProfile(ctx, req.(*DeleteProfileRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WLAN_Connect_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ConnectRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WLANServer).Connect(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/wlanapi.v1.WLAN/Connect",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WLANServer).Connect(ctx, req.(*ConnectRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WLAN_Disconnect_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DisconnectRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WLANServer).Disconnect(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/wlanapi.v1.WLAN/Disconnect",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WLANServer).Disconnect(ctx, req.(*DisconnectRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WLAN_GetHostedNetwork_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetHostedNetworkRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WLANServer).GetHostedNetwork(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/wlanapi.v1.WLAN/GetHostedNetwork",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WLANServer).GetHostedNetwork(ctx, req.(*GetHostedNetworkRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WLAN_SetHostedNetwork_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetHostedNetworkRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WLANServer).SetHostedNetwork(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/wlanapi.v1.WLAN/SetHostedNetwork",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WLANServer).SetHostedNetwork(ctx, req.(*SetHostedNetworkRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WLAN_WatchEvents_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchEventsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(WLANServer).WatchEvents(m, &wLANWatchEventsServer{stream})
+}
+
+type WLAN_WatchEventsServer interface {
+	Send(*Event) error
+	grpc.ServerStream
+}
+
+type wLANWatchEventsServer struct {
+	grpc.ServerStream
+}
+
+func (x *wLANWatchEventsServer) Send(m *Event) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// WLAN_ServiceDesc is the grpc.ServiceDesc for WLAN service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var WLAN_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "wlanapi.v1.WLAN",
+	HandlerType: (*WLANServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListInterfaces",
+			Handler:    _WLAN_ListInterfaces_Handler,
+		},
+		{
+			MethodName: "Scan",
+			Handler:    _WLAN_Scan_Handler,
+		},
+		{
+			MethodName: "ListNetworks",
+			Handler:    _WLAN_ListNetworks_Handler,
+		},
+		{
+			MethodName: "ListBSS",
+			Handler:    _WLAN_ListBSS_Handler,
+		},
+		{
+			MethodName: "ListProfiles",
+			Handler:    _WLAN_ListProfiles_Handler,
+		},
+		{
+			MethodName: "GetProfile",
+			Handler:    _WLAN_GetProfile_Handler,
+		},
+		{
+			MethodName: "SetProfile",
+			Handler:    _WLAN_SetProfile_Handler,
+		},
+		{
+			MethodName: "DeleteProfile",
+			Handler:    _WLAN_DeleteProfile_Handler,
+		},
+		{
+			MethodName: "Connect",
+			Handler:    _WLAN_Connect_Handler,
+		},
+		{
+			MethodName: "Disconnect",
+			Handler:    _WLAN_Disconnect_Handler,
+		},
+		{
+			MethodName: "GetHostedNetwork",
+			Handler:    _WLAN_GetHostedNetwork_Handler,
+		},
+		{
+			MethodName: "SetHostedNetwork",
+			Handler:    _WLAN_SetHostedNetwork_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchEvents",
+			Handler:       _WLAN_WatchEvents_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "wlan.proto",
+}
@@ -0,0 +1,9 @@
+//go:build !windows
+// +build !windows
+
+package wlanapi
+
+import "wlanapi/binary"
+
+//GUID identifies interfaces and device services. It has the layout of windows.GUID.
+type GUID = binary.GUID
@@ -0,0 +1,9 @@
+//go:build windows
+// +build windows
+
+package wlanapi
+
+import "golang.org/x/sys/windows"
+
+//GUID identifies interfaces and device services. It is windows.GUID on Windows.
+type GUID = windows.GUID
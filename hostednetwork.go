@@ -0,0 +1,84 @@
+package wlanapi
+
+import (
+	"errors"
+
+	"wlanapi/binary"
+)
+
+//ErrHostedNetworkState is returned when the state of the Hosted Network does not allow an operation: starting
+//it when it is unavailable or already active, or stopping it when it is not active.
+var ErrHostedNetworkState = errors.New("wlanapi: operation not valid in the state of the hosted network")
+
+//HostedNetworkSettings are the connection settings of the wireless Hosted Network,
+//as WLAN_HOSTED_NETWORK_CONNECTION_SETTINGS.
+type HostedNetworkSettings struct {
+	SSID     []byte
+	MaxPeers uint32
+}
+
+//HostedNetworkBackend is implemented by backends that run the wireless Hosted Network.
+type HostedNetworkBackend interface {
+	//HostedNetworkStatus queries the status of the Hosted Network, as WlanHostedNetworkQueryStatus.
+	HostedNetworkStatus() (*binary.HostedNetworkStatus, error)
+	//HostedNetworkSettings queries the connection settings, as WlanHostedNetworkQueryProperty.
+	HostedNetworkSettings() (*HostedNetworkSettings, error)
+	//SetHostedNetworkSettings sets the connection settings, as WlanHostedNetworkSetProperty.
+	SetHostedNetworkSettings(s HostedNetworkSettings) error
+	//StartHostedNetwork starts the Hosted Network, as WlanHostedNetworkStartUsing.
+	StartHostedNetwork() error
+	//StopHostedNetwork stops the Hosted Network, as WlanHostedNetworkStopUsing.
+	StopHostedNetwork() error
+}
+
+func (c *Client) hostedNetworkBackend() (HostedNetworkBackend, error) {
+	if b, ok := c.backend.(HostedNetworkBackend); ok {
+		return b, nil
+	}
+	return nil, ErrBackendNotSupported
+}
+
+//HostedNetworkStatus queries the status of the wireless Hosted Network and its peers.
+func (c *Client) HostedNetworkStatus() (*binary.HostedNetworkStatus, error) {
+	b, err := c.hostedNetworkBackend()
+	if err != nil {
+		return nil, err
+	}
+	return b.HostedNetworkStatus()
+}
+
+//HostedNetworkSettings queries the SSID and maximum number of peers of the wireless Hosted Network.
+func (c *Client) HostedNetworkSettings() (*HostedNetworkSettings, error) {
+	b, err := c.hostedNetworkBackend()
+	if err != nil {
+		return nil, err
+	}
+	return b.HostedNetworkSettings()
+}
+
+//SetHostedNetworkSettings sets the SSID and maximum number of peers of the wireless Hosted Network.
+func (c *Client) SetHostedNetworkSettings(s HostedNetworkSettings) error {
+	b, err := c.hostedNetworkBackend()
+	if err != nil {
+		return err
+	}
+	return b.SetHostedNetworkSettings(s)
+}
+
+//StartHostedNetwork starts the wireless Hosted Network.
+func (c *Client) StartHostedNetwork() error {
+	b, err := c.hostedNetworkBackend()
+	if err != nil {
+		return err
+	}
+	return b.StartHostedNetwork()
+}
+
+//StopHostedNetwork stops the wireless Hosted Network.
+func (c *Client) StopHostedNetwork() error {
+	b, err := c.hostedNetworkBackend()
+	if err != nil {
+		return err
+	}
+	return b.StopHostedNetwork()
+}
@@ -0,0 +1,128 @@
+package httpapi
+
+import (
+	"crypto/subtle"
+	"crypto/x509"
+	"errors"
+	"net/http"
+	"strings"
+)
+
+//Authenticator authorizes the requests of a Server.
+type Authenticator interface {
+	//Authenticate returns an error when the request is not authorized; it is answered with 401 Unauthorized.
+	Authenticate(r *http.Request) error
+}
+
+//Challenger is implemented by Authenticators that tell clients how to authenticate, in the WWW-Authenticate
+//header of 401 Unauthorized responses.
+type Challenger interface {
+	Challenge() string
+}
+
+//AuthenticatorFunc adapts a function to an Authenticator.
+type AuthenticatorFunc func(r *http.Request) error
+
+func (f AuthenticatorFunc) Authenticate(r *http.Request) error {
+	return f(r)
+}
+
+//ErrUnauthorized is returned by the Authenticators of the package for requests without valid credentials.
+var ErrUnauthorized = errors.New("httpapi: unauthorized")
+
+type bearerTokens [][]byte
+
+//BearerToken returns an Authenticator of the requests whose Authorization header carries one of the tokens,
+//as "Bearer token". It panics if a token is empty, as an unset environment variable would give, since the
+//empty token would authorize any request.
+func BearerToken(tokens ...string) Authenticator {
+	b := make(bearerTokens, len(tokens))
+	for n, t := range tokens {
+		if strings.TrimSpace(t) == "" {
+			panic("httpapi: BearerToken token is empty")
+		}
+		b[n] = []byte(t)
+	}
+	return b
+}
+
+func (b bearerTokens) Authenticate(r *http.Request) error {
+	const prefix = "bearer "
+	h := r.Header.Get("Authorization")
+	if len(h) <= len(prefix) || !strings.EqualFold(h[:len(prefix)], prefix) {
+		return ErrUnauthorized
+	}
+	token := []byte(strings.TrimSpace(h[len(prefix):]))
+	if len(token) == 0 {
+		return ErrUnauthorized
+	}
+	match := 0
+	for _, t := range b {
+		//Compare with every token in constant time, so that the time taken does not tell which one matched.
+		match |= subtle.ConstantTimeCompare(token, t)
+	}
+	if match == 0 {
+		return ErrUnauthorized
+	}
+	return nil
+}
+
+func (b bearerTokens) Challenge() string {
+	return `Bearer realm="wlanapi"`
+}
+
+//ClientCertificate returns an Authenticator of the requests made over TLS with a client certificate that the
+//server verified, for mutual TLS. The tls.Config of the server must set ClientCAs and a ClientAuth that verifies
+//certificates, such as tls.RequireAndVerifyClientCert. A non-nil allow further restricts the accepted
+//certificates; see CommonNames.
+func ClientCertificate(allow func(*x509.Certificate) bool) Authenticator {
+	return AuthenticatorFunc(func(r *http.Request) error {
+		if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 || len(r.TLS.VerifiedChains[0]) == 0 {
+			return ErrUnauthorized
+		}
+		if allow != nil && !allow(r.TLS.VerifiedChains[0][0]) {
+			return ErrUnauthorized
+		}
+		return nil
+	})
+}
+
+//CommonNames returns a function for ClientCertificate that accepts the certificates with one of the common names.
+func CommonNames(names ...string) func(*x509.Certificate) bool {
+	return func(c *x509.Certificate) bool {
+		for _, n := range names {
+			if c.Subject.CommonName == n {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+type anyOf []Authenticator
+
+//AnyOf returns an Authenticator of the requests that one of a authorizes, such as either a bearer token or a
+//client certificate.
+func AnyOf(a ...Authenticator) Authenticator {
+	return anyOf(a)
+}
+
+func (a anyOf) Authenticate(r *http.Request) error {
+	err := ErrUnauthorized
+	for _, auth := range a {
+		if err = auth.Authenticate(r); err == nil {
+			return nil
+		}
+	}
+	return err
+}
+
+func (a anyOf) Challenge() string {
+	var challenges []string
+	for _, auth := range a {
+		if c, ok := auth.(Challenger); ok {
+			challenges = append(challenges, c.Challenge())
+		}
+	}
+	return strings.Join(challenges, ", ")
+}
@@ -0,0 +1,57 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+//events streams the notifications of the sources of the source parameter, or of all sources, until the client
+//goes away. Each event is named after its source and carries an Event.
+func (s *Server) events(c *call) (interface{}, error) {
+	sources := map[string]bool{}
+	if v := c.r.URL.Query().Get("source"); v != "" {
+		for _, name := range strings.Split(v, ",") {
+			sources[strings.TrimSpace(name)] = true
+		}
+	}
+	flusher, ok := c.w.(http.Flusher)
+	if !ok {
+		return nil, errors.New("httpapi: the connection cannot stream events")
+	}
+	ch, cancel, err := s.client.Backend().Notifications()
+	if err != nil {
+		return nil, err
+	}
+	defer cancel()
+
+	h := c.w.Header()
+	h.Set("Content-Type", "text/event-stream")
+	h.Set("Cache-Control", "no-cache")
+	c.w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+	for {
+		select {
+		case <-c.r.Context().Done():
+			return nil, nil
+		case n, ok := <-ch:
+			if !ok {
+				return nil, nil
+			}
+			e := Event(n.Event())
+			if len(sources) > 0 && !sources[e.Source] {
+				continue
+			}
+			data, err := json.Marshal(e)
+			if err != nil {
+				return nil, nil
+			}
+			if _, err := fmt.Fprintf(c.w, "event: %s\ndata: %s\n\n", e.Source, data); err != nil {
+				return nil, nil
+			}
+			flusher.Flush()
+		}
+	}
+}
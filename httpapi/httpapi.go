@@ -0,0 +1,417 @@
+//Package httpapi serves a Client over HTTP for remote management: its interfaces, scans, available networks and
+//BSSes, profiles, connections and the wireless Hosted Network as JSON resources, and its notifications as
+//Server-Sent Events.
+//
+//The endpoints are:
+//
+//	GET    /interfaces                the interfaces and their connections
+//	POST   /interfaces/{id}/scan      requests a scan
+//	GET    /interfaces/{id}/networks  the available networks
+//	GET    /interfaces/{id}/bss       the visible BSSes
+//	GET    /profiles                  the profiles of the selected interfaces
+//	POST   /profiles                  adds a profile
+//	GET    /profiles/{name}           a profile with its XML
+//	PUT    /profiles/{name}           adds or replaces a profile
+//	DELETE /profiles/{name}           deletes a profile
+//	POST   /connect                   connects the selected interfaces
+//	POST   /disconnect                disconnects the selected interfaces
+//	GET    /hostednetwork             the status and settings of the Hosted Network
+//	POST   /hostednetwork             changes the settings of the Hosted Network, starts or stops it
+//	GET    /events                    the notifications, as Server-Sent Events
+//	GET    /openapi.json              the OpenAPI document of the endpoints
+//
+//An {id} is the GUID of an interface, with or without braces. The other endpoints select interfaces with the
+//interface query parameter or request field, parsed by wlanapi.ParseInterfaceSelector. Failed requests are
+//answered with an Error.
+//
+//SSIDs are strings of bytes that JSON strings cannot carry when they are not valid UTF-8; the responses then
+//also give them in hexadecimal as ssidHex, which the requests accept in place of ssid.
+package httpapi
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"wlanapi"
+	"wlanapi/internal/remote"
+)
+
+//maxRequestBody bounds the size of request bodies; profiles are a few kilobytes.
+const maxRequestBody = 1 << 20
+
+//Server serves a Client over HTTP.
+type Server struct {
+	client  *wlanapi.Client
+	service *remote.Service
+	auth    Authenticator
+	routes  []route
+}
+
+//New returns a Server of c that authorizes requests with auth. A nil auth serves every request, which is
+//only safe on a loopback address. The OpenAPI document is always served without authentication.
+func New(c *wlanapi.Client, auth Authenticator) *Server {
+	return &Server{client: c, service: remote.New(c), auth: auth, routes: routeTable()}
+}
+
+//parameter is a query parameter of a route.
+type parameter struct {
+	name, typ, description string
+}
+
+//route is an endpoint. Its models also describe it in the OpenAPI document.
+type route struct {
+	method, path, summary string
+	query                 []parameter
+	//request and response are zero values of the models of the request and response bodies, or nil.
+	request, response interface{}
+	status            int
+	//stream marks the endpoints that write their own response as Server-Sent Events of the response model.
+	stream bool
+	//public marks the endpoints served without authentication.
+	public bool
+	handle func(s *Server, c *call) (interface{}, error)
+}
+
+//call is a request being served.
+type call struct {
+	w      http.ResponseWriter
+	r      *http.Request
+	params map[string]string
+}
+
+var selectorParameter = parameter{"interface", "string", "the interfaces: all, connected, a GUID or part of a description"}
+
+func routeTable() []route {
+	return []route{
+		{method: http.MethodGet, path: "/interfaces", summary: "List the interfaces and their connections",
+			query: []parameter{selectorParameter}, response: []Interface{}, status: http.StatusOK, handle: (*Server).interfaces},
+		{method: http.MethodPost, path: "/interfaces/{id}/scan", summary: "Request a scan",
+			response: Interface{}, status: http.StatusAccepted, handle: (*Server).scan},
+		{method: http.MethodGet, path: "/interfaces/{id}/networks", summary: "List the available networks",
+			response: []Network{}, status: http.StatusOK, handle: (*Server).networks},
+		{method: http.MethodGet, path: "/interfaces/{id}/bss", summary: "List the visible BSSes",
+			response: []BSS{}, status: http.StatusOK, handle: (*Server).bssList},
+		{method: http.MethodGet, path: "/profiles", summary: "List the profiles of the selected interfaces",
+			query: []parameter{selectorParameter}, response: []Profile{}, status: http.StatusOK, handle: (*Server).profiles},
+		{method: http.MethodPost, path: "/profiles", summary: "Add a profile to the selected interfaces",
+			request: ProfileRequest{}, response: []Profile{}, status: http.StatusCreated, handle: (*Server).createProfile},
+		{method: http.MethodGet, path: "/profiles/{name}", summary: "Get a profile with its XML",
+			query:    []parameter{selectorParameter, {"key", "boolean", "return the key in plain text"}},
+			response: []Profile{}, status: http.StatusOK, handle: (*Server).profile},
+		{method: http.MethodPut, path: "/profiles/{name}", summary: "Add or replace a profile on the selected interfaces",
+			request: ProfileRequest{}, response: []Profile{}, status: http.StatusOK, handle: (*Server).putProfile},
+		{method: http.MethodDelete, path: "/profiles/{name}", summary: "Delete a profile from the selected interfaces",
+			query: []parameter{selectorParameter}, status: http.StatusNoContent, handle: (*Server).deleteProfile},
+		{method: http.MethodPost, path: "/connect", summary: "Connect the selected interfaces",
+			request: ConnectRequest{}, response: []Interface{}, status: http.StatusAccepted, handle: (*Server).connect},
+		{method: http.MethodPost, path: "/disconnect", summary: "Disconnect the selected interfaces",
+			request: DisconnectRequest{}, response: []Interface{}, status: http.StatusAccepted, handle: (*Server).disconnect},
+		{method: http.MethodGet, path: "/hostednetwork", summary: "Get the status and settings of the Hosted Network",
+			response: HostedNetwork{}, status: http.StatusOK, handle: (*Server).hostedNetwork},
+		{method: http.MethodPost, path: "/hostednetwork", summary: "Change the settings of the Hosted Network, start or stop it",
+			request: HostedNetworkRequest{}, response: HostedNetwork{}, status: http.StatusOK, handle: (*Server).setHostedNetwork},
+		{method: http.MethodGet, path: "/events", summary: "Stream the notifications as Server-Sent Events",
+			query:    []parameter{{"source", "string", "comma-separated sources to stream: acm, msm, onex, security, ihv, hnwk, device service"}},
+			response: Event{}, status: http.StatusOK, stream: true, handle: (*Server).events},
+		{method: http.MethodGet, path: "/openapi.json", summary: "Get the OpenAPI document",
+			status: http.StatusOK, public: true, handle: (*Server).openAPI},
+	}
+}
+
+//match returns the path parameters of the route when it matches the escaped path.
+func (rt *route) match(path string) (map[string]string, bool) {
+	want, got := strings.Split(rt.path, "/"), strings.Split(path, "/")
+	if len(want) != len(got) {
+		return nil, false
+	}
+	params := map[string]string{}
+	for n, w := range want {
+		if strings.HasPrefix(w, "{") {
+			v, err := url.PathUnescape(got[n])
+			if err != nil || v == "" {
+				return nil, false
+			}
+			params[strings.Trim(w, "{}")] = v
+		} else if w != got[n] {
+			return nil, false
+		}
+	}
+	return params, true
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var allowed []string
+	for n := range s.routes {
+		rt := &s.routes[n]
+		params, ok := rt.match(r.URL.EscapedPath())
+		if !ok {
+			continue
+		}
+		if rt.method != r.Method {
+			allowed = append(allowed, rt.method)
+			continue
+		}
+		if s.auth != nil && !rt.public {
+			if err := s.auth.Authenticate(r); err != nil {
+				if c, ok := s.auth.(Challenger); ok && c.Challenge() != "" {
+					w.Header().Set("WWW-Authenticate", c.Challenge())
+				}
+				writeError(w, &statusError{http.StatusUnauthorized, err})
+				return
+			}
+		}
+		v, err := rt.handle(s, &call{w: w, r: r, params: params})
+		switch {
+		case err != nil:
+			writeError(w, err)
+		case rt.stream:
+		case rt.status == http.StatusNoContent:
+			w.WriteHeader(rt.status)
+		default:
+			writeJSON(w, rt.status, v)
+		}
+		return
+	}
+	if len(allowed) > 0 {
+		w.Header().Set("Allow", strings.Join(allowed, ", "))
+		writeError(w, &statusError{http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", r.Method)})
+		return
+	}
+	writeError(w, &statusError{http.StatusNotFound, fmt.Errorf("no resource at %s", r.URL.Path)})
+}
+
+//statusError is an error answered with a given status.
+type statusError struct {
+	status int
+	err    error
+}
+
+func (e *statusError) Error() string {
+	return e.err.Error()
+}
+
+func (e *statusError) Unwrap() error {
+	return e.err
+}
+
+func badRequest(format string, args ...interface{}) error {
+	return &statusError{http.StatusBadRequest, fmt.Errorf(format, args...)}
+}
+
+//kindStatus is the status that answers the errors of each kind of the Service.
+var kindStatus = map[remote.Kind]int{
+	remote.Internal:    http.StatusInternalServerError,
+	remote.Invalid:     http.StatusBadRequest,
+	remote.NotFound:    http.StatusNotFound,
+	remote.Conflict:    http.StatusConflict,
+	remote.Unsupported: http.StatusNotImplemented,
+}
+
+//statusOf returns the status that answers err.
+func statusOf(err error) int {
+	var se *statusError
+	if errors.As(err, &se) {
+		return se.status
+	}
+	return kindStatus[remote.KindOf(err)]
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	writeJSON(w, statusOf(err), Error{Error: err.Error()})
+}
+
+//decode reads the JSON body of the request into v.
+func (c *call) decode(v interface{}) error {
+	d := json.NewDecoder(http.MaxBytesReader(c.w, c.r.Body, maxRequestBody))
+	d.DisallowUnknownFields()
+	if err := d.Decode(v); err != nil {
+		return badRequest("invalid request body: %v", err)
+	}
+	return nil
+}
+
+//newInterfaces converts the interfaces described by the Service.
+func newInterfaces(interfaces []remote.Interface) []Interface {
+	result := make([]Interface, len(interfaces))
+	for n, i := range interfaces {
+		result[n] = newInterface(i.Interface, i.Connection)
+	}
+	return result
+}
+
+//newProfiles converts the profiles read by the Service.
+func newProfiles(profiles []remote.Profile) []Profile {
+	result := make([]Profile, len(profiles))
+	for n, p := range profiles {
+		result[n] = newProfile(p.Interface, p.Info)
+		result[n].XML = p.XML
+	}
+	return result
+}
+
+func (s *Server) interfaces(c *call) (interface{}, error) {
+	interfaces, err := s.service.Interfaces(c.r.URL.Query().Get("interface"))
+	return newInterfaces(interfaces), err
+}
+
+func (s *Server) scan(c *call) (interface{}, error) {
+	i, err := s.service.Scan(c.params["id"])
+	if err != nil {
+		return nil, err
+	}
+	return newInterface(i.Interface, i.Connection), nil
+}
+
+func (s *Server) networks(c *call) (interface{}, error) {
+	networks, err := s.service.AvailableNetworks(c.params["id"])
+	if err != nil {
+		return nil, err
+	}
+	result := make([]Network, len(networks))
+	for n, network := range networks {
+		result[n] = newNetwork(network)
+	}
+	return result, nil
+}
+
+func (s *Server) bssList(c *call) (interface{}, error) {
+	entries, err := s.service.BSSList(c.params["id"])
+	if err != nil {
+		return nil, err
+	}
+	result := make([]BSS, len(entries))
+	for n, e := range entries {
+		result[n] = newBSS(e)
+	}
+	return result, nil
+}
+
+func (s *Server) profiles(c *call) (interface{}, error) {
+	profiles, err := s.service.Profiles(c.r.URL.Query().Get("interface"))
+	return newProfiles(profiles), err
+}
+
+func (s *Server) createProfile(c *call) (interface{}, error) {
+	var req ProfileRequest
+	if err := c.decode(&req); err != nil {
+		return nil, err
+	}
+	profiles, err := s.service.SetProfile(req.Interface, req.XML, req.Overwrite)
+	return newProfiles(profiles), err
+}
+
+func (s *Server) putProfile(c *call) (interface{}, error) {
+	var req ProfileRequest
+	if err := c.decode(&req); err != nil {
+		return nil, err
+	}
+	name, err := remote.ProfileName(req.XML)
+	if err != nil {
+		return nil, err
+	}
+	if name != c.params["name"] {
+		return nil, badRequest("the profile XML is named %q, not %q", name, c.params["name"])
+	}
+	profiles, err := s.service.SetProfile(req.Interface, req.XML, true)
+	return newProfiles(profiles), err
+}
+
+func (s *Server) profile(c *call) (interface{}, error) {
+	query := c.r.URL.Query()
+	key := false
+	if v := query.Get("key"); v != "" {
+		var err error
+		if key, err = strconv.ParseBool(v); err != nil {
+			return nil, badRequest("invalid key parameter %q", v)
+		}
+	}
+	profiles, err := s.service.Profile(query.Get("interface"), c.params["name"], key)
+	if err != nil {
+		return nil, err
+	}
+	return newProfiles(profiles), nil
+}
+
+func (s *Server) deleteProfile(c *call) (interface{}, error) {
+	return nil, s.service.DeleteProfile(c.r.URL.Query().Get("interface"), c.params["name"])
+}
+
+//parseSSID returns the SSID of a request, given either as a string or in hexadecimal.
+func parseSSID(ssid, ssidHex string) ([]byte, error) {
+	if ssidHex == "" {
+		return []byte(ssid), nil
+	}
+	if ssid != "" {
+		return nil, badRequest("a request needs either an ssid or an ssidHex")
+	}
+	b, err := hex.DecodeString(ssidHex)
+	if err != nil {
+		return nil, badRequest("invalid ssidHex %q", ssidHex)
+	}
+	return b, nil
+}
+
+func (s *Server) connect(c *call) (interface{}, error) {
+	var req ConnectRequest
+	if err := c.decode(&req); err != nil {
+		return nil, err
+	}
+	ssid, err := parseSSID(req.SSID, req.SSIDHex)
+	if err != nil {
+		return nil, err
+	}
+	interfaces, err := s.service.Connect(remote.ConnectRequest{
+		Selector:   req.Interface,
+		Profile:    req.Profile,
+		ProfileXML: req.ProfileXML,
+		SSID:       ssid,
+		BSSIDs:     req.BSSIDs,
+		BssType:    req.BssType,
+	})
+	return newInterfaces(interfaces), err
+}
+
+func (s *Server) disconnect(c *call) (interface{}, error) {
+	var req DisconnectRequest
+	if err := c.decode(&req); err != nil {
+		return nil, err
+	}
+	interfaces, err := s.service.Disconnect(req.Interface)
+	return newInterfaces(interfaces), err
+}
+
+func (s *Server) hostedNetwork(c *call) (interface{}, error) {
+	h, err := s.service.HostedNetwork()
+	if err != nil {
+		return nil, err
+	}
+	return newHostedNetwork(h.Status, h.Settings), nil
+}
+
+func (s *Server) setHostedNetwork(c *call) (interface{}, error) {
+	var req HostedNetworkRequest
+	if err := c.decode(&req); err != nil {
+		return nil, err
+	}
+	ssid, err := parseSSID(req.SSID, req.SSIDHex)
+	if err != nil {
+		return nil, err
+	}
+	h, err := s.service.SetHostedNetwork(remote.HostedNetworkRequest{Action: req.Action, SSID: ssid, MaxPeers: req.MaxPeers})
+	if err != nil {
+		return nil, err
+	}
+	return newHostedNetwork(h.Status, h.Settings), nil
+}
@@ -0,0 +1,394 @@
+package httpapi
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"wlanapi"
+	"wlanapi/simfile/simfiletest"
+)
+
+//serve serves the fixture with auth.
+func serve(t *testing.T, auth Authenticator) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(New(simfiletest.Client(t), auth))
+	t.Cleanup(server.Close)
+	return server
+}
+
+//do sends a request with a JSON body, when body is not nil, and decodes the JSON response into out.
+func do(t *testing.T, server *httptest.Server, method, path string, body, out interface{}) *http.Response {
+	t.Helper()
+	var b bytes.Buffer
+	if body != nil {
+		json.NewEncoder(&b).Encode(body)
+	}
+	req, err := http.NewRequest(method, server.URL+path, &b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := server.Client().Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if out != nil && resp.StatusCode < 300 {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			t.Fatalf("%s %s: %v", method, path, err)
+		}
+	}
+	return resp
+}
+
+func TestInterfaces(t *testing.T) {
+	server := serve(t, nil)
+	var interfaces []Interface
+	if resp := do(t, server, "GET", "/interfaces", nil, &interfaces); resp.StatusCode != http.StatusOK || len(interfaces) != 2 {
+		t.Fatalf("interfaces: %s %+v", resp.Status, interfaces)
+	}
+	c := interfaces[0].Connection
+	if interfaces[0].GUID != simfiletest.Intel || interfaces[0].State != "connected" || c == nil || c.SSID != "corp" ||
+		c.BSSID != "00:1a:1e:00:00:01" || c.AuthAlgorithm != "WPA2-Personal" || interfaces[1].Connection != nil {
+		t.Errorf("interfaces %+v, connection %+v", interfaces, c)
+	}
+	if do(t, server, "GET", "/interfaces?interface=realtek", nil, &interfaces); len(interfaces) != 1 ||
+		interfaces[0].Description != "Realtek RTL8812BU USB" {
+		t.Errorf("selected interfaces %+v", interfaces)
+	}
+	if resp := do(t, server, "GET", "/interfaces?interface=broadcom", nil, nil); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("no selected interface: %s", resp.Status)
+	}
+	if resp := do(t, server, "DELETE", "/interfaces", nil, nil); resp.StatusCode != http.StatusMethodNotAllowed ||
+		resp.Header.Get("Allow") != "GET" {
+		t.Errorf("DELETE /interfaces: %s, Allow %q", resp.Status, resp.Header.Get("Allow"))
+	}
+}
+
+func TestNetworksAndBSS(t *testing.T) {
+	server := serve(t, nil)
+	var networks []Network
+	do(t, server, "GET", "/interfaces/"+simfiletest.Intel+"/networks", nil, &networks)
+	if len(networks) != 2 || !networks[0].Connected || !networks[0].HasProfile || len(networks[0].PhyTypes) != 2 ||
+		networks[1].SecurityEnabled || networks[1].AuthAlgorithm != "Open" {
+		t.Errorf("networks %+v", networks)
+	}
+	var bsses []BSS
+	do(t, server, "GET", "/interfaces/11111111-2222-3333-4444-555555555555/bss", nil, &bsses)
+	if len(bsses) != 4 || bsses[1].BSSID != "00:1a:1e:00:00:02" || bsses[1].Band != "5GHz" || bsses[1].Channel != 36 {
+		t.Errorf("BSSes %+v", bsses)
+	}
+	if resp := do(t, server, "GET", "/interfaces/{00000000-0000-0000-0000-000000000000}/bss", nil, nil); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("BSSes of an unknown interface: %s", resp.Status)
+	}
+	if resp := do(t, server, "GET", "/interfaces/wlan0/bss", nil, nil); resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("BSSes of an invalid GUID: %s", resp.Status)
+	}
+}
+
+func TestProfiles(t *testing.T) {
+	server := serve(t, nil)
+	const home = "<WLANProfile><name>home</name><SSIDConfig><SSID><name>home</name></SSID></SSIDConfig></WLANProfile>"
+	var profiles []Profile
+	if resp := do(t, server, "POST", "/profiles", ProfileRequest{Interface: "intel", XML: home}, &profiles); resp.StatusCode != http.StatusCreated ||
+		len(profiles) != 1 || profiles[0].Name != "home" || profiles[0].Interface != simfiletest.Intel {
+		t.Fatalf("create: %s %+v", resp.Status, profiles)
+	}
+	if do(t, server, "GET", "/profiles", nil, &profiles); len(profiles) != 2 || profiles[0].Name != "corp" || profiles[0].XML != "" {
+		t.Errorf("profiles %+v", profiles)
+	}
+	if do(t, server, "GET", "/profiles/home?key=true", nil, &profiles); len(profiles) != 1 || profiles[0].XML != home {
+		t.Errorf("profile %+v", profiles)
+	}
+	if resp := do(t, server, "PUT", "/profiles/office", ProfileRequest{XML: home}, nil); resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("put with another name: %s", resp.Status)
+	}
+	if resp := do(t, server, "PUT", "/profiles/home", ProfileRequest{Interface: "all", XML: home}, &profiles); resp.StatusCode != http.StatusOK ||
+		len(profiles) != 2 {
+		t.Errorf("put: %s %+v", resp.Status, profiles)
+	}
+	if resp := do(t, server, "DELETE", "/profiles/home", nil, nil); resp.StatusCode != http.StatusNoContent {
+		t.Errorf("delete: %s", resp.Status)
+	}
+	for _, method := range []string{"GET", "DELETE"} {
+		if resp := do(t, server, method, "/profiles/home", nil, nil); resp.StatusCode != http.StatusNotFound {
+			t.Errorf("%s of a deleted profile: %s", method, resp.Status)
+		}
+	}
+	if resp := do(t, server, "POST", "/profiles", map[string]string{"xml": "<WLANProfile/>"}, nil); resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("profile without a name: %s", resp.Status)
+	}
+	if resp := do(t, server, "POST", "/profiles", map[string]string{"profile": home}, nil); resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("unknown field: %s", resp.Status)
+	}
+}
+
+func TestConnect(t *testing.T) {
+	server := serve(t, nil)
+	var interfaces []Interface
+	if resp := do(t, server, "POST", "/disconnect", DisconnectRequest{Interface: "intel"}, &interfaces); resp.StatusCode != http.StatusAccepted ||
+		len(interfaces) != 1 || interfaces[0].State != "disconnected" || interfaces[0].Connection != nil {
+		t.Fatalf("disconnect: %s %+v", resp.Status, interfaces)
+	}
+	req := ConnectRequest{Interface: simfiletest.Intel, Profile: "corp", BSSIDs: []string{"00:1a:1e:00:00:02"}}
+	if resp := do(t, server, "POST", "/connect", req, &interfaces); resp.StatusCode != http.StatusAccepted ||
+		len(interfaces) != 1 || interfaces[0].State != "connected" || interfaces[0].Connection.BSSID != "00:1a:1e:00:00:02" {
+		t.Fatalf("connect: %s %+v", resp.Status, interfaces)
+	}
+	for _, req := range []ConnectRequest{
+		{Interface: simfiletest.Intel},
+		{Interface: simfiletest.Intel, Profile: "corp", BSSIDs: []string{"00:1a"}},
+		{Interface: simfiletest.Intel, Profile: "corp", BssType: "mesh"},
+	} {
+		if resp := do(t, server, "POST", "/connect", req, nil); resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("connect %+v: %s", req, resp.Status)
+		}
+	}
+	if resp := do(t, server, "POST", "/connect", ConnectRequest{Interface: "realtek", Profile: "corp"}, nil); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("connect without the profile: %s", resp.Status)
+	}
+}
+
+func TestHostedNetwork(t *testing.T) {
+	server := serve(t, nil)
+	var h HostedNetwork
+	if do(t, server, "GET", "/hostednetwork", nil, &h); h.State != "idle" || h.SSID != "kiosk" || h.MaxPeers != 8 {
+		t.Errorf("hosted network %+v", h)
+	}
+	if resp := do(t, server, "POST", "/hostednetwork", HostedNetworkRequest{Action: "start", SSID: "lobby", MaxPeers: 4}, &h); resp.StatusCode != http.StatusOK ||
+		h.State != "active" || h.SSID != "lobby" || h.MaxPeers != 4 {
+		t.Errorf("start: %s %+v", resp.Status, h)
+	}
+	if resp := do(t, server, "POST", "/hostednetwork", HostedNetworkRequest{Action: "start"}, nil); resp.StatusCode != http.StatusConflict {
+		t.Errorf("start an active hosted network: %s", resp.Status)
+	}
+	if resp := do(t, server, "POST", "/hostednetwork", HostedNetworkRequest{Action: "restart"}, nil); resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("unknown action: %s", resp.Status)
+	}
+	h = HostedNetwork{}
+	if resp := do(t, server, "POST", "/hostednetwork", HostedNetworkRequest{MaxPeers: 6}, &h); resp.StatusCode != http.StatusOK ||
+		h.SSID != "lobby" || h.MaxPeers != 6 {
+		t.Errorf("set the maximum number of peers: %s %+v", resp.Status, h)
+	}
+}
+
+//TestHostedNetworkError checks that only the state of the Hosted Network makes a request conflict.
+func TestHostedNetworkError(t *testing.T) {
+	sim := simfiletest.Sim(t)
+	server := httptest.NewServer(New(wlanapi.NewClientWithBackend(sim), nil))
+	t.Cleanup(server.Close)
+	sim.SetHostedNetworkError(errors.New("driver failure"))
+	if resp := do(t, server, "POST", "/hostednetwork", HostedNetworkRequest{Action: "start"}, nil); resp.StatusCode != http.StatusInternalServerError {
+		t.Errorf("start: %s", resp.Status)
+	}
+}
+
+func TestNonUTF8SSID(t *testing.T) {
+	server := serve(t, nil)
+	const ssid = "caf\xe9\xff"
+	var h HostedNetwork
+	if resp := do(t, server, "POST", "/hostednetwork", HostedNetworkRequest{SSIDHex: hex.EncodeToString([]byte(ssid)), MaxPeers: 2}, &h); resp.StatusCode != http.StatusOK ||
+		h.SSIDHex != "636166e9ff" {
+		t.Errorf("set: %s %+v", resp.Status, h)
+	}
+	h = HostedNetwork{}
+	if do(t, server, "GET", "/hostednetwork", nil, &h); h.SSIDHex != "636166e9ff" || h.MaxPeers != 2 {
+		t.Errorf("hosted network %+v", h)
+	}
+	for _, req := range []HostedNetworkRequest{{SSIDHex: "zz"}, {SSID: "lobby", SSIDHex: "6c6f626279"}} {
+		if resp := do(t, server, "POST", "/hostednetwork", req, nil); resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("set %+v: %s", req, resp.Status)
+		}
+	}
+	h = HostedNetwork{}
+	if do(t, server, "POST", "/hostednetwork", HostedNetworkRequest{SSID: "lobby"}, &h); h.SSID != "lobby" || h.SSIDHex != "" {
+		t.Errorf("valid UTF-8 SSID %+v", h)
+	}
+}
+
+func TestEvents(t *testing.T) {
+	server := serve(t, nil)
+	resp, err := server.Client().Get(server.URL + "/events?source=acm")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.Header.Get("Content-Type") != "text/event-stream" {
+		t.Fatalf("content type %q", resp.Header.Get("Content-Type"))
+	}
+	do(t, server, "POST", "/hostednetwork", HostedNetworkRequest{Action: "start"}, nil)
+	do(t, server, "POST", "/interfaces/"+simfiletest.Intel+"/scan", nil, nil)
+
+	lines := make(chan string)
+	go func() {
+		r := bufio.NewReader(resp.Body)
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				close(lines)
+				return
+			}
+			lines <- line
+		}
+	}()
+	var got []string
+	for len(got) < 3 {
+		select {
+		case line, ok := <-lines:
+			if !ok {
+				t.Fatalf("stream ended after %q", got)
+			}
+			got = append(got, line)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("no event after %q", got)
+		}
+	}
+	want := []string{
+		"event: acm\n",
+		`data: {"interface":"` + simfiletest.Intel + `","source":"acm","code":7,"notification":"scan complete"}` + "\n",
+		"\n",
+	}
+	if strings.Join(got, "") != strings.Join(want, "") {
+		t.Errorf("events %q, want %q", got, want)
+	}
+}
+
+func TestAuthentication(t *testing.T) {
+	server := serve(t, BearerToken("first", "second"))
+	for header, want := range map[string]int{
+		"":              http.StatusUnauthorized,
+		"Bearer third":  http.StatusUnauthorized,
+		"Bearer    ":    http.StatusUnauthorized,
+		"Basic second":  http.StatusUnauthorized,
+		"Bearer second": http.StatusOK,
+		"bearer first":  http.StatusOK,
+	} {
+		req, _ := http.NewRequest("GET", server.URL+"/interfaces", nil)
+		if header != "" {
+			req.Header.Set("Authorization", header)
+		}
+		resp, err := server.Client().Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != want {
+			t.Errorf("Authorization %q: %s", header, resp.Status)
+		}
+		if want == http.StatusUnauthorized && resp.Header.Get("WWW-Authenticate") != `Bearer realm="wlanapi"` {
+			t.Errorf("Authorization %q: WWW-Authenticate %q", header, resp.Header.Get("WWW-Authenticate"))
+		}
+	}
+	if resp := do(t, server, "GET", "/openapi.json", nil, nil); resp.StatusCode != http.StatusOK {
+		t.Errorf("document without authentication: %s", resp.Status)
+	}
+	//An empty presented token is refused before the comparison, which would match an empty token.
+	r := httptest.NewRequest("GET", "/interfaces", nil)
+	r.Header.Set("Authorization", "Bearer  \t")
+	if err := (bearerTokens{[]byte{}}).Authenticate(r); err != ErrUnauthorized {
+		t.Errorf("empty token: %v", err)
+	}
+	for _, tokens := range [][]string{{""}, {"first", " "}} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("BearerToken(%q) accepted an empty token", tokens)
+				}
+			}()
+			BearerToken(tokens...)
+		}()
+	}
+}
+
+func TestClientCertificate(t *testing.T) {
+	verified := func(cn string) *http.Request {
+		r := httptest.NewRequest("GET", "/interfaces", nil)
+		r.TLS = &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{{Subject: pkix.Name{CommonName: cn}}}}}
+		return r
+	}
+	auth := ClientCertificate(CommonNames("kiosk-1", "kiosk-2"))
+	if err := auth.Authenticate(verified("kiosk-2")); err != nil {
+		t.Errorf("allowed certificate: %v", err)
+	}
+	if err := auth.Authenticate(verified("laptop")); err != ErrUnauthorized {
+		t.Errorf("other certificate: %v", err)
+	}
+	plain := httptest.NewRequest("GET", "/interfaces", nil)
+	plain.TLS = &tls.ConnectionState{}
+	if err := ClientCertificate(nil).Authenticate(plain); err != ErrUnauthorized {
+		t.Errorf("TLS without a client certificate: %v", err)
+	}
+
+	either := AnyOf(auth, BearerToken("secret"))
+	plain.Header.Set("Authorization", "Bearer secret")
+	if either.Authenticate(plain) != nil || either.Authenticate(verified("kiosk-1")) != nil || either.Authenticate(verified("laptop")) == nil {
+		t.Error("AnyOf of a certificate and a token")
+	}
+	if c := either.(Challenger).Challenge(); c != `Bearer realm="wlanapi"` {
+		t.Errorf("challenge %q", c)
+	}
+}
+
+func TestDocument(t *testing.T) {
+	server := serve(t, nil)
+	var doc struct {
+		OpenAPI    string                                       `json:"openapi"`
+		Paths      map[string]map[string]map[string]interface{} `json:"paths"`
+		Components struct {
+			Schemas map[string]struct {
+				Properties map[string]interface{} `json:"properties"`
+				Required   []string               `json:"required"`
+			} `json:"schemas"`
+		} `json:"components"`
+	}
+	resp, err := server.Client().Get(server.URL + "/openapi.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	var raw bytes.Buffer
+	if _, err := raw.ReadFrom(resp.Body); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(raw.Bytes(), &doc); err != nil {
+		t.Fatal(err)
+	}
+	if doc.OpenAPI != "3.1.0" {
+		t.Errorf("OpenAPI version %q", doc.OpenAPI)
+	}
+	for _, rt := range routeTable() {
+		if doc.Paths[rt.path][strings.ToLower(rt.method)] == nil {
+			t.Errorf("no operation %s %s", rt.method, rt.path)
+		}
+	}
+	bss := doc.Components.Schemas["BSS"]
+	if bss.Properties["bssid"] == nil || bss.Properties["band"] == nil || bss.Properties["ies"] == nil {
+		t.Errorf("BSS schema %+v", bss)
+	}
+	if f, _ := bss.Properties["frequencyKHz"].(map[string]interface{}); f["type"] != "integer" || f["format"] != "int64" || f["minimum"] != 0.0 {
+		t.Errorf("schema of a uint32 %v", f)
+	}
+	if h, _ := doc.Components.Schemas["HostedNetwork"].Properties["ssidHex"].(map[string]interface{}); h["type"] != "string" || h["description"] == nil {
+		t.Errorf("schema of ssidHex %v", h)
+	}
+	if p := doc.Components.Schemas["Profile"]; strings.Join(p.Required, ",") != "interface,name,groupPolicy,user" {
+		t.Errorf("required properties of Profile %q", p.Required)
+	}
+	//Every reference resolves to a schema.
+	for _, ref := range strings.Split(raw.String(), `"$ref":"#/components/schemas/`)[1:] {
+		name := ref[:strings.IndexByte(ref, '"')]
+		if _, ok := doc.Components.Schemas[name]; !ok {
+			t.Errorf("unresolved reference to %s", name)
+		}
+	}
+}
@@ -0,0 +1,242 @@
+package httpapi
+
+import (
+	"encoding/hex"
+	"net"
+	"unicode/utf8"
+
+	"wlanapi"
+	"wlanapi/binary"
+	"wlanapi/survey"
+)
+
+//Interface is a wireless LAN interface and its connection.
+type Interface struct {
+	GUID        string      `json:"guid"`
+	Description string      `json:"description"`
+	State       string      `json:"state"`
+	Connection  *Connection `json:"connection,omitempty"`
+}
+
+//Connection is the connection of an interface.
+type Connection struct {
+	Mode            string  `json:"mode"`
+	Profile         string  `json:"profile"`
+	SSID            string  `json:"ssid"`
+	SSIDHex         string  `json:"ssidHex,omitempty"`
+	BSSID           string  `json:"bssid"`
+	BssType         string  `json:"bssType"`
+	PhyType         string  `json:"phyType"`
+	SignalQuality   uint32  `json:"signalQuality"`
+	RxRateMbps      float64 `json:"rxRateMbps"`
+	TxRateMbps      float64 `json:"txRateMbps"`
+	SecurityEnabled bool    `json:"securityEnabled"`
+	OneXEnabled     bool    `json:"oneXEnabled"`
+	AuthAlgorithm   string  `json:"authAlgorithm"`
+	CipherAlgorithm string  `json:"cipherAlgorithm"`
+}
+
+//Network is an available network of an interface.
+type Network struct {
+	SSID                 string   `json:"ssid"`
+	SSIDHex              string   `json:"ssidHex,omitempty"`
+	Profile              string   `json:"profile,omitempty"`
+	BssType              string   `json:"bssType"`
+	BSSCount             uint32   `json:"bssCount"`
+	Connectable          bool     `json:"connectable"`
+	NotConnectableReason string   `json:"notConnectableReason,omitempty"`
+	PhyTypes             []string `json:"phyTypes"`
+	SignalQuality        uint32   `json:"signalQuality"`
+	SecurityEnabled      bool     `json:"securityEnabled"`
+	AuthAlgorithm        string   `json:"authAlgorithm"`
+	CipherAlgorithm      string   `json:"cipherAlgorithm"`
+	Connected            bool     `json:"connected"`
+	HasProfile           bool     `json:"hasProfile"`
+}
+
+//BSS is a BSS visible to an interface: its survey record with the band and channel of its frequency.
+type BSS struct {
+	survey.BSS
+	Band    string `json:"band,omitempty"`
+	Channel int    `json:"channel,omitempty"`
+}
+
+//Profile is a profile of an interface. XML is only returned when a single profile is read.
+type Profile struct {
+	Interface   string `json:"interface"`
+	Name        string `json:"name"`
+	GroupPolicy bool   `json:"groupPolicy"`
+	User        bool   `json:"user"`
+	XML         string `json:"xml,omitempty"`
+}
+
+//ProfileRequest sets a profile on the selected interfaces.
+type ProfileRequest struct {
+	//Interface selects the interfaces: all, connected, a GUID or part of a description.
+	Interface string `json:"interface,omitempty"`
+	XML       string `json:"xml"`
+	//Overwrite replaces a profile of the same name; PUT always replaces it.
+	Overwrite bool `json:"overwrite,omitempty"`
+}
+
+//ConnectRequest connects the selected interfaces with a stored profile or with the XML of a temporary one.
+type ConnectRequest struct {
+	Interface  string `json:"interface,omitempty"`
+	Profile    string `json:"profile,omitempty"`
+	ProfileXML string `json:"profileXml,omitempty"`
+	SSID       string `json:"ssid,omitempty"`
+	//SSIDHex is the SSID in hexadecimal, for SSIDs that are not valid UTF-8; it excludes SSID.
+	SSIDHex string   `json:"ssidHex,omitempty"`
+	BSSIDs  []string `json:"bssids,omitempty"`
+	//BssType is infrastructure, the default, or independent.
+	BssType string `json:"bssType,omitempty"`
+}
+
+//DisconnectRequest disconnects the selected interfaces.
+type DisconnectRequest struct {
+	Interface string `json:"interface,omitempty"`
+}
+
+//HostedNetwork is the status and the settings of the wireless Hosted Network.
+type HostedNetwork struct {
+	State        string `json:"state"`
+	SSID         string `json:"ssid"`
+	SSIDHex      string `json:"ssidHex,omitempty"`
+	MaxPeers     uint32 `json:"maxPeers"`
+	BSSID        string `json:"bssid,omitempty"`
+	PhyType      string `json:"phyType,omitempty"`
+	FrequencyKHz uint32 `json:"frequencyKHz,omitempty"`
+	Peers        []Peer `json:"peers"`
+}
+
+//Peer is a peer of the Hosted Network.
+type Peer struct {
+	MAC       string `json:"mac"`
+	AuthState string `json:"authState"`
+}
+
+//HostedNetworkRequest changes the settings of the Hosted Network, then starts or stops it.
+type HostedNetworkRequest struct {
+	//Action is start, stop, or empty to only change the settings.
+	Action string `json:"action,omitempty"`
+	//SSID and MaxPeers replace the settings they are given for; the settings left empty are kept.
+	SSID string `json:"ssid,omitempty"`
+	//SSIDHex is the SSID in hexadecimal, for SSIDs that are not valid UTF-8; it excludes SSID.
+	SSIDHex  string `json:"ssidHex,omitempty"`
+	MaxPeers uint32 `json:"maxPeers,omitempty"`
+}
+
+//Event is a notification streamed by GET /events, as wlanapi.Event names it.
+type Event struct {
+	//Interface is the GUID of the interface, empty for notifications of the Hosted Network.
+	Interface string `json:"interface,omitempty"`
+	//Source is acm, msm, onex, security, ihv, hnwk or device service.
+	Source string `json:"source"`
+	Code   uint32 `json:"code"`
+	//Notification names the code of the notifications of the Auto Configuration Module.
+	Notification string `json:"notification,omitempty"`
+	//Reason is the reason of a failed scan or connection, or of a disconnection.
+	Reason string `json:"reason,omitempty"`
+}
+
+//Error is the body of the responses of failed requests.
+type Error struct {
+	Error string `json:"error"`
+}
+
+func mac(addr [6]byte) string {
+	return net.HardwareAddr(addr[:]).String()
+}
+
+//ssidHex returns the SSID in hexadecimal when JSON cannot carry it as a string.
+func ssidHex(ssid []byte) string {
+	if utf8.Valid(ssid) {
+		return ""
+	}
+	return hex.EncodeToString(ssid)
+}
+
+func newInterface(i *wlanapi.Interface, c *binary.ConnectionAttributes) Interface {
+	result := Interface{GUID: i.GUID.String(), Description: i.Description, State: i.State.String()}
+	if c != nil {
+		result.Connection = &Connection{
+			Mode:            wlanapi.WLAN_CONNECTION_MODE(c.Mode).String(),
+			Profile:         c.ProfileName,
+			SSID:            string(c.SSID),
+			SSIDHex:         ssidHex(c.SSID),
+			BSSID:           mac(c.BSSID),
+			BssType:         wlanapi.DOT11_BSS_TYPE(c.BssType).String(),
+			PhyType:         wlanapi.DOT11_PHY_TYPE(c.PhyType).String(),
+			SignalQuality:   c.SignalQuality,
+			RxRateMbps:      float64(c.RxRate) / 1000,
+			TxRateMbps:      float64(c.TxRate) / 1000,
+			SecurityEnabled: c.SecurityEnabled,
+			OneXEnabled:     c.OneXEnabled,
+			AuthAlgorithm:   wlanapi.DOT11_AUTH_ALGORITHM(c.AuthAlgorithm).String(),
+			CipherAlgorithm: wlanapi.DOT11_CIPHER_ALGORITHM(c.CipherAlgorithm).String(),
+		}
+	}
+	return result
+}
+
+func newNetwork(n binary.AvailableNetwork) Network {
+	result := Network{
+		SSID:            string(n.SSID),
+		SSIDHex:         ssidHex(n.SSID),
+		Profile:         n.ProfileName,
+		BssType:         wlanapi.DOT11_BSS_TYPE(n.BssType).String(),
+		BSSCount:        n.NumberOfBssids,
+		Connectable:     n.NetworkConnectable,
+		PhyTypes:        make([]string, len(n.PhyTypes)),
+		SignalQuality:   n.SignalQuality,
+		SecurityEnabled: n.SecurityEnabled,
+		AuthAlgorithm:   wlanapi.DOT11_AUTH_ALGORITHM(n.DefaultAuthAlgorithm).String(),
+		CipherAlgorithm: wlanapi.DOT11_CIPHER_ALGORITHM(n.DefaultCipherAlgorithm).String(),
+		Connected:       n.Flags&wlanapi.WLAN_AVAILABLE_NETWORK_CONNECTED != 0,
+		HasProfile:      n.Flags&wlanapi.WLAN_AVAILABLE_NETWORK_HAS_PROFILE != 0,
+	}
+	if !n.NetworkConnectable {
+		result.NotConnectableReason = wlanapi.WLAN_REASON_CODE(n.NotConnectableReason).String()
+	}
+	for k, t := range n.PhyTypes {
+		result.PhyTypes[k] = wlanapi.DOT11_PHY_TYPE(t).String()
+	}
+	return result
+}
+
+func newBSS(e binary.BSSEntry) BSS {
+	result := BSS{BSS: survey.NewBSS(e)}
+	if band := wlanapi.BandOf(e.ChCenterFrequency); band != 0 {
+		result.Band = band.String()
+		result.Channel = wlanapi.ChannelOf(e.ChCenterFrequency)
+	}
+	return result
+}
+
+func newProfile(i *wlanapi.Interface, p binary.ProfileInfo) Profile {
+	return Profile{
+		Interface:   i.GUID.String(),
+		Name:        p.Name,
+		GroupPolicy: p.Flags&wlanapi.WLAN_PROFILE_GROUP_POLICY != 0,
+		User:        p.Flags&wlanapi.WLAN_PROFILE_USER != 0,
+	}
+}
+
+func newHostedNetwork(status *binary.HostedNetworkStatus, settings *wlanapi.HostedNetworkSettings) HostedNetwork {
+	result := HostedNetwork{
+		State:    wlanapi.WLAN_HOSTED_NETWORK_STATE(status.State).String(),
+		SSID:     string(settings.SSID),
+		SSIDHex:  ssidHex(settings.SSID),
+		MaxPeers: settings.MaxPeers,
+		Peers:    make([]Peer, len(status.Peers)),
+	}
+	if status.BSSID != [6]byte{} {
+		result.BSSID = mac(status.BSSID)
+		result.PhyType = wlanapi.DOT11_PHY_TYPE(status.PhyType).String()
+		result.FrequencyKHz = status.ChannelFrequency
+	}
+	for k, p := range status.Peers {
+		result.Peers[k] = Peer{MAC: mac(p.MacAddress), AuthState: wlanapi.WLAN_HOSTED_NETWORK_PEER_AUTH_STATE(p.AuthState).String()}
+	}
+	return result
+}
@@ -0,0 +1,200 @@
+package httpapi
+
+import (
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+//pathParameters describes the path parameters of the routes.
+var pathParameters = map[string]string{
+	"id":   "the GUID of the interface, with or without braces",
+	"name": "the name of the profile",
+}
+
+//propertyDescriptions describes the properties of the models whose names and types do not say it all.
+var propertyDescriptions = map[string]string{
+	"ssidHex": "the SSID in hexadecimal, for SSIDs that are not valid UTF-8, which ssid cannot carry",
+}
+
+//schemaRef returns a reference to a schema of the components of the document.
+func schemaRef(name string) map[string]interface{} {
+	return map[string]interface{}{"$ref": "#/components/schemas/" + name}
+}
+
+//schemas generates the JSON schemas of the models from their types and json tags.
+type schemas map[string]interface{}
+
+func (g schemas) schema(t reflect.Type) map[string]interface{} {
+	switch t.Kind() {
+	case reflect.Ptr:
+		return g.schema(t.Elem())
+	case reflect.Struct:
+		if _, ok := g[t.Name()]; !ok {
+			//Claim the name first, so that recursive models end.
+			g[t.Name()] = nil
+			g[t.Name()] = g.object(t)
+		}
+		return schemaRef(t.Name())
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return map[string]interface{}{"type": "string", "format": "byte"}
+		}
+		return map[string]interface{}{"type": "array", "items": g.schema(t.Elem())}
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return map[string]interface{}{"type": "integer", "format": "int" + strconv.Itoa(bits(t))}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		//The formats are signed, so a uint32 takes the int64 format.
+		format := "int32"
+		if t.Bits() >= 32 {
+			format = "int64"
+		}
+		return map[string]interface{}{"type": "integer", "format": format, "minimum": 0}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	}
+	return map[string]interface{}{}
+}
+
+//bits returns the size of an integer type, rounded up to the 32 or 64 bits of the OpenAPI formats.
+func bits(t reflect.Type) int {
+	if t.Bits() > 32 {
+		return 64
+	}
+	return 32
+}
+
+//object returns the schema of a struct, with the fields of its embedded structs.
+func (g schemas) object(t reflect.Type) map[string]interface{} {
+	properties := map[string]interface{}{}
+	required := []string{}
+	var fields func(t reflect.Type)
+	fields = func(t reflect.Type) {
+		for n := 0; n < t.NumField(); n++ {
+			f := t.Field(n)
+			tag := f.Tag.Get("json")
+			if f.Anonymous && tag == "" && f.Type.Kind() == reflect.Struct {
+				fields(f.Type)
+				continue
+			}
+			if f.PkgPath != "" || tag == "-" {
+				continue
+			}
+			name, options := tag, ""
+			if k := strings.IndexByte(tag, ','); k >= 0 {
+				name, options = tag[:k], tag[k:]
+			}
+			if name == "" {
+				name = f.Name
+			}
+			property := g.schema(f.Type)
+			if d, ok := propertyDescriptions[name]; ok {
+				property["description"] = d
+			}
+			properties[name] = property
+			if !strings.Contains(options, ",omitempty") {
+				required = append(required, name)
+			}
+		}
+	}
+	fields(t)
+	return map[string]interface{}{"type": "object", "properties": properties, "required": required}
+}
+
+//content returns the content of a body of a model in a media type.
+func (g schemas) content(mediaType string, model interface{}) map[string]interface{} {
+	return map[string]interface{}{mediaType: map[string]interface{}{"schema": g.schema(reflect.TypeOf(model))}}
+}
+
+//operation returns the OpenAPI operation of a route.
+func (g schemas) operation(rt *route) map[string]interface{} {
+	parameters := []interface{}{}
+	for _, segment := range strings.Split(rt.path, "/") {
+		if strings.HasPrefix(segment, "{") {
+			name := strings.Trim(segment, "{}")
+			parameters = append(parameters, map[string]interface{}{
+				"name": name, "in": "path", "required": true, "description": pathParameters[name],
+				"schema": map[string]interface{}{"type": "string"},
+			})
+		}
+	}
+	for _, p := range rt.query {
+		parameters = append(parameters, map[string]interface{}{
+			"name": p.name, "in": "query", "description": p.description,
+			"schema": map[string]interface{}{"type": p.typ},
+		})
+	}
+
+	response := map[string]interface{}{"description": http.StatusText(rt.status)}
+	switch {
+	case rt.stream:
+		response["content"] = g.content("text/event-stream", rt.response)
+	case rt.response != nil:
+		response["content"] = g.content("application/json", rt.response)
+	}
+	op := map[string]interface{}{
+		"summary":    rt.summary,
+		"parameters": parameters,
+		"responses": map[string]interface{}{
+			strconv.Itoa(rt.status): response,
+			"default": map[string]interface{}{
+				"description": "The request failed",
+				"content":     g.content("application/json", Error{}),
+			},
+		},
+	}
+	if rt.request != nil {
+		op["requestBody"] = map[string]interface{}{"required": true, "content": g.content("application/json", rt.request)}
+	}
+	if rt.public {
+		op["security"] = []interface{}{}
+	}
+	return op
+}
+
+//Document returns the OpenAPI 3.1 document of the endpoints of a Server, with the schemas of the models
+//generated from their json tags. Every endpoint but the document itself takes either a bearer token or a client
+//certificate, depending on the Authenticator of the Server.
+func Document() map[string]interface{} {
+	g := schemas{}
+	paths := map[string]interface{}{}
+	routes := routeTable()
+	for n := range routes {
+		rt := &routes[n]
+		item, ok := paths[rt.path].(map[string]interface{})
+		if !ok {
+			item = map[string]interface{}{}
+			paths[rt.path] = item
+		}
+		item[strings.ToLower(rt.method)] = g.operation(rt)
+	}
+	return map[string]interface{}{
+		"openapi": "3.1.0",
+		"info": map[string]interface{}{
+			"title":       "wlanapi",
+			"description": "Management of the wireless LAN interfaces, profiles, connections and Hosted Network of a host.",
+			"version":     "1",
+		},
+		"paths": paths,
+		"components": map[string]interface{}{
+			"schemas": map[string]interface{}(g),
+			"securitySchemes": map[string]interface{}{
+				"bearerToken":       map[string]interface{}{"type": "http", "scheme": "bearer"},
+				"clientCertificate": map[string]interface{}{"type": "mutualTLS"},
+			},
+		},
+		"security": []interface{}{
+			map[string]interface{}{"bearerToken": []string{}},
+			map[string]interface{}{"clientCertificate": []string{}},
+		},
+	}
+}
+
+func (s *Server) openAPI(c *call) (interface{}, error) {
+	return Document(), nil
+}
@@ -0,0 +1,88 @@
+package ie
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+//FT capability and policy bits of the MobilityDomainElement.
+const (
+	FTOverDS                  = 0x01
+	FTResourceRequestProtocol = 0x02
+)
+
+//MobilityDomainElement is the content of the Mobility Domain element of an FT (802.11r) BSS.
+type MobilityDomainElement struct {
+	//MDID identifies the mobility domain; stations fast-roam between BSSes of the same domain.
+	MDID               uint16
+	FTCapabilityPolicy uint8
+}
+
+//OverDS reports whether the BSS supports FT over the distribution system.
+func (m *MobilityDomainElement) OverDS() bool {
+	return m.FTCapabilityPolicy&FTOverDS != 0
+}
+
+//ParseMobilityDomain parses the data of a Mobility Domain element.
+func ParseMobilityDomain(data []byte) (*MobilityDomainElement, error) {
+	if len(data) < 3 {
+		return nil, errors.New("ie: truncated Mobility Domain element")
+	}
+	return &MobilityDomainElement{MDID: binary.LittleEndian.Uint16(data), FTCapabilityPolicy: data[2]}, nil
+}
+
+//Capabilities is a bit field of capabilities, bit 0 being the least significant bit of the first byte.
+//Bits past the end of the field are clear.
+type Capabilities []byte
+
+//Has reports whether the bit is set.
+func (c Capabilities) Has(bit int) bool {
+	return bit/8 < len(c) && c[bit/8]&(1<<(bit%8)) != 0
+}
+
+//Bits of the RM Enabled Capabilities element (802.11k).
+const (
+	RMLinkMeasurement        = 0
+	RMNeighborReport         = 1
+	RMParallelMeasurements   = 2
+	RMRepeatedMeasurements   = 3
+	RMBeaconPassive          = 4
+	RMBeaconActive           = 5
+	RMBeaconTable            = 6
+	RMBeaconReportConditions = 7
+)
+
+//Bits of the Extended Capabilities element.
+const (
+	ExtCapCoexistenceManagement = 0
+	ExtCapExtendedChannelSwitch = 2
+	ExtCapProxyARP              = 12
+	ExtCapWNMSleepMode          = 17
+	ExtCapTIMBroadcast          = 18
+	ExtCapBSSTransition         = 19
+	ExtCapMultipleBSSID         = 22
+	ExtCapInterworking          = 31
+	ExtCapQoSMap                = 32
+	ExtCapTDLSSupport           = 37
+	ExtCapWNMNotification       = 46
+	ExtCapUTF8SSID              = 48
+	ExtCapOperatingModeNotif    = 62
+	ExtCapFTMResponder          = 70
+	ExtCapFTMInitiator          = 71
+)
+
+//RMEnabledCapabilities returns the bits of the RM Enabled Capabilities element, or nil without it.
+func (es Elements) RMEnabledCapabilities() Capabilities {
+	if e, ok := es.Find(RMEnabledCapabilities); ok {
+		return Capabilities(e.Data)
+	}
+	return nil
+}
+
+//ExtendedCapabilities returns the bits of the Extended Capabilities element, or nil without it.
+func (es Elements) ExtendedCapabilities() Capabilities {
+	if e, ok := es.Find(ExtendedCapabilities); ok {
+		return Capabilities(e.Data)
+	}
+	return nil
+}
@@ -0,0 +1,304 @@
+package ie
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+)
+
+//HS20Indication is the vendor type of the Hotspot 2.0 Indication element, of OUIWFA.
+const HS20Indication = 0x10
+
+//AccessNetworkType is the access network type of the Interworking element.
+type AccessNetworkType uint8
+
+const (
+	PrivateNetwork           AccessNetworkType = 0
+	PrivateNetworkWithGuest  AccessNetworkType = 1
+	ChargeablePublicNetwork  AccessNetworkType = 2
+	FreePublicNetwork        AccessNetworkType = 3
+	PersonalDeviceNetwork    AccessNetworkType = 4
+	EmergencyServicesNetwork AccessNetworkType = 5
+	TestNetwork              AccessNetworkType = 14
+	WildcardNetwork          AccessNetworkType = 15
+)
+
+var accessNetworkTypeNames = map[AccessNetworkType]string{
+	PrivateNetwork:           "Private",
+	PrivateNetworkWithGuest:  "Private with guest access",
+	ChargeablePublicNetwork:  "Chargeable public",
+	FreePublicNetwork:        "Free public",
+	PersonalDeviceNetwork:    "Personal device",
+	EmergencyServicesNetwork: "Emergency services only",
+	TestNetwork:              "Test or experimental",
+	WildcardNetwork:          "Wildcard",
+}
+
+func (t AccessNetworkType) String() string {
+	if s, ok := accessNetworkTypeNames[t]; ok {
+		return s
+	}
+	return fmt.Sprintf("Access network type %d", uint8(t))
+}
+
+//Venue is the venue group and type of a BSS, as in the Interworking element.
+type Venue struct {
+	Group uint8
+	//Type is the venue type within the group, such as 13 for a coffee shop in the assembly group.
+	Type uint8
+}
+
+var venueGroupNames = []string{
+	"Unspecified", "Assembly", "Business", "Educational", "Factory and Industrial", "Institutional",
+	"Mercantile", "Residential", "Storage", "Utility and Miscellaneous", "Vehicular", "Outdoor",
+}
+
+//String names the venue group, with the number of the venue type.
+func (v Venue) String() string {
+	group := fmt.Sprintf("Venue group %d", v.Group)
+	if int(v.Group) < len(venueGroupNames) {
+		group = venueGroupNames[v.Group]
+	}
+	return fmt.Sprintf("%s, type %d", group, v.Type)
+}
+
+//InterworkingElement is the content of the Interworking element (802.11u) of a BSS that offers interworking
+//with external networks, as Passpoint BSSes do.
+type InterworkingElement struct {
+	AccessNetworkType AccessNetworkType
+	//Internet is set when the network provides connectivity to the Internet.
+	Internet bool
+	//ASRA is set when stations need to take an additional step, such as accepting terms, for access.
+	ASRA bool
+	ESR  bool
+	UESA bool
+	//Venue and HESSID are nil when the element omits them.
+	Venue *Venue
+	//HESSID identifies the homogeneous ESS that the BSS is part of.
+	HESSID net.HardwareAddr
+}
+
+//ParseInterworking parses the data of an Interworking element.
+func ParseInterworking(data []byte) (*InterworkingElement, error) {
+	if len(data) != 1 && len(data) != 3 && len(data) != 7 && len(data) != 9 {
+		return nil, fmt.Errorf("ie: Interworking element of %d bytes", len(data))
+	}
+	i := &InterworkingElement{
+		AccessNetworkType: AccessNetworkType(data[0] & 0x0f),
+		Internet:          data[0]&0x10 != 0,
+		ASRA:              data[0]&0x20 != 0,
+		ESR:               data[0]&0x40 != 0,
+		UESA:              data[0]&0x80 != 0,
+	}
+	data = data[1:]
+	if len(data) == 2 || len(data) == 8 {
+		i.Venue = &Venue{Group: data[0], Type: data[1]}
+		data = data[2:]
+	}
+	if len(data) == 6 {
+		i.HESSID = net.HardwareAddr(append([]byte(nil), data...))
+	}
+	return i, nil
+}
+
+//AdvertisementProtocolID identifies a protocol of the Advertisement Protocol element.
+type AdvertisementProtocolID uint8
+
+const (
+	ANQP                                AdvertisementProtocolID = 0
+	MIHInformationService               AdvertisementProtocolID = 1
+	MIHCommandAndEventServices          AdvertisementProtocolID = 2
+	EmergencyAlertSystem                AdvertisementProtocolID = 3
+	RegisteredLocationQuery             AdvertisementProtocolID = 4
+	VendorSpecificAdvertisementProtocol AdvertisementProtocolID = 221
+)
+
+var advertisementProtocolNames = map[AdvertisementProtocolID]string{
+	ANQP:                                "ANQP",
+	MIHInformationService:               "MIH Information Service",
+	MIHCommandAndEventServices:          "MIH Command and Event Services Capability Discovery",
+	EmergencyAlertSystem:                "Emergency Alert System",
+	RegisteredLocationQuery:             "Registered Location Query Protocol",
+	VendorSpecificAdvertisementProtocol: "Vendor Specific",
+}
+
+func (id AdvertisementProtocolID) String() string {
+	if s, ok := advertisementProtocolNames[id]; ok {
+		return s
+	}
+	return fmt.Sprintf("Advertisement protocol %d", uint8(id))
+}
+
+//AdvertisementProtocolTuple is a protocol of the Advertisement Protocol element, which GAS queries of the BSS may use.
+type AdvertisementProtocolTuple struct {
+	ID AdvertisementProtocolID
+	//QueryResponseLengthLimit is the number of 256 byte units the BSS returns in a response; 127 is no limit.
+	QueryResponseLengthLimit uint8
+	//PAMEBI is set when the responses to queries do not depend on the BSS of the ESS that answers them.
+	PAMEBI bool
+	//Vendor is the content of a vendor specific protocol, starting with its OUI.
+	Vendor []byte
+}
+
+var errShortAdvertisementProtocol = errors.New("ie: truncated Advertisement Protocol element")
+
+//ParseAdvertisementProtocol parses the data of an Advertisement Protocol element.
+func ParseAdvertisementProtocol(data []byte) ([]AdvertisementProtocolTuple, error) {
+	var list []AdvertisementProtocolTuple
+	for len(data) > 0 {
+		if len(data) < 2 {
+			return nil, errShortAdvertisementProtocol
+		}
+		p := AdvertisementProtocolTuple{
+			ID:                       AdvertisementProtocolID(data[1]),
+			QueryResponseLengthLimit: data[0] & 0x7f,
+			PAMEBI:                   data[0]&0x80 != 0,
+		}
+		data = data[2:]
+		if p.ID == VendorSpecificAdvertisementProtocol {
+			if len(data) < 1 || len(data) < 1+int(data[0]) {
+				return nil, errShortAdvertisementProtocol
+			}
+			p.Vendor = data[1 : 1+int(data[0])]
+			data = data[1+int(data[0]):]
+		}
+		list = append(list, p)
+	}
+	return list, nil
+}
+
+//OI is an organization identifier of a roaming consortium, of 3 or more bytes.
+type OI []byte
+
+//String formats the OI in hexadecimal, as Passpoint configurations write it.
+func (o OI) String() string {
+	return hex.EncodeToString(o)
+}
+
+//RoamingConsortiumElement is the content of the Roaming Consortium element, which lists the roaming consortiums
+//and service providers whose credentials the BSS accepts.
+type RoamingConsortiumElement struct {
+	//ANQPOIs is the number of OIs that an ANQP query returns, which may be more than the element holds.
+	ANQPOIs uint8
+	//OIs are the first OIs of the list, at most 3.
+	OIs []OI
+}
+
+//ParseRoamingConsortium parses the data of a Roaming Consortium element.
+func ParseRoamingConsortium(data []byte) (*RoamingConsortiumElement, error) {
+	errShort := errors.New("ie: truncated Roaming Consortium element")
+	if len(data) < 2 {
+		return nil, errShort
+	}
+	r := &RoamingConsortiumElement{ANQPOIs: data[0]}
+	lengths := []int{int(data[1] & 0x0f), int(data[1] >> 4)}
+	data = data[2:]
+	for _, n := range lengths {
+		if n == 0 {
+			break
+		}
+		if len(data) < n {
+			return nil, errShort
+		}
+		r.OIs = append(r.OIs, OI(data[:n]))
+		data = data[n:]
+	}
+	if len(data) > 0 {
+		r.OIs = append(r.OIs, OI(data))
+	}
+	return r, nil
+}
+
+//HS20IndicationElement is the content of the Hotspot 2.0 Indication element of a Passpoint BSS.
+type HS20IndicationElement struct {
+	//Release is the Passpoint release of the BSS, starting at 1.
+	Release int
+	//DGAFDisabled is set when the BSS does not forward group addressed frames, such as broadcast ARP.
+	DGAFDisabled bool
+	//PPSMOID and ANQPDomainID are nil when the element omits them. BSSes of the same ANQP domain return the
+	//same ANQP data, which stations need not query again.
+	PPSMOID      *uint16
+	ANQPDomainID *uint16
+}
+
+//ParseHS20Indication parses the data of the Hotspot 2.0 Indication vendor specific element (50:6f:9a type 16),
+//including its OUI and type.
+func ParseHS20Indication(data []byte) (*HS20IndicationElement, error) {
+	if len(data) < 4 || (OUI{data[0], data[1], data[2]}) != OUIWFA || data[3] != HS20Indication {
+		return nil, errors.New("ie: not a Hotspot 2.0 Indication element")
+	}
+	errShort := errors.New("ie: truncated Hotspot 2.0 Indication element")
+	data = data[4:]
+	if len(data) < 1 {
+		return nil, errShort
+	}
+	config := data[0]
+	h := &HS20IndicationElement{Release: int(config>>4) + 1, DGAFDisabled: config&0x01 != 0}
+	data = data[1:]
+	next := func() (*uint16, error) {
+		if len(data) < 2 {
+			return nil, errShort
+		}
+		v := binary.LittleEndian.Uint16(data)
+		data = data[2:]
+		return &v, nil
+	}
+	var err error
+	if config&0x02 != 0 {
+		if h.PPSMOID, err = next(); err != nil {
+			return nil, err
+		}
+	}
+	if config&0x04 != 0 {
+		if h.ANQPDomainID, err = next(); err != nil {
+			return nil, err
+		}
+	}
+	return h, nil
+}
+
+//Hotspot is the Passpoint (Hotspot 2.0) information of a BSS.
+type Hotspot struct {
+	Interworking *InterworkingElement
+	//AdvertisementProtocols, RoamingConsortium and Indication are empty or nil when the BSS omits their elements.
+	AdvertisementProtocols []AdvertisementProtocolTuple
+	RoamingConsortium      *RoamingConsortiumElement
+	Indication             *HS20IndicationElement
+}
+
+//Passpoint reports whether the BSS is a Passpoint BSS, which sends the Hotspot 2.0 Indication element.
+func (h *Hotspot) Passpoint() bool {
+	return h.Indication != nil
+}
+
+//Hotspot returns the interworking and Passpoint elements of a BSS; it is nil for BSSes without the Interworking
+//element.
+func (es Elements) Hotspot() (*Hotspot, error) {
+	e, ok := es.Find(Interworking)
+	if !ok {
+		return nil, nil
+	}
+	i, err := ParseInterworking(e.Data)
+	if err != nil {
+		return nil, err
+	}
+	h := &Hotspot{Interworking: i}
+	if e, ok := es.Find(AdvertisementProtocol); ok {
+		if h.AdvertisementProtocols, err = ParseAdvertisementProtocol(e.Data); err != nil {
+			return nil, err
+		}
+	}
+	if e, ok := es.Find(RoamingConsortium); ok {
+		if h.RoamingConsortium, err = ParseRoamingConsortium(e.Data); err != nil {
+			return nil, err
+		}
+	}
+	if e, ok := es.FindVendor(OUIWFA, HS20Indication); ok {
+		if h.Indication, err = ParseHS20Indication(e.Data); err != nil {
+			return nil, err
+		}
+	}
+	return h, nil
+}
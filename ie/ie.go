@@ -0,0 +1,157 @@
+//Package ie parses the 802.11 information elements (IEs) of beacons and probe responses,
+//as returned in the IE data of WLAN_BSS_ENTRY. Manufacturer names the OUIs of MAC addresses from a subset of the
+//IEEE registry, unless the full registry is loaded with LoadManufacturers.
+package ie
+
+import (
+	"bytes"
+	"fmt"
+)
+
+//ID is the element ID of an information element.
+type ID uint8
+
+const (
+	SSID                   ID = 0
+	SupportedRates         ID = 1
+	DSParameterSet         ID = 3
+	TIM                    ID = 5
+	Country                ID = 7
+	BSSLoad                ID = 11
+	HTCapabilities         ID = 45
+	RSN                    ID = 48
+	ExtendedSupportedRates ID = 50
+	MobilityDomain         ID = 54
+	HTOperation            ID = 61
+	RMEnabledCapabilities  ID = 70
+	Interworking           ID = 107
+	AdvertisementProtocol  ID = 108
+	RoamingConsortium      ID = 111
+	ExtendedCapabilities   ID = 127
+	CiscoCCX1              ID = 133
+	VHTCapabilities        ID = 191
+	VHTOperation           ID = 192
+	VendorSpecific         ID = 221
+	//Extension elements carry their real ID in the first byte of their data.
+	Extension ID = 255
+)
+
+var idNames = map[ID]string{
+	SSID:                   "SSID",
+	SupportedRates:         "Supported Rates",
+	DSParameterSet:         "DS Parameter Set",
+	TIM:                    "TIM",
+	Country:                "Country",
+	BSSLoad:                "BSS Load",
+	HTCapabilities:         "HT Capabilities",
+	RSN:                    "RSN",
+	ExtendedSupportedRates: "Extended Supported Rates",
+	MobilityDomain:         "Mobility Domain",
+	HTOperation:            "HT Operation",
+	RMEnabledCapabilities:  "RM Enabled Capabilities",
+	Interworking:           "Interworking",
+	AdvertisementProtocol:  "Advertisement Protocol",
+	RoamingConsortium:      "Roaming Consortium",
+	ExtendedCapabilities:   "Extended Capabilities",
+	CiscoCCX1:              "Cisco CCX1 CKIP + Device Name",
+	VHTCapabilities:        "VHT Capabilities",
+	VHTOperation:           "VHT Operation",
+	VendorSpecific:         "Vendor Specific",
+	Extension:              "Extension",
+}
+
+//String names the element ID as the standard does, or formats its number for IDs without a name.
+func (id ID) String() string {
+	if s, ok := idNames[id]; ok {
+		return s
+	}
+	return fmt.Sprintf("Element %d", uint8(id))
+}
+
+//OUI is an organizationally unique identifier, as in vendor specific elements and cipher suites.
+type OUI [3]byte
+
+var (
+	//OUIMicrosoft is the OUI of the WPA, WMM and WPS vendor specific elements.
+	OUIMicrosoft = OUI{0x00, 0x50, 0xf2}
+	//OUIIEEE is the OUI of the cipher and AKM suites of the RSN element.
+	OUIIEEE = OUI{0x00, 0x0f, 0xac}
+	//OUIWFA is the OUI of the Wi-Fi Alliance vendor specific elements, such as P2P.
+	OUIWFA = OUI{0x50, 0x6f, 0x9a}
+)
+
+func (o OUI) String() string {
+	return fmt.Sprintf("%02x:%02x:%02x", o[0], o[1], o[2])
+}
+
+//Element is an information element; Data excludes the ID and length bytes.
+type Element struct {
+	ID   ID
+	Data []byte
+}
+
+//Vendor returns the OUI and vendor type of a vendor specific element.
+func (e Element) Vendor() (OUI, byte, bool) {
+	if e.ID != VendorSpecific || len(e.Data) < 4 {
+		return OUI{}, 0, false
+	}
+	return OUI{e.Data[0], e.Data[1], e.Data[2]}, e.Data[3], true
+}
+
+//Bytes returns the element in its wire format.
+func (e Element) Bytes() []byte {
+	return append([]byte{byte(e.ID), byte(len(e.Data))}, e.Data...)
+}
+
+//Elements is the list of information elements of a frame, in order.
+type Elements []Element
+
+//Parse splits b into its elements. The data of the elements refers to b.
+//An element running past the end of b is an error; the elements before it are returned with it.
+func Parse(b []byte) (Elements, error) {
+	var es Elements
+	for len(b) > 0 {
+		if len(b) < 2 {
+			return es, fmt.Errorf("ie: truncated element header at %d trailing bytes", len(b))
+		}
+		n := int(b[1])
+		if len(b) < 2+n {
+			return es, fmt.Errorf("ie: element %d of %d bytes exceeds the %d remaining bytes", b[0], n, len(b)-2)
+		}
+		es = append(es, Element{ID(b[0]), b[2 : 2+n]})
+		b = b[2+n:]
+	}
+	return es, nil
+}
+
+//Find returns the first element with the ID.
+func (es Elements) Find(id ID) (Element, bool) {
+	for _, e := range es {
+		if e.ID == id {
+			return e, true
+		}
+	}
+	return Element{}, false
+}
+
+//FindVendor returns the first vendor specific element with the OUI and vendor type.
+func (es Elements) FindVendor(oui OUI, vendorType byte) (Element, bool) {
+	for _, e := range es {
+		if o, t, ok := e.Vendor(); ok && o == oui && t == vendorType {
+			return e, true
+		}
+	}
+	return Element{}, false
+}
+
+//SSID returns the data of the SSID element.
+func (es Elements) SSID() ([]byte, bool) {
+	e, ok := es.Find(SSID)
+	return e.Data, ok
+}
+
+//Hidden reports whether the SSID element is missing, empty or zeroed, as sent by APs that hide their SSID.
+func (es Elements) Hidden() bool {
+	ssid, ok := es.SSID()
+	return !ok || len(bytes.Trim(ssid, "\x00")) == 0
+}
@@ -0,0 +1,379 @@
+package ie
+
+import (
+	"strings"
+	"testing"
+)
+
+//rsnPSKSAE is the RSN element of a WPA2/WPA3 transition mode BSS with MFP capable.
+var rsnPSKSAE = []byte{48, 24,
+	1, 0,
+	0x00, 0x0f, 0xac, 4,
+	1, 0, 0x00, 0x0f, 0xac, 4,
+	2, 0, 0x00, 0x0f, 0xac, 2, 0x00, 0x0f, 0xac, 8,
+	0x80, 0}
+
+//wpaTKIP is the WPA element of a legacy WPA-Personal BSS.
+var wpaTKIP = []byte{221, 22,
+	0x00, 0x50, 0xf2, 1,
+	1, 0,
+	0x00, 0x50, 0xf2, 2,
+	1, 0, 0x00, 0x50, 0xf2, 2,
+	1, 0, 0x00, 0x50, 0xf2, 2}
+
+func concat(bs ...[]byte) []byte {
+	var c []byte
+	for _, b := range bs {
+		c = append(c, b...)
+	}
+	return c
+}
+
+func TestParse(t *testing.T) {
+	b := concat([]byte{0, 4, 'c', 'o', 'r', 'p', 3, 1, 6}, rsnPSKSAE, wpaTKIP)
+	es, err := Parse(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(es) != 4 {
+		t.Fatalf("%d elements, want 4", len(es))
+	}
+	if ssid, ok := es.SSID(); !ok || string(ssid) != "corp" || es.Hidden() {
+		t.Errorf("SSID %q, %t", ssid, ok)
+	}
+	if e, ok := es.Find(DSParameterSet); !ok || len(e.Data) != 1 || e.Data[0] != 6 {
+		t.Errorf("DS parameter set %v", e)
+	}
+	if e, ok := es.FindVendor(OUIMicrosoft, 1); !ok || string(e.Bytes()) != string(wpaTKIP) {
+		t.Errorf("WPA element %v", e)
+	}
+	if _, ok := es.FindVendor(OUIMicrosoft, 2); ok {
+		t.Error("found a WMM element")
+	}
+
+	es, err = Parse(b[:len(b)-3])
+	if err == nil || len(es) != 3 {
+		t.Errorf("truncated: %d elements, %v", len(es), err)
+	}
+	if _, err = Parse([]byte{0}); err == nil {
+		t.Error("truncated header parsed")
+	}
+	es, _ = Parse([]byte{0, 3, 0, 0, 0})
+	if !es.Hidden() {
+		t.Error("zeroed SSID is not hidden")
+	}
+}
+
+func TestParseRSN(t *testing.T) {
+	r, err := ParseRSN(rsnPSKSAE[2:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Version != 1 || r.GroupCipher != CipherCCMP || len(r.PairwiseCiphers) != 1 || r.PairwiseCiphers[0] != CipherCCMP ||
+		len(r.AKMs) != 2 || r.AKMs[0] != AKMPSK || r.AKMs[1] != AKMSAE || r.Capabilities != RSNCapabilityMFPC {
+		t.Errorf("%+v", r)
+	}
+
+	//Suites missing from the end take their defaults.
+	r, err = ParseRSN([]byte{1, 0})
+	if err != nil || r.GroupCipher != CipherCCMP || r.PairwiseCiphers[0] != CipherCCMP || r.AKMs[0] != AKM8021X {
+		t.Errorf("defaults %+v, %v", r, err)
+	}
+	if _, err = ParseRSN(rsnPSKSAE[2:12]); err == nil {
+		t.Error("truncated pairwise list parsed")
+	}
+
+	w, err := ParseWPA(wpaTKIP[2:])
+	if err != nil || w.GroupCipher != CipherWPATKIP || w.AKMs[0] != AKMWPAPSK {
+		t.Errorf("WPA %+v, %v", w, err)
+	}
+	if s := w.GroupCipher.String(); s != "TKIP" {
+		t.Errorf("WPA group cipher %q", s)
+	}
+	if s := Cipher(0x00112233).String(); s != "00:11:22:33" {
+		t.Errorf("unknown cipher %q", s)
+	}
+	if !AKMFTSAE.FT() || AKMSAE.FT() {
+		t.Error("FT AKMs")
+	}
+}
+
+func TestSecurity(t *testing.T) {
+	tests := []struct {
+		capability uint16
+		ies        []byte
+		want       string
+	}{
+		{0x0001, nil, "Open"},
+		{0x0011, nil, "WEP"},
+		{0x0011, wpaTKIP, "WPA-Personal"},
+		{0x0011, rsnPSKSAE, "WPA2-Personal/WPA3-Personal"},
+		{0x0011, concat(rsnPSKSAE, wpaTKIP), "WPA-Personal/WPA2-Personal/WPA3-Personal"},
+		{0x0011, []byte{48, 20, 1, 0, 0, 0x0f, 0xac, 4, 1, 0, 0, 0x0f, 0xac, 4, 1, 0, 0, 0x0f, 0xac, 1, 0, 0}, "WPA2-Enterprise"},
+		{0x0001, []byte{48, 20, 1, 0, 0, 0x0f, 0xac, 4, 1, 0, 0, 0x0f, 0xac, 4, 1, 0, 0, 0x0f, 0xac, 0x12, 0, 0}, "OWE"},
+		{0x0011, []byte{48, 20, 1, 0, 0, 0x0f, 0xac, 4, 1, 0, 0, 0x0f, 0xac, 4, 1, 0, 0, 0x0f, 0xac, 0x63, 0, 0}, "00:0f:ac:63"},
+	}
+	for _, tt := range tests {
+		es, _ := Parse(tt.ies)
+		s, err := ParseSecurity(tt.capability, es)
+		if err != nil {
+			t.Errorf("%s: %v", tt.want, err)
+			continue
+		}
+		if got := s.String(); got != tt.want {
+			t.Errorf("security %q, want %q", got, tt.want)
+		}
+		if s.Open() != (tt.want == "Open") {
+			t.Errorf("%s: open %t", tt.want, s.Open())
+		}
+	}
+}
+
+//htOperation returns an HT Operation element of a primary channel and secondary channel offset.
+func htOperation(primary, offset byte) []byte {
+	e := make([]byte, 24)
+	e[0], e[1], e[2], e[3] = byte(HTOperation), 22, primary, offset
+	if offset != SecondaryChannelNone {
+		e[3] |= 0x04
+	}
+	return e
+}
+
+func TestOperatingChannel(t *testing.T) {
+	tests := []struct {
+		name     string
+		primary  int
+		sixGHz   bool
+		ies      []byte
+		width    int
+		channels []int
+	}{
+		{"20 MHz", 6, false, nil, 20, []int{6}},
+		{"2.4 GHz 40 MHz below", 6, false, htOperation(6, SecondaryChannelBelow), 40, []int{2, 6}},
+		{"5 GHz 40 MHz above", 36, false, htOperation(36, SecondaryChannelAbove), 40, []int{36, 40}},
+		{"80 MHz", 44, false, concat(htOperation(44, SecondaryChannelBelow), []byte{192, 5, 1, 42, 0, 0xfc, 0xff}), 80, []int{36, 40, 44, 48}},
+		{"160 MHz", 44, false, concat(htOperation(44, SecondaryChannelBelow), []byte{192, 5, 1, 42, 50, 0xfc, 0xff}),
+			160, []int{36, 40, 44, 48, 52, 56, 60, 64}},
+		{"80+80 MHz", 36, false, concat(htOperation(36, SecondaryChannelAbove), []byte{192, 5, 1, 42, 155, 0xfc, 0xff}),
+			160, []int{36, 40, 44, 48, 149, 153, 157, 161}},
+		{"VHT without HT 40 MHz", 36, false, concat(htOperation(36, SecondaryChannelNone), []byte{192, 5, 1, 42, 0, 0xfc, 0xff}), 20, []int{36}},
+		{"6 GHz 80 MHz", 37, true, []byte{255, 12, HEOperation, 0, 0, 0x02, 0x3f, 0xfc, 0xff, 37, 2, 39, 0, 6}, 80, []int{33, 37, 41, 45}},
+		{"6 GHz 160 MHz", 37, true, []byte{255, 12, HEOperation, 0, 0, 0x02, 0x3f, 0xfc, 0xff, 37, 3, 39, 47, 6}, 160,
+			[]int{33, 37, 41, 45, 49, 53, 57, 61}},
+		{"6 GHz ignores HT", 37, true, htOperation(37, SecondaryChannelAbove), 20, []int{37}},
+	}
+	for _, tt := range tests {
+		es, err := Parse(tt.ies)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		o := es.OperatingChannel(tt.primary, tt.sixGHz)
+		channels := o.Channels()
+		if o.Width != tt.width || len(channels) != len(tt.channels) {
+			t.Errorf("%s: %+v %v", tt.name, o, channels)
+			continue
+		}
+		for n := range channels {
+			if channels[n] != tt.channels[n] {
+				t.Errorf("%s: channels %v, want %v", tt.name, channels, tt.channels)
+				break
+			}
+		}
+	}
+
+	l, err := ParseBSSLoad([]byte{12, 0, 128, 0x10, 0x27})
+	if err != nil || l.StationCount != 12 || l.AvailableAdmissionCapacity != 10000 || l.Utilization() < 0.5 || l.Utilization() > 0.51 {
+		t.Errorf("BSS load %+v, %v", l, err)
+	}
+	if _, err := ParseBSSLoad([]byte{12, 0}); err == nil {
+		t.Error("truncated BSS Load parsed")
+	}
+}
+
+func TestHotspot(t *testing.T) {
+	es, err := Parse(concat(
+		[]byte{107, 9, 0x13, 1, 13, 0x02, 0, 0, 0, 0x01, 0},
+		[]byte{108, 2, 0x7f, 0},
+		[]byte{111, 10, 3, 0x35, 0x00, 0x1b, 0xc5, 0x04, 0xbd, 0x50, 0x6f, 0x9a},
+		[]byte{221, 7, 0x50, 0x6f, 0x9a, HS20Indication, 0x14, 0x34, 0x12},
+	))
+	if err != nil {
+		t.Fatal(err)
+	}
+	h, err := es.Hotspot()
+	if err != nil {
+		t.Fatal(err)
+	}
+	i := h.Interworking
+	if i.AccessNetworkType != FreePublicNetwork || !i.Internet || i.ASRA || i.Venue == nil || i.HESSID.String() != "02:00:00:00:01:00" {
+		t.Errorf("interworking %+v", i)
+	}
+	if s := i.Venue.String(); s != "Assembly, type 13" {
+		t.Errorf("venue %q", s)
+	}
+	if len(h.AdvertisementProtocols) != 1 || h.AdvertisementProtocols[0].ID != ANQP || h.AdvertisementProtocols[0].QueryResponseLengthLimit != 127 {
+		t.Errorf("advertisement protocols %+v", h.AdvertisementProtocols)
+	}
+	if r := h.RoamingConsortium; r.ANQPOIs != 3 || len(r.OIs) != 2 || r.OIs[0].String() != "001bc504bd" || r.OIs[1].String() != "506f9a" {
+		t.Errorf("roaming consortium %+v", r)
+	}
+	if !h.Passpoint() || h.Indication.Release != 2 || h.Indication.PPSMOID != nil || *h.Indication.ANQPDomainID != 0x1234 {
+		t.Errorf("indication %+v", h.Indication)
+	}
+
+	i, err = ParseInterworking([]byte{0x02})
+	if err != nil || i.AccessNetworkType != ChargeablePublicNetwork || i.Venue != nil || i.HESSID != nil {
+		t.Errorf("interworking without venue %+v, %v", i, err)
+	}
+	p, err := ParseAdvertisementProtocol([]byte{0x80, 221, 4, 0x00, 0x11, 0x22, 0x33, 0x00, 0})
+	if err != nil || len(p) != 2 || !p[0].PAMEBI || len(p[0].Vendor) != 4 || p[1].ID.String() != "ANQP" {
+		t.Errorf("vendor advertisement protocol %+v, %v", p, err)
+	}
+	if _, err := ParseInterworking([]byte{0x02, 1}); err == nil {
+		t.Error("Interworking element of 2 bytes parsed")
+	}
+	if _, err := ParseAdvertisementProtocol([]byte{0x00, 221, 4, 0}); err == nil {
+		t.Error("truncated vendor advertisement protocol parsed")
+	}
+	if _, err := ParseRoamingConsortium([]byte{3, 0x35, 0x00, 0x1b}); err == nil {
+		t.Error("truncated Roaming Consortium parsed")
+	}
+	if _, err := ParseHS20Indication([]byte{0x50, 0x6f, 0x9a, HS20Indication, 0x02, 0}); err == nil {
+		t.Error("truncated Hotspot 2.0 Indication parsed")
+	}
+	if h, err := (Elements{}).Hotspot(); h != nil || err != nil {
+		t.Errorf("hotspot without interworking %+v, %v", h, err)
+	}
+}
+
+//vendorElement returns a vendor specific element of an OUI, vendor type and content.
+func vendorElement(oui OUI, vendorType byte, content ...[]byte) []byte {
+	data := concat(append([][]byte{oui[:], {vendorType}}, content...)...)
+	return append([]byte{byte(VendorSpecific), byte(len(data))}, data...)
+}
+
+func TestVendor(t *testing.T) {
+	wmm := vendorElement(OUIMicrosoft, VendorWMM, []byte{WMMParameter, 1, 0x81, 0,
+		0x03, 0xa4, 0, 0,
+		0x27, 0xa4, 0, 0,
+		0x42, 0x43, 94, 0,
+		0x62, 0x32, 47, 0})
+	wps := vendorElement(OUIMicrosoft, VendorWPS,
+		[]byte{0x10, 0x4a, 0, 1, 0x10},
+		[]byte{0x10, 0x44, 0, 1, WPSConfigured},
+		[]byte{0x10, 0x57, 0, 1, 1},
+		[]byte{0x10, 0x21, 0, 7}, []byte("Example"),
+		[]byte{0x10, 0x11, 0, 5}, []byte("AP-01"),
+		[]byte{0x10, 0x49, 0, 6, 0x00, 0x37, 0x2a, 0, 1, 0x20})
+	es, err := Parse(concat(wmm, wps,
+		vendorElement(OUIWFA, VendorP2P, []byte{p2pCapability, 2, 0, 0x25, P2PGroupOwner}),
+		vendorElement(OUIWFA, VendorP2P, []byte{p2pDeviceInfo, 25, 0, 0x02, 0, 0, 0, 0x0a, 0x0b, 0x01, 0x88,
+			0, 7, 0x00, 0x50, 0xf2, 0x04, 0, 1, 0, 0x10, 0x11, 0, 4}, []byte("TV-1")),
+		vendorElement(OUIAruba, VendorArubaAPName, []byte{0}, []byte("ap-lobby")),
+	))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	v, err := es[0].DecodeVendor()
+	w, ok := v.(*WMMElement)
+	if err != nil || !ok || !w.UAPSD || w.ParameterSetCount != 1 || len(w.AccessCategories) != 4 {
+		t.Fatalf("WMM %+v, %v", v, err)
+	}
+	if be, vo := w.AccessCategories[WMMBestEffort], w.AccessCategories[WMMVoice]; be.AIFSN != 3 || be.CWMin != 15 || be.CWMax != 1023 ||
+		vo.AIFSN != 2 || vo.CWMin != 3 || vo.CWMax != 7 || vo.TXOPLimit != 47 {
+		t.Errorf("WMM access categories %+v", w.AccessCategories)
+	}
+
+	p, err := es.WPS()
+	if err != nil || !p.Configured() || !p.APSetupLocked || p.Version != 0x10 || p.Version2 != 0x20 ||
+		p.Manufacturer != "Example" || p.DeviceName != "AP-01" {
+		t.Errorf("WPS %+v, %v", p, err)
+	}
+
+	//The P2P attributes are split over two elements.
+	if _, err := es[2].DecodeVendor(); err != nil {
+		t.Errorf("first P2P element: %v", err)
+	}
+	d, err := es.P2P()
+	if err != nil || !d.GroupOwner() || d.DeviceCapability != 0x25 || d.DeviceAddress.String() != "02:00:00:00:0a:0b" ||
+		d.DeviceName != "TV-1" || d.ConfigMethods != 0x0188 || len(d.Attributes) != 2 {
+		t.Errorf("P2P %+v, %v", d, err)
+	}
+
+	if name, ok := es.APName(); !ok || name != "ap-lobby" {
+		t.Errorf("AP name %q", name)
+	}
+	cisco := make([]byte, 32)
+	cisco[0], cisco[1] = byte(CiscoCCX1), 30
+	copy(cisco[12:], "ap-cisco")
+	es, _ = Parse(concat(wmm, cisco))
+	if name, ok := es.APName(); !ok || name != "ap-cisco" {
+		t.Errorf("Cisco AP name %q", name)
+	}
+	if p, err := es.WPS(); p != nil || err != nil {
+		t.Errorf("WPS without WPS elements %+v, %v", p, err)
+	}
+
+	test := OUI{0x00, 0x11, 0x22}
+	es, _ = Parse(vendorElement(test, 1, []byte("data")))
+	if _, err := es[0].DecodeVendor(); err != ErrNoVendorDecoder {
+		t.Errorf("unregistered decoder: %v", err)
+	}
+	r := newVendorRegistry()
+	r.register(test, 1, NewVendorDecoder("Test", func(data []byte) (interface{}, error) {
+		return string(data[4:]), nil
+	}))
+	if v, err := r.decode(es[0]); v != "data" || err != nil {
+		t.Errorf("registered decoder %v, %v", v, err)
+	}
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("registered a decoder twice")
+			}
+		}()
+		r.register(test, 1, NewVendorDecoder("Test", nil))
+	}()
+	if _, err := (Element{ID: SSID}).DecodeVendor(); err == nil {
+		t.Error("decoded an SSID element")
+	}
+	if _, err := ParseWMM([]byte{0x00, 0x50, 0xf2, VendorWMM, WMMParameter, 1, 0x81, 0}); err == nil {
+		t.Error("truncated WMM Parameter element parsed")
+	}
+	if _, err := ParseWPS([]byte{0x00, 0x50, 0xf2, VendorWPS, 0x10, 0x4a, 0, 2, 0x10}); err == nil {
+		t.Error("truncated WPS attribute parsed")
+	}
+}
+
+func TestManufacturer(t *testing.T) {
+	if name, ok := Manufacturer([]byte{0x00, 0x0b, 0x86, 0x12, 0x34, 0x56}); !ok || name != "Aruba, a Hewlett Packard Enterprise Company" {
+		t.Errorf("Aruba BSSID: %q", name)
+	}
+	if name, ok := Manufacturer([]byte{0x02, 0x0b, 0x86, 0x12, 0x34, 0x56}); ok {
+		t.Errorf("locally administered BSSID: %q", name)
+	}
+	const registry = "OUI/MA-L   Organization\n\n00-11-22   (hex)\t\tExample Corp\n001122     (base 16)\t\tExample Corp\n" +
+		"00-0B-86   (hex)\t\tAruba Networks\n"
+	loadEmbedded()
+	manufacturersMu.Lock()
+	saved := manufacturers
+	manufacturers = map[OUI]string{}
+	for oui, name := range saved {
+		manufacturers[oui] = name
+	}
+	manufacturersMu.Unlock()
+	t.Cleanup(func() {
+		manufacturersMu.Lock()
+		manufacturers = saved
+		manufacturersMu.Unlock()
+	})
+	if err := LoadManufacturers(strings.NewReader(registry)); err != nil {
+		t.Fatal(err)
+	}
+	for oui, want := range map[OUI]string{{0x00, 0x11, 0x22}: "Example Corp", {0x00, 0x0b, 0x86}: "Aruba Networks", OUIWFA: "Wi-Fi Alliance"} {
+		if name, _ := Manufacturer(oui[:]); name != want {
+			t.Errorf("%v: %q, want %q", oui, name, want)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+//go:build ignore
+// +build ignore
+
+//mkoui writes oui.txt.gz, the gzipped IEEE MA-L registry that Manufacturer embeds. It downloads the registry from
+//the IEEE, or reads a copy of it with -in:
+//
+//	go generate ./ie
+//	go run mkoui.go -in oui.txt
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+)
+
+const registryURL = "https://standards-oui.ieee.org/oui/oui.txt"
+
+func main() {
+	in := flag.String("in", "", "read the registry from this file instead of "+registryURL)
+	out := flag.String("out", "oui.txt.gz", "write the gzipped registry to this file")
+	flag.Parse()
+	log.SetFlags(0)
+	log.SetPrefix("mkoui: ")
+
+	registry, err := read(*in)
+	if err != nil {
+		log.Fatal(err)
+	}
+	//An error page would replace the table with nothing, so the registry must have OUIs.
+	n := bytes.Count(registry, []byte("(hex)"))
+	if n == 0 {
+		log.Fatal("the registry has no OUIs")
+	}
+	var b bytes.Buffer
+	//The header is left empty, so that the same registry gives the same file.
+	z, _ := gzip.NewWriterLevel(&b, gzip.BestCompression)
+	z.Write(registry)
+	if err := z.Close(); err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile(*out, b.Bytes(), 0o644); err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("wrote %d OUIs to %s", n, *out)
+}
+
+func read(path string) ([]byte, error) {
+	if path != "" {
+		return os.ReadFile(path)
+	}
+	resp, err := http.Get(registryURL)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s: %s", registryURL, resp.Status)
+	}
+	return io.ReadAll(resp.Body)
+}
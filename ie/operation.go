@@ -0,0 +1,259 @@
+package ie
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+//HEOperation is the extension ID of the HE Operation element.
+const HEOperation = 36
+
+//FindExtension returns the first extension element with the extension ID; its Data keeps the extension ID.
+func (es Elements) FindExtension(extID byte) (Element, bool) {
+	for _, e := range es {
+		if e.ID == Extension && len(e.Data) > 0 && e.Data[0] == extID {
+			return e, true
+		}
+	}
+	return Element{}, false
+}
+
+//BSSLoadElement is the content of the BSS Load element.
+type BSSLoadElement struct {
+	StationCount uint16
+	//ChannelUtilization is the share of time the AP sensed the medium busy, scaled to 255.
+	ChannelUtilization uint8
+	//AvailableAdmissionCapacity is in units of 32 microseconds per second.
+	AvailableAdmissionCapacity uint16
+}
+
+//Utilization returns the channel utilization as a fraction.
+func (l *BSSLoadElement) Utilization() float64 {
+	return float64(l.ChannelUtilization) / 255
+}
+
+//ParseBSSLoad parses the data of a BSS Load element.
+func ParseBSSLoad(data []byte) (*BSSLoadElement, error) {
+	if len(data) < 5 {
+		return nil, errors.New("ie: truncated BSS Load element")
+	}
+	return &BSSLoadElement{
+		StationCount:               binary.LittleEndian.Uint16(data),
+		ChannelUtilization:         data[2],
+		AvailableAdmissionCapacity: binary.LittleEndian.Uint16(data[3:]),
+	}, nil
+}
+
+//Secondary channel offsets of the HTOperationElement.
+const (
+	SecondaryChannelNone  = 0
+	SecondaryChannelAbove = 1
+	SecondaryChannelBelow = 3
+)
+
+//HTOperationElement is the start of the content of the HT Operation element.
+type HTOperationElement struct {
+	PrimaryChannel         uint8
+	SecondaryChannelOffset uint8
+	//AnyChannelWidth is set when the BSS may use 40 MHz channels.
+	AnyChannelWidth bool
+}
+
+//ParseHTOperation parses the data of an HT Operation element.
+func ParseHTOperation(data []byte) (*HTOperationElement, error) {
+	if len(data) < 22 {
+		return nil, errors.New("ie: truncated HT Operation element")
+	}
+	return &HTOperationElement{
+		PrimaryChannel:         data[0],
+		SecondaryChannelOffset: data[1] & 0x03,
+		AnyChannelWidth:        data[1]&0x04 != 0,
+	}, nil
+}
+
+//VHT channel widths of the VHTOperationElement.
+const (
+	VHTChannelWidth20Or40 = 0
+	VHTChannelWidth80     = 1
+	//VHTChannelWidth160 and VHTChannelWidth80Plus80 are deprecated; BSSes signal these widths
+	//with VHTChannelWidth80 and a second segment.
+	VHTChannelWidth160      = 2
+	VHTChannelWidth80Plus80 = 3
+)
+
+//VHTOperationElement is the content of the VHT Operation element.
+type VHTOperationElement struct {
+	ChannelWidth uint8
+	//CenterFrequencySegment0 and CenterFrequencySegment1 are channel numbers.
+	CenterFrequencySegment0 uint8
+	CenterFrequencySegment1 uint8
+	BasicMCSSet             uint16
+}
+
+//ParseVHTOperation parses the data of a VHT Operation element.
+func ParseVHTOperation(data []byte) (*VHTOperationElement, error) {
+	if len(data) < 5 {
+		return nil, errors.New("ie: truncated VHT Operation element")
+	}
+	return &VHTOperationElement{
+		ChannelWidth:            data[0],
+		CenterFrequencySegment0: data[1],
+		CenterFrequencySegment1: data[2],
+		BasicMCSSet:             binary.LittleEndian.Uint16(data[3:]),
+	}, nil
+}
+
+//SixGHzOperation is the 6 GHz Operation Information of the HE Operation element.
+type SixGHzOperation struct {
+	PrimaryChannel uint8
+	//ChannelWidth is 0 for 20 MHz, 1 for 40 MHz, 2 for 80 MHz and 3 for 80+80 or 160 MHz.
+	ChannelWidth            uint8
+	CenterFrequencySegment0 uint8
+	CenterFrequencySegment1 uint8
+	MinimumRate             uint8
+}
+
+//HEOperationElement is the content of the HE Operation element.
+type HEOperationElement struct {
+	Parameters uint32
+	BSSColor   uint8
+	//VHTOperation is present when a 5 GHz HE BSS carries the VHT operation information in the element.
+	VHTOperation *VHTOperationElement
+	SixGHz       *SixGHzOperation
+}
+
+//ParseHEOperation parses the data of an HE Operation extension element, which starts with the extension ID.
+func ParseHEOperation(data []byte) (*HEOperationElement, error) {
+	errShort := errors.New("ie: truncated HE Operation element")
+	if len(data) < 7 || data[0] != HEOperation {
+		return nil, errShort
+	}
+	data = data[1:]
+	h := &HEOperationElement{
+		Parameters: uint32(data[0]) | uint32(data[1])<<8 | uint32(data[2])<<16,
+		BSSColor:   data[3],
+	}
+	data = data[6:]
+	if h.Parameters&(1<<14) != 0 {
+		if len(data) < 3 {
+			return nil, errShort
+		}
+		h.VHTOperation = &VHTOperationElement{ChannelWidth: data[0], CenterFrequencySegment0: data[1], CenterFrequencySegment1: data[2]}
+		data = data[3:]
+	}
+	if h.Parameters&(1<<15) != 0 {
+		if len(data) < 1 {
+			return nil, errShort
+		}
+		data = data[1:]
+	}
+	if h.Parameters&(1<<17) != 0 {
+		if len(data) < 5 {
+			return nil, errShort
+		}
+		h.SixGHz = &SixGHzOperation{
+			PrimaryChannel:          data[0],
+			ChannelWidth:            data[1] & 0x03,
+			CenterFrequencySegment0: data[2],
+			CenterFrequencySegment1: data[3],
+			MinimumRate:             data[4],
+		}
+	}
+	return h, nil
+}
+
+//OperatingChannel is the channel a BSS operates on: its primary 20 MHz channel bonded with secondary channels.
+type OperatingChannel struct {
+	Primary int
+	//Width is in MHz; Center is the channel number of the center of the widest segment.
+	Width  int
+	Center int
+	//Segment1 is the center of the second 80 MHz segment of an 80+80 MHz BSS.
+	Segment1 int
+}
+
+//OperatingChannel returns the channel of a BSS from its primary channel and its HT, VHT and HE Operation elements.
+//sixGHz selects the 6 GHz rules, where only the HE Operation element describes the width.
+//Elements that fail to parse are ignored, which leaves the BSS at a narrower width.
+func (es Elements) OperatingChannel(primary int, sixGHz bool) OperatingChannel {
+	o := OperatingChannel{Primary: primary, Width: 20, Center: primary}
+	var vht *VHTOperationElement
+	if e, ok := es.FindExtension(HEOperation); ok {
+		if he, err := ParseHEOperation(e.Data); err == nil {
+			if sixGHz && he.SixGHz != nil {
+				s := he.SixGHz
+				o.Width = 20 << s.ChannelWidth
+				o.Center = int(s.CenterFrequencySegment0)
+				if s.ChannelWidth == 3 {
+					segments(&o, int(s.CenterFrequencySegment0), int(s.CenterFrequencySegment1))
+				}
+				return o
+			}
+			vht = he.VHTOperation
+		}
+	}
+	if sixGHz {
+		return o
+	}
+
+	if e, ok := es.Find(HTOperation); ok {
+		if ht, err := ParseHTOperation(e.Data); err == nil && ht.AnyChannelWidth {
+			switch ht.SecondaryChannelOffset {
+			case SecondaryChannelAbove:
+				o.Width, o.Center = 40, primary+2
+			case SecondaryChannelBelow:
+				o.Width, o.Center = 40, primary-2
+			}
+		}
+	}
+	if e, ok := es.Find(VHTOperation); ok && vht == nil {
+		vht, _ = ParseVHTOperation(e.Data)
+	}
+	if vht == nil || o.Width < 40 {
+		return o
+	}
+	ccfs0, ccfs1 := int(vht.CenterFrequencySegment0), int(vht.CenterFrequencySegment1)
+	switch vht.ChannelWidth {
+	case VHTChannelWidth80:
+		o.Width, o.Center = 80, ccfs0
+		if ccfs1 != 0 {
+			segments(&o, ccfs0, ccfs1)
+		}
+	case VHTChannelWidth160:
+		o.Width, o.Center = 160, ccfs0
+	case VHTChannelWidth80Plus80:
+		o.Width, o.Center, o.Segment1 = 160, ccfs0, ccfs1
+	}
+	return o
+}
+
+//segments sets a 160 MHz channel from the centers of its 80 MHz segments: the second segment is the center
+//of a contiguous 160 MHz channel, or the center of the second half of an 80+80 MHz channel.
+func segments(o *OperatingChannel, ccfs0, ccfs1 int) {
+	o.Width = 160
+	switch d := ccfs1 - ccfs0; {
+	case d == 8 || d == -8:
+		o.Center = ccfs1
+	case ccfs1 != 0:
+		o.Center, o.Segment1 = ccfs0, ccfs1
+	default:
+		o.Width = 80
+	}
+}
+
+//Channels returns the numbers of the 20 MHz channels the BSS occupies.
+func (o OperatingChannel) Channels() []int {
+	if o.Segment1 != 0 {
+		a := OperatingChannel{Width: 80, Center: o.Center}.Channels()
+		return append(a, OperatingChannel{Width: 80, Center: o.Segment1}.Channels()...)
+	}
+	n := o.Width / 20
+	if n <= 1 {
+		return []int{o.Primary}
+	}
+	channels := make([]int, n)
+	for i := range channels {
+		channels[i] = o.Center - 2*(n-1) + 4*i
+	}
+	return channels
+}
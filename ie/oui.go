@@ -0,0 +1,92 @@
+package ie
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	_ "embed"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+)
+
+//go:generate go run mkoui.go
+
+//ouiTable is the gzipped IEEE MA-L registry, as mkoui.go writes it. The committed table is NOT the full
+//registry: it is a subset of 273 OUIs of common Wi-Fi equipment, out of the tens of thousands the IEEE assigned.
+//go generate replaces it with the full registry.
+//
+//go:embed oui.txt.gz
+var ouiTable []byte
+
+var (
+	manufacturersOnce sync.Once
+	manufacturersMu   sync.RWMutex
+	manufacturers     = map[OUI]string{}
+)
+
+//readManufacturers adds the OUIs of a registry in the format of the IEEE oui.txt to the table, whose lines of
+//OUIs read "00-50-F2   (hex)		MICROSOFT CORP.".
+func readManufacturers(r io.Reader) error {
+	table := map[OUI]string{}
+	s := bufio.NewScanner(r)
+	for s.Scan() {
+		fields := strings.SplitN(s.Text(), "(hex)", 2)
+		if len(fields) != 2 {
+			continue
+		}
+		b, err := hex.DecodeString(strings.ReplaceAll(strings.TrimSpace(fields[0]), "-", ""))
+		if err != nil || len(b) != 3 {
+			continue
+		}
+		table[OUI{b[0], b[1], b[2]}] = strings.TrimSpace(fields[1])
+	}
+	if err := s.Err(); err != nil {
+		return fmt.Errorf("ie: reading OUI registry: %v", err)
+	}
+	manufacturersMu.Lock()
+	defer manufacturersMu.Unlock()
+	for oui, name := range table {
+		manufacturers[oui] = name
+	}
+	return nil
+}
+
+func loadEmbedded() {
+	manufacturersOnce.Do(func() {
+		//The embedded table is a gzip stream written by mkoui.go and read from memory, so it does not fail.
+		z, err := gzip.NewReader(bytes.NewReader(ouiTable))
+		if err != nil {
+			return
+		}
+		_ = readManufacturers(z)
+	})
+}
+
+//LoadManufacturers adds the OUIs of a registry in the format of https://standards-oui.ieee.org/oui/oui.txt to
+//the table of Manufacturer, replacing the names of OUIs that it has. Callers that need the manufacturers of all
+//the OUIs load the full registry with it, as the embedded table only has a subset of them.
+func LoadManufacturers(r io.Reader) error {
+	loadEmbedded()
+	return readManufacturers(r)
+}
+
+//Manufacturer returns the organization that the IEEE assigned the OUI of a MAC address to, such as the BSSID of a
+//BSS. Locally administered addresses, as randomized addresses and the BSSIDs of additional BSSes of an AP are,
+//have no manufacturer.
+//
+//The embedded table is a subset of the IEEE registry, of the OUIs of common Wi-Fi equipment: Manufacturer
+//reports no manufacturer for the other OUIs until the full registry is loaded with LoadManufacturers, or embedded
+//by regenerating the table with go generate.
+func Manufacturer(mac []byte) (string, bool) {
+	if len(mac) < 3 || mac[0]&0x02 != 0 {
+		return "", false
+	}
+	loadEmbedded()
+	manufacturersMu.RLock()
+	defer manufacturersMu.RUnlock()
+	name, ok := manufacturers[OUI{mac[0], mac[1], mac[2]}]
+	return name, ok
+}
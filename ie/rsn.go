@@ -0,0 +1,377 @@
+package ie
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+//Cipher is a cipher suite selector: an OUI and a suite type.
+type Cipher uint32
+
+//AKM is an authentication and key management suite selector: an OUI and a suite type.
+type AKM uint32
+
+func suite(oui OUI, t byte) uint32 {
+	return uint32(oui[0])<<24 | uint32(oui[1])<<16 | uint32(oui[2])<<8 | uint32(t)
+}
+
+func suiteOUI(s uint32) OUI {
+	return OUI{byte(s >> 24), byte(s >> 16), byte(s >> 8)}
+}
+
+const (
+	CipherUseGroup      Cipher = 0x000fac00
+	CipherWEP40         Cipher = 0x000fac01
+	CipherTKIP          Cipher = 0x000fac02
+	CipherCCMP          Cipher = 0x000fac04
+	CipherWEP104        Cipher = 0x000fac05
+	CipherBIPCMAC128    Cipher = 0x000fac06
+	CipherGroupNotAllow Cipher = 0x000fac07
+	CipherGCMP          Cipher = 0x000fac08
+	CipherGCMP256       Cipher = 0x000fac09
+	CipherCCMP256       Cipher = 0x000fac0a
+	CipherBIPGMAC128    Cipher = 0x000fac0b
+	CipherBIPGMAC256    Cipher = 0x000fac0c
+	CipherBIPCMAC256    Cipher = 0x000fac0d
+	CipherWPAWEP40      Cipher = 0x0050f201
+	CipherWPATKIP       Cipher = 0x0050f202
+	CipherWPACCMP       Cipher = 0x0050f204
+	CipherWPAWEP104     Cipher = 0x0050f205
+)
+
+var cipherStrings = map[byte]string{
+	0x00: "use group",
+	0x01: "WEP-40",
+	0x02: "TKIP",
+	0x04: "CCMP-128",
+	0x05: "WEP-104",
+	0x06: "BIP-CMAC-128",
+	0x07: "group addressed traffic not allowed",
+	0x08: "GCMP-128",
+	0x09: "GCMP-256",
+	0x0a: "CCMP-256",
+	0x0b: "BIP-GMAC-128",
+	0x0c: "BIP-GMAC-256",
+	0x0d: "BIP-CMAC-256",
+}
+
+//OUI returns the OUI of the suite, 00:0f:ac for RSN and 00:50:f2 for WPA.
+func (c Cipher) OUI() OUI {
+	return suiteOUI(uint32(c))
+}
+
+func (c Cipher) String() string {
+	t := byte(c)
+	if s, ok := cipherStrings[t]; ok && (c.OUI() == OUIIEEE || c.OUI() == OUIMicrosoft && t <= 5) {
+		return s
+	}
+	return fmt.Sprintf("%v:%02x", c.OUI(), t)
+}
+
+const (
+	AKM8021X          AKM = 0x000fac01
+	AKMPSK            AKM = 0x000fac02
+	AKMFT8021X        AKM = 0x000fac03
+	AKMFTPSK          AKM = 0x000fac04
+	AKM8021XSHA256    AKM = 0x000fac05
+	AKMPSKSHA256      AKM = 0x000fac06
+	AKMTDLS           AKM = 0x000fac07
+	AKMSAE            AKM = 0x000fac08
+	AKMFTSAE          AKM = 0x000fac09
+	AKMAPPeerKey      AKM = 0x000fac0a
+	AKM8021XSuiteB    AKM = 0x000fac0b
+	AKM8021XSuiteB192 AKM = 0x000fac0c
+	AKMFT8021XSHA384  AKM = 0x000fac0d
+	AKMFILSSHA256     AKM = 0x000fac0e
+	AKMFILSSHA384     AKM = 0x000fac0f
+	AKMFTFILSSHA256   AKM = 0x000fac10
+	AKMFTFILSSHA384   AKM = 0x000fac11
+	AKMOWE            AKM = 0x000fac12
+	AKMFTPSKSHA384    AKM = 0x000fac13
+	AKMPSKSHA384      AKM = 0x000fac14
+	AKMSAEExt         AKM = 0x000fac18
+	AKMFTSAEExt       AKM = 0x000fac19
+	AKMWPA8021X       AKM = 0x0050f201
+	AKMWPAPSK         AKM = 0x0050f202
+)
+
+var akmStrings = map[AKM]string{
+	AKM8021X:          "802.1X",
+	AKMPSK:            "PSK",
+	AKMFT8021X:        "FT-802.1X",
+	AKMFTPSK:          "FT-PSK",
+	AKM8021XSHA256:    "802.1X-SHA256",
+	AKMPSKSHA256:      "PSK-SHA256",
+	AKMTDLS:           "TDLS",
+	AKMSAE:            "SAE",
+	AKMFTSAE:          "FT-SAE",
+	AKMAPPeerKey:      "AP-PeerKey",
+	AKM8021XSuiteB:    "802.1X-Suite-B",
+	AKM8021XSuiteB192: "802.1X-Suite-B-192",
+	AKMFT8021XSHA384:  "FT-802.1X-SHA384",
+	AKMFILSSHA256:     "FILS-SHA256",
+	AKMFILSSHA384:     "FILS-SHA384",
+	AKMFTFILSSHA256:   "FT-FILS-SHA256",
+	AKMFTFILSSHA384:   "FT-FILS-SHA384",
+	AKMOWE:            "OWE",
+	AKMFTPSKSHA384:    "FT-PSK-SHA384",
+	AKMPSKSHA384:      "PSK-SHA384",
+	AKMSAEExt:         "SAE-EXT-KEY",
+	AKMFTSAEExt:       "FT-SAE-EXT-KEY",
+	AKMWPA8021X:       "WPA-802.1X",
+	AKMWPAPSK:         "WPA-PSK",
+}
+
+//OUI returns the OUI of the suite, 00:0f:ac for RSN and 00:50:f2 for WPA.
+func (a AKM) OUI() OUI {
+	return suiteOUI(uint32(a))
+}
+
+func (a AKM) String() string {
+	if s, ok := akmStrings[a]; ok {
+		return s
+	}
+	return fmt.Sprintf("%v:%02x", a.OUI(), byte(a))
+}
+
+//FT reports whether the AKM is a fast BSS transition (802.11r) AKM.
+func (a AKM) FT() bool {
+	switch a {
+	case AKMFT8021X, AKMFTPSK, AKMFTSAE, AKMFT8021XSHA384, AKMFTFILSSHA256, AKMFTFILSSHA384, AKMFTPSKSHA384, AKMFTSAEExt:
+		return true
+	}
+	return false
+}
+
+//RSN capabilities of the RSNElement.
+const (
+	RSNCapabilityPreauth uint16 = 0x0001
+	RSNCapabilityMFPR    uint16 = 0x0040
+	RSNCapabilityMFPC    uint16 = 0x0080
+)
+
+//RSNElement is the content of an RSN element, or of the WPA vendor specific element which has the same layout.
+//Suites missing from a truncated element take their default values.
+type RSNElement struct {
+	Version         uint16
+	GroupCipher     Cipher
+	PairwiseCiphers []Cipher
+	AKMs            []AKM
+	Capabilities    uint16
+	PMKIDs          [][16]byte
+	//GroupManagementCipher is 0 unless the element lists it.
+	GroupManagementCipher Cipher
+}
+
+var errShortRSN = errors.New("ie: truncated RSN element")
+
+//ParseRSN parses the data of an RSN element.
+func ParseRSN(data []byte) (*RSNElement, error) {
+	return parseRSN(data, OUIIEEE)
+}
+
+//ParseWPA parses the data of the WPA vendor specific element (00:50:f2 type 1), including its OUI and type.
+func ParseWPA(data []byte) (*RSNElement, error) {
+	if len(data) < 4 || (OUI{data[0], data[1], data[2]}) != OUIMicrosoft || data[3] != 1 {
+		return nil, errors.New("ie: not a WPA element")
+	}
+	return parseRSN(data[4:], OUIMicrosoft)
+}
+
+func parseRSN(b []byte, oui OUI) (*RSNElement, error) {
+	if len(b) < 2 {
+		return nil, errShortRSN
+	}
+	r := &RSNElement{
+		Version:         binary.LittleEndian.Uint16(b),
+		GroupCipher:     Cipher(suite(oui, 4)),
+		PairwiseCiphers: []Cipher{Cipher(suite(oui, 4))},
+		AKMs:            []AKM{AKM(suite(oui, 1))},
+	}
+	if oui == OUIMicrosoft {
+		r.GroupCipher, r.PairwiseCiphers[0] = CipherWPATKIP, CipherWPATKIP
+	}
+	b = b[2:]
+	if len(b) == 0 {
+		return r, nil
+	}
+	if len(b) < 4 {
+		return nil, errShortRSN
+	}
+	r.GroupCipher = Cipher(binary.BigEndian.Uint32(b))
+	b = b[4:]
+
+	suites := func() ([]uint32, bool, error) {
+		if len(b) == 0 {
+			return nil, false, nil
+		}
+		if len(b) < 2 {
+			return nil, false, errShortRSN
+		}
+		n := int(binary.LittleEndian.Uint16(b))
+		if len(b) < 2+4*n {
+			return nil, false, errShortRSN
+		}
+		s := make([]uint32, n)
+		for i := range s {
+			s[i] = binary.BigEndian.Uint32(b[2+4*i:])
+		}
+		b = b[2+4*n:]
+		return s, true, nil
+	}
+	pairwise, ok, err := suites()
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return r, nil
+	}
+	r.PairwiseCiphers = make([]Cipher, len(pairwise))
+	for i, s := range pairwise {
+		r.PairwiseCiphers[i] = Cipher(s)
+	}
+	akms, ok, err := suites()
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return r, nil
+	}
+	r.AKMs = make([]AKM, len(akms))
+	for i, s := range akms {
+		r.AKMs[i] = AKM(s)
+	}
+
+	if len(b) < 2 {
+		return r, nil
+	}
+	r.Capabilities = binary.LittleEndian.Uint16(b)
+	b = b[2:]
+	if len(b) < 2 {
+		return r, nil
+	}
+	n := int(binary.LittleEndian.Uint16(b))
+	if len(b) < 2+16*n {
+		return nil, errShortRSN
+	}
+	r.PMKIDs = make([][16]byte, n)
+	for i := range r.PMKIDs {
+		copy(r.PMKIDs[i][:], b[2+16*i:])
+	}
+	b = b[2+16*n:]
+	if len(b) >= 4 {
+		r.GroupManagementCipher = Cipher(binary.BigEndian.Uint32(b))
+	}
+	return r, nil
+}
+
+//Security is the security a BSS advertises: the privacy bit of its capability information and its RSN and WPA elements.
+type Security struct {
+	Privacy bool
+	RSN     *RSNElement
+	WPA     *RSNElement
+}
+
+//capabilityPrivacy is the privacy bit of the capability information of a BSS.
+const capabilityPrivacy = 0x0010
+
+//ParseSecurity returns the security advertised by a BSS with the capability information and elements.
+func ParseSecurity(capability uint16, es Elements) (Security, error) {
+	s := Security{Privacy: capability&capabilityPrivacy != 0}
+	var err error
+	if e, ok := es.Find(RSN); ok {
+		if s.RSN, err = ParseRSN(e.Data); err != nil {
+			return s, err
+		}
+	}
+	if e, ok := es.FindVendor(OUIMicrosoft, 1); ok {
+		if s.WPA, err = ParseWPA(e.Data); err != nil {
+			return s, err
+		}
+	}
+	return s, nil
+}
+
+//AKMs returns the AKMs of the RSN element, then those of the WPA element.
+func (s Security) AKMs() []AKM {
+	var akms []AKM
+	for _, r := range []*RSNElement{s.RSN, s.WPA} {
+		if r != nil {
+			akms = append(akms, r.AKMs...)
+		}
+	}
+	return akms
+}
+
+//PairwiseCiphers returns the pairwise ciphers of the RSN element, then those of the WPA element.
+func (s Security) PairwiseCiphers() []Cipher {
+	var ciphers []Cipher
+	for _, r := range []*RSNElement{s.RSN, s.WPA} {
+		if r != nil {
+			ciphers = append(ciphers, r.PairwiseCiphers...)
+		}
+	}
+	return ciphers
+}
+
+//Open reports whether the BSS accepts stations without any authentication or encryption.
+func (s Security) Open() bool {
+	return !s.Privacy && s.RSN == nil && s.WPA == nil
+}
+
+//securityClasses are the names of the AKMs as the Wi-Fi Alliance certifies them, in the order String lists them.
+var securityClasses = []struct {
+	name string
+	akms []AKM
+}{
+	{"WPA-Personal", []AKM{AKMWPAPSK}},
+	{"WPA-Enterprise", []AKM{AKMWPA8021X}},
+	{"WPA2-Personal", []AKM{AKMPSK, AKMFTPSK, AKMPSKSHA256}},
+	{"WPA2-Enterprise", []AKM{AKM8021X, AKMFT8021X}},
+	{"WPA3-Personal", []AKM{AKMSAE, AKMFTSAE, AKMSAEExt, AKMFTSAEExt}},
+	{"WPA3-Enterprise", []AKM{AKM8021XSHA256}},
+	{"WPA3-Enterprise-192", []AKM{AKM8021XSuiteB192, AKMFT8021XSHA384}},
+	{"OWE", []AKM{AKMOWE}},
+}
+
+//String names the security as Open, WEP, or the certification names of the AKMs joined by slashes,
+//such as WPA2-Personal/WPA3-Personal for a transition mode BSS.
+func (s Security) String() string {
+	if s.RSN == nil && s.WPA == nil {
+		if s.Privacy {
+			return "WEP"
+		}
+		return "Open"
+	}
+	akms := s.AKMs()
+	var names []string
+	known := map[AKM]bool{}
+	for _, c := range securityClasses {
+		for _, a := range c.akms {
+			known[a] = true
+		}
+		for _, a := range akms {
+			if contains(c.akms, a) {
+				names = append(names, c.name)
+				break
+			}
+		}
+	}
+	for _, a := range akms {
+		if !known[a] {
+			names = append(names, a.String())
+		}
+	}
+	return strings.Join(names, "/")
+}
+
+func contains(akms []AKM, a AKM) bool {
+	for _, b := range akms {
+		if a == b {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,240 @@
+package ie
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+//Vendor types of the built-in vendor decoders.
+const (
+	VendorWPA = 1
+	VendorWMM = 2
+	VendorWPS = 4
+	VendorP2P = 9
+	//VendorArubaAPName is the vendor type of the AP name element of OUIAruba.
+	VendorArubaAPName = 3
+)
+
+//OUIAruba is the OUI of the vendor specific elements of Aruba APs.
+var OUIAruba = OUI{0x00, 0x0b, 0x86}
+
+//VendorDecoder decodes the vendor specific elements of an OUI and vendor type.
+type VendorDecoder interface {
+	//Name names the elements, such as WMM.
+	Name() string
+	//Decode decodes the data of an element, which starts with the OUI and vendor type.
+	Decode(data []byte) (interface{}, error)
+}
+
+type vendorDecoder struct {
+	name   string
+	decode func(data []byte) (interface{}, error)
+}
+
+func (d vendorDecoder) Name() string {
+	return d.name
+}
+
+func (d vendorDecoder) Decode(data []byte) (interface{}, error) {
+	return d.decode(data)
+}
+
+//NewVendorDecoder returns the VendorDecoder of a name and a decode function.
+func NewVendorDecoder(name string, decode func(data []byte) (interface{}, error)) VendorDecoder {
+	return vendorDecoder{name, decode}
+}
+
+type vendorKey struct {
+	oui        OUI
+	vendorType byte
+}
+
+//vendorRegistry maps OUIs and vendor types to the decoders of their vendor specific elements.
+type vendorRegistry struct {
+	mu       sync.RWMutex
+	decoders map[vendorKey]VendorDecoder
+}
+
+func newVendorRegistry() *vendorRegistry {
+	return &vendorRegistry{decoders: map[vendorKey]VendorDecoder{}}
+}
+
+//vendorDecoders is the registry of RegisterVendorDecoder and LookupVendorDecoder, with the built-in decoders.
+var vendorDecoders = &vendorRegistry{decoders: map[vendorKey]VendorDecoder{
+	{OUIMicrosoft, VendorWPA}: NewVendorDecoder("WPA", func(data []byte) (interface{}, error) {
+		return ParseWPA(data)
+	}),
+	{OUIMicrosoft, VendorWMM}: NewVendorDecoder("WMM", func(data []byte) (interface{}, error) {
+		return ParseWMM(data)
+	}),
+	{OUIMicrosoft, VendorWPS}: NewVendorDecoder("WPS", func(data []byte) (interface{}, error) {
+		return ParseWPS(data)
+	}),
+	{OUIWFA, VendorP2P}: NewVendorDecoder("P2P", func(data []byte) (interface{}, error) {
+		return ParseP2P(data)
+	}),
+	{OUIWFA, HS20Indication}: NewVendorDecoder("Hotspot 2.0 Indication", func(data []byte) (interface{}, error) {
+		return ParseHS20Indication(data)
+	}),
+	{OUIAruba, VendorArubaAPName}: NewVendorDecoder("AP Name", func(data []byte) (interface{}, error) {
+		return parseArubaAPName(data)
+	}),
+}}
+
+func (r *vendorRegistry) register(oui OUI, vendorType byte, decoder VendorDecoder) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if decoder == nil {
+		panic("ie: RegisterVendorDecoder decoder is nil")
+	}
+	k := vendorKey{oui, vendorType}
+	if _, dup := r.decoders[k]; dup {
+		panic(fmt.Sprintf("ie: RegisterVendorDecoder called twice for %v type %d", oui, vendorType))
+	}
+	r.decoders[k] = decoder
+}
+
+func (r *vendorRegistry) lookup(oui OUI, vendorType byte) (VendorDecoder, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	d, ok := r.decoders[vendorKey{oui, vendorType}]
+	return d, ok
+}
+
+//decode decodes a vendor specific element with the decoder registered for its OUI and vendor type.
+func (r *vendorRegistry) decode(e Element) (interface{}, error) {
+	oui, t, ok := e.Vendor()
+	if !ok {
+		return nil, errors.New("ie: not a vendor specific element")
+	}
+	d, ok := r.lookup(oui, t)
+	if !ok {
+		return nil, ErrNoVendorDecoder
+	}
+	return d.Decode(e.Data)
+}
+
+//RegisterVendorDecoder makes decoder decode the vendor specific elements of an OUI and vendor type.
+//It panics if decoder is nil or a decoder is already registered for the OUI and vendor type.
+func RegisterVendorDecoder(oui OUI, vendorType byte, decoder VendorDecoder) {
+	vendorDecoders.register(oui, vendorType, decoder)
+}
+
+//LookupVendorDecoder returns the decoder registered for an OUI and vendor type.
+func LookupVendorDecoder(oui OUI, vendorType byte) (VendorDecoder, bool) {
+	return vendorDecoders.lookup(oui, vendorType)
+}
+
+//ErrNoVendorDecoder is returned by DecodeVendor when no decoder is registered for the OUI and vendor type.
+var ErrNoVendorDecoder = errors.New("ie: no decoder registered for vendor specific element")
+
+//DecodeVendor decodes a vendor specific element with the decoder registered for its OUI and vendor type.
+func (e Element) DecodeVendor() (interface{}, error) {
+	return vendorDecoders.decode(e)
+}
+
+//Access categories of WMM, indexing WMMElement.AccessCategories.
+const (
+	WMMBestEffort = 0
+	WMMBackground = 1
+	WMMVideo      = 2
+	WMMVoice      = 3
+)
+
+//Subtypes of the WMM element.
+const (
+	WMMInformation = 0
+	WMMParameter   = 1
+)
+
+//WMMAccessCategory is the EDCA parameters of an access category of the WMM Parameter element.
+type WMMAccessCategory struct {
+	AIFSN uint8
+	//ACM is set when stations need admission control to send frames of the category.
+	ACM bool
+	//CWMin and CWMax are the bounds of the contention window, in slots.
+	CWMin uint16
+	CWMax uint16
+	//TXOPLimit is in units of 32 microseconds; 0 allows one frame per transmit opportunity.
+	TXOPLimit uint16
+}
+
+//WMMElement is the content of the WMM Information or Parameter element.
+type WMMElement struct {
+	Subtype uint8
+	Version uint8
+	//ParameterSetCount changes when the AP changes the parameters of the access categories.
+	ParameterSetCount uint8
+	//UAPSD is set when the AP supports unscheduled automatic power save delivery.
+	UAPSD bool
+	//AccessCategories are the parameters of the WMM Parameter element, by access category; nil for the
+	//WMM Information element.
+	AccessCategories []WMMAccessCategory
+}
+
+//ParseWMM parses the data of a WMM vendor specific element (00:50:f2 type 2), including its OUI and type.
+func ParseWMM(data []byte) (*WMMElement, error) {
+	if len(data) < 4 || (OUI{data[0], data[1], data[2]}) != OUIMicrosoft || data[3] != VendorWMM {
+		return nil, errors.New("ie: not a WMM element")
+	}
+	errShort := errors.New("ie: truncated WMM element")
+	data = data[4:]
+	if len(data) < 3 {
+		return nil, errShort
+	}
+	w := &WMMElement{Subtype: data[0], Version: data[1], ParameterSetCount: data[2] & 0x0f, UAPSD: data[2]&0x80 != 0}
+	if w.Subtype != WMMParameter {
+		return w, nil
+	}
+	data = data[3:]
+	if len(data) < 1+4*4 {
+		return nil, errShort
+	}
+	w.AccessCategories = make([]WMMAccessCategory, 4)
+	for n := 0; n < 4; n++ {
+		r := data[1+4*n:]
+		w.AccessCategories[r[0]>>5&0x03] = WMMAccessCategory{
+			AIFSN:     r[0] & 0x0f,
+			ACM:       r[0]&0x10 != 0,
+			CWMin:     1<<(r[1]&0x0f) - 1,
+			CWMax:     1<<(r[1]>>4) - 1,
+			TXOPLimit: binary.LittleEndian.Uint16(r[2:]),
+		}
+	}
+	return w, nil
+}
+
+//APName is the name of the AP of a BSS, as some vendors send it in their elements.
+type APName string
+
+func parseArubaAPName(data []byte) (APName, error) {
+	//The name follows a reserved byte.
+	if len(data) < 5 || (OUI{data[0], data[1], data[2]}) != OUIAruba || data[3] != VendorArubaAPName {
+		return "", errors.New("ie: not an Aruba AP name element")
+	}
+	return APName(strings.TrimRight(string(data[5:]), "\x00")), nil
+}
+
+//APName returns the name of the AP, from a vendor specific element whose registered decoder returns an APName or
+//from the Cisco CCX1 element.
+func (es Elements) APName() (string, bool) {
+	for _, e := range es {
+		switch {
+		case e.ID == VendorSpecific:
+			if name, err := e.DecodeVendor(); err == nil {
+				if name, ok := name.(APName); ok && name != "" {
+					return string(name), true
+				}
+			}
+		case e.ID == CiscoCCX1 && len(e.Data) >= 26:
+			//The name is 16 bytes, padded with NULs, after 10 bytes of other data.
+			if name := strings.TrimRight(string(e.Data[10:26]), "\x00"); name != "" {
+				return name, true
+			}
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,281 @@
+package ie
+
+import (
+	"encoding/binary"
+	"errors"
+	"net"
+)
+
+//The Wi-Fi Protected Setup State of the WPSElement.
+const (
+	WPSNotConfigured = 1
+	WPSConfigured    = 2
+)
+
+//Device password IDs of the WPSElement.
+const (
+	WPSPasswordPIN        = 0x0000
+	WPSPasswordPushButton = 0x0004
+)
+
+//Attribute types of WPS.
+const (
+	wpsConfigMethods                 = 0x1008
+	wpsDeviceName                    = 0x1011
+	wpsDevicePasswordID              = 0x1012
+	wpsManufacturer                  = 0x1021
+	wpsModelName                     = 0x1023
+	wpsModelNumber                   = 0x1024
+	wpsRFBands                       = 0x103c
+	wpsSelectedRegistrar             = 0x1041
+	wpsSerialNumber                  = 0x1042
+	wpsState                         = 0x1044
+	wpsUUIDE                         = 0x1047
+	wpsVendorExtension               = 0x1049
+	wpsVersion                       = 0x104a
+	wpsSelectedRegistrarConfigMethod = 0x1053
+	wpsPrimaryDeviceType             = 0x1054
+	wpsAPSetupLocked                 = 0x1057
+)
+
+//wfaVendorID is the vendor ID of the Wi-Fi Alliance in WPS vendor extensions.
+var wfaVendorID = []byte{0x00, 0x37, 0x2a}
+
+//WPSElement is the content of the WPS element of a BSS that supports Wi-Fi Protected Setup.
+type WPSElement struct {
+	//Version is 0x10; Version2 is the version of WPS 2.0 and later, such as 0x20, or 0 for WPS 1.0.
+	Version  uint8
+	Version2 uint8
+	State    uint8
+	//APSetupLocked is set when the AP refuses PIN registrations after failed attempts.
+	APSetupLocked bool
+	//SelectedRegistrar is set while a registrar accepts enrollees, such as after the push button was pressed.
+	SelectedRegistrar bool
+	DevicePasswordID  uint16
+	//ConfigMethods is the bit field of the configuration methods of the AP, or of the selected registrar.
+	ConfigMethods uint16
+	UUID          []byte
+	Manufacturer  string
+	ModelName     string
+	ModelNumber   string
+	SerialNumber  string
+	DeviceName    string
+	//PrimaryDeviceType is the category, OUI and subcategory of the device.
+	PrimaryDeviceType []byte
+	RFBands           uint8
+}
+
+//Configured reports whether the AP is configured, having a network key to hand out.
+func (w *WPSElement) Configured() bool {
+	return w.State == WPSConfigured
+}
+
+//wpsAttributes splits WPS data into its attributes, whose type and length are big endian.
+func wpsAttributes(data []byte, f func(t uint16, v []byte)) error {
+	for len(data) > 0 {
+		if len(data) < 4 {
+			return errors.New("ie: truncated WPS attribute")
+		}
+		t, n := binary.BigEndian.Uint16(data), int(binary.BigEndian.Uint16(data[2:]))
+		if len(data) < 4+n {
+			return errors.New("ie: truncated WPS attribute")
+		}
+		f(t, data[4:4+n])
+		data = data[4+n:]
+	}
+	return nil
+}
+
+//ParseWPS parses the data of a WPS vendor specific element (00:50:f2 type 4), including its OUI and type.
+func ParseWPS(data []byte) (*WPSElement, error) {
+	if len(data) < 4 || (OUI{data[0], data[1], data[2]}) != OUIMicrosoft || data[3] != VendorWPS {
+		return nil, errors.New("ie: not a WPS element")
+	}
+	w := &WPSElement{}
+	u8 := func(v []byte) uint8 {
+		if len(v) < 1 {
+			return 0
+		}
+		return v[0]
+	}
+	u16 := func(v []byte) uint16 {
+		if len(v) < 2 {
+			return 0
+		}
+		return binary.BigEndian.Uint16(v)
+	}
+	err := wpsAttributes(data[4:], func(t uint16, v []byte) {
+		switch t {
+		case wpsVersion:
+			w.Version = u8(v)
+		case wpsState:
+			w.State = u8(v)
+		case wpsAPSetupLocked:
+			w.APSetupLocked = u8(v) != 0
+		case wpsSelectedRegistrar:
+			w.SelectedRegistrar = u8(v) != 0
+		case wpsDevicePasswordID:
+			w.DevicePasswordID = u16(v)
+		case wpsConfigMethods, wpsSelectedRegistrarConfigMethod:
+			w.ConfigMethods = u16(v)
+		case wpsUUIDE:
+			w.UUID = v
+		case wpsManufacturer:
+			w.Manufacturer = string(v)
+		case wpsModelName:
+			w.ModelName = string(v)
+		case wpsModelNumber:
+			w.ModelNumber = string(v)
+		case wpsSerialNumber:
+			w.SerialNumber = string(v)
+		case wpsDeviceName:
+			w.DeviceName = string(v)
+		case wpsPrimaryDeviceType:
+			w.PrimaryDeviceType = v
+		case wpsRFBands:
+			w.RFBands = u8(v)
+		case wpsVendorExtension:
+			//The subelements of the Wi-Fi Alliance extension have a byte of ID and of length; 0 is Version2.
+			if len(v) < 3 || string(v[:3]) != string(wfaVendorID) {
+				return
+			}
+			for s := v[3:]; len(s) >= 2 && len(s) >= 2+int(s[1]); s = s[2+int(s[1]):] {
+				if s[0] == 0 && s[1] > 0 {
+					w.Version2 = s[2]
+				}
+			}
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+//Bits of the group capability of the P2PElement.
+const (
+	P2PGroupOwner          = 0x01
+	P2PPersistentGroup     = 0x02
+	P2PGroupLimit          = 0x04
+	P2PIntraBSS            = 0x08
+	P2PCrossConnection     = 0x10
+	P2PPersistentReconnect = 0x20
+)
+
+//Attribute IDs of P2P.
+const (
+	p2pCapability       = 2
+	p2pDeviceID         = 3
+	p2pListenChannel    = 6
+	p2pDeviceInfo       = 13
+	p2pOperatingChannel = 17
+)
+
+//P2PElement is the content of the P2P element of a Wi-Fi Direct device or group owner.
+type P2PElement struct {
+	DeviceCapability uint8
+	GroupCapability  uint8
+	//DeviceAddress is the P2P device address, from the P2P Device ID or P2P Device Info attribute.
+	DeviceAddress net.HardwareAddr
+	DeviceName    string
+	ConfigMethods uint16
+	//PrimaryDeviceType is the category, OUI and subcategory of the device.
+	PrimaryDeviceType []byte
+	//ListenChannel and OperatingChannel are channel numbers, or 0 without their attributes.
+	ListenChannel    uint8
+	OperatingChannel uint8
+	//Attributes are the data of all attributes by ID, including those decoded above.
+	Attributes map[uint8][]byte
+}
+
+//GroupOwner reports whether the device is the group owner of a P2P group, acting as its AP.
+func (p *P2PElement) GroupOwner() bool {
+	return p.GroupCapability&P2PGroupOwner != 0
+}
+
+//ParseP2P parses the data of a P2P vendor specific element (50:6f:9a type 9), including its OUI and type.
+//A device may split its attributes over several P2P elements; Elements.P2P decodes them together.
+func ParseP2P(data []byte) (*P2PElement, error) {
+	if len(data) < 4 || (OUI{data[0], data[1], data[2]}) != OUIWFA || data[3] != VendorP2P {
+		return nil, errors.New("ie: not a P2P element")
+	}
+	return parseP2P(data[4:])
+}
+
+func parseP2P(data []byte) (*P2PElement, error) {
+	errShort := errors.New("ie: truncated P2P attribute")
+	p := &P2PElement{Attributes: map[uint8][]byte{}}
+	for len(data) > 0 {
+		if len(data) < 3 {
+			return nil, errShort
+		}
+		id, n := data[0], int(binary.LittleEndian.Uint16(data[1:]))
+		if len(data) < 3+n {
+			return nil, errShort
+		}
+		v := data[3 : 3+n]
+		data = data[3+n:]
+		p.Attributes[id] = v
+		switch {
+		case id == p2pCapability && n >= 2:
+			p.DeviceCapability, p.GroupCapability = v[0], v[1]
+		case id == p2pDeviceID && n >= 6:
+			p.DeviceAddress = net.HardwareAddr(v[:6])
+		case id == p2pListenChannel && n >= 5:
+			//The channel follows the country string and operating class.
+			p.ListenChannel = v[4]
+		case id == p2pOperatingChannel && n >= 5:
+			p.OperatingChannel = v[4]
+		case id == p2pDeviceInfo && n >= 17:
+			p.DeviceAddress = net.HardwareAddr(v[:6])
+			p.ConfigMethods = binary.BigEndian.Uint16(v[6:])
+			p.PrimaryDeviceType = v[8:16]
+			//The secondary device types precede the name, which is a WPS Device Name attribute.
+			rest := v[17:]
+			if len(rest) < 8*int(v[16]) {
+				return nil, errShort
+			}
+			err := wpsAttributes(rest[8*int(v[16]):], func(t uint16, v []byte) {
+				if t == wpsDeviceName {
+					p.DeviceName = string(v)
+				}
+			})
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	return p, nil
+}
+
+//vendorData returns the data of the vendor specific elements of an OUI and vendor type, after their OUI and type,
+//concatenated as the attributes of an element split over several are.
+func (es Elements) vendorData(oui OUI, vendorType byte) ([]byte, bool) {
+	var data []byte
+	found := false
+	for _, e := range es {
+		if o, t, ok := e.Vendor(); ok && o == oui && t == vendorType {
+			data = append(data, e.Data[4:]...)
+			found = true
+		}
+	}
+	return data, found
+}
+
+//P2P returns the P2P attributes of all P2P elements; it is nil for BSSes without them.
+func (es Elements) P2P() (*P2PElement, error) {
+	data, ok := es.vendorData(OUIWFA, VendorP2P)
+	if !ok {
+		return nil, nil
+	}
+	return parseP2P(data)
+}
+
+//WPS returns the WPS attributes of all WPS elements; it is nil for BSSes without them.
+func (es Elements) WPS() (*WPSElement, error) {
+	data, ok := es.vendorData(OUIMicrosoft, VendorWPS)
+	if !ok {
+		return nil, nil
+	}
+	return ParseWPS(append([]byte{OUIMicrosoft[0], OUIMicrosoft[1], OUIMicrosoft[2], VendorWPS}, data...))
+}
@@ -0,0 +1,50 @@
+//go:build windows
+// +build windows
+
+package wlanapi
+
+import (
+	"errors"
+	"runtime"
+	"unsafe"
+)
+
+//ihvBufferSize is the first output buffer size tried by IHVControl.
+const ihvBufferSize = 1024
+
+//IHVControl sends the in buffer to the IHV service or driver of the interface and returns its response.
+//The output buffer is grown on ERROR_MORE_DATA up to maxOut bytes.
+func (i *Interface) IHVControl(kind WLAN_IHV_CONTROL_TYPE, in []byte, maxOut int) ([]byte, error) {
+	if maxOut <= 0 {
+		return nil, errors.New("wlanapi: IHVControl maxOut must be positive")
+	}
+	var pIn *BYTE
+	if len(in) > 0 {
+		pIn = (*BYTE)(unsafe.Pointer(&in[0]))
+	}
+	return growBuffer(ihvBufferSize, maxOut, func(out []byte) (int, error) {
+		n, err := WlanIhvControl(i.client.handle, &i.GUID, kind,
+			DWORD(len(in)), pIn, DWORD(len(out)), (*BYTE)(unsafe.Pointer(&out[0])))
+		runtime.KeepAlive(in)
+		runtime.KeepAlive(out)
+		return int(n), err
+	})
+}
+
+//IHVRequest encodes req with the codec registered for the interface description,
+//sends it with IHVControl and decodes the response.
+func (i *Interface) IHVRequest(req interface{}) (interface{}, error) {
+	codec, ok := LookupIHVCodec(i.Description)
+	if !ok {
+		return nil, ErrNoIHVCodec
+	}
+	in, err := codec.EncodeRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	out, err := i.IHVControl(codec.Type(), in, codec.MaxResponseSize())
+	if err != nil {
+		return nil, err
+	}
+	return codec.DecodeResponse(out)
+}
@@ -0,0 +1,44 @@
+package wlanapi
+
+import (
+	"testing"
+)
+
+type testIHVCodec struct{ name string }
+
+func (c testIHVCodec) Type() WLAN_IHV_CONTROL_TYPE                     { return WlanIhvControlTypeDriver }
+func (c testIHVCodec) MaxResponseSize() int                            { return 64 }
+func (c testIHVCodec) EncodeRequest(req interface{}) ([]byte, error)   { return nil, nil }
+func (c testIHVCodec) DecodeResponse(resp []byte) (interface{}, error) { return c.name, nil }
+
+func TestLookupIHVCodec(t *testing.T) {
+	r := newIHVRegistry()
+	r.register("Intel(R) Wi-Fi 6", testIHVCodec{"family"})
+	r.register("Intel(R) Wi-Fi 6 AX201 160MHz", testIHVCodec{"exact"})
+	r.register("Wi-Fi", testIHVCodec{"generic"})
+	r.register("Realtek", testIHVCodec{"realtek"})
+	r.register("Adapter", testIHVCodec{"adapter"})
+
+	tests := []struct {
+		description string
+		want        string
+	}{
+		{"Intel(R) Wi-Fi 6 AX201 160MHz", "exact"},
+		{"Intel(R) Wi-Fi 6 AX200 160MHz", "family"},
+		{"Realtek Wi-Fi Adapter", "adapter"},
+		{"Ralink Wi-Fi", "generic"},
+		{"Qualcomm Atheros", ""},
+	}
+	for _, tt := range tests {
+		codec, ok := r.lookup(tt.description)
+		if tt.want == "" {
+			if ok {
+				t.Errorf("LookupIHVCodec(%q) found %v", tt.description, codec)
+			}
+			continue
+		}
+		if !ok || codec.(testIHVCodec).name != tt.want {
+			t.Errorf("LookupIHVCodec(%q) = %v, want %s", tt.description, codec, tt.want)
+		}
+	}
+}
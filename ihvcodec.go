@@ -0,0 +1,76 @@
+package wlanapi
+
+import (
+	"errors"
+	"strings"
+	"sync"
+)
+
+//IHVCodec converts vendor-specific request and response structs to and from IHV control buffers.
+type IHVCodec interface {
+	//Type is the software the codec talks to.
+	Type() WLAN_IHV_CONTROL_TYPE
+	//MaxResponseSize bounds the response buffer.
+	MaxResponseSize() int
+	EncodeRequest(req interface{}) ([]byte, error)
+	DecodeResponse(resp []byte) (interface{}, error)
+}
+
+//ihvRegistry maps interface descriptions to the codecs of their IHV software.
+type ihvRegistry struct {
+	mu     sync.RWMutex
+	codecs map[string]IHVCodec
+}
+
+func newIHVRegistry() *ihvRegistry {
+	return &ihvRegistry{codecs: map[string]IHVCodec{}}
+}
+
+//ihvCodecs is the registry of RegisterIHVCodec and LookupIHVCodec.
+var ihvCodecs = newIHVRegistry()
+
+func (r *ihvRegistry) register(description string, codec IHVCodec) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if codec == nil {
+		panic("wlanapi: RegisterIHVCodec codec is nil")
+	}
+	if _, dup := r.codecs[description]; dup {
+		panic("wlanapi: RegisterIHVCodec called twice for " + description)
+	}
+	r.codecs[description] = codec
+}
+
+func (r *ihvRegistry) lookup(description string) (IHVCodec, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if codec, ok := r.codecs[description]; ok {
+		return codec, true
+	}
+	var match string
+	for d := range r.codecs {
+		if (len(d) > len(match) || len(d) == len(match) && d < match) && strings.Contains(description, d) {
+			match = d
+		}
+	}
+	if match == "" {
+		return nil, false
+	}
+	return r.codecs[match], true
+}
+
+//RegisterIHVCodec makes codec available to interfaces whose description contains description.
+//It panics if codec is nil or a codec is already registered for description.
+func RegisterIHVCodec(description string, codec IHVCodec) {
+	ihvCodecs.register(description, codec)
+}
+
+//LookupIHVCodec returns the codec registered for an interface description.
+//An exact match wins, otherwise the longest registered description contained in it, and the lexically first
+//of the longest when several are as long.
+func LookupIHVCodec(description string) (IHVCodec, bool) {
+	return ihvCodecs.lookup(description)
+}
+
+//ErrNoIHVCodec is returned by IHVRequest when no codec is registered for the interface.
+var ErrNoIHVCodec = errors.New("wlanapi: no IHV codec registered for interface")
@@ -0,0 +1,461 @@
+//Package remote carries out the requests of the remote management servers, httpapi and grpcapi, on a Client:
+//it selects the interfaces, reads their connections, finds their profiles, checks the parameters of connections
+//and changes the Hosted Network, so that both servers behave the same. The servers convert the results to their
+//models and answer the errors with the status codes of the Kind of each.
+package remote
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+
+	"wlanapi"
+	"wlanapi/binary"
+)
+
+//Kind classifies the errors of the requests.
+type Kind int
+
+const (
+	//Internal is the kind of the errors of the Client that are not of another kind.
+	Internal Kind = iota
+	//Invalid is the kind of invalid requests.
+	Invalid
+	//NotFound is the kind of the requests of unknown interfaces and profiles.
+	NotFound
+	//Conflict is the kind of the requests that the state of an interface or of the Hosted Network does not allow.
+	Conflict
+	//Unsupported is the kind of the requests of features that the backend does not have.
+	Unsupported
+)
+
+//Error is an error of a request of a given kind.
+type Error struct {
+	Kind Kind
+	Err  error
+}
+
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+func invalid(format string, args ...interface{}) error {
+	return &Error{Invalid, fmt.Errorf(format, args...)}
+}
+
+func notFound(format string, args ...interface{}) error {
+	return &Error{NotFound, fmt.Errorf(format, args...)}
+}
+
+//KindOf returns the kind of an error of a request. InterfaceErrors are of the kind of their errors when they
+//agree on one.
+func KindOf(err error) Kind {
+	var e *Error
+	var errs wlanapi.InterfaceErrors
+	switch {
+	case errors.As(err, &e):
+		return e.Kind
+	case errors.As(err, &errs):
+		kind := KindOf(errs[0])
+		for _, e := range errs[1:] {
+			if KindOf(e) != kind {
+				return Internal
+			}
+		}
+		return kind
+	case errors.Is(err, wlanapi.ErrProfileNotFound):
+		return NotFound
+	case errors.Is(err, wlanapi.ErrNotConnected), errors.Is(err, wlanapi.ErrHostedNetworkState):
+		return Conflict
+	case errors.Is(err, wlanapi.ErrBackendNotSupported):
+		return Unsupported
+	}
+	return Internal
+}
+
+//Service carries out the requests on a Client.
+type Service struct {
+	client *wlanapi.Client
+}
+
+//New returns a Service of c.
+func New(c *wlanapi.Client) *Service {
+	return &Service{client: c}
+}
+
+//Interface is an interface with its connection, nil when it is not connected.
+type Interface struct {
+	Interface  *wlanapi.Interface
+	Connection *binary.ConnectionAttributes
+}
+
+//describe reads the connection of an interface.
+func describe(i *wlanapi.Interface) (Interface, error) {
+	conn, err := i.Connection()
+	if errors.Is(err, wlanapi.ErrNotConnected) || errors.Is(err, wlanapi.ErrBackendNotSupported) {
+		conn, err = nil, nil
+	}
+	return Interface{Interface: i, Connection: conn}, err
+}
+
+//refresh describes an interface after an operation changed its state.
+func (s *Service) refresh(i *wlanapi.Interface) (Interface, error) {
+	interfaces, err := s.client.Interfaces()
+	if err != nil {
+		return Interface{}, err
+	}
+	for _, current := range interfaces {
+		if current.GUID == i.GUID {
+			return describe(current)
+		}
+	}
+	return describe(i)
+}
+
+//interfaceByGUID returns the interface with the GUID, with or without braces.
+func (s *Service) interfaceByGUID(id string) (*wlanapi.Interface, error) {
+	if !strings.HasPrefix(id, "{") {
+		id = "{" + id + "}"
+	}
+	guid, err := wlanapi.ParseGUID(strings.ToUpper(id))
+	if err != nil {
+		return nil, &Error{Invalid, err}
+	}
+	interfaces, err := s.client.Interfaces()
+	if err != nil {
+		return nil, err
+	}
+	for _, i := range interfaces {
+		if i.GUID == guid {
+			return i, nil
+		}
+	}
+	return nil, notFound("no interface %s", guid.String())
+}
+
+//each runs fn on the interfaces chosen by a selector, as wlanapi.ParseInterfaceSelector parses it, returning the
+//errors of those where it fails. No interface matching the selector is an error.
+func (s *Service) each(selector string, fn func(*wlanapi.Interface) error) error {
+	sel, err := wlanapi.ParseInterfaceSelector(selector)
+	if err != nil {
+		return &Error{Invalid, err}
+	}
+	interfaces, err := s.client.SelectInterfaces(sel)
+	if err != nil {
+		return err
+	}
+	if len(interfaces) == 0 {
+		return notFound("no interface matches %q", selector)
+	}
+	var errs wlanapi.InterfaceErrors
+	for _, i := range interfaces {
+		if err := fn(i); err != nil {
+			errs = append(errs, &wlanapi.InterfaceError{Interface: i, Err: err})
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+//Interfaces describes the selected interfaces.
+func (s *Service) Interfaces(selector string) ([]Interface, error) {
+	var result []Interface
+	err := s.each(selector, func(i *wlanapi.Interface) error {
+		v, err := describe(i)
+		if err == nil {
+			result = append(result, v)
+		}
+		return err
+	})
+	return result, err
+}
+
+//Scan requests a scan on an interface and describes it.
+func (s *Service) Scan(id string) (Interface, error) {
+	i, err := s.interfaceByGUID(id)
+	if err != nil {
+		return Interface{}, err
+	}
+	if err := i.Scan(); err != nil {
+		return Interface{}, err
+	}
+	return describe(i)
+}
+
+//AvailableNetworks returns the available networks of an interface.
+func (s *Service) AvailableNetworks(id string) ([]binary.AvailableNetwork, error) {
+	i, err := s.interfaceByGUID(id)
+	if err != nil {
+		return nil, err
+	}
+	return i.AvailableNetworks()
+}
+
+//BSSList returns the BSSes visible to an interface.
+func (s *Service) BSSList(id string) ([]binary.BSSEntry, error) {
+	i, err := s.interfaceByGUID(id)
+	if err != nil {
+		return nil, err
+	}
+	return i.BSSList()
+}
+
+//Profile is a profile of an interface. XML is only read when a single profile is requested.
+type Profile struct {
+	Interface *wlanapi.Interface
+	Info      binary.ProfileInfo
+	XML       string
+}
+
+//findProfile returns the profile of an interface with the name.
+func findProfile(i *wlanapi.Interface, name string) (Profile, error) {
+	profiles, err := i.Profiles()
+	if err != nil {
+		return Profile{}, err
+	}
+	for _, p := range profiles {
+		if p.Name == name {
+			return Profile{Interface: i, Info: p}, nil
+		}
+	}
+	return Profile{}, wlanapi.ErrProfileNotFound
+}
+
+//ProfileName returns the name of a profile from its XML.
+func ProfileName(profileXML string) (string, error) {
+	var p struct {
+		Name string `xml:"name"`
+	}
+	if err := xml.Unmarshal([]byte(profileXML), &p); err != nil {
+		return "", invalid("invalid profile XML: %v", err)
+	}
+	if p.Name == "" {
+		return "", invalid("the profile XML has no name")
+	}
+	return p.Name, nil
+}
+
+//Profiles returns the profiles of the selected interfaces.
+func (s *Service) Profiles(selector string) ([]Profile, error) {
+	var result []Profile
+	err := s.each(selector, func(i *wlanapi.Interface) error {
+		profiles, err := i.Profiles()
+		for _, p := range profiles {
+			result = append(result, Profile{Interface: i, Info: p})
+		}
+		return err
+	})
+	return result, err
+}
+
+//Profile returns the profile with the name, and its XML, of the selected interfaces that have it. The key is in
+//plain text when plaintextKey is set. No interface having the profile is an error.
+func (s *Service) Profile(selector, name string, plaintextKey bool) ([]Profile, error) {
+	var result []Profile
+	err := s.each(selector, func(i *wlanapi.Interface) error {
+		p, err := findProfile(i, name)
+		if errors.Is(err, wlanapi.ErrProfileNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if p.XML, err = i.ProfileXML(name, plaintextKey); err != nil {
+			return err
+		}
+		result = append(result, p)
+		return nil
+	})
+	if err == nil && len(result) == 0 {
+		return nil, notFound("no profile %q", name)
+	}
+	return result, err
+}
+
+//SetProfile sets a profile on the selected interfaces, replacing a profile of the same name when overwrite is
+//set, and returns the profile of each.
+func (s *Service) SetProfile(selector, profileXML string, overwrite bool) ([]Profile, error) {
+	name, err := ProfileName(profileXML)
+	if err != nil {
+		return nil, err
+	}
+	var result []Profile
+	err = s.each(selector, func(i *wlanapi.Interface) error {
+		if err := i.SetProfile(profileXML, overwrite); err != nil {
+			return err
+		}
+		p, err := findProfile(i, name)
+		if err == nil {
+			result = append(result, p)
+		}
+		return err
+	})
+	return result, err
+}
+
+//DeleteProfile deletes a profile from the selected interfaces that have it. No interface having the profile is
+//an error.
+func (s *Service) DeleteProfile(selector, name string) error {
+	deleted := false
+	err := s.each(selector, func(i *wlanapi.Interface) error {
+		err := i.DeleteProfile(name)
+		if errors.Is(err, wlanapi.ErrProfileNotFound) {
+			return nil
+		}
+		deleted = deleted || err == nil
+		return err
+	})
+	if err == nil && !deleted {
+		return notFound("no profile %q", name)
+	}
+	return err
+}
+
+//ConnectRequest is a request to connect the selected interfaces with a stored profile or with the XML of a
+//temporary one.
+type ConnectRequest struct {
+	Selector   string
+	Profile    string
+	ProfileXML string
+	SSID       []byte
+	//BSSIDs are MAC addresses in the formats of net.ParseMAC.
+	BSSIDs []string
+	//BssType is infrastructure, independent, or empty for infrastructure.
+	BssType string
+}
+
+//parameters checks the request and returns its connection parameters.
+func (r *ConnectRequest) parameters() (wlanapi.ConnectionParameters, error) {
+	p := wlanapi.ConnectionParameters{Profile: r.Profile, ProfileXML: r.ProfileXML, SSID: r.SSID}
+	if (r.Profile == "") == (r.ProfileXML == "") {
+		return p, invalid("a connection needs either a profile or the XML of a profile")
+	}
+	for _, b := range r.BSSIDs {
+		addr, err := net.ParseMAC(b)
+		if err != nil || len(addr) != 6 {
+			return p, invalid("invalid BSSID %q", b)
+		}
+		var bssid [6]byte
+		copy(bssid[:], addr)
+		p.BSSIDs = append(p.BSSIDs, bssid)
+	}
+	if r.BssType == "" {
+		return p, nil
+	}
+	for _, t := range []wlanapi.DOT11_BSS_TYPE{1, 2} {
+		if strings.EqualFold(r.BssType, t.String()) {
+			p.BssType = t
+			return p, nil
+		}
+	}
+	return p, invalid("invalid BSS type %q", r.BssType)
+}
+
+//Connect connects the selected interfaces and describes them.
+func (s *Service) Connect(r ConnectRequest) ([]Interface, error) {
+	p, err := r.parameters()
+	if err != nil {
+		return nil, err
+	}
+	var result []Interface
+	err = s.each(r.Selector, func(i *wlanapi.Interface) error {
+		if err := i.Connect(p); err != nil {
+			return err
+		}
+		v, err := s.refresh(i)
+		if err == nil {
+			result = append(result, v)
+		}
+		return err
+	})
+	return result, err
+}
+
+//Disconnect disconnects the selected interfaces and describes them.
+func (s *Service) Disconnect(selector string) ([]Interface, error) {
+	var result []Interface
+	err := s.each(selector, func(i *wlanapi.Interface) error {
+		if err := i.Disconnect(); err != nil {
+			return err
+		}
+		v, err := s.refresh(i)
+		if err == nil {
+			result = append(result, v)
+		}
+		return err
+	})
+	return result, err
+}
+
+//HostedNetwork is the status and the settings of the Hosted Network.
+type HostedNetwork struct {
+	Status   *binary.HostedNetworkStatus
+	Settings *wlanapi.HostedNetworkSettings
+}
+
+//HostedNetwork reads the status and the settings of the Hosted Network.
+func (s *Service) HostedNetwork() (HostedNetwork, error) {
+	status, err := s.client.HostedNetworkStatus()
+	if err != nil {
+		return HostedNetwork{}, err
+	}
+	settings, err := s.client.HostedNetworkSettings()
+	if err != nil {
+		return HostedNetwork{}, err
+	}
+	return HostedNetwork{Status: status, Settings: settings}, nil
+}
+
+//HostedNetworkRequest changes the settings of the Hosted Network, then starts or stops it.
+type HostedNetworkRequest struct {
+	//Action is start, stop, or empty to only change the settings.
+	Action string
+	//SSID and MaxPeers replace the settings they are given for; the settings left empty are kept.
+	SSID     []byte
+	MaxPeers uint32
+}
+
+//SetHostedNetwork carries out a request on the Hosted Network and returns its new status and settings. Starting
+//or stopping it in a state that does not allow it is a Conflict.
+func (s *Service) SetHostedNetwork(r HostedNetworkRequest) (HostedNetwork, error) {
+	var action func() error
+	switch r.Action {
+	case "start":
+		action = s.client.StartHostedNetwork
+	case "stop":
+		action = s.client.StopHostedNetwork
+	case "":
+	default:
+		return HostedNetwork{}, invalid("invalid action %q: want start or stop", r.Action)
+	}
+	if len(r.SSID) > 0 || r.MaxPeers != 0 {
+		//Keep the setting the request leaves out.
+		settings, err := s.client.HostedNetworkSettings()
+		if err != nil {
+			return HostedNetwork{}, err
+		}
+		if len(r.SSID) > 0 {
+			settings.SSID = r.SSID
+		}
+		if r.MaxPeers != 0 {
+			settings.MaxPeers = r.MaxPeers
+		}
+		if err := s.client.SetHostedNetworkSettings(*settings); err != nil {
+			return HostedNetwork{}, err
+		}
+	}
+	if action != nil {
+		if err := action(); err != nil {
+			return HostedNetwork{}, err
+		}
+	}
+	return s.HostedNetwork()
+}
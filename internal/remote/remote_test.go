@@ -0,0 +1,105 @@
+package remote
+
+import (
+	"errors"
+	"testing"
+
+	"wlanapi"
+	"wlanapi/simfile/simfiletest"
+)
+
+func TestKindOf(t *testing.T) {
+	intel := &wlanapi.Interface{}
+	for _, c := range []struct {
+		err  error
+		kind Kind
+	}{
+		{errors.New("driver failure"), Internal},
+		{invalid("invalid BSSID"), Invalid},
+		{wlanapi.ErrProfileNotFound, NotFound},
+		{wlanapi.ErrNotConnected, Conflict},
+		{wlanapi.ErrHostedNetworkState, Conflict},
+		{wlanapi.ErrBackendNotSupported, Unsupported},
+		{wlanapi.InterfaceErrors{{Interface: intel, Err: wlanapi.ErrNotConnected}, {Interface: intel, Err: wlanapi.ErrNotConnected}}, Conflict},
+		{wlanapi.InterfaceErrors{{Interface: intel, Err: wlanapi.ErrNotConnected}, {Interface: intel, Err: wlanapi.ErrProfileNotFound}}, Internal},
+	} {
+		if kind := KindOf(c.err); kind != c.kind {
+			t.Errorf("KindOf(%v) = %v, want %v", c.err, kind, c.kind)
+		}
+	}
+}
+
+func TestInterfaces(t *testing.T) {
+	s := New(simfiletest.Client(t))
+	interfaces, err := s.Interfaces("")
+	if err != nil || len(interfaces) != 2 || interfaces[0].Connection == nil || interfaces[1].Connection != nil {
+		t.Errorf("interfaces %+v, %v", interfaces, err)
+	}
+	if _, err := s.Interfaces("nothing"); KindOf(err) != NotFound {
+		t.Errorf("no interface: %v", err)
+	}
+	if _, err := s.Scan(simfiletest.Intel[1 : len(simfiletest.Intel)-1]); err != nil {
+		t.Errorf("scan with a GUID without braces: %v", err)
+	}
+	if _, err := s.Scan("{1234}"); KindOf(err) != Invalid {
+		t.Errorf("scan with an invalid GUID: %v", err)
+	}
+}
+
+func TestProfile(t *testing.T) {
+	s := New(simfiletest.Client(t))
+	profiles, err := s.Profile("", "corp", false)
+	if err != nil || len(profiles) != 1 || profiles[0].Interface.GUID.String() != simfiletest.Intel || profiles[0].XML == "" {
+		t.Errorf("profile %+v, %v", profiles, err)
+	}
+	if _, err := s.Profile("", "home", false); KindOf(err) != NotFound {
+		t.Errorf("missing profile: %v", err)
+	}
+	if _, err := s.SetProfile("", "<WLANProfile/>", false); KindOf(err) != Invalid {
+		t.Errorf("profile without a name: %v", err)
+	}
+	if err := s.DeleteProfile("", "home"); KindOf(err) != NotFound {
+		t.Errorf("delete a missing profile: %v", err)
+	}
+}
+
+func TestConnectRequest(t *testing.T) {
+	r := ConnectRequest{Profile: "corp", BSSIDs: []string{"00:1a:1e:00:00:02"}, BssType: "Independent"}
+	p, err := r.parameters()
+	if err != nil || len(p.BSSIDs) != 1 || p.BSSIDs[0][5] != 2 || p.BssType != 2 {
+		t.Errorf("parameters %+v, %v", p, err)
+	}
+	for _, r := range []ConnectRequest{
+		{},
+		{Profile: "corp", ProfileXML: "<WLANProfile/>"},
+		{Profile: "corp", BSSIDs: []string{"00:1a"}},
+		{Profile: "corp", BssType: "mesh"},
+	} {
+		if _, err := r.parameters(); KindOf(err) != Invalid {
+			t.Errorf("parameters of %+v: %v", r, err)
+		}
+	}
+}
+
+func TestSetHostedNetwork(t *testing.T) {
+	sim := simfiletest.Sim(t)
+	s := New(wlanapi.NewClientWithBackend(sim))
+	h, err := s.SetHostedNetwork(HostedNetworkRequest{MaxPeers: 4})
+	if err != nil || string(h.Settings.SSID) != "kiosk" || h.Settings.MaxPeers != 4 {
+		t.Errorf("set the maximum number of peers: %+v, %v", h.Settings, err)
+	}
+	h, err = s.SetHostedNetwork(HostedNetworkRequest{Action: "start", SSID: []byte("lobby")})
+	if err != nil || string(h.Settings.SSID) != "lobby" || h.Settings.MaxPeers != 4 {
+		t.Errorf("set the SSID and start: %+v, %v", h.Settings, err)
+	}
+	if _, err := s.SetHostedNetwork(HostedNetworkRequest{Action: "start"}); KindOf(err) != Conflict {
+		t.Errorf("start an active hosted network: %v", err)
+	}
+	if _, err := s.SetHostedNetwork(HostedNetworkRequest{Action: "restart"}); KindOf(err) != Invalid {
+		t.Errorf("unknown action: %v", err)
+	}
+	sim.SetHostedNetworkError(errors.New("driver failure"))
+	if _, err := s.SetHostedNetwork(HostedNetworkRequest{Action: "stop"}); KindOf(err) != Internal {
+		t.Errorf("failing hosted network: %v", err)
+	}
+}
@@ -0,0 +1,433 @@
+package wlanapi
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+
+	"wlanapi/binary"
+	"wlanapi/ie"
+)
+
+//Clock is the time source of a Monitor. Tests replace it to control the schedule.
+type Clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+func (systemClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+const (
+	//DefaultMonitorInterval is the time between the scans of a Monitor.
+	DefaultMonitorInterval = 30 * time.Second
+	//DefaultScanTimeout bounds the wait for the scan complete notification.
+	//Drivers must complete a scan within 4 seconds; the margin covers busy systems.
+	DefaultScanTimeout = 10 * time.Second
+	//DefaultMonitorHistory is the number of samples kept per BSSID.
+	DefaultMonitorHistory = 120
+	//DefaultRSSIDelta is the RSSI change in dB that raises an RSSIDelta event.
+	DefaultRSSIDelta = 10
+	//DefaultMonitorRetention is how long the history of a BSS is kept after it was last seen.
+	DefaultMonitorRetention = time.Hour
+)
+
+//MonitorConfig configures a Monitor. Zero values select the defaults.
+type MonitorConfig struct {
+	//Interval is the time between two scans; Jitter randomly shortens or lengthens each wait by up to its value.
+	Interval time.Duration
+	Jitter   time.Duration
+	//ScanTimeout bounds the wait for the scan complete or scan fail notification of each interface.
+	ScanTimeout time.Duration
+	//History is the number of samples kept per BSSID.
+	History int
+	//RSSIDelta is the change in dB, from the RSSI of the last event of a BSS, that raises an RSSIDelta event.
+	RSSIDelta int32
+	//MissedScans is the number of consecutive scans a BSS must be missing from before BSSDisappeared; at least 1.
+	MissedScans int
+	//Retention is how long the history of a disappeared BSS is kept after it was last seen. A BSS that comes
+	//back later starts a new history.
+	Retention time.Duration
+	//Selector chooses the monitored interfaces; nil monitors all of them.
+	Selector InterfaceSelector
+	Clock    Clock
+	//Rand is the source of the jitter.
+	Rand *rand.Rand
+	//OnError is called by Run with the errors of a round, which does not stop monitoring.
+	OnError func(error)
+}
+
+//MonitorEventType is the kind of change reported by a MonitorEvent.
+type MonitorEventType int
+
+const (
+	BSSAppeared MonitorEventType = iota
+	BSSDisappeared
+	ChannelChanged
+	SecurityChanged
+	RSSIDelta
+)
+
+func (t MonitorEventType) String() string {
+	switch t {
+	case BSSAppeared:
+		return "BSSAppeared"
+	case BSSDisappeared:
+		return "BSSDisappeared"
+	case ChannelChanged:
+		return "ChannelChanged"
+	case SecurityChanged:
+		return "SecurityChanged"
+	case RSSIDelta:
+		return "RSSIDelta"
+	}
+	return fmt.Sprintf("MonitorEventType(%d)", int(t))
+}
+
+//BSSState is what one scan saw of a BSS.
+type BSSState struct {
+	Time        time.Time
+	RSSI        int32
+	LinkQuality uint32
+	Channel     int
+	Band        Band
+	Privacy     bool
+	//SecurityIEs holds the RSN and WPA information elements, which list the security suites of the BSS.
+	SecurityIEs []byte
+}
+
+//MonitorEvent is a change of a BSS seen by an interface.
+//Old is nil for BSSAppeared and New is nil for BSSDisappeared.
+type MonitorEvent struct {
+	Type      MonitorEventType
+	Time      time.Time
+	Interface GUID
+	BSSID     [6]byte
+	SSID      []byte
+	Old, New  *BSSState
+}
+
+//BSSHistory is the history of a BSSID seen by an interface.
+type BSSHistory struct {
+	Interface GUID
+	BSSID     [6]byte
+	SSID      []byte
+	FirstSeen time.Time
+	LastSeen  time.Time
+	//Present is false once the BSS has disappeared.
+	Present bool
+	//Samples holds the latest states, oldest first.
+	Samples []BSSState
+
+	missed   int
+	baseline int32
+}
+
+type bssKey struct {
+	iface GUID
+	bssid [6]byte
+}
+
+//Monitor scans the interfaces of a client periodically and reports how the BSSes they see change.
+type Monitor struct {
+	client *Client
+	config MonitorConfig
+
+	mu        sync.Mutex
+	histories map[bssKey]*BSSHistory
+}
+
+//NewMonitor returns a Monitor of the interfaces of c.
+func NewMonitor(c *Client, config MonitorConfig) *Monitor {
+	if config.Interval <= 0 {
+		config.Interval = DefaultMonitorInterval
+	}
+	if config.ScanTimeout <= 0 {
+		config.ScanTimeout = DefaultScanTimeout
+	}
+	if config.History <= 0 {
+		config.History = DefaultMonitorHistory
+	}
+	if config.RSSIDelta <= 0 {
+		config.RSSIDelta = DefaultRSSIDelta
+	}
+	if config.MissedScans <= 0 {
+		config.MissedScans = 1
+	}
+	if config.Retention <= 0 {
+		config.Retention = DefaultMonitorRetention
+	}
+	if config.Clock == nil {
+		config.Clock = systemClock{}
+	}
+	if config.Rand == nil {
+		config.Rand = rand.New(rand.NewSource(time.Now().UnixNano()))
+	}
+	return &Monitor{client: c, config: config, histories: map[bssKey]*BSSHistory{}}
+}
+
+//Run polls until ctx is done and sends the events to ch, waiting Interval plus jitter between rounds.
+//It returns ctx.Err().
+func (m *Monitor) Run(ctx context.Context, ch chan<- MonitorEvent) error {
+	for {
+		events, err := m.Poll(ctx)
+		if err != nil && m.config.OnError != nil && ctx.Err() == nil {
+			m.config.OnError(err)
+		}
+		for _, e := range events {
+			select {
+			case ch <- e:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+		select {
+		case <-m.config.Clock.After(m.wait()):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+//wait returns the interval with a uniformly distributed jitter in [-Jitter, Jitter].
+func (m *Monitor) wait() time.Duration {
+	d := m.config.Interval
+	if m.config.Jitter > 0 {
+		d += time.Duration(m.config.Rand.Int63n(int64(2*m.config.Jitter)+1)) - m.config.Jitter
+	}
+	if d < 0 {
+		d = 0
+	}
+	return d
+}
+
+//Poll runs one round: it requests a scan on every monitored interface, waits for the scan complete
+//notifications, reads the BSS lists and returns the changes since the previous round.
+//Interfaces that fail are left out of the round and reported with InterfaceErrors.
+func (m *Monitor) Poll(ctx context.Context) ([]MonitorEvent, error) {
+	interfaces, err := m.client.SelectInterfaces(m.config.Selector)
+	if err != nil {
+		return nil, err
+	}
+	notifications, cancel, err := m.client.backend.Notifications()
+	if err != nil {
+		return nil, err
+	}
+	defer cancel()
+
+	var failed InterfaceErrors
+	collect := func(err error) {
+		if errs, ok := err.(InterfaceErrors); ok {
+			failed = append(failed, errs...)
+		}
+	}
+	scanned := make([]bool, len(interfaces))
+	err = m.client.forEach(ctx, interfaces, func(_ context.Context, n int, i *Interface) error {
+		if err := i.Scan(); err != nil {
+			return err
+		}
+		scanned[n] = true
+		return nil
+	})
+	collect(err)
+
+	pending := map[GUID]*Interface{}
+	for n, i := range interfaces {
+		if scanned[n] {
+			pending[i.GUID] = i
+		}
+	}
+	timeout := m.config.Clock.After(m.config.ScanTimeout)
+	for len(pending) > 0 {
+		select {
+		case n, ok := <-notifications:
+			if !ok {
+				pending = nil
+				break
+			}
+			i := pending[n.InterfaceGuid]
+			if i == nil || n.Source != WLAN_NOTIFICATION_SOURCE_ACM {
+				continue
+			}
+			switch WLAN_NOTIFICATION_ACM(n.Code) {
+			case WlanNotificationAcmScanComplete:
+				delete(pending, n.InterfaceGuid)
+			case WlanNotificationAcmScanFail:
+				//The BSS list still holds the results of earlier scans.
+				delete(pending, n.InterfaceGuid)
+				failed = append(failed, &InterfaceError{Interface: i, Err: scanFailure(n.Data)})
+			}
+		case <-timeout:
+			pending = nil
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	lists := make([][]binary.BSSEntry, len(interfaces))
+	listed := make([]bool, len(interfaces))
+	err = m.client.forEach(ctx, interfaces, func(_ context.Context, n int, i *Interface) (err error) {
+		lists[n], err = i.BSSList()
+		listed[n] = err == nil
+		return err
+	})
+	collect(err)
+
+	now := m.config.Clock.Now()
+	var events []MonitorEvent
+	m.mu.Lock()
+	for n, i := range interfaces {
+		if listed[n] {
+			events = append(events, m.update(now, i.GUID, lists[n])...)
+		}
+	}
+	m.mu.Unlock()
+	if len(failed) > 0 {
+		return events, failed
+	}
+	return events, nil
+}
+
+func scanFailure(data []byte) error {
+	if len(data) < 4 {
+		return fmt.Errorf("wlanapi: scan failed")
+	}
+	reason := WLAN_REASON_CODE(uint32(data[0]) | uint32(data[1])<<8 | uint32(data[2])<<16 | uint32(data[3])<<24)
+	return fmt.Errorf("wlanapi: scan failed: %v", reason)
+}
+
+//update records the BSS list of an interface and returns the events it raises.
+func (m *Monitor) update(now time.Time, iface GUID, entries []binary.BSSEntry) []MonitorEvent {
+	var events []MonitorEvent
+	seen := map[[6]byte]bool{}
+	for _, e := range entries {
+		if seen[e.BSSID] {
+			continue
+		}
+		seen[e.BSSID] = true
+		state := BSSState{
+			Time:        now,
+			RSSI:        e.RSSI,
+			LinkQuality: e.LinkQuality,
+			Channel:     ChannelOf(e.ChCenterFrequency),
+			Band:        BandOf(e.ChCenterFrequency),
+			Privacy:     e.CapabilityInformation&capabilityPrivacy != 0,
+			SecurityIEs: securityIEs(e.IEs),
+		}
+		event := func(t MonitorEventType, old *BSSState) {
+			s := state
+			events = append(events, MonitorEvent{Type: t, Time: now, Interface: iface, BSSID: e.BSSID, SSID: e.SSID, Old: old, New: &s})
+		}
+
+		key := bssKey{iface, e.BSSID}
+		h := m.histories[key]
+		switch {
+		case h == nil:
+			h = &BSSHistory{Interface: iface, BSSID: e.BSSID, FirstSeen: now}
+			m.histories[key] = h
+			fallthrough
+		case !h.Present:
+			h.Present = true
+			h.baseline = state.RSSI
+			event(BSSAppeared, nil)
+		default:
+			last := h.Samples[len(h.Samples)-1]
+			if last.Channel != state.Channel {
+				event(ChannelChanged, &last)
+			}
+			if last.Privacy != state.Privacy || !bytes.Equal(last.SecurityIEs, state.SecurityIEs) {
+				event(SecurityChanged, &last)
+			}
+			if delta := state.RSSI - h.baseline; delta >= m.config.RSSIDelta || -delta >= m.config.RSSIDelta {
+				h.baseline = state.RSSI
+				event(RSSIDelta, &last)
+			}
+		}
+		h.SSID = e.SSID
+		h.LastSeen = now
+		h.missed = 0
+		if len(h.Samples) == m.config.History {
+			copy(h.Samples, h.Samples[1:])
+			h.Samples = h.Samples[:len(h.Samples)-1]
+		}
+		h.Samples = append(h.Samples, state)
+	}
+
+	var gone []*BSSHistory
+	for key, h := range m.histories {
+		if key.iface != iface || seen[key.bssid] {
+			continue
+		}
+		if h.Present {
+			h.missed++
+			if h.missed >= m.config.MissedScans {
+				h.Present = false
+				gone = append(gone, h)
+			}
+		} else if now.Sub(h.LastSeen) > m.config.Retention {
+			delete(m.histories, key)
+		}
+	}
+	sort.Slice(gone, func(i, j int) bool { return bytes.Compare(gone[i].BSSID[:], gone[j].BSSID[:]) < 0 })
+	for _, h := range gone {
+		last := h.Samples[len(h.Samples)-1]
+		events = append(events, MonitorEvent{Type: BSSDisappeared, Time: now, Interface: iface, BSSID: h.BSSID, SSID: h.SSID, Old: &last})
+	}
+	return events
+}
+
+//History returns a copy of the history of a BSSID seen by an interface.
+func (m *Monitor) History(iface GUID, bssid [6]byte) (BSSHistory, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	h := m.histories[bssKey{iface, bssid}]
+	if h == nil {
+		return BSSHistory{}, false
+	}
+	c := *h
+	c.Samples = append([]BSSState(nil), h.Samples...)
+	return c, true
+}
+
+//Histories returns a copy of every history, ordered by interface and BSSID.
+func (m *Monitor) Histories() []BSSHistory {
+	m.mu.Lock()
+	histories := make([]BSSHistory, 0, len(m.histories))
+	for _, h := range m.histories {
+		c := *h
+		c.Samples = append([]BSSState(nil), h.Samples...)
+		histories = append(histories, c)
+	}
+	m.mu.Unlock()
+	sort.Slice(histories, func(i, j int) bool {
+		a, b := histories[i].Interface.String(), histories[j].Interface.String()
+		if a != b {
+			return a < b
+		}
+		return bytes.Compare(histories[i].BSSID[:], histories[j].BSSID[:]) < 0
+	})
+	return histories
+}
+
+//securityIEs returns the RSN element and the WPA vendor element (OUI 00:50:f2, type 1) of ies.
+func securityIEs(ies []byte) []byte {
+	es, _ := ie.Parse(ies)
+	var security []byte
+	for _, e := range es {
+		if oui, t, ok := e.Vendor(); e.ID == ie.RSN || ok && oui == ie.OUIMicrosoft && t == 1 {
+			security = append(security, e.Bytes()...)
+		}
+	}
+	return security
+}
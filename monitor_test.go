@@ -0,0 +1,332 @@
+package wlanapi
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"wlanapi/binary"
+)
+
+//fakeClock fires the channels returned by After when the test advances it.
+type fakeClock struct {
+	mu     sync.Mutex
+	now    time.Time
+	timers []fakeTimer
+	//calls receives the duration of every After call.
+	calls chan time.Duration
+}
+
+type fakeTimer struct {
+	at time.Time
+	ch chan time.Time
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), calls: make(chan time.Duration, 100)}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	ch := make(chan time.Time, 1)
+	c.timers = append(c.timers, fakeTimer{c.now.Add(d), ch})
+	c.mu.Unlock()
+	c.calls <- d
+	return ch
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+	timers := c.timers[:0]
+	for _, t := range c.timers {
+		if t.at.After(c.now) {
+			timers = append(timers, t)
+		} else {
+			t.ch <- c.now
+		}
+	}
+	c.timers = timers
+}
+
+//waitAfter waits for an After call of duration d.
+func (c *fakeClock) waitAfter(t *testing.T, d time.Duration) {
+	t.Helper()
+	for {
+		select {
+		case got := <-c.calls:
+			if got == d {
+				return
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("no After(%v)", d)
+		}
+	}
+}
+
+var rsnPSK = []byte{48, 20, 1, 0, 0, 0x0f, 0xac, 4, 1, 0, 0, 0x0f, 0xac, 4, 1, 0, 0, 0x0f, 0xac, 2, 0, 0}
+var rsnSAE = []byte{48, 20, 1, 0, 0, 0x0f, 0xac, 4, 1, 0, 0, 0x0f, 0xac, 4, 1, 0, 0, 0x0f, 0xac, 8, 0xc0, 0}
+
+func monitorBSS(id byte, khz uint32, rssi int32, rsn []byte) binary.BSSEntry {
+	ies := append([]byte{0, 3, 'l', 'a', 'b'}, rsn...)
+	return binary.BSSEntry{SSID: []byte("lab"), BSSID: [6]byte{2, 0, 0, 0, 0, id}, BssType: 1, RSSI: rssi,
+		LinkQuality: uint32(2 * (rssi + 100)), ChCenterFrequency: khz, CapabilityInformation: 0x0011, IEs: ies}
+}
+
+func newMonitorSim() (*Sim, GUID) {
+	sim := NewSim()
+	sim.AddInterface(binary.InterfaceInfo{InterfaceGuid: simGUID(1), Description: "Intel(R) Wi-Fi 6 AX201", State: 1})
+	return sim, GUID(simGUID(1))
+}
+
+func eventTypes(events []MonitorEvent) []MonitorEventType {
+	types := make([]MonitorEventType, len(events))
+	for n, e := range events {
+		types[n] = e.Type
+	}
+	return types
+}
+
+func expectEvents(t *testing.T, events []MonitorEvent, want ...MonitorEventType) {
+	t.Helper()
+	got := eventTypes(events)
+	if len(got) != len(want) {
+		t.Fatalf("events %v, want %v", got, want)
+	}
+	for n := range want {
+		if got[n] != want[n] {
+			t.Fatalf("events %v, want %v", got, want)
+		}
+	}
+}
+
+func TestMonitorPoll(t *testing.T) {
+	sim, iface := newMonitorSim()
+	clock := newFakeClock()
+	m := NewMonitor(NewClientWithBackend(sim), MonitorConfig{Clock: clock, History: 3, MissedScans: 2})
+	ctx := context.Background()
+
+	sim.SetBSSList(iface, []binary.BSSEntry{monitorBSS(1, 2437000, -50, rsnPSK), monitorBSS(2, 5180000, -60, rsnPSK)})
+	events, err := m.Poll(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectEvents(t, events, BSSAppeared, BSSAppeared)
+	if e := events[0]; e.Old != nil || e.New.Channel != 6 || e.New.Band != Band2_4GHz || !e.New.Privacy ||
+		string(e.New.SecurityIEs) != string(rsnPSK) || e.Interface != iface || string(e.SSID) != "lab" {
+		t.Errorf("appeared: %+v %+v", e, e.New)
+	}
+
+	//The first BSS moves to channel 11, the second gets 9 dB weaker and a third appears.
+	clock.Advance(time.Minute)
+	sim.SetBSSList(iface, []binary.BSSEntry{monitorBSS(1, 2462000, -50, rsnPSK), monitorBSS(2, 5180000, -69, rsnPSK),
+		monitorBSS(3, 5955000, -70, rsnSAE)})
+	events, _ = m.Poll(ctx)
+	expectEvents(t, events, ChannelChanged, BSSAppeared)
+	if e := events[0]; e.Old.Channel != 6 || e.New.Channel != 11 {
+		t.Errorf("channel change %d -> %d", e.Old.Channel, e.New.Channel)
+	}
+
+	//The deltas add up from the RSSI of the last event, not of the last scan.
+	clock.Advance(time.Minute)
+	sim.SetBSSList(iface, []binary.BSSEntry{monitorBSS(1, 2462000, -50, rsnPSK), monitorBSS(2, 5180000, -71, rsnSAE),
+		monitorBSS(3, 5955000, -70, rsnSAE)})
+	events, _ = m.Poll(ctx)
+	expectEvents(t, events, SecurityChanged, RSSIDelta)
+	if e := events[1]; e.Old.RSSI != -69 || e.New.RSSI != -71 {
+		t.Errorf("RSSI delta %d -> %d", e.Old.RSSI, e.New.RSSI)
+	}
+
+	//A BSS disappears after two missed scans.
+	sim.SetBSSList(iface, []binary.BSSEntry{monitorBSS(2, 5180000, -71, rsnSAE), monitorBSS(3, 5955000, -70, rsnSAE)})
+	clock.Advance(time.Minute)
+	events, _ = m.Poll(ctx)
+	expectEvents(t, events)
+	clock.Advance(time.Minute)
+	events, _ = m.Poll(ctx)
+	expectEvents(t, events, BSSDisappeared)
+	if e := events[0]; e.BSSID[5] != 1 || e.New != nil || e.Old.Channel != 11 {
+		t.Errorf("disappeared: %+v", e)
+	}
+
+	h, ok := m.History(iface, [6]byte{2, 0, 0, 0, 0, 1})
+	if !ok || h.Present || len(h.Samples) != 3 {
+		t.Fatalf("history of the first BSS: %+v", h)
+	}
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	if !h.FirstSeen.Equal(start) || !h.LastSeen.Equal(start.Add(2*time.Minute)) || !h.Samples[0].Time.Equal(start) {
+		t.Errorf("first seen %v, last seen %v, oldest sample %v", h.FirstSeen, h.LastSeen, h.Samples[0].Time)
+	}
+	h, _ = m.History(iface, [6]byte{2, 0, 0, 0, 0, 2})
+	if len(h.Samples) != 3 || h.Samples[0].Time != start.Add(2*time.Minute) || h.Samples[2].RSSI != -71 {
+		t.Errorf("history of the second BSS is not bounded: %+v", h.Samples)
+	}
+	if n := len(m.Histories()); n != 3 {
+		t.Errorf("%d histories, want 3", n)
+	}
+
+	//A BSS that comes back appears again.
+	sim.SetBSSList(iface, []binary.BSSEntry{monitorBSS(1, 2462000, -50, rsnPSK), monitorBSS(2, 5180000, -71, rsnSAE),
+		monitorBSS(3, 5955000, -70, rsnSAE)})
+	events, _ = m.Poll(ctx)
+	expectEvents(t, events, BSSAppeared)
+}
+
+func TestMonitorRetention(t *testing.T) {
+	sim, iface := newMonitorSim()
+	clock := newFakeClock()
+	m := NewMonitor(NewClientWithBackend(sim), MonitorConfig{Clock: clock, Retention: 5 * time.Minute})
+	ctx := context.Background()
+
+	sim.SetBSSList(iface, []binary.BSSEntry{monitorBSS(1, 2437000, -50, rsnPSK), monitorBSS(2, 5180000, -60, rsnPSK)})
+	m.Poll(ctx)
+	sim.SetBSSList(iface, []binary.BSSEntry{monitorBSS(2, 5180000, -60, rsnPSK)})
+	for n := 0; n < 5; n++ {
+		clock.Advance(time.Minute)
+		m.Poll(ctx)
+	}
+	if h, ok := m.History(iface, [6]byte{2, 0, 0, 0, 0, 1}); !ok || h.Present {
+		t.Fatalf("history of a BSS gone for 5 minutes: %+v, %v", h, ok)
+	}
+	clock.Advance(time.Minute)
+	m.Poll(ctx)
+	if _, ok := m.History(iface, [6]byte{2, 0, 0, 0, 0, 1}); ok {
+		t.Error("kept the history of a BSS gone for longer than the retention")
+	}
+	if _, ok := m.History(iface, [6]byte{2, 0, 0, 0, 0, 2}); !ok || len(m.Histories()) != 1 {
+		t.Errorf("histories %+v", m.Histories())
+	}
+}
+
+func TestMonitorScanFailure(t *testing.T) {
+	sim, iface := newMonitorSim()
+	sim.AddInterface(binary.InterfaceInfo{InterfaceGuid: simGUID(2), Description: "Realtek", State: 1})
+	sim.SetBSSList(iface, []binary.BSSEntry{monitorBSS(1, 2437000, -50, nil)})
+	sim.SetBSSList(GUID(simGUID(2)), []binary.BSSEntry{monitorBSS(1, 2437000, -50, nil)})
+	sim.SetScanFailure(iface, WLAN_REASON_CODE_UNKNOWN)
+	m := NewMonitor(NewClientWithBackend(sim), MonitorConfig{Clock: newFakeClock()})
+
+	//The failed scan is reported but the cached BSS list is still read.
+	events, err := m.Poll(context.Background())
+	var errs InterfaceErrors
+	if !errors.As(err, &errs) || len(errs) != 1 || errs[0].Interface.GUID != iface {
+		t.Fatalf("error %v", err)
+	}
+	expectEvents(t, events, BSSAppeared, BSSAppeared)
+
+	//An interface whose list cannot be read keeps its BSSes.
+	sim.SetScanFailure(iface, WLAN_REASON_CODE_SUCCESS)
+	sim.SetError(GUID(simGUID(2)), errors.New("device removed"))
+	events, err = m.Poll(context.Background())
+	if !errors.As(err, &errs) || len(errs) != 2 || errs[0].Interface.GUID != GUID(simGUID(2)) {
+		t.Fatalf("error %v", err)
+	}
+	expectEvents(t, events)
+}
+
+//quietSim does not notify the end of scans.
+type quietSim struct {
+	*Sim
+	scanned chan struct{}
+	mu      sync.Mutex
+	listed  bool
+}
+
+func (s *quietSim) Scan(iface GUID) error {
+	s.scanned <- struct{}{}
+	return nil
+}
+
+func (s *quietSim) BSSList(iface GUID) ([]binary.BSSEntry, error) {
+	s.mu.Lock()
+	s.listed = true
+	s.mu.Unlock()
+	return s.Sim.BSSList(iface)
+}
+
+func (s *quietSim) wasListed() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.listed
+}
+
+func TestMonitorWaitsForScan(t *testing.T) {
+	sim, iface := newMonitorSim()
+	sim.SetBSSList(iface, []binary.BSSEntry{monitorBSS(1, 2437000, -50, nil)})
+	quiet := &quietSim{Sim: sim, scanned: make(chan struct{}, 1)}
+	clock := newFakeClock()
+	m := NewMonitor(NewClientWithBackend(quiet), MonitorConfig{Clock: clock, ScanTimeout: 5 * time.Second})
+
+	poll := func() chan []MonitorEvent {
+		done := make(chan []MonitorEvent, 1)
+		go func() {
+			events, _ := m.Poll(context.Background())
+			done <- events
+		}()
+		<-quiet.scanned
+		clock.waitAfter(t, 5*time.Second)
+		return done
+	}
+
+	done := poll()
+	if quiet.wasListed() {
+		t.Fatal("BSS list read before the scan completed")
+	}
+	sim.Notify(Notification{Source: WLAN_NOTIFICATION_SOURCE_ACM, Code: uint32(WlanNotificationAcmScanComplete), InterfaceGuid: iface})
+	expectEvents(t, <-done, BSSAppeared)
+
+	//Without a notification the round goes on after the scan timeout.
+	sim.SetBSSList(iface, nil)
+	done = poll()
+	clock.Advance(5 * time.Second)
+	expectEvents(t, <-done, BSSDisappeared)
+}
+
+func TestMonitorRun(t *testing.T) {
+	sim, iface := newMonitorSim()
+	sim.SetBSSList(iface, []binary.BSSEntry{monitorBSS(1, 2437000, -50, nil)})
+	clock := newFakeClock()
+	m := NewMonitor(NewClientWithBackend(sim), MonitorConfig{Clock: clock, Interval: time.Minute, Jitter: 10 * time.Second})
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := make(chan MonitorEvent)
+	done := make(chan error, 1)
+	go func() { done <- m.Run(ctx, ch) }()
+
+	if e := <-ch; e.Type != BSSAppeared {
+		t.Fatalf("first event %v", e.Type)
+	}
+	//Wait for the wait between rounds, which is longer than the scan timeout.
+	var wait time.Duration
+	for wait = range clock.calls {
+		if wait != DefaultScanTimeout {
+			break
+		}
+	}
+	if wait < 50*time.Second || wait > 70*time.Second {
+		t.Fatalf("wait %v, want a minute with 10s jitter", wait)
+	}
+	if n := sim.Scans(iface); n != 1 {
+		t.Fatalf("%d scans before the interval, want 1", n)
+	}
+	sim.SetBSSList(iface, nil)
+	clock.Advance(wait)
+	if e := <-ch; e.Type != BSSDisappeared {
+		t.Fatalf("second event %v", e.Type)
+	}
+	if n := sim.Scans(iface); n != 2 {
+		t.Fatalf("%d scans after the interval, want 2", n)
+	}
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Fatalf("Run returned %v", err)
+	}
+}
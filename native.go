@@ -0,0 +1,233 @@
+//go:build windows
+// +build windows
+
+package wlanapi
+
+import (
+	"fmt"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+
+	"wlanapi/binary"
+)
+
+//nativeBackend is the Backend of a client opened by NewClient.
+type nativeBackend struct {
+	handle windows.Handle
+	client *Client
+}
+
+func (b *nativeBackend) Interfaces() ([]binary.InterfaceInfo, error) {
+	iil, err := WlanEnumInterfaces(b.handle)
+	if err != nil {
+		return nil, err
+	}
+	defer WlanFreeMemory(PVOID(unsafe.Pointer(iil)))
+	return iil.Decode()
+}
+
+func (b *nativeBackend) Scan(iface GUID) error {
+	return WlanScan(b.handle, &iface, nil, nil)
+}
+
+func (b *nativeBackend) AvailableNetworks(iface GUID) ([]binary.AvailableNetwork, error) {
+	list, err := WlanGetAvailableNetworkList(b.handle, &iface, 0)
+	if err != nil {
+		return nil, err
+	}
+	defer WlanFreeMemory(PVOID(unsafe.Pointer(list)))
+	return list.Decode()
+}
+
+func (b *nativeBackend) BSSList(iface GUID) ([]binary.BSSEntry, error) {
+	list, err := WlanGetNetworkBssList(b.handle, &iface, nil, Dot11BssTypeAny, FALSE)
+	if err != nil {
+		return nil, err
+	}
+	defer WlanFreeMemory(PVOID(unsafe.Pointer(list)))
+	return list.Decode()
+}
+
+func (b *nativeBackend) Notifications() (<-chan Notification, func(), error) {
+	return b.client.Subscribe(WLAN_NOTIFICATION_SOURCE_ALL)
+}
+
+func (b *nativeBackend) Close() error {
+	return WlanCloseHandle(b.handle)
+}
+
+func (b *nativeBackend) Profiles(iface GUID) ([]binary.ProfileInfo, error) {
+	list, err := WlanGetProfileList(b.handle, &iface)
+	if err != nil {
+		return nil, err
+	}
+	defer WlanFreeMemory(PVOID(unsafe.Pointer(list)))
+	return list.Decode()
+}
+
+func (b *nativeBackend) Profile(iface GUID, name string, plaintextKey bool) (string, error) {
+	var flags DWORD
+	if plaintextKey {
+		flags = WLAN_PROFILE_GET_PLAINTEXT_KEY
+	}
+	xml, _, err := WlanGetProfile(b.handle, &iface, name, &flags)
+	if err == windows.ERROR_NOT_FOUND {
+		return "", ErrProfileNotFound
+	}
+	return xml, err
+}
+
+func (b *nativeBackend) SetProfile(iface GUID, xml string, overwrite bool) error {
+	reason, err := WlanSetProfile(b.handle, &iface, 0, xml, "", boolOf(overwrite))
+	if err == windows.ERROR_BAD_PROFILE && reason != nil {
+		return fmt.Errorf("wlanapi: bad profile: %v", WLAN_REASON_CODE(*reason))
+	}
+	return err
+}
+
+func (b *nativeBackend) DeleteProfile(iface GUID, name string) error {
+	err := WlanDeleteProfile(b.handle, &iface, name)
+	if err == windows.ERROR_NOT_FOUND {
+		return ErrProfileNotFound
+	}
+	return err
+}
+
+func (b *nativeBackend) Connect(iface GUID, p ConnectionParameters) error {
+	params := WLAN_CONNECTION_PARAMETERS{wlanConnectionMode: p.mode(), dot11BssType: p.bssType()}
+	profile := p.Profile
+	if p.ProfileXML != "" {
+		profile = p.ProfileXML
+	}
+	var err error
+	if params.strProfile, err = windows.UTF16PtrFromString(profile); err != nil {
+		return err
+	}
+	if len(p.SSID) > 0 {
+		if len(p.SSID) > 32 {
+			return fmt.Errorf("wlanapi: SSID %q is longer than 32 bytes", p.SSID)
+		}
+		params.pDot11Ssid = &DOT11_SSID{uSSIDLength: ULONG(len(p.SSID))}
+		copy(params.pDot11Ssid.ucSSID[:], p.SSID)
+	}
+	if len(p.BSSIDs) > 0 {
+		params.pDesiredBssidList = newBSSIDList(p.BSSIDs)
+	}
+	return WlanConnect(b.handle, &params, &iface)
+}
+
+//newBSSIDList returns a DOT11_BSSID_LIST holding bssids.
+func newBSSIDList(bssids [][6]byte) *DOT11_BSSID_LIST {
+	size := unsafe.Sizeof(DOT11_BSSID_LIST{}) + uintptr(len(bssids)-1)*unsafe.Sizeof(DOT11_MAC_ADDRESS{})
+	buf := make([]uint64, (size+7)/8)
+	l := (*DOT11_BSSID_LIST)(unsafe.Pointer(&buf[0]))
+	l.Header = NDIS_OBJECT_HEADER{
+		Type:     NDIS_OBJECT_TYPE_DEFAULT,
+		Revision: DOT11_BSSID_LIST_REVISION_1,
+		Size:     USHORT(unsafe.Sizeof(DOT11_BSSID_LIST{})),
+	}
+	l.uNumOfEntries = ULONG(len(bssids))
+	l.uTotalNumOfEntries = ULONG(len(bssids))
+	entries := unsafe.Slice(&l.BSSIDs[0], len(bssids))
+	for n, bssid := range bssids {
+		for k, b := range bssid {
+			entries[n][k] = UCHAR(b)
+		}
+	}
+	return l
+}
+
+func (b *nativeBackend) Disconnect(iface GUID) error {
+	return WlanDisconnect(b.handle, &iface)
+}
+
+func (b *nativeBackend) Connection(iface GUID) (*binary.ConnectionAttributes, error) {
+	size, data, _, err := WlanQueryInterface(b.handle, &iface, WlanIntfOpcodeCurrentConnection)
+	if err == windows.ERROR_INVALID_STATE {
+		return nil, ErrNotConnected
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer WlanFreeMemory(PVOID(unsafe.Pointer(data)))
+	return binary.DecodeConnectionAttributes(binary.NativeABI(), nativeBytes(unsafe.Pointer(data), int(*size)))
+}
+
+func (b *nativeBackend) Statistics(iface GUID) (*binary.Statistics, error) {
+	size, data, _, err := WlanQueryInterface(b.handle, &iface, WlanIntfOpcodeStatistics)
+	if err == windows.ERROR_INVALID_STATE {
+		return nil, ErrNotConnected
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer WlanFreeMemory(PVOID(unsafe.Pointer(data)))
+	return binary.DecodeStatistics(binary.NativeABI(), nativeBytes(unsafe.Pointer(data), int(*size)))
+}
+
+func (b *nativeBackend) HostedNetworkStatus() (*binary.HostedNetworkStatus, error) {
+	status, err := WlanHostedNetworkQueryStatus(b.handle)
+	if err != nil {
+		return nil, err
+	}
+	defer WlanFreeMemory(PVOID(unsafe.Pointer(status)))
+	return status.Decode()
+}
+
+func (b *nativeBackend) HostedNetworkSettings() (*HostedNetworkSettings, error) {
+	_, data, _, err := WlanHostedNetworkQueryProperty(b.handle, wlan_hosted_network_opcode_connection_settings)
+	if err != nil {
+		return nil, err
+	}
+	defer WlanFreeMemory(PVOID(unsafe.Pointer(data)))
+	cs := (*WLAN_HOSTED_NETWORK_CONNECTION_SETTINGS)(unsafe.Pointer(data))
+	n := cs.hostedNetworkSSID.uSSIDLength
+	if n > 32 {
+		n = 32
+	}
+	return &HostedNetworkSettings{
+		SSID:     append([]byte(nil), cs.hostedNetworkSSID.ucSSID[:n]...),
+		MaxPeers: uint32(cs.dwMaxNumberOfPeers),
+	}, nil
+}
+
+func (b *nativeBackend) SetHostedNetworkSettings(s HostedNetworkSettings) error {
+	if len(s.SSID) == 0 || len(s.SSID) > 32 {
+		return fmt.Errorf("wlanapi: invalid hosted network SSID %q", s.SSID)
+	}
+	cs := WLAN_HOSTED_NETWORK_CONNECTION_SETTINGS{dwMaxNumberOfPeers: DWORD(s.MaxPeers)}
+	cs.hostedNetworkSSID.uSSIDLength = ULONG(len(s.SSID))
+	copy(cs.hostedNetworkSSID.ucSSID[:], s.SSID)
+	reason, err := WlanHostedNetworkSetProperty(b.handle, wlan_hosted_network_opcode_connection_settings,
+		DWORD(unsafe.Sizeof(cs)), PVOID(unsafe.Pointer(&cs)))
+	return hostedNetworkError(err, *reason)
+}
+
+func (b *nativeBackend) StartHostedNetwork() error {
+	reason, err := WlanHostedNetworkStartUsing(b.handle)
+	return hostedNetworkError(err, reason)
+}
+
+func (b *nativeBackend) StopHostedNetwork() error {
+	reason, err := WlanHostedNetworkStopUsing(b.handle)
+	return hostedNetworkError(err, reason)
+}
+
+//hostedNetworkError adds the reason of a failed Hosted Network call to its error.
+func hostedNetworkError(err error, reason WLAN_HOSTED_NETWORK_REASON) error {
+	if err != nil && reason == wlan_hosted_network_reason_stop_before_start {
+		return fmt.Errorf("%w: %v", ErrHostedNetworkState, err)
+	}
+	if err != nil && reason != wlan_hosted_network_reason_success {
+		return fmt.Errorf("wlanapi: hosted network: %v (reason %d)", err, uint32(reason))
+	}
+	return err
+}
+
+func boolOf(b bool) BOOL {
+	if b {
+		return TRUE
+	}
+	return FALSE
+}
@@ -0,0 +1,181 @@
+package netsh
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+
+	"wlanapi"
+	"wlanapi/binary"
+)
+
+//printer writes the lines of a text, keeping the first error.
+type printer struct {
+	w   *bufio.Writer
+	err error
+}
+
+func newPrinter(w io.Writer) *printer {
+	return &printer{w: bufio.NewWriter(w)}
+}
+
+func (p *printer) line(format string, args ...interface{}) {
+	if p.err == nil {
+		_, p.err = fmt.Fprintf(p.w, format+"\n", args...)
+	}
+}
+
+//field writes a field whose label is padded to width and indented by indent.
+func (p *printer) field(indent, width int, label, value string) {
+	p.line("%s%-*s: %s", strings.Repeat(" ", indent), width, label, value)
+}
+
+func (p *printer) flush() error {
+	if p.err != nil {
+		return p.err
+	}
+	return p.w.Flush()
+}
+
+//count returns the header of a list of n items.
+func count(one, many string, n int) string {
+	if n == 1 {
+		return fmt.Sprintf(one, n)
+	}
+	return fmt.Sprintf(many, n)
+}
+
+func mac(addr [6]byte) string {
+	return net.HardwareAddr(addr[:]).String()
+}
+
+//mbps formats a rate in kbps as netsh does, without trailing zeros.
+func mbps(kbps uint32) string {
+	return strconv.FormatFloat(float64(kbps)/1000, 'f', -1, 64)
+}
+
+//WriteInterfaces writes interfaces as "netsh wlan show interfaces" prints them with the labels l.
+//Lines end with a line feed rather than the carriage return and line feed of netsh.
+func WriteInterfaces(w io.Writer, interfaces *Interfaces, l *Labels) error {
+	p := newPrinter(w)
+	const width = 23
+	f := func(label, value string) { p.field(4, width, label, value) }
+	p.line("")
+	p.line("%s", count(l.InterfaceCount, l.InterfacesCount, len(interfaces.Interfaces)))
+	for _, i := range interfaces.Interfaces {
+		p.line("")
+		f(l.Name, i.Name)
+		f(l.Description, i.Interface.Description)
+		f(l.GUID, strings.ToLower(strings.Trim(i.Interface.GUID.String(), "{}")))
+		f(l.PhysicalAddress, mac(i.PhysicalAddress))
+		f(l.State, text(l.States, i.Interface.State.String()))
+		c := i.Connection
+		if c == nil {
+			continue
+		}
+		f(l.SSID, string(c.SSID))
+		f(l.BSSID, mac(c.BSSID))
+		f(l.NetworkType, text(l.NetworkTypes, bssTypeName(c.BssType)))
+		f(l.RadioType, text(l.RadioTypes, phyName(c.PhyType)))
+		f(l.Authentication, text(l.Authentications, authName(c.AuthAlgorithm)))
+		f(l.Cipher, text(l.Ciphers, cipherName(c.CipherAlgorithm)))
+		f(l.ConnectionMode, text(l.ConnectionModes, modeName(c.Mode)))
+		if i.Band != 0 {
+			f(l.Band, text(l.Bands, i.Band.String()))
+		}
+		if i.Channel != 0 {
+			f(l.Channel, strconv.Itoa(i.Channel))
+		}
+		f(l.ReceiveRate, mbps(c.RxRate))
+		f(l.TransmitRate, mbps(c.TxRate))
+		f(l.Signal, fmt.Sprintf("%d%%", c.SignalQuality))
+		f(l.Profile, c.ProfileName)
+	}
+	if interfaces.HostedNetworkStatus != "" {
+		p.line("")
+		f(l.HostedNetworkStatus, interfaces.HostedNetworkStatus)
+	}
+	p.line("")
+	return p.flush()
+}
+
+//rates formats the basic or other rates of a BSS in Mbps.
+func rates(rates []uint16, basic bool) string {
+	var s []string
+	for _, r := range rates {
+		if (r&0x8000 != 0) == basic {
+			s = append(s, strconv.FormatFloat(float64(r&0x7fff)/2, 'f', -1, 64))
+		}
+	}
+	return strings.Join(s, " ")
+}
+
+//WriteNetworks writes network lists as "netsh wlan show networks mode=bssid" prints them with the labels l.
+func WriteNetworks(w io.Writer, lists []InterfaceNetworks, l *Labels) error {
+	p := newPrinter(w)
+	const networkWidth, bssWidth = 24, 19
+	for _, list := range lists {
+		p.line("")
+		p.line("%s : %s", l.InterfaceName, list.InterfaceName)
+		p.line("%s", count(l.NetworkCount, l.NetworksCount, len(list.Networks)))
+		for k, n := range list.Networks {
+			f := func(label, value string) { p.field(4, networkWidth, label, value) }
+			p.line("")
+			p.line("%s %d : %s", l.SSID, k+1, n.SSID)
+			f(l.NetworkType, text(l.NetworkTypes, n.BssType.String()))
+			f(l.Authentication, text(l.Authentications, n.AuthAlgorithm.String()))
+			f(l.Encryption, text(l.Ciphers, n.CipherAlgorithm.String()))
+			for j, b := range n.BSSes {
+				f(fmt.Sprintf("%s %d", l.BSSID, j+1), mac(b.BSSID))
+				f := func(label, value string) { p.field(9, bssWidth, label, value) }
+				f(l.Signal, fmt.Sprintf("%d%%", b.LinkQuality))
+				f(l.RadioType, text(l.RadioTypes, phyName(b.PhyType)))
+				if b.Band != 0 {
+					f(l.Band, text(l.Bands, b.Band.String()))
+				}
+				f(l.Channel, strconv.Itoa(b.Channel))
+				f(l.BasicRates, rates(b.Rates, true))
+				f(l.OtherRates, rates(b.Rates, false))
+			}
+		}
+		p.line("")
+	}
+	return p.flush()
+}
+
+//WriteProfiles writes profile lists as "netsh wlan show profiles" prints them with the labels l.
+func WriteProfiles(w io.Writer, lists []InterfaceProfiles, l *Labels) error {
+	p := newPrinter(w)
+	const width = 21
+	section := func(title string, user bool, profiles []binary.ProfileInfo) {
+		p.line("")
+		p.line("%s", title)
+		p.line("%s", strings.Repeat("-", len([]rune(title))))
+		none := true
+		for _, profile := range profiles {
+			if (profile.Flags&wlanapi.WLAN_PROFILE_GROUP_POLICY == 0) != user {
+				continue
+			}
+			label := l.AllUserProfile
+			if profile.Flags&wlanapi.WLAN_PROFILE_USER != 0 {
+				label = l.CurrentUserProfile
+			}
+			p.field(4, width, label, profile.Name)
+			none = false
+		}
+		if none {
+			p.line("    %s", l.None)
+		}
+	}
+	for _, list := range lists {
+		p.line("")
+		p.line("%s %s:", l.ProfilesOnInterface, list.InterfaceName)
+		section(l.GroupPolicyProfiles, false, list.Profiles)
+		section(l.UserProfiles, true, list.Profiles)
+		p.line("")
+	}
+	return p.flush()
+}
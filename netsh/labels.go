@@ -0,0 +1,137 @@
+package netsh
+
+import "strings"
+
+//Labels are the texts of a netsh locale: the labels of the fields, the section headers, and the texts of values.
+//The value maps translate the String of a value, such as "connected" for an interface state or "WPA2-Personal"
+//for an authentication algorithm, into the text netsh prints; values missing from a map print as their String.
+type Labels struct {
+	//Fields of "show interfaces".
+	Name                string
+	Description         string
+	GUID                string
+	PhysicalAddress     string
+	State               string
+	SSID                string
+	BSSID               string
+	NetworkType         string
+	RadioType           string
+	Authentication      string
+	Cipher              string
+	ConnectionMode      string
+	Band                string
+	Channel             string
+	ReceiveRate         string
+	TransmitRate        string
+	Signal              string
+	Profile             string
+	HostedNetworkStatus string
+	//InterfaceCount and InterfacesCount format the header of "show interfaces" for one and several interfaces.
+	InterfaceCount, InterfacesCount string
+
+	//Fields of "show networks mode=bssid"; the SSID and BSSID of each network and BSS are numbered.
+	InterfaceName string
+	Encryption    string
+	BasicRates    string
+	OtherRates    string
+	//NetworkCount and NetworksCount format the header of a network list for one and several networks.
+	NetworkCount, NetworksCount string
+
+	//Sections and entries of "show profiles".
+	ProfilesOnInterface string
+	GroupPolicyProfiles string
+	UserProfiles        string
+	AllUserProfile      string
+	CurrentUserProfile  string
+	None                string
+
+	//Aliases maps other labels of a field, as printed by other versions of netsh, to the label of the field.
+	Aliases map[string]string
+
+	States          map[string]string
+	NetworkTypes    map[string]string
+	Authentications map[string]string
+	Ciphers         map[string]string
+	RadioTypes      map[string]string
+	ConnectionModes map[string]string
+	Bands           map[string]string
+}
+
+//English are the labels of netsh in the English locale.
+var English = &Labels{
+	Name:                "Name",
+	Description:         "Description",
+	GUID:                "GUID",
+	PhysicalAddress:     "Physical address",
+	State:               "State",
+	SSID:                "SSID",
+	BSSID:               "BSSID",
+	NetworkType:         "Network type",
+	RadioType:           "Radio type",
+	Authentication:      "Authentication",
+	Cipher:              "Cipher",
+	ConnectionMode:      "Connection mode",
+	Band:                "Band",
+	Channel:             "Channel",
+	ReceiveRate:         "Receive rate (Mbps)",
+	TransmitRate:        "Transmit rate (Mbps)",
+	Signal:              "Signal",
+	Profile:             "Profile",
+	HostedNetworkStatus: "Hosted network status",
+	InterfaceCount:      "There is %d interface on the system:",
+	InterfacesCount:     "There are %d interfaces on the system:",
+
+	InterfaceName: "Interface name",
+	Encryption:    "Encryption",
+	BasicRates:    "Basic rates (Mbps)",
+	OtherRates:    "Other rates (Mbps)",
+	NetworkCount:  "There is %d network currently visible.",
+	NetworksCount: "There are %d networks currently visible.",
+
+	ProfilesOnInterface: "Profiles on interface",
+	GroupPolicyProfiles: "Group policy profiles (read only)",
+	UserProfiles:        "User profiles",
+	AllUserProfile:      "All User Profile",
+	CurrentUserProfile:  "Current User Profile",
+	None:                "<None>",
+
+	//Windows 11 labels the BSSID of the connection AP BSSID.
+	Aliases: map[string]string{"AP BSSID": "BSSID"},
+
+	NetworkTypes: map[string]string{
+		"infrastructure": "Infrastructure",
+		"independent":    "Adhoc",
+		"any":            "Any",
+	},
+	ConnectionModes: map[string]string{
+		"profile":            "Profile",
+		"temporary profile":  "Temporary Profile",
+		"discovery secure":   "Discovery (Secure)",
+		"discovery unsecure": "Discovery (Unsecure)",
+		"auto":               "Auto Connect",
+	},
+	Bands: map[string]string{
+		"2.4GHz": "2.4 GHz",
+		"5GHz":   "5 GHz",
+		"6GHz":   "6 GHz",
+		"60GHz":  "60 GHz",
+	},
+}
+
+//text returns the netsh text of a value.
+func text(values map[string]string, s string) string {
+	if t, ok := values[s]; ok {
+		return t
+	}
+	return s
+}
+
+//value returns the value among those from 0 to n whose netsh text is t, ignoring case.
+func value(values map[string]string, t string, n uint32, name func(uint32) string) (uint32, bool) {
+	for v := uint32(0); v <= n; v++ {
+		if strings.EqualFold(text(values, name(v)), t) {
+			return v, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,455 @@
+//Package netsh parses and prints the text of "netsh wlan show interfaces", "netsh wlan show networks mode=bssid"
+//and "netsh wlan show profiles", so captures of it can be compared with the results of the API.
+//
+//The labels of the text depend on the display language of Windows; English is built in, and other
+//locales are read and printed with their own Labels.
+package netsh
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+
+	"wlanapi"
+	"wlanapi/binary"
+	"wlanapi/signal"
+)
+
+//InterfaceStatus is an interface of "show interfaces".
+type InterfaceStatus struct {
+	//Name is the name of the network connection of the interface, such as Wi-Fi.
+	Name string
+	//Interface holds the GUID, description and state; it is not bound to a Client.
+	Interface       wlanapi.Interface
+	PhysicalAddress [6]byte
+	//Connection is set when the text shows an SSID. Its rates are in kbps, as in WLAN_ASSOCIATION_ATTRIBUTES.
+	Connection *binary.ConnectionAttributes
+	//Band and Channel are those of the connection; Band is 0 for versions of netsh that do not show it.
+	Band    wlanapi.Band
+	Channel int
+}
+
+//Interfaces is the text of "show interfaces".
+type Interfaces struct {
+	Interfaces []InterfaceStatus
+	//HostedNetworkStatus is the status of the wireless Hosted Network as printed, such as "Not available".
+	HostedNetworkStatus string
+}
+
+//InterfaceNetworks is the network list of an interface in "show networks mode=bssid".
+type InterfaceNetworks struct {
+	InterfaceName string
+	//Networks are in the order of the text. The RSSIs of their BSSes are derived from the signal qualities,
+	//and their frequencies from the bands and channels.
+	Networks []*wlanapi.Network
+}
+
+//InterfaceProfiles is the profile list of an interface in "show profiles".
+type InterfaceProfiles struct {
+	InterfaceName string
+	//Profiles are in preference order; their flags tell group policy and per-user profiles apart.
+	Profiles []binary.ProfileInfo
+}
+
+//capabilityPrivacy is the Privacy bit of the 802.11 Capability Information field.
+const capabilityPrivacy = 0x0010
+
+//field is a "label : value" line of the text.
+type field struct {
+	line         int
+	label, value string
+}
+
+//scanner reads the fields of a text.
+type scanner struct {
+	s      *bufio.Scanner
+	labels *Labels
+	line   int
+	//text is the current line without its indentation.
+	text string
+}
+
+func newScanner(r io.Reader, l *Labels) *scanner {
+	return &scanner{s: bufio.NewScanner(r), labels: l}
+}
+
+//next reads the next line and returns its field; ok is false for lines without a colon.
+func (s *scanner) next() (f field, ok bool, more bool) {
+	if !s.s.Scan() {
+		return f, false, false
+	}
+	s.line++
+	s.text = strings.TrimSpace(s.s.Text())
+	n := strings.Index(s.text, ":")
+	if n < 0 {
+		return f, false, true
+	}
+	f = field{line: s.line, label: strings.TrimSpace(s.text[:n]), value: strings.TrimSpace(s.text[n+1:])}
+	if label, ok := s.labels.Aliases[f.label]; ok {
+		f.label = label
+	}
+	return f, true, true
+}
+
+func (f field) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("netsh: line %d: %s", f.line, fmt.Sprintf(format, args...))
+}
+
+//numbered returns the number of a label such as "SSID 2" made of prefix and a number.
+func numbered(label, prefix string) (int, bool) {
+	if !strings.HasPrefix(label, prefix+" ") {
+		return 0, false
+	}
+	n, err := strconv.Atoi(label[len(prefix)+1:])
+	return n, err == nil
+}
+
+func (f field) mac() ([6]byte, error) {
+	var addr [6]byte
+	mac, err := net.ParseMAC(f.value)
+	if err != nil || len(mac) != len(addr) {
+		return addr, f.errorf("invalid address %q", f.value)
+	}
+	copy(addr[:], mac)
+	return addr, nil
+}
+
+func (f field) number() (int, error) {
+	n, err := strconv.Atoi(f.value)
+	if err != nil {
+		return 0, f.errorf("invalid %s %q", f.label, f.value)
+	}
+	return n, nil
+}
+
+//percent parses a signal quality such as 90%.
+func (f field) percent() (uint32, error) {
+	n, err := strconv.ParseUint(strings.TrimSpace(strings.TrimSuffix(f.value, "%")), 10, 32)
+	if err != nil || n > 100 {
+		return 0, f.errorf("invalid %s %q", f.label, f.value)
+	}
+	return uint32(n), nil
+}
+
+//mbps parses a rate in Mbps, with a decimal point or comma, into kbps.
+func (f field) mbps() (uint32, error) {
+	v, err := strconv.ParseFloat(strings.Replace(f.value, ",", ".", 1), 64)
+	if err != nil || v < 0 {
+		return 0, f.errorf("invalid %s %q", f.label, f.value)
+	}
+	return uint32(v*1000 + 0.5), nil
+}
+
+//enum parses the text of a value from 0 to n of a type named by name.
+func (f field) enum(values map[string]string, n uint32, name func(uint32) string) (uint32, error) {
+	v, ok := value(values, f.value, n, name)
+	if !ok {
+		return 0, f.errorf("unknown %s %q", f.label, f.value)
+	}
+	return v, nil
+}
+
+func stateName(v uint32) string   { return wlanapi.WLAN_INTERFACE_STATE(v).String() }
+func bssTypeName(v uint32) string { return wlanapi.DOT11_BSS_TYPE(v).String() }
+func authName(v uint32) string    { return wlanapi.DOT11_AUTH_ALGORITHM(v).String() }
+func cipherName(v uint32) string  { return wlanapi.DOT11_CIPHER_ALGORITHM(v).String() }
+func phyName(v uint32) string     { return wlanapi.DOT11_PHY_TYPE(v).String() }
+func modeName(v uint32) string    { return wlanapi.WLAN_CONNECTION_MODE(v).String() }
+func bandName(v uint32) string    { return wlanapi.Band(1 << v).String() }
+
+//The largest values of the enumerations netsh prints.
+const (
+	maxState   = 7
+	maxBssType = 3
+	maxAuth    = 11
+	maxCipher  = 0x101
+	maxPhy     = 11
+	maxMode    = 4
+	//maxBand is the shift of the largest band.
+	maxBand = 3
+)
+
+func (f field) band(l *Labels) (wlanapi.Band, error) {
+	v, err := f.enum(l.Bands, maxBand, bandName)
+	return wlanapi.Band(1 << v), err
+}
+
+//bandOfChannel guesses the band of a channel for netsh versions that do not print bands.
+func bandOfChannel(channel int) wlanapi.Band {
+	if channel <= 14 {
+		return wlanapi.Band2_4GHz
+	}
+	return wlanapi.Band5GHz
+}
+
+//ParseInterfaces parses the text of "netsh wlan show interfaces" printed with the labels l.
+//Fields it does not know are skipped.
+func ParseInterfaces(r io.Reader, l *Labels) (*Interfaces, error) {
+	s := newScanner(r, l)
+	result := &Interfaces{}
+	var i *InterfaceStatus
+	connection := func() *binary.ConnectionAttributes {
+		if i.Connection == nil {
+			i.Connection = &binary.ConnectionAttributes{BssType: 1}
+		}
+		return i.Connection
+	}
+	for {
+		f, ok, more := s.next()
+		if !more {
+			break
+		}
+		if !ok {
+			continue
+		}
+		var err error
+		var v uint32
+		switch f.label {
+		case l.Name:
+			result.Interfaces = append(result.Interfaces, InterfaceStatus{Name: f.value})
+			i = &result.Interfaces[len(result.Interfaces)-1]
+			continue
+		case l.HostedNetworkStatus:
+			result.HostedNetworkStatus = f.value
+			continue
+		}
+		if i == nil {
+			continue
+		}
+		switch f.label {
+		case l.Description:
+			i.Interface.Description = f.value
+		case l.GUID:
+			guid := f.value
+			if !strings.HasPrefix(guid, "{") {
+				guid = "{" + guid + "}"
+			}
+			if i.Interface.GUID, err = wlanapi.ParseGUID(guid); err != nil {
+				err = f.errorf("invalid GUID %q", f.value)
+			}
+		case l.PhysicalAddress:
+			i.PhysicalAddress, err = f.mac()
+		case l.State:
+			v, err = f.enum(l.States, maxState, stateName)
+			i.Interface.State = wlanapi.WLAN_INTERFACE_STATE(v)
+		case l.SSID:
+			connection().SSID = []byte(f.value)
+		case l.BSSID:
+			connection().BSSID, err = f.mac()
+		case l.NetworkType:
+			connection().BssType, err = f.enum(l.NetworkTypes, maxBssType, bssTypeName)
+		case l.RadioType:
+			connection().PhyType, err = f.enum(l.RadioTypes, maxPhy, phyName)
+		case l.Authentication:
+			connection().AuthAlgorithm, err = f.enum(l.Authentications, maxAuth, authName)
+		case l.Cipher:
+			connection().CipherAlgorithm, err = f.enum(l.Ciphers, maxCipher, cipherName)
+		case l.ConnectionMode:
+			connection().Mode, err = f.enum(l.ConnectionModes, maxMode, modeName)
+		case l.Band:
+			i.Band, err = f.band(l)
+		case l.Channel:
+			i.Channel, err = f.number()
+		case l.ReceiveRate:
+			connection().RxRate, err = f.mbps()
+		case l.TransmitRate:
+			connection().TxRate, err = f.mbps()
+		case l.Signal:
+			connection().SignalQuality, err = f.percent()
+		case l.Profile:
+			connection().ProfileName = f.value
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err := s.s.Err(); err != nil {
+		return nil, err
+	}
+	for n := range result.Interfaces {
+		i := &result.Interfaces[n]
+		if c := i.Connection; c != nil {
+			c.State = uint32(i.Interface.State)
+			c.SecurityEnabled = isSecure(c.AuthAlgorithm, c.CipherAlgorithm)
+			if i.Band == 0 && i.Channel != 0 {
+				i.Band = bandOfChannel(i.Channel)
+			}
+		}
+	}
+	return result, nil
+}
+
+//isSecure reports whether a network with the algorithms is secured, as netsh does not print it.
+func isSecure(auth, cipher uint32) bool {
+	return wlanapi.DOT11_AUTH_ALGORITHM(auth) != wlanapi.DOT11_AUTH_ALGO_80211_OPEN ||
+		wlanapi.DOT11_CIPHER_ALGORITHM(cipher) != wlanapi.DOT11_CIPHER_ALGO_NONE
+}
+
+//ParseNetworks parses the text of "netsh wlan show networks mode=bssid", or of "show networks" without
+//BSSes, printed with the labels l. Fields it does not know are skipped.
+func ParseNetworks(r io.Reader, l *Labels) ([]InterfaceNetworks, error) {
+	s := newScanner(r, l)
+	var result []InterfaceNetworks
+	var network *wlanapi.Network
+	var bss *wlanapi.BSS
+	for {
+		f, ok, more := s.next()
+		if !more {
+			break
+		}
+		if !ok {
+			continue
+		}
+		var err error
+		if f.label == l.InterfaceName {
+			result = append(result, InterfaceNetworks{InterfaceName: f.value})
+			network, bss = nil, nil
+			continue
+		}
+		if _, ok := numbered(f.label, l.SSID); ok {
+			if len(result) == 0 {
+				result = append(result, InterfaceNetworks{})
+			}
+			network = &wlanapi.Network{SSID: []byte(f.value), BssType: 1}
+			list := &result[len(result)-1]
+			list.Networks = append(list.Networks, network)
+			bss = nil
+			continue
+		}
+		if network == nil {
+			continue
+		}
+		if _, ok := numbered(f.label, l.BSSID); ok {
+			network.BSSes = append(network.BSSes, wlanapi.BSS{})
+			bss = &network.BSSes[len(network.BSSes)-1]
+			if bss.BSSID, err = f.mac(); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		var v uint32
+		switch f.label {
+		case l.NetworkType:
+			v, err = f.enum(l.NetworkTypes, maxBssType, bssTypeName)
+			network.BssType = wlanapi.DOT11_BSS_TYPE(v)
+		case l.Authentication:
+			v, err = f.enum(l.Authentications, maxAuth, authName)
+			network.AuthAlgorithm = wlanapi.DOT11_AUTH_ALGORITHM(v)
+		case l.Encryption:
+			v, err = f.enum(l.Ciphers, maxCipher, cipherName)
+			network.CipherAlgorithm = wlanapi.DOT11_CIPHER_ALGORITHM(v)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if bss == nil {
+			continue
+		}
+		switch f.label {
+		case l.Signal:
+			bss.LinkQuality, err = f.percent()
+		case l.RadioType:
+			bss.PhyType, err = f.enum(l.RadioTypes, maxPhy, phyName)
+		case l.Band:
+			bss.Band, err = f.band(l)
+		case l.Channel:
+			bss.Channel, err = f.number()
+		case l.BasicRates, l.OtherRates:
+			basic := f.label == l.BasicRates
+			for _, word := range strings.Fields(f.value) {
+				rate, err := (field{line: f.line, label: f.label, value: word}).mbps()
+				if err != nil {
+					return nil, err
+				}
+				r := uint16(rate / 500)
+				if basic {
+					r |= 0x8000
+				}
+				bss.Rates = append(bss.Rates, r)
+			}
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err := s.s.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, list := range result {
+		for _, n := range list.Networks {
+			n.SecurityEnabled = isSecure(uint32(n.AuthAlgorithm), uint32(n.CipherAlgorithm))
+			for k := range n.BSSes {
+				b := &n.BSSes[k]
+				if b.Band == 0 && b.Channel != 0 {
+					b.Band = bandOfChannel(b.Channel)
+				}
+				b.ChCenterFrequency = wlanapi.FrequencyOf(b.Band, b.Channel)
+				b.SSID = n.SSID
+				b.BssType = uint32(n.BssType)
+				b.RSSI = int32(signal.RSSI(int(b.LinkQuality)))
+				b.Privacy = n.SecurityEnabled
+				if b.Privacy {
+					b.CapabilityInformation |= capabilityPrivacy
+				}
+				if b.LinkQuality > n.SignalQuality {
+					n.SignalQuality = b.LinkQuality
+				}
+				if k == 0 || b.RSSI > n.StrongestRSSI {
+					n.StrongestRSSI = b.RSSI
+				}
+				n.Bands |= wlanapi.BandSet(b.Band)
+				if !containsPhyType(n.PhyTypes, wlanapi.DOT11_PHY_TYPE(b.PhyType)) {
+					n.PhyTypes = append(n.PhyTypes, wlanapi.DOT11_PHY_TYPE(b.PhyType))
+				}
+			}
+		}
+	}
+	return result, nil
+}
+
+func containsPhyType(types []wlanapi.DOT11_PHY_TYPE, t wlanapi.DOT11_PHY_TYPE) bool {
+	for _, v := range types {
+		if v == t {
+			return true
+		}
+	}
+	return false
+}
+
+//ParseProfiles parses the text of "netsh wlan show profiles" printed with the labels l.
+func ParseProfiles(r io.Reader, l *Labels) ([]InterfaceProfiles, error) {
+	s := newScanner(r, l)
+	var result []InterfaceProfiles
+	var groupPolicy bool
+	for {
+		f, ok, more := s.next()
+		if !more {
+			break
+		}
+		switch {
+		case strings.HasPrefix(s.text, l.ProfilesOnInterface+" "):
+			name := strings.TrimSuffix(strings.TrimPrefix(s.text, l.ProfilesOnInterface+" "), ":")
+			result = append(result, InterfaceProfiles{InterfaceName: name})
+			groupPolicy = false
+		case s.text == l.GroupPolicyProfiles:
+			groupPolicy = true
+		case s.text == l.UserProfiles:
+			groupPolicy = false
+		case ok && len(result) > 0 && (f.label == l.AllUserProfile || f.label == l.CurrentUserProfile):
+			var flags uint32
+			switch {
+			case groupPolicy:
+				flags = wlanapi.WLAN_PROFILE_GROUP_POLICY
+			case f.label == l.CurrentUserProfile:
+				flags = wlanapi.WLAN_PROFILE_USER
+			}
+			list := &result[len(result)-1]
+			list.Profiles = append(list.Profiles, binary.ProfileInfo{Name: f.value, Flags: flags})
+		}
+	}
+	return result, s.s.Err()
+}
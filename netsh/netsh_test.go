@@ -0,0 +1,152 @@
+package netsh
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+
+	"wlanapi"
+)
+
+//roundTrip parses the capture file with parse and checks that write prints it back unchanged.
+func roundTrip(t *testing.T, file string, parse func([]byte) (interface{}, error), write func(*bytes.Buffer, interface{}) error) interface{} {
+	t.Helper()
+	capture, err := os.ReadFile("testdata/" + file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	v, err := parse(capture)
+	if err != nil {
+		t.Fatalf("%s: %v", file, err)
+	}
+	var b bytes.Buffer
+	if err := write(&b, v); err != nil {
+		t.Fatal(err)
+	}
+	if b.String() != string(capture) {
+		t.Errorf("%s printed as\n%s\nwant\n%s", file, b.String(), capture)
+	}
+	return v
+}
+
+func TestInterfaces(t *testing.T) {
+	v := roundTrip(t, "interfaces.txt", func(b []byte) (interface{}, error) {
+		return ParseInterfaces(bytes.NewReader(b), English)
+	}, func(b *bytes.Buffer, v interface{}) error {
+		return WriteInterfaces(b, v.(*Interfaces), English)
+	})
+	interfaces := v.(*Interfaces)
+	if len(interfaces.Interfaces) != 2 || interfaces.HostedNetworkStatus != "Not available" {
+		t.Fatalf("interfaces %+v", interfaces)
+	}
+	i := interfaces.Interfaces[0]
+	guid, _ := wlanapi.ParseGUID("{11111111-2222-3333-4444-555555555555}")
+	c := i.Connection
+	if i.Name != "Wi-Fi" || i.Interface.GUID != guid || i.Interface.State.String() != "connected" || c == nil {
+		t.Fatalf("interface %+v", i)
+	}
+	if string(c.SSID) != "corp" || c.BSSID != [6]byte{0x00, 0x1a, 0x1e, 0, 0, 1} || c.RxRate != 573500 || c.TxRate != 1201000 ||
+		c.SignalQuality != 90 || c.ProfileName != "corp" || !c.SecurityEnabled ||
+		wlanapi.DOT11_AUTH_ALGORITHM(c.AuthAlgorithm).String() != "WPA2-Personal" ||
+		wlanapi.WLAN_CONNECTION_MODE(c.Mode).String() != "profile" || wlanapi.DOT11_PHY_TYPE(c.PhyType).String() != "802.11ax" {
+		t.Errorf("connection %+v", c)
+	}
+	if i.Band != wlanapi.Band5GHz || i.Channel != 36 {
+		t.Errorf("band %v, channel %d", i.Band, i.Channel)
+	}
+	if interfaces.Interfaces[1].Connection != nil {
+		t.Errorf("connection of a disconnected interface %+v", interfaces.Interfaces[1].Connection)
+	}
+}
+
+func TestNetworks(t *testing.T) {
+	v := roundTrip(t, "networks.txt", func(b []byte) (interface{}, error) {
+		return ParseNetworks(bytes.NewReader(b), English)
+	}, func(b *bytes.Buffer, v interface{}) error {
+		return WriteNetworks(b, v.([]InterfaceNetworks), English)
+	})
+	lists := v.([]InterfaceNetworks)
+	if len(lists) != 1 || lists[0].InterfaceName != "Wi-Fi" || len(lists[0].Networks) != 2 {
+		t.Fatalf("network lists %+v", lists)
+	}
+	corp, guest := lists[0].Networks[0], lists[0].Networks[1]
+	if string(corp.SSID) != "corp" || len(corp.BSSes) != 2 || corp.SignalQuality != 90 || !corp.SecurityEnabled ||
+		corp.Bands != wlanapi.BandSet(wlanapi.Band2_4GHz|wlanapi.Band5GHz) || len(corp.PhyTypes) != 2 {
+		t.Fatalf("network %+v", corp)
+	}
+	b := corp.BSSes[1]
+	if b.ChCenterFrequency != 2437000 || b.Channel != 6 || b.RSSI != corp.StrongestRSSI-15 || !b.Privacy ||
+		b.Rates[2] != 0x8000|11 || b.Rates[4] != 12 || string(b.SSID) != "corp" {
+		t.Errorf("BSS %+v", b)
+	}
+	if b := guest.BSSes[0]; guest.SecurityEnabled || b.Privacy || b.Band != wlanapi.Band6GHz || b.ChCenterFrequency != 5975000 {
+		t.Errorf("open 6 GHz network %+v", guest)
+	}
+}
+
+func TestProfiles(t *testing.T) {
+	v := roundTrip(t, "profiles.txt", func(b []byte) (interface{}, error) {
+		return ParseProfiles(bytes.NewReader(b), English)
+	}, func(b *bytes.Buffer, v interface{}) error {
+		return WriteProfiles(b, v.([]InterfaceProfiles), English)
+	})
+	lists := v.([]InterfaceProfiles)
+	if len(lists) != 2 || lists[1].InterfaceName != "Wi-Fi 2" || len(lists[1].Profiles) != 0 {
+		t.Fatalf("profile lists %+v", lists)
+	}
+	p := lists[0].Profiles
+	if len(p) != 3 || p[0].Flags != wlanapi.WLAN_PROFILE_GROUP_POLICY || p[1].Name != "corp" || p[1].Flags != 0 ||
+		p[2].Flags != wlanapi.WLAN_PROFILE_USER {
+		t.Errorf("profiles %+v", p)
+	}
+}
+
+func TestLocalizedLabels(t *testing.T) {
+	german := *English
+	german.Name, german.State, german.Signal, german.Channel = "Name", "Status", "Signal", "Kanal"
+	german.NetworkType, german.RadioType, german.Authentication, german.Cipher = "Netzwerktyp", "Funktyp", "Authentifizierung", "Verschlüsselung"
+	german.ConnectionMode, german.ReceiveRate, german.TransmitRate = "Verbindungsmodus", "Empfangsrate (MBit/s)", "Übertragungsrate (MBit/s)"
+	german.PhysicalAddress, german.Description, german.Profile = "Physische Adresse", "Beschreibung", "Profil"
+	german.InterfacesCount = "Es sind %d Schnittstellen auf dem System vorhanden:"
+	german.States = map[string]string{"connected": "Verbunden", "disconnected": "Getrennt"}
+	german.NetworkTypes = map[string]string{"infrastructure": "Infrastruktur"}
+	german.ConnectionModes = map[string]string{"profile": "Profil"}
+	german.Bands = map[string]string{"5GHz": "5 GHz"}
+
+	capture, _ := os.ReadFile("testdata/interfaces.txt")
+	interfaces, err := ParseInterfaces(bytes.NewReader(capture), English)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var b bytes.Buffer
+	if err := WriteInterfaces(&b, interfaces, &german); err != nil {
+		t.Fatal(err)
+	}
+	for _, line := range []string{
+		"    Status                 : Verbunden\n",
+		"    Netzwerktyp            : Infrastruktur\n",
+		"    Empfangsrate (MBit/s)  : 573.5\n",
+		"    Status                 : Getrennt\n",
+	} {
+		if !strings.Contains(b.String(), line) {
+			t.Errorf("missing %q in\n%s", line, b.String())
+		}
+	}
+
+	//Values are read back case-insensitively, and with a decimal comma.
+	text := strings.Replace(strings.Replace(b.String(), "Verbunden", "verbunden", 1), "573.5", "573,5", 1)
+	again, err := ParseInterfaces(strings.NewReader(text), &german)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c := again.Interfaces[0].Connection; again.Interfaces[0].Interface.State != interfaces.Interfaces[0].Interface.State ||
+		c.RxRate != 573500 || c.Mode != interfaces.Interfaces[0].Connection.Mode {
+		t.Errorf("interface read with German labels %+v", again.Interfaces[0])
+	}
+
+	if _, err := ParseInterfaces(strings.NewReader("    Name : Wi-Fi\n    Status : Unterwegs\n"), &german); err == nil ||
+		!strings.Contains(err.Error(), "line 2") {
+		t.Errorf("unknown state: %v", err)
+	}
+}
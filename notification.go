@@ -0,0 +1,156 @@
+//go:build windows
+// +build windows
+
+package wlanapi
+
+import (
+	"sync"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+type subscription struct {
+	sources DWORD
+	ch      chan Notification
+}
+
+var (
+	notificationCallbackOnce sync.Once
+	notificationCallback     uintptr
+
+	notificationClientsMu sync.Mutex
+	notificationClients   = map[uintptr]*Client{}
+	notificationClientID  uintptr
+)
+
+func onNotification(data *WLAN_NOTIFICATION_DATA, context uintptr) uintptr {
+	notificationClientsMu.Lock()
+	c := notificationClients[context]
+	notificationClientsMu.Unlock()
+	if c == nil || data == nil {
+		return 0
+	}
+
+	n := Notification{
+		Source:        uint32(data.NotificationSource),
+		Code:          uint32(data.NotificationCode),
+		InterfaceGuid: data.InterfaceGuid,
+	}
+	if data.dwDataSize > 0 && data.pData != 0 {
+		n.Data = make([]byte, data.dwDataSize)
+		pData := *(*unsafe.Pointer)(unsafe.Pointer(&data.pData))
+		copy(n.Data, unsafe.Slice((*byte)(pData), data.dwDataSize))
+	}
+	c.dispatch(n)
+	return 0
+}
+
+func (c *Client) dispatch(n Notification) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for s := range c.subscriptions {
+		if s.sources&DWORD(n.Source) == 0 {
+			continue
+		}
+		select {
+		case s.ch <- n:
+		default:
+		}
+	}
+}
+
+//Subscribe registers for notifications from the given WLAN_NOTIFICATION_SOURCE_* sources.
+//The returned function cancels the subscription and closes the channel.
+func (c *Client) Subscribe(sources DWORD) (<-chan Notification, func(), error) {
+	s := &subscription{sources: sources, ch: make(chan Notification, notificationBuffer)}
+
+	c.registerMu.Lock()
+	defer c.registerMu.Unlock()
+	c.mu.Lock()
+	if c.subscriptions == nil {
+		c.subscriptions = map[*subscription]struct{}{}
+	}
+	c.subscriptions[s] = struct{}{}
+	c.mu.Unlock()
+	if err := c.registerNotifications(); err != nil {
+		c.mu.Lock()
+		delete(c.subscriptions, s)
+		c.mu.Unlock()
+		return nil, nil, err
+	}
+
+	var once sync.Once
+	cancel := func() {
+		once.Do(func() {
+			c.registerMu.Lock()
+			defer c.registerMu.Unlock()
+			c.mu.Lock()
+			_, ok := c.subscriptions[s]
+			if ok {
+				delete(c.subscriptions, s)
+				close(s.ch)
+			}
+			c.mu.Unlock()
+			if ok {
+				c.registerNotifications()
+			}
+		})
+	}
+	return s.ch, cancel, nil
+}
+
+//registerNotifications registers the union of the subscribed sources with the WLAN service.
+//c.registerMu must be held, but not c.mu: the WLAN service waits for running callbacks
+//before it returns, and those callbacks take c.mu.
+func (c *Client) registerNotifications() error {
+	var sources DWORD
+	c.mu.Lock()
+	for s := range c.subscriptions {
+		sources |= s.sources
+	}
+	c.mu.Unlock()
+	if sources == c.sources {
+		return nil
+	}
+
+	notificationCallbackOnce.Do(func() {
+		notificationCallback = windows.NewCallback(onNotification)
+	})
+	if c.id == 0 {
+		notificationClientsMu.Lock()
+		notificationClientID++
+		c.id = notificationClientID
+		notificationClients[c.id] = c
+		notificationClientsMu.Unlock()
+	}
+
+	var err error
+	if sources == WLAN_NOTIFICATION_SOURCE_NONE {
+		_, err = WlanRegisterNotification(c.handle, sources, TRUE, 0, 0)
+	} else {
+		_, err = WlanRegisterNotification(c.handle, sources, TRUE, notificationCallback, PVOID(c.id))
+	}
+	if err != nil {
+		return err
+	}
+	c.sources = sources
+	return nil
+}
+
+//closeSubscriptions closes all subscription channels after the handle is closed.
+func (c *Client) closeSubscriptions() {
+	notificationClientsMu.Lock()
+	delete(notificationClients, c.id)
+	notificationClientsMu.Unlock()
+
+	c.registerMu.Lock()
+	defer c.registerMu.Unlock()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for s := range c.subscriptions {
+		close(s.ch)
+	}
+	c.subscriptions = nil
+	c.sources = WLAN_NOTIFICATION_SOURCE_NONE
+}
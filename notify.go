@@ -0,0 +1,174 @@
+package wlanapi
+
+import (
+	"fmt"
+
+	"wlanapi/binary"
+)
+
+//notificationBuffer is the channel capacity of a subscription.
+//Notifications are dropped when a subscriber falls this far behind, since the WLAN service
+//must never be blocked by a slow reader.
+const notificationBuffer = 64
+
+//Notification sources used by WlanRegisterNotification.
+//https://docs.microsoft.com/en-us/windows/win32/api/wlanapi/nf-wlanapi-wlanregisternotification
+const (
+	WLAN_NOTIFICATION_SOURCE_NONE           = 0x00000000
+	WLAN_NOTIFICATION_SOURCE_ONEX           = 0x00000004
+	WLAN_NOTIFICATION_SOURCE_ACM            = 0x00000008
+	WLAN_NOTIFICATION_SOURCE_MSM            = 0x00000010
+	WLAN_NOTIFICATION_SOURCE_SECURITY       = 0x00000020
+	WLAN_NOTIFICATION_SOURCE_IHV            = 0x00000040
+	WLAN_NOTIFICATION_SOURCE_HNWK           = 0x00000080
+	WLAN_NOTIFICATION_SOURCE_DEVICE_SERVICE = 0x00000800
+	WLAN_NOTIFICATION_SOURCE_ALL            = 0x0000ffff
+)
+
+//Notification is a notification delivered by the WLAN service to a Client.
+//Source is one of the WLAN_NOTIFICATION_SOURCE_* values and Code depends on it,
+//such as a WLAN_NOTIFICATION_ACM for the ACM source.
+//Data is a copy of the notification payload; its layout depends on Source and Code.
+type Notification struct {
+	Source        uint32
+	Code          uint32
+	InterfaceGuid GUID
+	Data          []byte
+}
+
+//ACM returns the code of a notification of the Auto Configuration Module; ok is false for the other sources.
+func (n Notification) ACM() (code WLAN_NOTIFICATION_ACM, ok bool) {
+	return WLAN_NOTIFICATION_ACM(n.Code), n.Source == WLAN_NOTIFICATION_SOURCE_ACM
+}
+
+//connectionReasonOffset is the offset of wlanReasonCode in WLAN_CONNECTION_NOTIFICATION_DATA,
+//after the connection mode, profile name, SSID, BSS type and security flag.
+const connectionReasonOffset = 4 + 256*2 + 36 + 4 + 4
+
+//Reason returns the WLAN_REASON_CODE of a scan fail, connection complete, connection attempt fail
+//or disconnected notification of the Auto Configuration Module, or success when it carries none.
+func (n Notification) Reason() WLAN_REASON_CODE {
+	code, ok := n.ACM()
+	if !ok {
+		return WLAN_REASON_CODE_SUCCESS
+	}
+	offset := connectionReasonOffset
+	switch code {
+	case WlanNotificationAcmScanFail:
+		offset = 0
+	case WlanNotificationAcmConnectionComplete, WlanNotificationAcmConnectionAttemptFail, WlanNotificationAcmDisconnected:
+	default:
+		return WLAN_REASON_CODE_SUCCESS
+	}
+	if len(n.Data) < offset+4 {
+		return WLAN_REASON_CODE_SUCCESS
+	}
+	d := n.Data[offset:]
+	return WLAN_REASON_CODE(uint32(d[0]) | uint32(d[1])<<8 | uint32(d[2])<<16 | uint32(d[3])<<24)
+}
+
+//VirtualStationNotification is a decoded notification of the wireless Hosted Network, which runs on a virtual
+//station of the interface. The fields that Code does not carry are zero.
+type VirtualStationNotification struct {
+	Code WLAN_HOSTED_NETWORK_NOTIFICATION_CODE
+	//OldState and NewState are set by state changes, as WLAN_HOSTED_NETWORK_STATE_CHANGE.
+	OldState WLAN_HOSTED_NETWORK_STATE
+	NewState WLAN_HOSTED_NETWORK_STATE
+	//OldPeer and NewPeer are set by peer state changes, as WLAN_HOSTED_NETWORK_DATA_PEER_STATE_CHANGE.
+	OldPeer binary.HostedNetworkPeer
+	NewPeer binary.HostedNetworkPeer
+	//Reason is set by state and peer state changes.
+	Reason WLAN_HOSTED_NETWORK_REASON
+	//SoftwareRadioState and HardwareRadioState are set by radio state changes, as WLAN_HOSTED_NETWORK_RADIO_STATE.
+	SoftwareRadioState DOT11_RADIO_STATE
+	HardwareRadioState DOT11_RADIO_STATE
+}
+
+//VirtualStation decodes a notification of the wireless Hosted Network; ok is false for the other sources
+//and for notifications whose data is too short for their code.
+func (n Notification) VirtualStation() (v VirtualStationNotification, ok bool) {
+	if n.Source != WLAN_NOTIFICATION_SOURCE_HNWK {
+		return v, false
+	}
+	u32 := func(offset int) uint32 {
+		d := n.Data[offset:]
+		return uint32(d[0]) | uint32(d[1])<<8 | uint32(d[2])<<16 | uint32(d[3])<<24
+	}
+	//WLAN_HOSTED_NETWORK_PEER_STATE is the MAC address, padded to 8 bytes, and the authentication state.
+	peer := func(offset int) (p binary.HostedNetworkPeer) {
+		copy(p.MacAddress[:], n.Data[offset:])
+		p.AuthState = u32(offset + 8)
+		return p
+	}
+	v.Code = WLAN_HOSTED_NETWORK_NOTIFICATION_CODE(n.Code)
+	switch v.Code {
+	case wlan_hosted_network_state_change:
+		if len(n.Data) < 12 {
+			return v, false
+		}
+		v.OldState, v.NewState = WLAN_HOSTED_NETWORK_STATE(u32(0)), WLAN_HOSTED_NETWORK_STATE(u32(4))
+		v.Reason = WLAN_HOSTED_NETWORK_REASON(u32(8))
+	case wlan_hosted_network_peer_state_change:
+		if len(n.Data) < 28 {
+			return v, false
+		}
+		v.OldPeer, v.NewPeer = peer(0), peer(12)
+		v.Reason = WLAN_HOSTED_NETWORK_REASON(u32(24))
+	case wlan_hosted_network_radio_state_change:
+		if len(n.Data) < 8 {
+			return v, false
+		}
+		v.SoftwareRadioState, v.HardwareRadioState = DOT11_RADIO_STATE(u32(0)), DOT11_RADIO_STATE(u32(4))
+	default:
+		return v, false
+	}
+	return v, true
+}
+
+//sourceNames are the names of the notification sources in events.
+var sourceNames = map[uint32]string{
+	WLAN_NOTIFICATION_SOURCE_ONEX:           "onex",
+	WLAN_NOTIFICATION_SOURCE_ACM:            "acm",
+	WLAN_NOTIFICATION_SOURCE_MSM:            "msm",
+	WLAN_NOTIFICATION_SOURCE_SECURITY:       "security",
+	WLAN_NOTIFICATION_SOURCE_IHV:            "ihv",
+	WLAN_NOTIFICATION_SOURCE_HNWK:           "hnwk",
+	WLAN_NOTIFICATION_SOURCE_DEVICE_SERVICE: "device service",
+}
+
+//SourceName returns the name of a WLAN_NOTIFICATION_SOURCE_* value: acm, msm, onex, security, ihv, hnwk
+//or device service, and the value in hexadecimal for the others.
+func SourceName(source uint32) string {
+	if name, ok := sourceNames[source]; ok {
+		return name
+	}
+	return fmt.Sprintf("0x%x", source)
+}
+
+//Event is a notification with its source, code and reason named, as the servers stream them.
+type Event struct {
+	//Interface is the GUID of the interface, empty for notifications of the Hosted Network.
+	Interface string
+	//Source is the SourceName of the source.
+	Source string
+	Code   uint32
+	//Notification names the code of the notifications of the Auto Configuration Module.
+	Notification string
+	//Reason is the reason of a failed scan or connection, or of a disconnection.
+	Reason string
+}
+
+//Event returns the event of the notification.
+func (n Notification) Event() Event {
+	e := Event{Source: SourceName(n.Source), Code: n.Code}
+	if n.InterfaceGuid != (GUID{}) {
+		e.Interface = n.InterfaceGuid.String()
+	}
+	if code, ok := n.ACM(); ok {
+		e.Notification = code.String()
+		if reason := n.Reason(); reason != WLAN_REASON_CODE_SUCCESS {
+			e.Reason = reason.String()
+		}
+	}
+	return e
+}
@@ -0,0 +1,76 @@
+package wlanapi
+
+import (
+	"errors"
+
+	"wlanapi/binary"
+)
+
+//Flags of WLAN_PROFILE_INFO.dwFlags, and of WlanGetProfile and WlanSetProfile.
+const (
+	WLAN_PROFILE_GROUP_POLICY      = 0x00000001
+	WLAN_PROFILE_USER              = 0x00000002
+	WLAN_PROFILE_GET_PLAINTEXT_KEY = 0x00000004
+)
+
+//ErrBackendNotSupported is returned for operations the backend of a client does not implement.
+var ErrBackendNotSupported = errors.New("wlanapi: operation not supported by the backend")
+
+//ErrProfileNotFound is returned for a profile the interface does not have.
+var ErrProfileNotFound = errors.New("wlanapi: profile not found")
+
+//ProfileBackend is implemented by backends that manage the profiles of their interfaces.
+type ProfileBackend interface {
+	//Profiles lists the profiles of the interface in preference order, as WlanGetProfileList.
+	Profiles(iface GUID) ([]binary.ProfileInfo, error)
+	//Profile retrieves the XML of a profile, as WlanGetProfile.
+	//plaintextKey asks for the key in plain text instead of encrypted, which needs administrator rights.
+	Profile(iface GUID, name string, plaintextKey bool) (string, error)
+	//SetProfile adds the profile of xml, or replaces the profile of the same name if overwrite is set, as WlanSetProfile.
+	SetProfile(iface GUID, xml string, overwrite bool) error
+	//DeleteProfile deletes a profile, as WlanDeleteProfile.
+	DeleteProfile(iface GUID, name string) error
+}
+
+func (i *Interface) profileBackend() (ProfileBackend, error) {
+	if b, ok := i.client.backend.(ProfileBackend); ok {
+		return b, nil
+	}
+	return nil, ErrBackendNotSupported
+}
+
+//Profiles lists the profiles of the interface in preference order.
+func (i *Interface) Profiles() ([]binary.ProfileInfo, error) {
+	b, err := i.profileBackend()
+	if err != nil {
+		return nil, err
+	}
+	return b.Profiles(i.GUID)
+}
+
+//ProfileXML retrieves the XML of a profile; plaintextKey asks for the key in plain text.
+func (i *Interface) ProfileXML(name string, plaintextKey bool) (string, error) {
+	b, err := i.profileBackend()
+	if err != nil {
+		return "", err
+	}
+	return b.Profile(i.GUID, name, plaintextKey)
+}
+
+//SetProfile adds the profile of xml; with overwrite it replaces the profile of the same name.
+func (i *Interface) SetProfile(xml string, overwrite bool) error {
+	b, err := i.profileBackend()
+	if err != nil {
+		return err
+	}
+	return b.SetProfile(i.GUID, xml, overwrite)
+}
+
+//DeleteProfile deletes a profile of the interface.
+func (i *Interface) DeleteProfile(name string) error {
+	b, err := i.profileBackend()
+	if err != nil {
+		return err
+	}
+	return b.DeleteProfile(i.GUID, name)
+}
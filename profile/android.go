@@ -0,0 +1,182 @@
+package profile
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+//AndroidEnterpriseConfig is the 802.1X configuration of an Android network, with the fields of
+//WifiEnterpriseConfig.
+type AndroidEnterpriseConfig struct {
+	//EAPMethod is the name of the WifiEnterpriseConfig.Eap constant of the method: PEAP, TLS or TTLS.
+	EAPMethod string `json:"eapMethod"`
+	//Phase2Method is the name of the WifiEnterpriseConfig.Phase2 constant of the inner authentication.
+	Phase2Method      string `json:"phase2Method,omitempty"`
+	AnonymousIdentity string `json:"anonymousIdentity,omitempty"`
+	//DomainSuffixMatch is a semicolon separated list of the names the certificate of the server may have.
+	DomainSuffixMatch string `json:"domainSuffixMatch,omitempty"`
+}
+
+//AndroidSuggestion is a network suggestion of Android 10 and later, with the settings of
+//WifiNetworkSuggestion.Builder.
+type AndroidSuggestion struct {
+	SSID                     string                   `json:"ssid"`
+	IsHiddenSSID             bool                     `json:"isHiddenSsid,omitempty"`
+	WPA2Passphrase           string                   `json:"wpa2Passphrase,omitempty"`
+	WPA3Passphrase           string                   `json:"wpa3Passphrase,omitempty"`
+	IsEnhancedOpen           bool                     `json:"isEnhancedOpen,omitempty"`
+	WPA2EnterpriseConfig     *AndroidEnterpriseConfig `json:"wpa2EnterpriseConfig,omitempty"`
+	WPA3EnterpriseConfig     *AndroidEnterpriseConfig `json:"wpa3EnterpriseConfig,omitempty"`
+	WPA3Enterprise192Config  *AndroidEnterpriseConfig `json:"wpa3Enterprise192BitModeConfig,omitempty"`
+	IsInitialAutojoinEnabled bool                     `json:"isInitialAutojoinEnabled"`
+}
+
+//AndroidConfiguration is a network of the WifiConfiguration class that Android before version 10 adds networks
+//with, and that device owners still can.
+type AndroidConfiguration struct {
+	//SSID is quoted, as WifiConfiguration takes it, or the hexadecimal digits of an SSID that is not text.
+	SSID       string `json:"SSID"`
+	HiddenSSID bool   `json:"hiddenSSID,omitempty"`
+	//PreSharedKey is a quoted passphrase, or the 64 hexadecimal digits of a PSK.
+	PreSharedKey  string   `json:"preSharedKey,omitempty"`
+	WEPKeys       []string `json:"wepKeys,omitempty"`
+	WEPTxKeyIndex int      `json:"wepTxKeyIndex"`
+	//AllowedKeyManagement and the other allowed sets are the names of the bits of the WifiConfiguration BitSets.
+	AllowedKeyManagement   []string                 `json:"allowedKeyManagement"`
+	AllowedProtocols       []string                 `json:"allowedProtocols,omitempty"`
+	AllowedPairwiseCiphers []string                 `json:"allowedPairwiseCiphers,omitempty"`
+	RequirePMF             bool                     `json:"requirePmf,omitempty"`
+	EnterpriseConfig       *AndroidEnterpriseConfig `json:"enterpriseConfig,omitempty"`
+}
+
+//androidText reports whether an SSID is text that Android takes quoted.
+func androidText(ssid string) bool {
+	if !utf8.ValidString(ssid) {
+		return false
+	}
+	for _, r := range ssid {
+		if r < 0x20 || r == 0x7f {
+			return false
+		}
+	}
+	return true
+}
+
+//androidEnterprise returns the enterprise configuration of EAP.
+func androidEnterprise(e *EAP, w *warnings) *AndroidEnterpriseConfig {
+	if e == nil {
+		return nil
+	}
+	c := &AndroidEnterpriseConfig{
+		EAPMethod:         strings.ToUpper(e.Method.String()),
+		Phase2Method:      strings.ToUpper(e.Phase2),
+		AnonymousIdentity: e.AnonymousIdentity,
+		DomainSuffixMatch: strings.Join(e.ServerNames, ";"),
+	}
+	if c.Phase2Method == "" {
+		c.Phase2Method = "NONE"
+	}
+	if len(e.TrustedRootCAs) > 0 {
+		w.add("TrustedRootCA", "Android trusts CAs by certificate rather than thumbprint; set the CA certificate of the configuration")
+	}
+	return c
+}
+
+//ToAndroidSuggestion returns the network suggestion of a profile, with warnings for the settings that suggestions
+//lack. Suggestions have no WEP or WPA-Personal networks.
+func ToAndroidSuggestion(p *WLANProfile) (*AndroidSuggestion, []Warning, error) {
+	w := &warnings{}
+	b, err := p.export(w)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !androidText(b.SSID) {
+		return nil, nil, fmt.Errorf("profile: %s: suggestions take an SSID that is text", b.Name)
+	}
+	s := &AndroidSuggestion{SSID: b.SSID, IsHiddenSSID: b.Hidden, IsInitialAutojoinEnabled: !b.Manual}
+	if len(b.Key) == 64 && b.Security.Personal() {
+		return nil, nil, fmt.Errorf("profile: %s: suggestions take a passphrase rather than a PSK", b.Name)
+	}
+	switch b.Security {
+	case Open:
+	case OWE:
+		s.IsEnhancedOpen = true
+	case WPA2Personal:
+		s.WPA2Passphrase = b.Key
+	case WPA3Personal:
+		s.WPA3Passphrase = b.Key
+	case WPA3Transition:
+		//A WPA2 suggestion of Android 11 and later connects to transition mode networks with WPA3.
+		s.WPA2Passphrase = b.Key
+	case WPA2Enterprise:
+		s.WPA2EnterpriseConfig = androidEnterprise(b.EAP, w)
+	case WPA3Enterprise:
+		s.WPA3EnterpriseConfig = androidEnterprise(b.EAP, w)
+	case WPA3Enterprise192:
+		s.WPA3Enterprise192Config = androidEnterprise(b.EAP, w)
+	default:
+		return nil, nil, fmt.Errorf("profile: %s: Android suggestions have no %v networks", b.Name, b.Security)
+	}
+	if b.Security.Enterprise() && b.EAP == nil {
+		return nil, nil, fmt.Errorf("profile: %s: enterprise suggestions need an EAP configuration", b.Name)
+	}
+	return s, w.list, nil
+}
+
+//ToAndroidConfiguration returns the WifiConfiguration of a profile, with warnings for the settings that it lacks.
+func ToAndroidConfiguration(p *WLANProfile) (*AndroidConfiguration, []Warning, error) {
+	w := &warnings{}
+	b, err := p.export(w)
+	if err != nil {
+		return nil, nil, err
+	}
+	c := &AndroidConfiguration{SSID: `"` + b.SSID + `"`, HiddenSSID: b.Hidden}
+	if !androidText(b.SSID) {
+		c.SSID = hex.EncodeToString([]byte(b.SSID))
+	}
+	if b.Manual {
+		w.add("connectionMode", "WifiConfiguration networks are joined automatically")
+	}
+	key := `"` + b.Key + `"`
+	if len(b.Key) == 64 {
+		key = b.Key
+	}
+	switch b.Security {
+	case Open:
+		c.AllowedKeyManagement = []string{"NONE"}
+	case WEP:
+		c.AllowedKeyManagement = []string{"NONE"}
+		if len(b.Key) == 10 || len(b.Key) == 26 {
+			c.WEPKeys = []string{b.Key}
+		} else {
+			c.WEPKeys = []string{`"` + b.Key + `"`}
+		}
+	case WPAPersonal:
+		c.AllowedKeyManagement, c.PreSharedKey = []string{"WPA_PSK"}, key
+		c.AllowedProtocols, c.AllowedPairwiseCiphers = []string{"WPA"}, []string{"TKIP"}
+	case WPA2Personal:
+		c.AllowedKeyManagement, c.PreSharedKey = []string{"WPA_PSK"}, key
+		c.AllowedProtocols, c.AllowedPairwiseCiphers = []string{"RSN"}, []string{"CCMP"}
+	case WPA3Personal:
+		c.AllowedKeyManagement, c.PreSharedKey, c.RequirePMF = []string{"SAE"}, key, true
+	case WPA3Transition:
+		w.add("transitionMode", "WifiConfiguration has no transition mode; the network is WPA2-Personal")
+		c.AllowedKeyManagement, c.PreSharedKey = []string{"WPA_PSK"}, key
+		c.AllowedProtocols, c.AllowedPairwiseCiphers = []string{"RSN"}, []string{"CCMP"}
+	case OWE:
+		c.AllowedKeyManagement, c.RequirePMF = []string{"OWE"}, true
+	case WPA2Enterprise:
+		c.AllowedKeyManagement = []string{"WPA_EAP", "IEEE8021X"}
+		c.AllowedProtocols, c.AllowedPairwiseCiphers = []string{"RSN"}, []string{"CCMP"}
+	case WPA3Enterprise:
+		c.AllowedKeyManagement, c.RequirePMF = []string{"WPA_EAP", "IEEE8021X"}, true
+		c.AllowedProtocols, c.AllowedPairwiseCiphers = []string{"RSN"}, []string{"CCMP"}
+	case WPA3Enterprise192:
+		c.AllowedKeyManagement, c.RequirePMF = []string{"SUITE_B_192"}, true
+		c.AllowedProtocols, c.AllowedPairwiseCiphers = []string{"RSN"}, []string{"GCMP_256"}
+	}
+	c.EnterpriseConfig = androidEnterprise(b.EAP, w)
+	return c, w.list, nil
+}
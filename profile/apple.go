@@ -0,0 +1,297 @@
+package profile
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+//The payload types of Apple configuration profiles.
+const (
+	PayloadConfiguration = "Configuration"
+	PayloadWiFi          = "com.apple.wifi.managed"
+)
+
+//The EncryptionType values of a Wi-Fi payload.
+const (
+	appleNone = "None"
+	appleWEP  = "WEP"
+	appleWPA  = "WPA"
+	appleWPA2 = "WPA2"
+	appleWPA3 = "WPA3"
+	appleAny  = "Any"
+)
+
+//MobileConfig describes the configuration profile that ToMobileConfig wraps the Wi-Fi payloads of profiles in.
+type MobileConfig struct {
+	//Identifier is the reverse DNS identifier of the configuration profile, such as com.example.wifi. The payloads
+	//are identified by it and their profile names, as are the UUIDs, so exporting the same profiles again
+	//replaces them on the devices.
+	Identifier   string
+	DisplayName  string
+	Organization string
+	Description  string
+}
+
+//payloadUUID returns the UUID of a payload of an identifier, in the uppercase that Apple writes.
+func payloadUUID(identifier string) string {
+	return strings.ToUpper(uuid(identifier))
+}
+
+//ToMobileConfig returns the configuration profile of Wi-Fi payloads of profiles, with warnings for the settings
+//that the payloads lack.
+func ToMobileConfig(c MobileConfig, profiles ...*WLANProfile) ([]byte, []Warning, error) {
+	if c.Identifier == "" {
+		return nil, nil, fmt.Errorf("profile: configuration profile has no identifier")
+	}
+	var (
+		content []interface{}
+		all     []Warning
+	)
+	for _, p := range profiles {
+		payload, warnings, err := applePayload(c.Identifier, p)
+		if err != nil {
+			return nil, nil, err
+		}
+		content = append(content, payload)
+		all = append(all, warnings...)
+	}
+	root := plistDict{
+		"PayloadContent":     content,
+		"PayloadDisplayName": c.DisplayName,
+		"PayloadIdentifier":  c.Identifier,
+		"PayloadType":        PayloadConfiguration,
+		"PayloadUUID":        payloadUUID(c.Identifier),
+		"PayloadVersion":     int64(1),
+	}
+	if root["PayloadDisplayName"] == "" {
+		root["PayloadDisplayName"] = c.Identifier
+	}
+	if c.Organization != "" {
+		root["PayloadOrganization"] = c.Organization
+	}
+	if c.Description != "" {
+		root["PayloadDescription"] = c.Description
+	}
+	var b bytes.Buffer
+	if err := writePlist(&b, root); err != nil {
+		return nil, nil, err
+	}
+	return b.Bytes(), all, nil
+}
+
+//applePayload returns the Wi-Fi payload of a profile.
+func applePayload(identifier string, p *WLANProfile) (plistDict, []Warning, error) {
+	w := &warnings{}
+	b, err := p.export(w)
+	if err != nil {
+		return nil, nil, err
+	}
+	id := identifier + ".wifi." + b.Name
+	payload := plistDict{
+		"AutoJoin":           !b.Manual,
+		"HIDDEN_NETWORK":     b.Hidden,
+		"PayloadDisplayName": b.Name,
+		"PayloadIdentifier":  id,
+		"PayloadType":        PayloadWiFi,
+		"PayloadUUID":        payloadUUID(id),
+		"PayloadVersion":     int64(1),
+		"SSID_STR":           b.SSID,
+	}
+	encryption := appleWPA2
+	switch b.Security {
+	case Open:
+		encryption = appleNone
+	case OWE:
+		encryption = appleNone
+		w.add("authentication", "Apple devices connect to open networks with OWE where the network offers it; the payload is an open network")
+	case WEP:
+		encryption = appleWEP
+	case WPAPersonal:
+		encryption = appleWPA
+	case WPA3Personal, WPA3Enterprise:
+		encryption = appleWPA3
+	case WPA3Transition:
+		w.add("transitionMode", "Apple devices connect to WPA2 networks with WPA3 where the network offers it; the payload is a WPA2 network")
+	case WPA3Enterprise192:
+		encryption = appleWPA3
+		w.add("authentication", "the payload is a WPA3-Enterprise network, which Apple devices connect to in 192-bit mode where the network requires it")
+	}
+	payload["EncryptionType"] = encryption
+	if b.Security.Personal() {
+		payload["Password"] = b.Key
+	}
+	if e := b.EAP; e != nil {
+		eap := plistDict{"AcceptEAPTypes": []interface{}{int64(e.Method)}}
+		if e.Method == EAPTTLS {
+			eap["TTLSInnerAuthentication"] = e.Phase2
+		}
+		if e.AnonymousIdentity != "" {
+			eap["OuterIdentity"] = e.AnonymousIdentity
+		}
+		if len(e.ServerNames) > 0 {
+			var names []interface{}
+			for _, n := range e.ServerNames {
+				names = append(names, n)
+			}
+			eap["TLSTrustedServerNames"] = names
+		}
+		if len(e.TrustedRootCAs) > 0 {
+			w.add("TrustedRootCA", "Apple devices trust CAs by certificate payloads rather than thumbprints; add them to the configuration profile")
+		}
+		payload["EAPClientConfiguration"] = eap
+	}
+	return payload, w.list, nil
+}
+
+//appleKnown are the keys of a Wi-Fi payload that conversions handle.
+var appleKnown = map[string]bool{
+	"AutoJoin": true, "HIDDEN_NETWORK": true, "PayloadDisplayName": true, "PayloadIdentifier": true, "PayloadType": true,
+	"PayloadUUID": true, "PayloadVersion": true, "PayloadDescription": true, "PayloadOrganization": true, "SSID_STR": true,
+	"EncryptionType": true, "Password": true, "EAPClientConfiguration": true, "IsHotspot": true, "ProxyType": true,
+}
+
+//FromMobileConfig returns the profiles of the Wi-Fi payloads of a configuration profile, with warnings for the
+//settings and payloads that profiles lack.
+func FromMobileConfig(data []byte) ([]*WLANProfile, []Warning, error) {
+	v, err := parsePlist(data)
+	if err != nil {
+		return nil, nil, err
+	}
+	root, ok := v.(plistDict)
+	if !ok || root.string("PayloadType") != PayloadConfiguration {
+		return nil, nil, fmt.Errorf("profile: property list is not a configuration profile")
+	}
+	var (
+		profiles []*WLANProfile
+		all      []Warning
+	)
+	for _, c := range root.array("PayloadContent") {
+		payload, ok := c.(plistDict)
+		if !ok {
+			return nil, nil, fmt.Errorf("profile: payload is not a dict")
+		}
+		if t := payload.string("PayloadType"); t != PayloadWiFi {
+			all = append(all, Warning{Network: payload.string("PayloadDisplayName"), Setting: "PayloadType",
+				Message: fmt.Sprintf("%s payloads are not converted", t)})
+			continue
+		}
+		p, warnings, err := fromApplePayload(payload)
+		if err != nil {
+			return nil, nil, err
+		}
+		profiles = append(profiles, p)
+		all = append(all, warnings...)
+	}
+	return profiles, all, nil
+}
+
+//fromApplePayload returns the profile of a Wi-Fi payload.
+func fromApplePayload(payload plistDict) (*WLANProfile, []Warning, error) {
+	b := Builder{
+		Name:   payload.string("PayloadDisplayName"),
+		SSID:   payload.string("SSID_STR"),
+		Hidden: payload.bool("HIDDEN_NETWORK", false),
+		Manual: !payload.bool("AutoJoin", true),
+	}
+	w := &warnings{network: b.Name}
+	if w.network == "" {
+		w.network = b.SSID
+	}
+	if payload.bool("IsHotspot", false) {
+		return nil, nil, fmt.Errorf("profile: payload %s: Hotspot 2.0 payloads are not supported", w.network)
+	}
+	eap := payload.dict("EAPClientConfiguration")
+	switch encryption := payload.string("EncryptionType"); {
+	case encryption == appleNone || encryption == "":
+		b.Security = Open
+	case encryption == appleWEP:
+		b.Security = WEP
+	case eap != nil && encryption == appleWPA3:
+		b.Security = WPA3Enterprise
+	case eap != nil && (encryption == appleWPA2 || encryption == appleWPA || encryption == appleAny):
+		b.Security = WPA2Enterprise
+	case encryption == appleWPA:
+		b.Security = WPAPersonal
+	case encryption == appleWPA2:
+		b.Security = WPA2Personal
+	case encryption == appleWPA3:
+		b.Security = WPA3Personal
+	case encryption == appleAny:
+		b.Security = WPA2Personal
+		w.add("EncryptionType", "any encryption is converted as WPA2-Personal")
+	default:
+		return nil, nil, fmt.Errorf("profile: payload %s: unknown EncryptionType %s", w.network, encryption)
+	}
+	if b.Security.Personal() {
+		b.Key = payload.string("Password")
+	}
+	if eap != nil {
+		e, err := appleEAP(eap, w)
+		if err != nil {
+			return nil, nil, err
+		}
+		b.EAP = e
+	}
+	for _, key := range payload.keys() {
+		if !appleKnown[key] {
+			w.add(key, "profiles have no such setting")
+		}
+	}
+	if t := payload.string("ProxyType"); t != "" && t != "None" {
+		w.add("ProxyType", "proxy settings are not stored in profiles")
+	}
+	p, err := b.Build()
+	if err != nil {
+		return nil, nil, err
+	}
+	return p, w.list, nil
+}
+
+//appleEAP returns the EAP configuration of an EAPClientConfiguration dict.
+func appleEAP(eap plistDict, w *warnings) (*EAP, error) {
+	types := eap.array("AcceptEAPTypes")
+	if len(types) == 0 {
+		return nil, fmt.Errorf("profile: EAPClientConfiguration has no AcceptEAPTypes")
+	}
+	if len(types) > 1 {
+		w.add("AcceptEAPTypes", "only the first of %d EAP types is converted", len(types))
+	}
+	t, _ := types[0].(int64)
+	e := &EAP{Method: EAPMethod(t)}
+	switch e.Method {
+	case EAPPEAP:
+		e.Phase2 = Phase2MSCHAPv2
+	case EAPTTLS:
+		switch inner := eap.string("TTLSInnerAuthentication"); inner {
+		case Phase2PAP, Phase2CHAP, Phase2MSCHAP, Phase2MSCHAPv2:
+			e.Phase2 = inner
+		case "":
+			//MSCHAPv2 is the default of Apple devices.
+			e.Phase2 = Phase2MSCHAPv2
+		default:
+			return nil, fmt.Errorf("profile: unsupported TTLS inner authentication %s", inner)
+		}
+	case EAPTLS:
+	default:
+		return nil, fmt.Errorf("profile: unsupported EAP method %v", e.Method)
+	}
+	if id := eap.string("OuterIdentity"); id != "" {
+		if e.Method == EAPTTLS {
+			e.AnonymousIdentity = id
+		} else {
+			w.add("OuterIdentity", "only TTLS profiles have an anonymous identity")
+		}
+	}
+	e.ServerNames = eap.strings("TLSTrustedServerNames")
+	for _, key := range eap.keys() {
+		switch key {
+		case "AcceptEAPTypes", "TTLSInnerAuthentication", "OuterIdentity", "TLSTrustedServerNames":
+		case "UserName", "UserPassword", "PayloadCertificateAnchorUUID":
+			w.add("EAPClientConfiguration."+key, "credentials and certificates are not stored in profiles")
+		default:
+			w.add("EAPClientConfiguration."+key, "profiles have no such setting")
+		}
+	}
+	return e, nil
+}
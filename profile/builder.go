@@ -0,0 +1,193 @@
+package profile
+
+import (
+	"encoding/hex"
+	"fmt"
+)
+
+//SecurityType is a kind of network security, which sets the authentication and encryption of a profile.
+type SecurityType int
+
+const (
+	Open SecurityType = iota
+	WEP
+	WPAPersonal
+	WPA2Personal
+	WPA3Personal
+	//WPA3Transition is WPA3-Personal that falls back to WPA2-Personal on the BSSes of a transition mode network.
+	WPA3Transition
+	OWE
+	WPA2Enterprise
+	WPA3Enterprise
+	WPA3Enterprise192
+)
+
+//securities are the authentication and encryption of each SecurityType.
+var securities = []struct {
+	name                       string
+	authentication, encryption string
+	transition, oneX           bool
+}{
+	Open:              {"Open", AuthOpen, EncryptionNone, false, false},
+	WEP:               {"WEP", AuthOpen, EncryptionWEP, false, false},
+	WPAPersonal:       {"WPA-Personal", AuthWPAPSK, EncryptionTKIP, false, false},
+	WPA2Personal:      {"WPA2-Personal", AuthWPA2PSK, EncryptionAES, false, false},
+	WPA3Personal:      {"WPA3-Personal", AuthWPA3SAE, EncryptionAES, false, false},
+	WPA3Transition:    {"WPA3-Personal transition", AuthWPA3SAE, EncryptionAES, true, false},
+	OWE:               {"OWE", AuthOWE, EncryptionAES, false, false},
+	WPA2Enterprise:    {"WPA2-Enterprise", AuthWPA2, EncryptionAES, false, true},
+	WPA3Enterprise:    {"WPA3-Enterprise", AuthWPA3ENT, EncryptionAES, false, true},
+	WPA3Enterprise192: {"WPA3-Enterprise-192", AuthWPA3ENT192, EncryptionGCMP256, false, true},
+}
+
+func (s SecurityType) String() string {
+	if s >= 0 && int(s) < len(securities) {
+		return securities[s].name
+	}
+	return fmt.Sprintf("SecurityType(%d)", int(s))
+}
+
+//Personal reports whether the security authenticates with a shared key.
+func (s SecurityType) Personal() bool {
+	switch s {
+	case WEP, WPAPersonal, WPA2Personal, WPA3Personal, WPA3Transition:
+		return true
+	}
+	return false
+}
+
+//Enterprise reports whether the security authenticates with 802.1X.
+func (s SecurityType) Enterprise() bool {
+	return s >= 0 && int(s) < len(securities) && securities[s].oneX
+}
+
+//Builder describes a profile by its network rather than by its XML elements.
+type Builder struct {
+	//Name is the name of the profile; empty uses the SSID.
+	Name     string
+	SSID     string
+	Hidden   bool
+	Security SecurityType
+	//Key is the passphrase of a personal network, or its 64 hexadecimal digit PSK; the WEP key of a WEP network.
+	Key string
+	//Manual keeps the interface from connecting to the network automatically.
+	Manual bool
+	//EAP is the 802.1X authentication of an enterprise network; nil leaves it to the interface to ask for.
+	EAP *EAP
+}
+
+//isHex reports whether s is made of hexadecimal digits.
+func isHex(s string) bool {
+	_, err := hex.DecodeString(s)
+	return err == nil
+}
+
+//sharedKey returns the sharedKey element of the key of the builder.
+func (b *Builder) sharedKey() (*SharedKey, error) {
+	switch n := len(b.Key); {
+	case !b.Security.Personal():
+		if b.Key != "" {
+			return nil, fmt.Errorf("profile: %v takes no key", b.Security)
+		}
+		return nil, nil
+	case b.Security == WEP:
+		//WEP-40 and WEP-104 keys, as 5 or 13 characters or 10 or 26 hexadecimal digits.
+		if n != 5 && n != 13 && !((n == 10 || n == 26) && isHex(b.Key)) {
+			return nil, fmt.Errorf("profile: invalid WEP key of %d characters", n)
+		}
+		return &SharedKey{KeyType: NetworkKey, KeyMaterial: b.Key}, nil
+	case n == 64 && isHex(b.Key):
+		return &SharedKey{KeyType: NetworkKey, KeyMaterial: b.Key}, nil
+	case n >= 8 && n <= 63:
+		return &SharedKey{KeyType: PassPhrase, KeyMaterial: b.Key}, nil
+	}
+	return nil, fmt.Errorf("profile: invalid passphrase of %d characters, want 8 to 63", len(b.Key))
+}
+
+//Build returns the profile of the network.
+func (b Builder) Build() (*WLANProfile, error) {
+	if b.SSID == "" || len(b.SSID) > 32 {
+		return nil, fmt.Errorf("profile: invalid SSID %q", b.SSID)
+	}
+	if b.Security < 0 || int(b.Security) >= len(securities) {
+		return nil, fmt.Errorf("profile: unknown security %v", b.Security)
+	}
+	key, err := b.sharedKey()
+	if err != nil {
+		return nil, err
+	}
+	var oneX *OneX
+	if b.EAP != nil {
+		if !b.Security.Enterprise() {
+			return nil, fmt.Errorf("profile: %v takes no EAP configuration", b.Security)
+		}
+		if oneX, err = b.EAP.oneX(); err != nil {
+			return nil, err
+		}
+	}
+	s := securities[b.Security]
+	p := &WLANProfile{
+		Name:           b.Name,
+		SSIDConfig:     SSIDConfig{SSIDs: []SSID{NewSSID([]byte(b.SSID))}, NonBroadcast: b.Hidden},
+		ConnectionType: ESS,
+		ConnectionMode: Auto,
+		MSM: MSM{Security: Security{
+			AuthEncryption: AuthEncryption{Authentication: s.authentication, Encryption: s.encryption, UseOneX: s.oneX},
+			SharedKey:      key,
+			OneX:           oneX,
+		}},
+	}
+	if p.Name == "" {
+		p.Name = b.SSID
+	}
+	if b.Manual {
+		p.ConnectionMode = Manual
+	}
+	if s.transition {
+		transition := true
+		p.MSM.Security.AuthEncryption.TransitionMode = &transition
+	}
+	return p, nil
+}
+
+//Builder returns the builder of the profile. Passpoint profiles, profiles with a protected key, and profiles whose
+//authentication and encryption match no SecurityType have no builder.
+func (p *WLANProfile) Builder() (Builder, error) {
+	if p.Hotspot2 != nil {
+		return Builder{}, fmt.Errorf("profile: profile %q is a Passpoint profile", p.Name)
+	}
+	ssid, err := p.SSID()
+	if err != nil {
+		return Builder{}, err
+	}
+	b := Builder{
+		Name:   p.Name,
+		SSID:   string(ssid),
+		Hidden: p.SSIDConfig.NonBroadcast,
+		Manual: p.ConnectionMode == Manual,
+	}
+	ae := p.MSM.Security.AuthEncryption
+	transition := ae.TransitionMode != nil && *ae.TransitionMode
+	found := false
+	for n, s := range securities {
+		if s.authentication == ae.Authentication && s.encryption == ae.Encryption && s.transition == transition {
+			b.Security, found = SecurityType(n), true
+			break
+		}
+	}
+	if !found {
+		return b, fmt.Errorf("profile: unsupported authentication %s with encryption %s", ae.Authentication, ae.Encryption)
+	}
+	if k := p.MSM.Security.SharedKey; k != nil {
+		if k.Protected {
+			return b, fmt.Errorf("profile: the key of profile %q is protected", p.Name)
+		}
+		b.Key = k.KeyMaterial
+	}
+	if o := p.MSM.Security.OneX; o != nil && b.Security.Enterprise() {
+		if b.EAP, err = o.eap(); err != nil {
+			return b, err
+		}
+	}
+	return b, nil
+}
@@ -0,0 +1,50 @@
+package profile
+
+import "fmt"
+
+//Warning is a setting that a conversion between a profile and another format dropped or changed, as the other
+//format has no way to express it.
+type Warning struct {
+	//Network is the name of the profile or network of the setting.
+	Network string
+	//Setting is the name of the setting in the format it was converted from.
+	Setting string
+	Message string
+}
+
+func (w Warning) String() string {
+	return fmt.Sprintf("%s: %s: %s", w.Network, w.Setting, w.Message)
+}
+
+//warnings collects the warnings of the conversion of a network.
+type warnings struct {
+	network string
+	list    []Warning
+}
+
+func (w *warnings) add(setting, format string, args ...interface{}) {
+	w.list = append(w.list, Warning{Network: w.network, Setting: setting, Message: fmt.Sprintf(format, args...)})
+}
+
+//export returns the builder of a profile that is converted to another format, with warnings for the settings the
+//builder does not hold.
+func (p *WLANProfile) export(w *warnings) (Builder, error) {
+	w.network = p.Name
+	b, err := p.Builder()
+	if err != nil {
+		return b, err
+	}
+	if len(p.SSIDConfig.SSIDs) > 1 {
+		w.add("SSIDConfig", "only the first of %d SSIDs is converted", len(p.SSIDConfig.SSIDs))
+	}
+	if p.ConnectionType == IBSS {
+		w.add("connectionType", "ad hoc profiles are converted as infrastructure networks")
+	}
+	if p.AutoSwitch {
+		w.add("autoSwitch", "switching to more preferred networks is not converted")
+	}
+	if b.Security.Enterprise() && b.EAP == nil {
+		w.add("OneX", "the profile has no EAP configuration")
+	}
+	return b, nil
+}
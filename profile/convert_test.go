@@ -0,0 +1,422 @@
+package profile
+
+import (
+	"encoding/json"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+//settings returns the settings of warnings.
+func settings(warnings []Warning) []string {
+	var s []string
+	for _, w := range warnings {
+		s = append(s, w.Setting)
+	}
+	return s
+}
+
+//builders are networks of every security, converted to each format and back.
+var builders = []Builder{
+	{Name: "cafe", SSID: "cafe", Security: Open, Hidden: true},
+	{Name: "lab", SSID: "lab", Security: WEP, Key: "0123456789"},
+	{Name: "lab ascii", SSID: "lab", Security: WEP, Key: "abcde"},
+	{Name: "old", SSID: "old", Security: WPAPersonal, Key: "correct horse"},
+	{Name: "home", SSID: "home", Security: WPA2Personal, Key: "pass word; with\\ escapes "},
+	{Name: "psk", SSID: "psk", Security: WPA2Personal, Key: strings.Repeat("0f", 32), Manual: true},
+	{Name: "home6", SSID: "home6", Security: WPA3Personal, Key: "correct horse"},
+	{Name: "airport", SSID: "airport", Security: OWE},
+	{Name: "binary", SSID: "\x00a;b\xff", Security: Open},
+	{Name: "corp", SSID: "corp", Security: WPA2Enterprise, EAP: &EAP{
+		Method: EAPPEAP, Phase2: Phase2MSCHAPv2, ServerNames: []string{"radius.example.com", "nps.example.com"},
+	}},
+	{Name: "eduroam", SSID: "eduroam", Security: WPA3Enterprise, EAP: &EAP{
+		Method: EAPTTLS, Phase2: Phase2PAP, AnonymousIdentity: "anonymous@example.edu",
+	}},
+	{Name: "ttls", SSID: "ttls", Security: WPA2Enterprise, EAP: &EAP{Method: EAPTTLS, Phase2: Phase2MSCHAP}},
+	{Name: "lab 192", SSID: "lab", Security: WPA3Enterprise192, EAP: &EAP{Method: EAPTLS}},
+}
+
+//roundTrip checks that a builder comes back from a profile converted to another format.
+func roundTrip(t *testing.T, format string, b Builder, convert func(*WLANProfile) (*WLANProfile, []Warning, error)) {
+	t.Helper()
+	p, err := b.Build()
+	if err != nil {
+		t.Fatalf("%s: %v", b.Name, err)
+	}
+	back, warnings, err := convert(p)
+	if err != nil {
+		t.Errorf("%s %s: %v", format, b.Name, err)
+		return
+	}
+	got, err := back.Builder()
+	if err != nil || !reflect.DeepEqual(got, b) || len(warnings) != 0 {
+		t.Errorf("%s %s: %+v %v %v", format, b.Name, got, warnings, err)
+	}
+}
+
+func TestSupplicantRoundTrip(t *testing.T) {
+	for _, b := range builders {
+		roundTrip(t, "wpa_supplicant", b, func(p *WLANProfile) (*WLANProfile, []Warning, error) {
+			n, warnings, err := ToSupplicant(p)
+			if err != nil || len(warnings) != 0 {
+				return nil, warnings, err
+			}
+			networks, err := ParseSupplicant([]byte(n.String()))
+			if err != nil || len(networks) != 1 {
+				t.Fatalf("%s: %v %v", n, networks, err)
+			}
+			return FromSupplicant(networks[0])
+		})
+	}
+	roundTrip(t, "wpa_supplicant", Builder{Name: "mixed", SSID: "mixed", Security: WPA3Transition, Key: "correct horse"},
+		func(p *WLANProfile) (*WLANProfile, []Warning, error) {
+			n, _, _ := ToSupplicant(p)
+			return FromSupplicant(n)
+		})
+}
+
+func TestKeyfileRoundTrip(t *testing.T) {
+	for _, b := range builders {
+		roundTrip(t, "keyfile", b, func(p *WLANProfile) (*WLANProfile, []Warning, error) {
+			k, warnings, err := ToKeyfile(p)
+			if err != nil || len(warnings) != 0 {
+				return nil, warnings, err
+			}
+			k, err = ParseKeyfile([]byte(k.String()))
+			if err != nil {
+				t.Fatal(err)
+			}
+			return FromKeyfile(k)
+		})
+	}
+
+	//NetworkManager has no transition mode; the network comes back as WPA2-Personal.
+	p, _ := Builder{SSID: "mixed", Security: WPA3Transition, Key: "correct horse"}.Build()
+	k, warnings, err := ToKeyfile(p)
+	if err != nil || len(warnings) != 1 || warnings[0].Setting != "transitionMode" {
+		t.Errorf("transition mode: %v %v", warnings, err)
+	}
+	if back, _, err := FromKeyfile(k); err != nil || back.MSM.Security.AuthEncryption.Authentication != AuthWPA2PSK {
+		t.Errorf("transition mode back: %+v %v", back, err)
+	}
+}
+
+func TestFromSupplicant(t *testing.T) {
+	data, err := os.ReadFile("testdata/wpa_supplicant.conf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	networks, err := ParseSupplicant(data)
+	if err != nil || len(networks) != 7 {
+		t.Fatalf("ParseSupplicant: %d networks, %v", len(networks), err)
+	}
+	for i, want := range []struct {
+		b        Builder
+		warnings string
+	}{
+		{Builder{Name: "home", SSID: "home", Security: WPA2Personal, Key: "correct horse"}, ""},
+		{Builder{Name: "home6", SSID: "home6", Security: WPA3Personal, Key: "correct horse"}, ""},
+		{Builder{Name: `mixed "up"`, SSID: `mixed "up"`, Security: WPA3Transition, Hidden: true,
+			Key: "a3c7f8e2d1b0a9f8e7d6c5b4a3928170f6e5d4c3b2a19080f7e6d5c4b3a29180"}, ""},
+		{Builder{Name: "cafe", SSID: "cafe", Security: Open, Manual: true}, "priority"},
+		{Builder{Name: "corp", SSID: "corp", Security: WPA2Enterprise, EAP: &EAP{
+			Method: EAPPEAP, Phase2: Phase2MSCHAPv2, ServerNames: []string{"radius.example.com", "nps.example.com"},
+		}}, "identity password ca_cert"},
+		{Builder{Name: "eduroam", SSID: "eduroam", Security: WPA3Enterprise, EAP: &EAP{
+			Method: EAPTTLS, Phase2: Phase2PAP, AnonymousIdentity: "anonymous@example.edu",
+		}}, ""},
+		{Builder{Name: "lab", SSID: "lab", Security: WPA3Enterprise192, EAP: &EAP{Method: EAPTLS}}, "client_cert private_key"},
+	} {
+		p, warnings, err := FromSupplicant(networks[i])
+		if err != nil {
+			t.Errorf("network %d: %v", i, err)
+			continue
+		}
+		b, err := p.Builder()
+		if err != nil || !reflect.DeepEqual(b, want.b) {
+			t.Errorf("network %d: %+v %v, want %+v", i, b, err, want.b)
+		}
+		if s := strings.Join(settings(warnings), " "); s != want.warnings {
+			t.Errorf("network %d: warnings %v, want %s", i, warnings, want.warnings)
+		}
+	}
+
+	for _, block := range []string{
+		"network={\n\tkey_mgmt=NONE\n}",
+		"network={\n\tssid=\"adhoc\"\n\tmode=1\n\tkey_mgmt=NONE\n}",
+		"network={\n\tssid=\"wep\"\n\tkey_mgmt=IEEE8021X\n}",
+		"network={\n\tssid=\"corp\"\n\tkey_mgmt=WPA-EAP\n\teap=FAST\n}",
+		"network={\n\tssid=\"corp\"\n\tkey_mgmt=WPA-EAP\n\teap=PEAP\n\tphase2=\"auth=GTC\"\n}",
+		"network={\n\tssid=\"home\"\n\tpsk=\"short\"\n}",
+	} {
+		networks, err := ParseSupplicant([]byte(block))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if p, _, err := FromSupplicant(networks[0]); err == nil {
+			t.Errorf("converted %s as %+v", block, p)
+		}
+	}
+	for _, text := range []string{"network={\n\tssid=\"a\"\n", "}\n", "network={\nnetwork={\n}\n}\n", "network={\n\tssid\n}\n"} {
+		if _, err := ParseSupplicant([]byte(text)); err == nil {
+			t.Errorf("parsed %q", text)
+		}
+	}
+}
+
+func TestToSupplicant(t *testing.T) {
+	p, _ := Builder{Name: "corp", SSID: "corp", Security: WPA2Enterprise, Manual: true, EAP: &EAP{
+		Method: EAPPEAP, Phase2: Phase2MSCHAPv2, ServerNames: []string{"radius.example.com"},
+		TrustedRootCAs: []string{"a8985d3a65e5e5c4b2d7d66d40c6dd2fb19c5436"},
+	}}.Build()
+	p.AutoSwitch = true
+	n, warnings, err := ToSupplicant(p)
+	const want = `network={
+	ssid="corp"
+	key_mgmt=WPA-EAP
+	proto=RSN
+	pairwise=CCMP
+	eap=PEAP
+	phase2="auth=MSCHAPV2"
+	domain_suffix_match="radius.example.com"
+	disabled=1
+}
+`
+	if err != nil || n.String() != want {
+		t.Errorf("ToSupplicant:\n%s%v", n, err)
+	}
+	if s := strings.Join(settings(warnings), " "); s != "autoSwitch TrustedRootCA" {
+		t.Errorf("warnings %v", warnings)
+	}
+
+	p, _ = Builder{SSID: "home6", Security: WPA3Personal, Key: strings.Repeat("0f", 32)}.Build()
+	if _, _, err := ToSupplicant(p); err == nil {
+		t.Error("SAE network with a PSK")
+	}
+}
+
+func TestFromKeyfile(t *testing.T) {
+	for _, c := range []struct {
+		file     string
+		b        Builder
+		warnings string
+	}{
+		{"testdata/home.nmconnection", Builder{Name: "Home Wi-Fi", SSID: "home", Security: WPA2Personal, Key: "correct horse", Hidden: true, Manual: true},
+			"connection.interface-name wifi.cloned-mac-address ipv4.method"},
+		{"testdata/eduroam.nmconnection", Builder{Name: "eduroam", SSID: "eduroam", Security: WPA3Enterprise, EAP: &EAP{
+			Method: EAPTTLS, Phase2: Phase2MSCHAPv2, AnonymousIdentity: "anonymous@example.edu", ServerNames: []string{"radius.example.edu"},
+		}}, "802-1x.eap 802-1x.identity 802-1x.ca-cert"},
+	} {
+		data, err := os.ReadFile(c.file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		k, err := ParseKeyfile(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		p, warnings, err := FromKeyfile(k)
+		if err != nil {
+			t.Errorf("%s: %v", c.file, err)
+			continue
+		}
+		if b, err := p.Builder(); err != nil || !reflect.DeepEqual(b, c.b) {
+			t.Errorf("%s: %+v %v, want %+v", c.file, b, err, c.b)
+		}
+		if s := strings.Join(settings(warnings), " "); s != c.warnings {
+			t.Errorf("%s: warnings %v, want %s", c.file, warnings, c.warnings)
+		}
+	}
+
+	for _, text := range []string{
+		"[connection]\nid=wired\ntype=ethernet\n",
+		"[connection]\nid=hotspot\ntype=wifi\n[wifi]\nssid=hotspot\nmode=ap\n",
+		"[connection]\nid=wep\ntype=wifi\n[wifi]\nssid=wep\n[wifi-security]\nkey-mgmt=none\nwep-key-type=2\nwep-key0=passphrase\n",
+		"[connection]\nid=dynamic\ntype=wifi\n[wifi]\nssid=dynamic\n[wifi-security]\nkey-mgmt=ieee8021x\n",
+		"[connection]\nid=leap\ntype=wifi\n[wifi]\nssid=leap\n[wifi-security]\nkey-mgmt=wpa-eap\n[802-1x]\neap=leap;\n",
+	} {
+		k, err := ParseKeyfile([]byte(text))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if p, _, err := FromKeyfile(k); err == nil {
+			t.Errorf("converted %q as %+v", text, p)
+		}
+	}
+	if _, err := ParseKeyfile([]byte("id=home\n")); err == nil {
+		t.Error("parsed a key outside a section")
+	}
+}
+
+func TestToKeyfile(t *testing.T) {
+	p, _ := Builder{Name: "Home Wi-Fi", SSID: "home", Security: WPA3Personal, Key: "correct horse", Hidden: true}.Build()
+	k, warnings, err := ToKeyfile(p)
+	const want = `[connection]
+id=Home Wi-Fi
+uuid=` + "%s" + `
+type=wifi
+
+[wifi]
+mode=infrastructure
+ssid=home
+hidden=true
+
+[wifi-security]
+key-mgmt=sae
+psk=correct horse
+
+[ipv4]
+method=auto
+
+[ipv6]
+addr-gen-mode=default
+method=auto
+`
+	if err != nil || len(warnings) != 0 || k.String() != strings.Replace(want, "%s", uuid("Home Wi-Fi"), 1) {
+		t.Errorf("ToKeyfile:\n%s%v %v", k, warnings, err)
+	}
+	if id, _ := k.Get("connection", "uuid"); len(id) != 36 || id[14] != '5' {
+		t.Errorf("uuid %s", id)
+	}
+}
+
+func TestMobileConfigRoundTrip(t *testing.T) {
+	for _, b := range builders {
+		switch {
+		case b.Security == OWE || b.Security == WPA3Enterprise192:
+			//Apple payloads have no OWE or 192-bit mode networks.
+			continue
+		case b.SSID == "\x00a;b\xff":
+			//XML cannot hold the SSID.
+			continue
+		}
+		roundTrip(t, "mobileconfig", b, func(p *WLANProfile) (*WLANProfile, []Warning, error) {
+			data, warnings, err := ToMobileConfig(MobileConfig{Identifier: "com.example.wifi"}, p)
+			if err != nil || len(warnings) != 0 {
+				return nil, warnings, err
+			}
+			profiles, warnings, err := FromMobileConfig(data)
+			if err != nil || len(profiles) != 1 {
+				t.Fatalf("%s: %v %v", data, profiles, err)
+			}
+			return profiles[0], warnings, nil
+		})
+	}
+}
+
+func TestFromMobileConfig(t *testing.T) {
+	data, err := os.ReadFile("testdata/example.mobileconfig")
+	if err != nil {
+		t.Fatal(err)
+	}
+	profiles, warnings, err := FromMobileConfig(data)
+	if err != nil || len(profiles) != 3 {
+		t.Fatalf("FromMobileConfig: %d profiles, %v", len(profiles), err)
+	}
+	for i, want := range []Builder{
+		{Name: "Corporate", SSID: "corp", Security: WPA2Enterprise, EAP: &EAP{
+			Method: EAPPEAP, Phase2: Phase2MSCHAPv2, ServerNames: []string{"radius.example.com", "nps.example.com"},
+		}},
+		{Name: "eduroam", SSID: "eduroam", Security: WPA3Enterprise, Manual: true, EAP: &EAP{
+			Method: EAPTTLS, Phase2: Phase2PAP, AnonymousIdentity: "anonymous@example.edu",
+		}},
+		{Name: "Home", SSID: "home", Security: WPA2Personal, Key: "correct & horse", Hidden: true},
+	} {
+		if b, err := profiles[i].Builder(); err != nil || !reflect.DeepEqual(b, want) {
+			t.Errorf("profile %d: %+v %v, want %+v", i, b, err, want)
+		}
+	}
+	const want = "PayloadType EAPClientConfiguration.PayloadCertificateAnchorUUID EAPClientConfiguration.UserName " +
+		"DisableAssociationMACRandomization ProxyType"
+	if s := strings.Join(settings(warnings), " "); s != want {
+		t.Errorf("warnings %v", warnings)
+	}
+
+	for _, text := range []string{
+		"bplist00",
+		"0\x82\x05\x10\x06\x09*\x86H\x86\xf7\r\x01\x07\x02",
+		"<plist><dict><key>PayloadType</key></dict></plist>",
+		"<plist><array/></plist>",
+		`<plist><dict><key>PayloadType</key><string>Configuration</string><key>PayloadContent</key><array><dict>
+			<key>PayloadType</key><string>com.apple.wifi.managed</string><key>SSID_STR</key><string>hs</string>
+			<key>IsHotspot</key><true/></dict></array></dict></plist>`,
+		`<plist><dict><key>PayloadType</key><string>Configuration</string><key>PayloadContent</key><array><dict>
+			<key>PayloadType</key><string>com.apple.wifi.managed</string><key>SSID_STR</key><string>corp</string>
+			<key>EncryptionType</key><string>WPA2</string><key>EAPClientConfiguration</key><dict>
+			<key>AcceptEAPTypes</key><array><integer>43</integer></array></dict></dict></array></dict></plist>`,
+	} {
+		if profiles, _, err := FromMobileConfig([]byte(text)); err == nil {
+			t.Errorf("converted %q as %v", text, profiles)
+		}
+	}
+}
+
+func TestToMobileConfig(t *testing.T) {
+	want, err := os.ReadFile("testdata/export.mobileconfig")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var profiles []*WLANProfile
+	for _, b := range builders[9:11] {
+		p, _ := b.Build()
+		profiles = append(profiles, p)
+	}
+	p, _ := Builder{Name: "mixed", SSID: "mixed", Security: WPA3Transition, Key: "correct horse", Manual: true}.Build()
+	profiles = append(profiles, p)
+	data, warnings, err := ToMobileConfig(MobileConfig{
+		Identifier: "com.example", DisplayName: "Example Wi-Fi", Organization: "Example",
+	}, profiles...)
+	if err != nil || string(data) != string(want) {
+		t.Errorf("ToMobileConfig:\n%s%v", data, err)
+	}
+	if s := strings.Join(settings(warnings), " "); s != "transitionMode" {
+		t.Errorf("warnings %v", warnings)
+	}
+	if _, _, err := ToMobileConfig(MobileConfig{}, p); err == nil {
+		t.Error("configuration profile without an identifier")
+	}
+}
+
+func TestToAndroid(t *testing.T) {
+	want, err := os.ReadFile("testdata/android.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var (
+		networks []interface{}
+		all      []string
+	)
+	for _, b := range builders {
+		p, _ := b.Build()
+		c, warnings, err := ToAndroidConfiguration(p)
+		if err != nil {
+			t.Fatalf("%s: %v", b.Name, err)
+		}
+		all = append(all, settings(warnings)...)
+		s, warnings, err := ToAndroidSuggestion(p)
+		switch {
+		case b.Security == WEP || b.Security == WPAPersonal || len(b.Key) == 64 || !androidText(b.SSID):
+			if err == nil {
+				t.Errorf("%s: suggestion %+v", b.Name, s)
+			}
+			networks = append(networks, c)
+			continue
+		case err != nil:
+			t.Fatalf("%s: %v", b.Name, err)
+		}
+		all = append(all, settings(warnings)...)
+		networks = append(networks, c, s)
+	}
+	data, err := json.MarshalIndent(networks, "", "\t")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data)+"\n" != string(want) {
+		t.Errorf("Android networks:\n%s", data)
+	}
+	if s := strings.Join(all, " "); s != "connectionMode" {
+		t.Errorf("warnings %v", all)
+	}
+}
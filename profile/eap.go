@@ -0,0 +1,325 @@
+package profile
+
+import (
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"strings"
+)
+
+//The namespaces of the 802.1X elements of a profile.
+const (
+	NamespaceOneX          = "http://www.microsoft.com/networking/OneX/v1"
+	NamespaceEapHostConfig = "http://www.microsoft.com/provisioning/EapHostConfig"
+	NamespaceEapCommon     = "http://www.microsoft.com/provisioning/EapCommon"
+	NamespaceBaseEap       = "http://www.microsoft.com/provisioning/BaseEapConnectionPropertiesV1"
+	NamespaceMsPeap        = "http://www.microsoft.com/provisioning/MsPeapConnectionPropertiesV1"
+	NamespaceMsChapV2      = "http://www.microsoft.com/provisioning/MsChapV2ConnectionPropertiesV1"
+	NamespaceEapTls        = "http://www.microsoft.com/provisioning/EapTlsConnectionPropertiesV1"
+	NamespaceEapTtls       = "http://www.microsoft.com/provisioning/EapTtlsConnectionPropertiesV1"
+)
+
+//OneX is the OneX element of the security of an 802.1X profile.
+type OneX struct {
+	XMLName       xml.Name  `xml:"http://www.microsoft.com/networking/OneX/v1 OneX"`
+	CacheUserData *bool     `xml:"cacheUserData,omitempty"`
+	AuthMode      string    `xml:"authMode,omitempty"`
+	EAPConfig     EAPConfig `xml:"EAPConfig"`
+}
+
+//EAPConfig holds the EapHostConfig of the EAP method.
+type EAPConfig struct {
+	EapHostConfig EapHostConfig `xml:"http://www.microsoft.com/provisioning/EapHostConfig EapHostConfig"`
+}
+
+//EapHostConfig is the EAP method and its configuration.
+type EapHostConfig struct {
+	EapMethod EapMethod     `xml:"EapMethod"`
+	Config    EapHostMethod `xml:"http://www.microsoft.com/provisioning/EapHostConfig Config"`
+}
+
+//EapMethod identifies an EAP method by its type and the author of its EapHost method.
+type EapMethod struct {
+	Type       EAPMethod `xml:"http://www.microsoft.com/provisioning/EapCommon Type"`
+	VendorID   uint32    `xml:"http://www.microsoft.com/provisioning/EapCommon VendorId"`
+	VendorType uint32    `xml:"http://www.microsoft.com/provisioning/EapCommon VendorType"`
+	AuthorID   uint32    `xml:"http://www.microsoft.com/provisioning/EapCommon AuthorId"`
+}
+
+//EapHostMethod is the configuration of the EAP method: an Eap element for the methods of Windows, an EapTtls
+//element for TTLS.
+type EapHostMethod struct {
+	Eap     *Eap     `xml:"http://www.microsoft.com/provisioning/BaseEapConnectionPropertiesV1 Eap,omitempty"`
+	EapTtls *EapTtls `xml:"http://www.microsoft.com/provisioning/EapTtlsConnectionPropertiesV1 EapTtls,omitempty"`
+}
+
+//Eap is the configuration of a method, and of the inner method of PEAP.
+type Eap struct {
+	Type    EAPMethod `xml:"Type"`
+	EapType *EapType  `xml:"EapType"`
+}
+
+//EapType holds the properties of the PEAP, TLS and MSCHAPv2 methods, in the namespace of the method.
+type EapType struct {
+	XMLName                xml.Name
+	CredentialsSource      *CredentialsSource `xml:"CredentialsSource,omitempty"`
+	ServerValidation       *ServerValidation  `xml:"ServerValidation,omitempty"`
+	DifferentUsername      *bool              `xml:"DifferentUsername,omitempty"`
+	FastReconnect          *bool              `xml:"FastReconnect,omitempty"`
+	InnerEapOptional       *bool              `xml:"InnerEapOptional,omitempty"`
+	Eap                    *Eap               `xml:"http://www.microsoft.com/provisioning/BaseEapConnectionPropertiesV1 Eap,omitempty"`
+	EnableQuarantineChecks *bool              `xml:"EnableQuarantineChecks,omitempty"`
+	RequireCryptoBinding   *bool              `xml:"RequireCryptoBinding,omitempty"`
+	UseWinLogonCredentials *bool              `xml:"UseWinLogonCredentials,omitempty"`
+}
+
+//CredentialsSource is the source of the client certificate of TLS.
+type CredentialsSource struct {
+	CertificateStore struct {
+		SimpleCertSelection bool `xml:"SimpleCertSelection"`
+	} `xml:"CertificateStore"`
+}
+
+//ServerValidation is the validation of the certificate of the server by PEAP and TLS.
+type ServerValidation struct {
+	DisableUserPromptForServerValidation bool `xml:"DisableUserPromptForServerValidation"`
+	//ServerNames is a semicolon separated list of the names the certificate of the server may have.
+	ServerNames string `xml:"ServerNames"`
+	//TrustedRootCA lists the SHA-1 thumbprints of the root CAs of the server, as hexadecimal bytes separated by spaces.
+	TrustedRootCA []string `xml:"TrustedRootCA"`
+}
+
+//EapTtls is the configuration of TTLS.
+type EapTtls struct {
+	ServerValidation     TtlsServerValidation `xml:"ServerValidation"`
+	Phase2Authentication Phase2Authentication `xml:"Phase2Authentication"`
+	Phase1Identity       Phase1Identity       `xml:"Phase1Identity"`
+}
+
+//TtlsServerValidation is the validation of the certificate of the server by TTLS.
+type TtlsServerValidation struct {
+	ServerNames       string   `xml:"ServerNames"`
+	TrustedRootCAHash []string `xml:"TrustedRootCAHash"`
+	DisablePrompt     bool     `xml:"DisablePrompt"`
+}
+
+//Phase2Authentication is the inner authentication of TTLS; one of its elements is set.
+type Phase2Authentication struct {
+	PAP      *struct{}       `xml:"PAPAuthentication"`
+	CHAP     *struct{}       `xml:"CHAPAuthentication"`
+	MSCHAP   *struct{}       `xml:"MSCHAPAuthentication"`
+	MSCHAPv2 *MSCHAPv2Config `xml:"MSCHAPv2Authentication"`
+}
+
+//MSCHAPv2Config is the MSCHAPv2 inner authentication of TTLS.
+type MSCHAPv2Config struct {
+	UseWinlogonCredentials bool `xml:"UseWinlogonCredentials"`
+}
+
+//Phase1Identity is the outer identity of TTLS.
+type Phase1Identity struct {
+	IdentityPrivacy   bool   `xml:"IdentityPrivacy"`
+	AnonymousIdentity string `xml:"AnonymousIdentity,omitempty"`
+}
+
+//EAPMethod is the type of an EAP method, as assigned by IANA.
+type EAPMethod uint8
+
+const (
+	EAPTLS      EAPMethod = 13
+	EAPTTLS     EAPMethod = 21
+	EAPPEAP     EAPMethod = 25
+	EAPMSCHAPv2 EAPMethod = 26
+)
+
+var eapNames = map[EAPMethod]string{
+	EAPTLS:      "TLS",
+	EAPTTLS:     "TTLS",
+	EAPPEAP:     "PEAP",
+	EAPMSCHAPv2: "MSCHAPv2",
+}
+
+func (m EAPMethod) String() string {
+	if s, ok := eapNames[m]; ok {
+		return s
+	}
+	return fmt.Sprintf("EAP(%d)", uint8(m))
+}
+
+//The inner authentications of TTLS and PEAP.
+const (
+	Phase2PAP      = "PAP"
+	Phase2CHAP     = "CHAP"
+	Phase2MSCHAP   = "MSCHAP"
+	Phase2MSCHAPv2 = "MSCHAPv2"
+)
+
+//EAP describes the 802.1X authentication of an enterprise network.
+type EAP struct {
+	//Method is EAPTLS, EAPTTLS or EAPPEAP.
+	Method EAPMethod
+	//Phase2 is the inner authentication of TTLS, or Phase2MSCHAPv2 for PEAP.
+	Phase2 string
+	//ServerNames are the names the certificate of the server may have.
+	ServerNames []string
+	//TrustedRootCAs are the SHA-1 thumbprints of the root CAs of the server, in lowercase hexadecimal.
+	TrustedRootCAs []string
+	//AnonymousIdentity is the outer identity of TTLS.
+	AnonymousIdentity string
+}
+
+//thumbprint returns the hexadecimal of a thumbprint as profiles write it, bytes separated by spaces.
+func thumbprint(s string) string {
+	var parts []string
+	for i := 0; i+2 <= len(s); i += 2 {
+		parts = append(parts, s[i:i+2])
+	}
+	return strings.Join(parts, " ")
+}
+
+//parseThumbprint returns the lowercase hexadecimal of a thumbprint of a profile.
+func parseThumbprint(s string) (string, error) {
+	s = strings.ToLower(strings.Join(strings.Fields(s), ""))
+	if b, err := hex.DecodeString(s); err != nil || len(b) != 20 {
+		return "", fmt.Errorf("profile: invalid certificate thumbprint %q", s)
+	}
+	return s, nil
+}
+
+func splitNames(s string) []string {
+	var names []string
+	for _, n := range strings.Split(s, ";") {
+		if n = strings.TrimSpace(n); n != "" {
+			names = append(names, n)
+		}
+	}
+	return names
+}
+
+//oneX returns the OneX element of the EAP configuration.
+func (e *EAP) oneX() (*OneX, error) {
+	cache := true
+	o := &OneX{CacheUserData: &cache, AuthMode: "user"}
+	o.EAPConfig.EapHostConfig.EapMethod.Type = e.Method
+	names := strings.Join(e.ServerNames, ";")
+	var cas []string
+	for _, ca := range e.TrustedRootCAs {
+		t, err := parseThumbprint(ca)
+		if err != nil {
+			return nil, err
+		}
+		cas = append(cas, thumbprint(t))
+	}
+
+	no := false
+	switch e.Method {
+	case EAPPEAP:
+		if e.Phase2 != Phase2MSCHAPv2 {
+			return nil, fmt.Errorf("profile: unsupported PEAP inner authentication %q", e.Phase2)
+		}
+		o.EAPConfig.EapHostConfig.Config.Eap = &Eap{Type: EAPPEAP, EapType: &EapType{
+			XMLName:          xml.Name{Space: NamespaceMsPeap, Local: "EapType"},
+			ServerValidation: &ServerValidation{ServerNames: names, TrustedRootCA: cas},
+			FastReconnect:    &cache,
+			InnerEapOptional: &no,
+			Eap: &Eap{Type: EAPMSCHAPv2, EapType: &EapType{
+				XMLName:                xml.Name{Space: NamespaceMsChapV2, Local: "EapType"},
+				UseWinLogonCredentials: &no,
+			}},
+			EnableQuarantineChecks: &no,
+			RequireCryptoBinding:   &no,
+		}}
+	case EAPTLS:
+		t := &EapType{
+			XMLName:           xml.Name{Space: NamespaceEapTls, Local: "EapType"},
+			CredentialsSource: &CredentialsSource{},
+			ServerValidation:  &ServerValidation{ServerNames: names, TrustedRootCA: cas},
+			DifferentUsername: &no,
+		}
+		t.CredentialsSource.CertificateStore.SimpleCertSelection = true
+		o.EAPConfig.EapHostConfig.Config.Eap = &Eap{Type: EAPTLS, EapType: t}
+	case EAPTTLS:
+		//The TTLS method of Windows is authored by Microsoft, whose vendor ID is 311.
+		o.EAPConfig.EapHostConfig.EapMethod.AuthorID = 311
+		t := &EapTtls{}
+		t.ServerValidation.ServerNames, t.ServerValidation.TrustedRootCAHash = names, cas
+		p := &t.Phase2Authentication
+		switch e.Phase2 {
+		case Phase2PAP:
+			p.PAP = &struct{}{}
+		case Phase2CHAP:
+			p.CHAP = &struct{}{}
+		case Phase2MSCHAP:
+			p.MSCHAP = &struct{}{}
+		case Phase2MSCHAPv2:
+			p.MSCHAPv2 = &MSCHAPv2Config{}
+		default:
+			return nil, fmt.Errorf("profile: unsupported TTLS inner authentication %q", e.Phase2)
+		}
+		t.Phase1Identity.IdentityPrivacy = e.AnonymousIdentity != ""
+		t.Phase1Identity.AnonymousIdentity = e.AnonymousIdentity
+		o.EAPConfig.EapHostConfig.Config.EapTtls = t
+	default:
+		return nil, fmt.Errorf("profile: unsupported EAP method %v", e.Method)
+	}
+	return o, nil
+}
+
+//eap returns the EAP configuration of the OneX element.
+func (o *OneX) eap() (*EAP, error) {
+	h := &o.EAPConfig.EapHostConfig
+	e := &EAP{Method: h.EapMethod.Type}
+	var (
+		names string
+		cas   []string
+	)
+	switch e.Method {
+	case EAPPEAP, EAPTLS:
+		c := h.Config.Eap
+		if c == nil || c.Type != e.Method || c.EapType == nil {
+			return nil, fmt.Errorf("profile: %v method has no configuration", e.Method)
+		}
+		if v := c.EapType.ServerValidation; v != nil {
+			names, cas = v.ServerNames, v.TrustedRootCA
+		}
+		if e.Method == EAPPEAP {
+			inner := c.EapType.Eap
+			if inner == nil || inner.Type != EAPMSCHAPv2 {
+				return nil, fmt.Errorf("profile: unsupported PEAP inner method")
+			}
+			e.Phase2 = Phase2MSCHAPv2
+		}
+	case EAPTTLS:
+		t := h.Config.EapTtls
+		if t == nil {
+			return nil, fmt.Errorf("profile: TTLS method has no configuration")
+		}
+		names, cas = t.ServerValidation.ServerNames, t.ServerValidation.TrustedRootCAHash
+		switch p := t.Phase2Authentication; {
+		case p.PAP != nil:
+			e.Phase2 = Phase2PAP
+		case p.CHAP != nil:
+			e.Phase2 = Phase2CHAP
+		case p.MSCHAP != nil:
+			e.Phase2 = Phase2MSCHAP
+		case p.MSCHAPv2 != nil:
+			e.Phase2 = Phase2MSCHAPv2
+		default:
+			return nil, fmt.Errorf("profile: unsupported TTLS inner authentication")
+		}
+		if t.Phase1Identity.IdentityPrivacy {
+			e.AnonymousIdentity = t.Phase1Identity.AnonymousIdentity
+		}
+	default:
+		return nil, fmt.Errorf("profile: unsupported EAP method %v", e.Method)
+	}
+	e.ServerNames = splitNames(names)
+	for _, ca := range cas {
+		t, err := parseThumbprint(ca)
+		if err != nil {
+			return nil, err
+		}
+		e.TrustedRootCAs = append(e.TrustedRootCAs, t)
+	}
+	return e, nil
+}
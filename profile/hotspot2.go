@@ -0,0 +1,136 @@
+package profile
+
+import (
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"strings"
+)
+
+//Hotspot2 is the Hotspot2 element of a Passpoint profile, which matches networks by the ANQP data of their BSSes
+//rather than by SSID.
+type Hotspot2 struct {
+	//DomainName is the domain name of the home service provider.
+	DomainName        string             `xml:"DomainName"`
+	NAIRealm          *NAIRealm          `xml:"NAIRealm,omitempty"`
+	Network3GPP       *Network3GPP       `xml:"Network3GPP,omitempty"`
+	RoamingConsortium *RoamingConsortium `xml:"RoamingConsortium,omitempty"`
+}
+
+//NAIRealm lists the NAI realms of a Passpoint profile.
+type NAIRealm struct {
+	Names []string `xml:"name"`
+}
+
+//Network3GPP lists the PLMN IDs, mobile country and network codes, of a Passpoint profile.
+type Network3GPP struct {
+	PLMNIDs []string `xml:"PLMNID"`
+}
+
+//RoamingConsortium lists the roaming consortium OIs of a Passpoint profile, in hexadecimal.
+type RoamingConsortium struct {
+	OUIs []string `xml:"OUI"`
+}
+
+//MarshalXML omits the SSIDConfig of Passpoint profiles, which have no SSIDs.
+func (c SSIDConfig) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	if len(c.SSIDs) == 0 && !c.NonBroadcast {
+		return nil
+	}
+	type ssidConfig SSIDConfig
+	return e.EncodeElement(ssidConfig(c), start)
+}
+
+//Passpoint is the credential of a Passpoint (Hotspot 2.0) service provider. Its profile connects to the networks
+//whose BSSes name the home service provider, one of the realms or one of the roaming consortium OIs in their ANQP
+//data, whatever their SSID.
+type Passpoint struct {
+	//Name is the name of the profile; it defaults to HomeSPFQDN.
+	Name string
+	//HomeSPFQDN is the domain name of the home service provider, such as example.com.
+	HomeSPFQDN string
+	//Realms are the NAI realms of the credential, the part after @ of the identities it authenticates with.
+	Realms []string
+	//RoamingConsortiumOIs are the OIs of the roaming consortiums the provider is part of, in hexadecimal, as the
+	//Roaming Consortium elements of BSSes list them.
+	RoamingConsortiumOIs []string
+	Manual               bool
+	//EAP is the EAP configuration of the credential; Passpoint networks are WPA2-Enterprise with TLS or TTLS.
+	EAP *EAP
+}
+
+//Build returns the profile of the credential.
+func (c Passpoint) Build() (*WLANProfile, error) {
+	if c.HomeSPFQDN == "" || strings.ContainsAny(c.HomeSPFQDN, " @") {
+		return nil, fmt.Errorf("profile: invalid home SP FQDN %q", c.HomeSPFQDN)
+	}
+	if c.EAP == nil || (c.EAP.Method != EAPTLS && c.EAP.Method != EAPTTLS) {
+		return nil, fmt.Errorf("profile: %s: Passpoint credentials need a TLS or TTLS EAP configuration", c.HomeSPFQDN)
+	}
+	oneX, err := c.EAP.oneX()
+	if err != nil {
+		return nil, err
+	}
+	h := &Hotspot2{DomainName: c.HomeSPFQDN}
+	for _, realm := range c.Realms {
+		if realm == "" || strings.ContainsAny(realm, " @") {
+			return nil, fmt.Errorf("profile: invalid NAI realm %q", realm)
+		}
+	}
+	if len(c.Realms) > 0 {
+		h.NAIRealm = &NAIRealm{Names: c.Realms}
+	}
+	for _, oi := range c.RoamingConsortiumOIs {
+		b, err := hex.DecodeString(oi)
+		if err != nil || len(b) < 3 || len(b) > 15 {
+			return nil, fmt.Errorf("profile: invalid roaming consortium OI %q", oi)
+		}
+		if h.RoamingConsortium == nil {
+			h.RoamingConsortium = &RoamingConsortium{}
+		}
+		h.RoamingConsortium.OUIs = append(h.RoamingConsortium.OUIs, fmt.Sprintf("%X", b))
+	}
+	s := securities[WPA2Enterprise]
+	p := &WLANProfile{
+		Name:           c.Name,
+		Hotspot2:       h,
+		ConnectionType: ESS,
+		ConnectionMode: Auto,
+		MSM: MSM{Security: Security{
+			AuthEncryption: AuthEncryption{Authentication: s.authentication, Encryption: s.encryption, UseOneX: s.oneX},
+			OneX:           oneX,
+		}},
+	}
+	if p.Name == "" {
+		p.Name = c.HomeSPFQDN
+	}
+	if c.Manual {
+		p.ConnectionMode = Manual
+	}
+	return p, nil
+}
+
+//Passpoint returns the credential of a Passpoint profile. PLMN IDs are not part of the credential.
+func (p *WLANProfile) Passpoint() (Passpoint, error) {
+	h := p.Hotspot2
+	if h == nil {
+		return Passpoint{}, fmt.Errorf("profile: profile %q is not a Passpoint profile", p.Name)
+	}
+	c := Passpoint{Name: p.Name, HomeSPFQDN: h.DomainName, Manual: p.ConnectionMode == Manual}
+	if h.NAIRealm != nil {
+		c.Realms = h.NAIRealm.Names
+	}
+	if h.RoamingConsortium != nil {
+		for _, oi := range h.RoamingConsortium.OUIs {
+			c.RoamingConsortiumOIs = append(c.RoamingConsortiumOIs, strings.ToLower(oi))
+		}
+	}
+	if o := p.MSM.Security.OneX; o != nil {
+		e, err := o.eap()
+		if err != nil {
+			return c, err
+		}
+		c.EAP = e
+	}
+	return c, nil
+}
@@ -0,0 +1,454 @@
+package profile
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha1"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+//KeyfileSection is a section of a NetworkManager keyfile, with its values as written in the file.
+type KeyfileSection struct {
+	Name   string
+	Fields []Field
+}
+
+//Keyfile is a NetworkManager .nmconnection keyfile.
+type Keyfile []KeyfileSection
+
+//value returns the value of a key of a section as written.
+func (k Keyfile) value(section, key string) (string, bool) {
+	for _, s := range k {
+		if s.Name != section {
+			continue
+		}
+		for _, f := range s.Fields {
+			if f.Key == key {
+				return f.Value, true
+			}
+		}
+	}
+	return "", false
+}
+
+//Get returns the value of a key of a section, unescaped.
+func (k Keyfile) Get(section, key string) (string, bool) {
+	v, ok := k.value(section, key)
+	return unescapeKeyfile(v), ok
+}
+
+//String returns the text of the keyfile.
+func (k Keyfile) String() string {
+	var b strings.Builder
+	for i, s := range k {
+		if i > 0 {
+			b.WriteByte('\n')
+		}
+		fmt.Fprintf(&b, "[%s]\n", s.Name)
+		for _, f := range s.Fields {
+			fmt.Fprintf(&b, "%s=%s\n", f.Key, f.Value)
+		}
+	}
+	return b.String()
+}
+
+//ParseKeyfile parses a keyfile.
+func ParseKeyfile(data []byte) (Keyfile, error) {
+	var k Keyfile
+	s := bufio.NewScanner(bytes.NewReader(data))
+	for line := 1; s.Scan(); line++ {
+		text := strings.TrimSpace(s.Text())
+		switch {
+		case text == "" || text[0] == '#':
+		case text[0] == '[' && text[len(text)-1] == ']':
+			k = append(k, KeyfileSection{Name: text[1 : len(text)-1]})
+		default:
+			i := strings.IndexByte(text, '=')
+			if i <= 0 || len(k) == 0 {
+				return nil, fmt.Errorf("profile: line %d: invalid line %q", line, text)
+			}
+			section := &k[len(k)-1]
+			section.Fields = append(section.Fields, Field{Key: strings.TrimSpace(text[:i]), Value: strings.TrimSpace(text[i+1:])})
+		}
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	return k, nil
+}
+
+//unescapeKeyfile removes the escapes of a value.
+func unescapeKeyfile(v string) string {
+	var b strings.Builder
+	for i := 0; i < len(v); i++ {
+		if v[i] != '\\' || i+1 == len(v) {
+			b.WriteByte(v[i])
+			continue
+		}
+		i++
+		switch v[i] {
+		case 's':
+			b.WriteByte(' ')
+		case 'n':
+			b.WriteByte('\n')
+		case 't':
+			b.WriteByte('\t')
+		case 'r':
+			b.WriteByte('\r')
+		default:
+			b.WriteByte(v[i])
+		}
+	}
+	return b.String()
+}
+
+//escapeKeyfile escapes a string value.
+func escapeKeyfile(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == '\\':
+			b.WriteString(`\\`)
+		case c == '\n':
+			b.WriteString(`\n`)
+		case c == '\t':
+			b.WriteString(`\t`)
+		case c == '\r':
+			b.WriteString(`\r`)
+		case c == ' ' && (i == 0 || i == len(s)-1):
+			b.WriteString(`\s`)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
+//keyfileList splits a list value as written, such as eap=peap;ttls; and unescapes its items.
+func keyfileList(v string) []string {
+	var list []string
+	start := 0
+	for i := 0; i < len(v); i++ {
+		switch v[i] {
+		case '\\':
+			i++
+		case ';':
+			list = append(list, unescapeKeyfile(v[start:i]))
+			start = i + 1
+		}
+	}
+	if start < len(v) {
+		list = append(list, unescapeKeyfile(v[start:]))
+	}
+	return list
+}
+
+//keyfileSSID returns the SSID of a value as written, which is a string or, for SSIDs that are not, a list of
+//byte values.
+func keyfileSSID(v string) string {
+	if list := keyfileList(v); strings.HasSuffix(v, ";") {
+		var b []byte
+		for _, s := range list {
+			n, err := strconv.ParseUint(s, 10, 8)
+			if err != nil {
+				return unescapeKeyfile(v)
+			}
+			b = append(b, byte(n))
+		}
+		return string(b)
+	}
+	return unescapeKeyfile(v)
+}
+
+//formatKeyfileSSID returns the value of an SSID: a string when it is printable, a list of bytes otherwise.
+func formatKeyfileSSID(ssid string) string {
+	printable := utf8.ValidString(ssid)
+	for _, r := range ssid {
+		if r < 0x20 || r == 0x7f || r == ';' {
+			printable = false
+		}
+	}
+	if printable {
+		return escapeKeyfile(ssid)
+	}
+	var b strings.Builder
+	for i := 0; i < len(ssid); i++ {
+		fmt.Fprintf(&b, "%d;", ssid[i])
+	}
+	return b.String()
+}
+
+//uuid returns a name based UUID of a profile, so exporting a profile twice gives the same connection.
+func uuid(name string) string {
+	h := sha1.Sum([]byte("wlanapi profile " + name))
+	h[6] = h[6]&0x0f | 0x50
+	h[8] = h[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", h[0:4], h[4:6], h[6:8], h[8:10], h[10:16])
+}
+
+//The pmf values of a keyfile.
+const (
+	pmfDefault  = "0"
+	pmfDisable  = "1"
+	pmfOptional = "2"
+	pmfRequired = "3"
+)
+
+//keyfileKnown are the keys that conversions of keyfiles handle, by section.
+var keyfileKnown = map[string]map[string]bool{
+	"connection":    {"id": true, "uuid": true, "type": true, "autoconnect": true, "timestamp": true, "permissions": true},
+	"wifi":          {"mode": true, "ssid": true, "hidden": true},
+	"wifi-security": {"key-mgmt": true, "psk": true, "proto": true, "pairwise": true, "group": true, "pmf": true, "auth-alg": true, "wep-key0": true, "wep-key-type": true, "wep-tx-keyidx": true},
+	"802-1x":        {"eap": true, "phase2-auth": true, "phase2-autheap": true, "anonymous-identity": true, "domain-suffix-match": true, "domain-match": true, "password-flags": true},
+}
+
+//keyfileCredentials are the keys of the credentials of a user, which profiles do not hold.
+var keyfileCredentials = map[string]bool{
+	"identity": true, "password": true, "client-cert": true, "private-key": true, "private-key-password": true,
+	"ca-cert": true, "ca-path": true, "system-ca-certs": true,
+}
+
+//FromKeyfile returns the profile of a keyfile, with warnings for the settings that profiles lack. The IP settings
+//of the connection are not converted.
+func FromKeyfile(k Keyfile) (*WLANProfile, []Warning, error) {
+	get := func(section, key string) string {
+		v, _ := k.Get(section, key)
+		return v
+	}
+	raw := func(section, key string) string {
+		v, _ := k.value(section, key)
+		return v
+	}
+	w := &warnings{network: get("connection", "id")}
+	if t := get("connection", "type"); t != "wifi" && t != "802-11-wireless" {
+		return nil, nil, fmt.Errorf("profile: connection %s of type %q is not a Wi-Fi connection", w.network, t)
+	}
+	if mode := get("wifi", "mode"); mode != "" && mode != "infrastructure" {
+		return nil, nil, fmt.Errorf("profile: connection %s: mode %s is not an infrastructure network", w.network, mode)
+	}
+	b := Builder{
+		Name:   w.network,
+		SSID:   keyfileSSID(raw("wifi", "ssid")),
+		Hidden: get("wifi", "hidden") == "true",
+		Manual: get("connection", "autoconnect") == "false",
+	}
+
+	keyMgmt, security := get("wifi-security", "key-mgmt"), false
+	for _, s := range k {
+		security = security || s.Name == "wifi-security"
+	}
+	proto := keyfileList(raw("wifi-security", "proto"))
+	wpa1 := len(proto) == 1 && proto[0] == "wpa"
+	switch {
+	case !security:
+		b.Security = Open
+	case keyMgmt == "none":
+		b.Security = WEP
+		if t := get("wifi-security", "wep-key-type"); t == "2" {
+			return nil, nil, fmt.Errorf("profile: connection %s: WEP passphrases are not supported", w.network)
+		}
+		if idx := get("wifi-security", "wep-tx-keyidx"); idx != "" && idx != "0" {
+			w.add("wep-tx-keyidx", "only the first WEP key is converted")
+		}
+		b.Key = get("wifi-security", "wep-key0")
+	case keyMgmt == "wpa-psk":
+		b.Security = WPA2Personal
+		if wpa1 {
+			b.Security = WPAPersonal
+		}
+		b.Key = get("wifi-security", "psk")
+	case keyMgmt == "sae":
+		b.Security = WPA3Personal
+		b.Key = get("wifi-security", "psk")
+	case keyMgmt == "owe":
+		b.Security = OWE
+	case keyMgmt == "wpa-eap-suite-b-192":
+		b.Security = WPA3Enterprise192
+	case keyMgmt == "wpa-eap" && get("wifi-security", "pmf") == pmfRequired:
+		b.Security = WPA3Enterprise
+	case keyMgmt == "wpa-eap":
+		b.Security = WPA2Enterprise
+		if wpa1 {
+			w.add("proto", "WPA-Enterprise is converted as WPA2-Enterprise")
+		}
+	default:
+		return nil, nil, fmt.Errorf("profile: connection %s: unsupported key-mgmt %q", w.network, keyMgmt)
+	}
+	if b.Security.Personal() {
+		if flags := get("wifi-security", "psk-flags"); flags != "" && flags != "0" && b.Key == "" {
+			w.add("psk-flags", "the key is kept by a secret agent rather than the keyfile")
+		}
+	}
+	if b.Security.Enterprise() {
+		e, err := keyfileEAP(k, w)
+		if err != nil {
+			return nil, nil, err
+		}
+		b.EAP = e
+	}
+
+	for _, s := range k {
+		switch s.Name {
+		case "ipv4", "ipv6":
+			if m, _ := k.Get(s.Name, "method"); m != "" && m != "auto" && m != "ignore" && m != "disabled" {
+				w.add(s.Name+".method", "IP settings are not stored in profiles")
+			}
+			continue
+		case "proxy":
+			continue
+		}
+		for _, f := range s.Fields {
+			setting := s.Name + "." + f.Key
+			switch {
+			case s.Name == "802-1x" && keyfileCredentials[f.Key]:
+				w.add(setting, "credentials and certificate files are not stored in profiles")
+			case f.Key == "auth-alg" && f.Value != "open":
+				w.add(setting, "only open system authentication is converted")
+			case strings.HasPrefix(f.Key, "wep-key") && f.Key != "wep-key0" && f.Key != "wep-key-type":
+				w.add(setting, "only the first WEP key is converted")
+			case f.Key == "anonymous-identity" && b.EAP != nil && b.EAP.Method != EAPTTLS:
+				w.add(setting, "only TTLS profiles have an anonymous identity")
+			case !keyfileKnown[s.Name][f.Key] && f.Key != "psk-flags":
+				w.add(setting, "profiles have no such setting")
+			}
+		}
+	}
+	p, err := b.Build()
+	if err != nil {
+		return nil, nil, err
+	}
+	return p, w.list, nil
+}
+
+//keyfileEAP returns the EAP configuration of the 802-1x section of a keyfile.
+func keyfileEAP(k Keyfile, w *warnings) (*EAP, error) {
+	v, _ := k.value("802-1x", "eap")
+	methods := keyfileList(v)
+	if len(methods) == 0 {
+		w.add("802-1x.eap", "the connection has no EAP method")
+		return nil, nil
+	}
+	if len(methods) > 1 {
+		w.add("802-1x.eap", "only the first of the EAP methods %s is converted", v)
+	}
+	e := &EAP{}
+	switch methods[0] {
+	case "peap":
+		e.Method = EAPPEAP
+	case "ttls":
+		e.Method = EAPTTLS
+	case "tls":
+		e.Method = EAPTLS
+	default:
+		return nil, fmt.Errorf("profile: unsupported EAP method %s", methods[0])
+	}
+	auth, _ := k.Get("802-1x", "phase2-auth")
+	if a, ok := k.Get("802-1x", "phase2-autheap"); ok && e.Method == EAPTTLS {
+		w.add("802-1x.phase2-autheap", "EAP-MSCHAPv2 is converted as MSCHAPv2")
+		auth = a
+	}
+	switch auth {
+	case "pap":
+		e.Phase2 = Phase2PAP
+	case "chap":
+		e.Phase2 = Phase2CHAP
+	case "mschap":
+		e.Phase2 = Phase2MSCHAP
+	case "mschapv2", "":
+		e.Phase2 = Phase2MSCHAPv2
+	default:
+		return nil, fmt.Errorf("profile: unsupported inner authentication %s", auth)
+	}
+	switch {
+	case e.Method == EAPTLS:
+		e.Phase2 = ""
+	case e.Method == EAPTTLS && auth == "":
+		return nil, fmt.Errorf("profile: TTLS connection has no inner authentication")
+	case e.Method == EAPTTLS:
+		e.AnonymousIdentity, _ = k.Get("802-1x", "anonymous-identity")
+	}
+	for _, key := range []string{"domain-suffix-match", "domain-match"} {
+		if v, ok := k.Get("802-1x", key); ok {
+			e.ServerNames = append(e.ServerNames, splitNames(v)...)
+		}
+	}
+	return e, nil
+}
+
+//ToKeyfile returns the keyfile of a profile, with warnings for the settings that NetworkManager lacks.
+func ToKeyfile(p *WLANProfile) (Keyfile, []Warning, error) {
+	w := &warnings{}
+	b, err := p.export(w)
+	if err != nil {
+		return nil, nil, err
+	}
+	connection := []Field{{"id", escapeKeyfile(b.Name)}, {"uuid", uuid(b.Name)}, {"type", "wifi"}}
+	if b.Manual {
+		connection = append(connection, Field{"autoconnect", "false"})
+	}
+	wifi := []Field{{"mode", "infrastructure"}, {"ssid", formatKeyfileSSID(b.SSID)}}
+	if b.Hidden {
+		wifi = append(wifi, Field{"hidden", "true"})
+	}
+	k := Keyfile{{Name: "connection", Fields: connection}, {Name: "wifi", Fields: wifi}}
+
+	var security []Field
+	add := func(key, value string) { security = append(security, Field{key, value}) }
+	switch b.Security {
+	case WEP:
+		add("key-mgmt", "none")
+		add("auth-alg", "open")
+		add("wep-key-type", "1")
+		add("wep-key0", escapeKeyfile(b.Key))
+	case WPAPersonal:
+		add("key-mgmt", "wpa-psk")
+		add("proto", "wpa;")
+		add("pairwise", "tkip;")
+		add("psk", escapeKeyfile(b.Key))
+	case WPA2Personal:
+		add("key-mgmt", "wpa-psk")
+		add("psk", escapeKeyfile(b.Key))
+	case WPA3Personal:
+		add("key-mgmt", "sae")
+		add("psk", escapeKeyfile(b.Key))
+	case WPA3Transition:
+		w.add("transitionMode", "NetworkManager has no transition mode setting; the connection is WPA2-Personal with optional PMF")
+		add("key-mgmt", "wpa-psk")
+		add("pmf", pmfOptional)
+		add("psk", escapeKeyfile(b.Key))
+	case OWE:
+		add("key-mgmt", "owe")
+	case WPA2Enterprise:
+		add("key-mgmt", "wpa-eap")
+	case WPA3Enterprise:
+		add("key-mgmt", "wpa-eap")
+		add("pmf", pmfRequired)
+	case WPA3Enterprise192:
+		add("key-mgmt", "wpa-eap-suite-b-192")
+	}
+	if security != nil {
+		k = append(k, KeyfileSection{Name: "wifi-security", Fields: security})
+	}
+	if e := b.EAP; e != nil {
+		onex := []Field{{"eap", strings.ToLower(e.Method.String()) + ";"}}
+		if e.Phase2 != "" {
+			onex = append(onex, Field{"phase2-auth", strings.ToLower(e.Phase2)})
+		}
+		if e.AnonymousIdentity != "" {
+			onex = append(onex, Field{"anonymous-identity", escapeKeyfile(e.AnonymousIdentity)})
+		}
+		if len(e.ServerNames) > 0 {
+			onex = append(onex, Field{"domain-suffix-match", escapeKeyfile(strings.Join(e.ServerNames, ";"))})
+		}
+		if len(e.TrustedRootCAs) > 0 {
+			w.add("TrustedRootCA", "NetworkManager trusts CAs by certificate file rather than thumbprint; set ca-cert")
+		}
+		k = append(k, KeyfileSection{Name: "802-1x", Fields: onex})
+	}
+	k = append(k, KeyfileSection{Name: "ipv4", Fields: []Field{{"method", "auto"}}},
+		KeyfileSection{Name: "ipv6", Fields: []Field{{"addr-gen-mode", "default"}, {"method", "auto"}}})
+	return k, w.list, nil
+}
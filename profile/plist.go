@@ -0,0 +1,242 @@
+package profile
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+//plistDict is a dict of a property list. The values of a property list are plistDict, []interface{}, string,
+//int64, float64, bool and []byte; dates are kept as their string.
+type plistDict map[string]interface{}
+
+func (d plistDict) string(key string) string {
+	s, _ := d[key].(string)
+	return s
+}
+
+func (d plistDict) bool(key string, def bool) bool {
+	if b, ok := d[key].(bool); ok {
+		return b
+	}
+	return def
+}
+
+func (d plistDict) dict(key string) plistDict {
+	v, _ := d[key].(plistDict)
+	return v
+}
+
+func (d plistDict) array(key string) []interface{} {
+	v, _ := d[key].([]interface{})
+	return v
+}
+
+//keys returns the keys of the dict, sorted.
+func (d plistDict) keys() []string {
+	keys := make([]string, 0, len(d))
+	for k := range d {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+//strings returns the strings of an array of the dict.
+func (d plistDict) strings(key string) []string {
+	var list []string
+	for _, v := range d.array(key) {
+		if s, ok := v.(string); ok {
+			list = append(list, s)
+		}
+	}
+	return list
+}
+
+//parsePlist parses an XML property list. Binary property lists, and configuration profiles signed as CMS, are
+//not supported.
+func parsePlist(data []byte) (interface{}, error) {
+	switch trimmed := bytes.TrimSpace(data); {
+	case bytes.HasPrefix(trimmed, []byte("bplist")):
+		return nil, errors.New("profile: binary property lists are not supported")
+	case !bytes.HasPrefix(trimmed, []byte("<")):
+		return nil, errors.New("profile: not an XML property list; signed configuration profiles are not supported")
+	}
+	d := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		t, err := d.Token()
+		if err != nil {
+			return nil, fmt.Errorf("profile: invalid property list: %v", err)
+		}
+		if start, ok := t.(xml.StartElement); ok {
+			if start.Name.Local != "plist" {
+				return nil, fmt.Errorf("profile: %s is not a plist element", start.Name.Local)
+			}
+			v, end, err := plistValue(d)
+			if err != nil {
+				return nil, fmt.Errorf("profile: invalid property list: %v", err)
+			}
+			if end {
+				return nil, errors.New("profile: empty property list")
+			}
+			return v, nil
+		}
+	}
+}
+
+//plistValue decodes the next value of the decoder; end reports that its parent element ended instead.
+func plistValue(d *xml.Decoder) (v interface{}, end bool, err error) {
+	var start xml.StartElement
+	for {
+		t, err := d.Token()
+		if err != nil {
+			return nil, false, err
+		}
+		if _, ok := t.(xml.EndElement); ok {
+			return nil, true, nil
+		}
+		var ok bool
+		if start, ok = t.(xml.StartElement); ok {
+			break
+		}
+	}
+	text := func() (string, error) {
+		var s string
+		err := d.DecodeElement(&s, &start)
+		return s, err
+	}
+	switch start.Name.Local {
+	case "dict":
+		dict := plistDict{}
+		for {
+			var key string
+			t, err := d.Token()
+			if err != nil {
+				return nil, false, err
+			}
+			switch t := t.(type) {
+			case xml.EndElement:
+				return dict, false, nil
+			case xml.StartElement:
+				if t.Name.Local != "key" {
+					return nil, false, fmt.Errorf("%s in a dict where a key is expected", t.Name.Local)
+				}
+				if err := d.DecodeElement(&key, &t); err != nil {
+					return nil, false, err
+				}
+				v, end, err := plistValue(d)
+				if err != nil {
+					return nil, false, err
+				}
+				if end {
+					return nil, false, fmt.Errorf("key %s has no value", key)
+				}
+				dict[key] = v
+			}
+		}
+	case "array":
+		list := []interface{}{}
+		for {
+			v, end, err := plistValue(d)
+			if err != nil {
+				return nil, false, err
+			}
+			if end {
+				return list, false, nil
+			}
+			list = append(list, v)
+		}
+	case "true", "false":
+		return start.Name.Local == "true", false, d.Skip()
+	case "string", "date":
+		s, err := text()
+		return s, false, err
+	case "integer":
+		s, err := text()
+		if err != nil {
+			return nil, false, err
+		}
+		n, err := strconv.ParseInt(strings.TrimSpace(s), 10, 64)
+		return n, false, err
+	case "real":
+		s, err := text()
+		if err != nil {
+			return nil, false, err
+		}
+		f, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+		return f, false, err
+	case "data":
+		s, err := text()
+		if err != nil {
+			return nil, false, err
+		}
+		b, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(s), ""))
+		return b, false, err
+	}
+	return nil, false, fmt.Errorf("unknown element %s", start.Name.Local)
+}
+
+//writePlist writes an XML property list, with the keys of dicts sorted and indented with tabs as Apple writes it.
+func writePlist(w io.Writer, v interface{}) error {
+	var b bytes.Buffer
+	b.WriteString(xml.Header)
+	b.WriteString(`<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">` + "\n")
+	b.WriteString(`<plist version="1.0">` + "\n")
+	if err := writePlistValue(&b, v, 0); err != nil {
+		return err
+	}
+	b.WriteString("</plist>\n")
+	_, err := w.Write(b.Bytes())
+	return err
+}
+
+func writePlistValue(b *bytes.Buffer, v interface{}, depth int) error {
+	indent := strings.Repeat("\t", depth)
+	element := func(name, text string) {
+		b.WriteString(indent + "<" + name + ">")
+		xml.EscapeText(b, []byte(text))
+		b.WriteString("</" + name + ">\n")
+	}
+	switch v := v.(type) {
+	case plistDict:
+		b.WriteString(indent + "<dict>\n")
+		for _, k := range v.keys() {
+			b.WriteString(indent + "\t<key>")
+			xml.EscapeText(b, []byte(k))
+			b.WriteString("</key>\n")
+			if err := writePlistValue(b, v[k], depth+1); err != nil {
+				return err
+			}
+		}
+		b.WriteString(indent + "</dict>\n")
+	case []interface{}:
+		b.WriteString(indent + "<array>\n")
+		for _, e := range v {
+			if err := writePlistValue(b, e, depth+1); err != nil {
+				return err
+			}
+		}
+		b.WriteString(indent + "</array>\n")
+	case string:
+		element("string", v)
+	case int64:
+		element("integer", strconv.FormatInt(v, 10))
+	case int:
+		element("integer", strconv.Itoa(v))
+	case float64:
+		element("real", strconv.FormatFloat(v, 'g', -1, 64))
+	case bool:
+		b.WriteString(indent + "<" + strconv.FormatBool(v) + "/>\n")
+	case []byte:
+		element("data", base64.StdEncoding.EncodeToString(v))
+	default:
+		return fmt.Errorf("profile: %T is not a property list value", v)
+	}
+	return nil
+}
@@ -0,0 +1,173 @@
+//Package profile models the XML of wireless profiles, as WlanSetProfile takes them and WlanGetProfile returns them,
+//and builds profiles for the common kinds of network security.
+//
+//Only the elements used by the converters of the module are modelled; see
+//https://docs.microsoft.com/en-us/windows/win32/nativewifi/wlan-profileschema-elements.
+package profile
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+)
+
+//The namespaces of the profile schema. Elements added after the first version of the schema, such as
+//transitionMode, carry the namespace of their version.
+const (
+	Namespace   = "http://www.microsoft.com/networking/WLAN/profile/v1"
+	NamespaceV4 = "http://www.microsoft.com/networking/WLAN/profile/v4"
+)
+
+//The values of the connectionType element.
+const (
+	ESS  = "ESS"
+	IBSS = "IBSS"
+)
+
+//The values of the connectionMode element.
+const (
+	Auto   = "auto"
+	Manual = "manual"
+)
+
+//The values of the authentication element.
+const (
+	AuthOpen       = "open"
+	AuthShared     = "shared"
+	AuthWPA        = "WPA"
+	AuthWPAPSK     = "WPAPSK"
+	AuthWPA2       = "WPA2"
+	AuthWPA2PSK    = "WPA2PSK"
+	AuthWPA3ENT    = "WPA3ENT"
+	AuthWPA3ENT192 = "WPA3ENT192"
+	AuthWPA3SAE    = "WPA3SAE"
+	AuthOWE        = "OWE"
+)
+
+//The values of the encryption element.
+const (
+	EncryptionNone    = "none"
+	EncryptionWEP     = "WEP"
+	EncryptionTKIP    = "TKIP"
+	EncryptionAES     = "AES"
+	EncryptionGCMP256 = "GCMP256"
+)
+
+//The values of the keyType element.
+const (
+	PassPhrase = "passPhrase"
+	NetworkKey = "networkKey"
+)
+
+//WLANProfile is the WLANProfile element of a profile. XMLName is untagged so that profiles parse with or without
+//the profile namespace.
+type WLANProfile struct {
+	XMLName    xml.Name
+	Name       string     `xml:"name"`
+	SSIDConfig SSIDConfig `xml:"SSIDConfig"`
+	//Hotspot2 is set for Passpoint profiles, which have no SSIDs.
+	Hotspot2       *Hotspot2 `xml:"Hotspot2,omitempty"`
+	ConnectionType string    `xml:"connectionType"`
+	ConnectionMode string    `xml:"connectionMode,omitempty"`
+	AutoSwitch     bool      `xml:"autoSwitch,omitempty"`
+	MSM            MSM       `xml:"MSM"`
+}
+
+//SSIDConfig lists the SSIDs of the network.
+type SSIDConfig struct {
+	SSIDs []SSID `xml:"SSID"`
+	//NonBroadcast is set for hidden networks, which the interface probes for.
+	NonBroadcast bool `xml:"nonBroadcast,omitempty"`
+}
+
+//SSID is an SSID, as hexadecimal bytes or a name; Hex takes precedence when both are set.
+type SSID struct {
+	Hex  string `xml:"hex,omitempty"`
+	Name string `xml:"name,omitempty"`
+}
+
+//NewSSID returns the SSID element of the bytes of an SSID, with both its hexadecimal and its name.
+func NewSSID(ssid []byte) SSID {
+	return SSID{Hex: fmt.Sprintf("%X", ssid), Name: string(ssid)}
+}
+
+//Bytes returns the bytes of the SSID.
+func (s SSID) Bytes() ([]byte, error) {
+	if s.Hex == "" {
+		return []byte(s.Name), nil
+	}
+	b, err := hex.DecodeString(s.Hex)
+	if err != nil {
+		return nil, fmt.Errorf("profile: invalid SSID hex %q", s.Hex)
+	}
+	return b, nil
+}
+
+//MSM holds the security settings of the profile.
+type MSM struct {
+	Security Security `xml:"security"`
+}
+
+//Security is the security element of a profile.
+type Security struct {
+	AuthEncryption AuthEncryption `xml:"authEncryption"`
+	SharedKey      *SharedKey     `xml:"sharedKey,omitempty"`
+	OneX           *OneX          `xml:"http://www.microsoft.com/networking/OneX/v1 OneX,omitempty"`
+}
+
+//AuthEncryption is the authentication and encryption of a profile.
+type AuthEncryption struct {
+	Authentication string `xml:"authentication"`
+	Encryption     string `xml:"encryption"`
+	UseOneX        bool   `xml:"useOneX"`
+	//TransitionMode lets a WPA3SAE profile connect to WPA2-Personal BSSes of a transition mode network.
+	TransitionMode *bool `xml:"http://www.microsoft.com/networking/WLAN/profile/v4 transitionMode,omitempty"`
+}
+
+//SharedKey is the key of a personal or WEP network.
+type SharedKey struct {
+	KeyType string `xml:"keyType"`
+	//Protected is set when KeyMaterial is encrypted, as WlanGetProfile returns it without a plain text key.
+	Protected   bool   `xml:"protected"`
+	KeyMaterial string `xml:"keyMaterial"`
+}
+
+//Parse parses the XML of a profile.
+func Parse(data []byte) (*WLANProfile, error) {
+	p := &WLANProfile{}
+	if err := xml.Unmarshal(data, p); err != nil {
+		return nil, fmt.Errorf("profile: invalid profile XML: %v", err)
+	}
+	if p.XMLName.Local != "WLANProfile" {
+		return nil, fmt.Errorf("profile: %s is not a WLANProfile element", p.XMLName.Local)
+	}
+	if p.Name == "" {
+		return nil, errors.New("profile: profile XML has no name")
+	}
+	return p, nil
+}
+
+//Marshal returns the XML of the profile, in the profile namespace and indented with tabs as Windows exports it.
+func (p *WLANProfile) Marshal() ([]byte, error) {
+	out := *p
+	out.XMLName = xml.Name{Space: Namespace, Local: "WLANProfile"}
+	var b bytes.Buffer
+	b.WriteString(`<?xml version="1.0"?>` + "\n")
+	e := xml.NewEncoder(&b)
+	e.Indent("", "\t")
+	if err := e.Encode(&out); err != nil {
+		return nil, fmt.Errorf("profile: %v", err)
+	}
+	b.WriteByte('\n')
+	return b.Bytes(), nil
+}
+
+//SSID returns the bytes of the first SSID of the profile.
+func (p *WLANProfile) SSID() ([]byte, error) {
+	if len(p.SSIDConfig.SSIDs) == 0 {
+		return nil, fmt.Errorf("profile: profile %q has no SSID", p.Name)
+	}
+	return p.SSIDConfig.SSIDs[0].Bytes()
+}
@@ -0,0 +1,181 @@
+package profile
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const exported = `<?xml version="1.0"?>
+<WLANProfile xmlns="http://www.microsoft.com/networking/WLAN/profile/v1">
+	<name>home</name>
+	<SSIDConfig>
+		<SSID>
+			<hex>686F6D65</hex>
+			<name>home</name>
+		</SSID>
+	</SSIDConfig>
+	<connectionType>ESS</connectionType>
+	<connectionMode>auto</connectionMode>
+	<MSM>
+		<security>
+			<authEncryption>
+				<authentication>WPA3SAE</authentication>
+				<encryption>AES</encryption>
+				<useOneX>false</useOneX>
+				<transitionMode xmlns="http://www.microsoft.com/networking/WLAN/profile/v4">true</transitionMode>
+			</authEncryption>
+			<sharedKey>
+				<keyType>passPhrase</keyType>
+				<protected>false</protected>
+				<keyMaterial>correct horse</keyMaterial>
+			</sharedKey>
+		</security>
+	</MSM>
+</WLANProfile>`
+
+func TestParse(t *testing.T) {
+	p, err := Parse([]byte(exported))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ae := p.MSM.Security.AuthEncryption
+	if p.Name != "home" || ae.Authentication != AuthWPA3SAE || ae.TransitionMode == nil || !*ae.TransitionMode ||
+		p.MSM.Security.SharedKey == nil || p.MSM.Security.SharedKey.KeyMaterial != "correct horse" {
+		t.Errorf("profile %+v", p)
+	}
+	if ssid, err := p.SSID(); err != nil || string(ssid) != "home" {
+		t.Errorf("SSID %q %v", ssid, err)
+	}
+	if _, err := Parse([]byte("<WLANProfile/>")); err == nil {
+		t.Error("parsed a profile without a name")
+	}
+	if _, err := Parse([]byte("<WLANProfiles><name>home</name></WLANProfiles>")); err == nil {
+		t.Error("parsed a WLANProfiles element")
+	}
+}
+
+func TestBuild(t *testing.T) {
+	for _, b := range []Builder{
+		{SSID: "cafe", Security: Open},
+		{SSID: "lab", Security: WEP, Key: "0123456789"},
+		{SSID: "home", Security: WPA2Personal, Key: "correct horse", Hidden: true},
+		{SSID: "home", Security: WPA2Personal, Key: strings.Repeat("ab", 32)},
+		{Name: "home 6GHz", SSID: "home", Security: WPA3Personal, Key: "correct horse", Manual: true},
+		{SSID: "home", Security: WPA3Transition, Key: "correct horse"},
+		{SSID: "airport", Security: OWE},
+		{SSID: "corp", Security: WPA3Enterprise192},
+	} {
+		p, err := b.Build()
+		if err != nil {
+			t.Errorf("%+v: %v", b, err)
+			continue
+		}
+		data, err := p.Marshal()
+		if err != nil {
+			t.Fatal(err)
+		}
+		p, err = Parse(data)
+		if err != nil {
+			t.Fatalf("%s: %v", data, err)
+		}
+		got, err := p.Builder()
+		if b.Name == "" {
+			b.Name = b.SSID
+		}
+		if err != nil || got != b {
+			t.Errorf("builder %+v %v, want %+v", got, err, b)
+		}
+	}
+
+	p, _ := Builder{SSID: "home", Security: WPA3Transition, Key: "correct horse"}.Build()
+	if data, _ := p.Marshal(); string(data) != exported+"\n" {
+		t.Errorf("marshalled\n%s", data)
+	}
+
+	for _, b := range []Builder{
+		{SSID: "", Security: Open},
+		{SSID: "cafe", Security: Open, Key: "correct horse"},
+		{SSID: "lab", Security: WEP, Key: "012345678"},
+		{SSID: "home", Security: WPA2Personal, Key: "short"},
+		{SSID: "home", Security: WPA2Personal, Key: strings.Repeat("x", 64)},
+		{SSID: "home", Security: SecurityType(42)},
+	} {
+		if _, err := b.Build(); err == nil {
+			t.Errorf("built %+v", b)
+		}
+	}
+}
+
+func TestBuilderOfUnsupported(t *testing.T) {
+	p, _ := Builder{SSID: "home", Security: WPA2Personal, Key: "correct horse"}.Build()
+	p.MSM.Security.SharedKey.Protected = true
+	if _, err := p.Builder(); err == nil {
+		t.Error("builder of a protected key")
+	}
+	p.MSM.Security.AuthEncryption.Encryption = EncryptionTKIP
+	if _, err := p.Builder(); err == nil {
+		t.Error("builder of WPA2PSK with TKIP")
+	}
+}
+
+func TestPasspoint(t *testing.T) {
+	c := Passpoint{
+		Name:                 "Example Passpoint",
+		HomeSPFQDN:           "example.com",
+		Realms:               []string{"example.com", "roaming.example.net"},
+		RoamingConsortiumOIs: []string{"001bc504bd", "506f9a"},
+		EAP:                  &EAP{Method: EAPTTLS, Phase2: Phase2MSCHAPv2, ServerNames: []string{"aaa.example.com"}},
+	}
+	p, err := c.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := p.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	const hotspot2 = `
+	<name>Example Passpoint</name>
+	<Hotspot2>
+		<DomainName>example.com</DomainName>
+		<NAIRealm>
+			<name>example.com</name>
+			<name>roaming.example.net</name>
+		</NAIRealm>
+		<RoamingConsortium>
+			<OUI>001BC504BD</OUI>
+			<OUI>506F9A</OUI>
+		</RoamingConsortium>
+	</Hotspot2>
+	<connectionType>ESS</connectionType>`
+	if !strings.Contains(string(data), hotspot2) || strings.Contains(string(data), "SSIDConfig") {
+		t.Errorf("marshalled\n%s", data)
+	}
+	p, err = Parse(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := p.Passpoint(); err != nil || !reflect.DeepEqual(got, c) {
+		t.Errorf("credential %+v %v, want %+v", got, err, c)
+	}
+	if _, err := p.Builder(); err == nil {
+		t.Error("builder of a Passpoint profile")
+	}
+	p, _ = Builder{SSID: "home", Security: WPA2Personal, Key: "correct horse"}.Build()
+	if _, err := p.Passpoint(); err == nil {
+		t.Error("credential of a profile without Hotspot2")
+	}
+
+	for _, c := range []Passpoint{
+		{EAP: &EAP{Method: EAPTLS}},
+		{HomeSPFQDN: "example.com"},
+		{HomeSPFQDN: "example.com", EAP: &EAP{Method: EAPPEAP, Phase2: Phase2MSCHAPv2}},
+		{HomeSPFQDN: "example.com", Realms: []string{"user@example.com"}, EAP: &EAP{Method: EAPTLS}},
+		{HomeSPFQDN: "example.com", RoamingConsortiumOIs: []string{"001b"}, EAP: &EAP{Method: EAPTLS}},
+	} {
+		if _, err := c.Build(); err == nil {
+			t.Errorf("built %+v", c)
+		}
+	}
+}
@@ -0,0 +1,426 @@
+package profile
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+//Field is a key and its value in a network block of wpa_supplicant.conf or a section of a keyfile.
+type Field struct {
+	Key, Value string
+}
+
+//SupplicantNetwork is a network={} block of wpa_supplicant.conf, with its values as written in the file: strings
+//keep their quotes.
+type SupplicantNetwork []Field
+
+//Get returns the value of a key of the network.
+func (n SupplicantNetwork) Get(key string) (string, bool) {
+	for _, f := range n {
+		if f.Key == key {
+			return f.Value, true
+		}
+	}
+	return "", false
+}
+
+//String returns the network block.
+func (n SupplicantNetwork) String() string {
+	var b strings.Builder
+	b.WriteString("network={\n")
+	for _, f := range n {
+		fmt.Fprintf(&b, "\t%s=%s\n", f.Key, f.Value)
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+//ParseSupplicant returns the network blocks of a wpa_supplicant.conf file; the global settings are skipped.
+func ParseSupplicant(data []byte) ([]SupplicantNetwork, error) {
+	var (
+		networks []SupplicantNetwork
+		network  SupplicantNetwork
+		in       bool
+	)
+	s := bufio.NewScanner(bytes.NewReader(data))
+	for line := 1; s.Scan(); line++ {
+		text := strings.TrimSpace(s.Text())
+		switch {
+		case text == "" || text[0] == '#':
+		case text == "network={":
+			if in {
+				return nil, fmt.Errorf("profile: line %d: network block in a network block", line)
+			}
+			in, network = true, SupplicantNetwork{}
+		case text == "}":
+			if !in {
+				return nil, fmt.Errorf("profile: line %d: } outside a network block", line)
+			}
+			in = false
+			networks = append(networks, network)
+		default:
+			i := strings.IndexByte(text, '=')
+			if i <= 0 {
+				return nil, fmt.Errorf("profile: line %d: invalid line %q", line, text)
+			}
+			if in {
+				network = append(network, Field{Key: text[:i], Value: text[i+1:]})
+			}
+		}
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	if in {
+		return nil, fmt.Errorf("profile: network block is not closed")
+	}
+	return networks, nil
+}
+
+//supplicantString returns the bytes of a string value: a quoted string, a P"" string with printf escapes, or
+//hexadecimal.
+func supplicantString(v string) (string, error) {
+	switch {
+	case len(v) >= 2 && v[0] == '"' && v[len(v)-1] == '"':
+		return v[1 : len(v)-1], nil
+	case len(v) >= 3 && v[0] == 'P' && v[1] == '"' && v[len(v)-1] == '"':
+		var b strings.Builder
+		for rest := v[2 : len(v)-1]; rest != ""; {
+			if strings.HasPrefix(rest, `\"`) {
+				b.WriteByte('"')
+				rest = rest[2:]
+				continue
+			}
+			c, multibyte, tail, err := strconv.UnquoteChar(rest, '"')
+			if err != nil {
+				return "", fmt.Errorf("profile: invalid string %s", v)
+			}
+			if multibyte {
+				b.WriteRune(c)
+			} else {
+				b.WriteByte(byte(c))
+			}
+			rest = tail
+		}
+		return b.String(), nil
+	}
+	b, err := hex.DecodeString(v)
+	if err != nil {
+		return "", fmt.Errorf("profile: invalid hexadecimal string %s", v)
+	}
+	return string(b), nil
+}
+
+//quoteSupplicant returns the value of a string: quoted when it is printable, hexadecimal otherwise.
+func quoteSupplicant(s string) string {
+	if !utf8.ValidString(s) {
+		return hex.EncodeToString([]byte(s))
+	}
+	for _, r := range s {
+		if r < 0x20 || r == 0x7f {
+			return hex.EncodeToString([]byte(s))
+		}
+	}
+	return `"` + s + `"`
+}
+
+//supplicantCredentials are the keys of the credentials of a user, which profiles do not hold.
+var supplicantCredentials = map[string]bool{
+	"identity": true, "password": true, "client_cert": true, "private_key": true, "private_key_passwd": true,
+	"ca_cert": true, "ca_path": true, "pin": true,
+}
+
+//FromSupplicant returns the profile of a network block, with warnings for the settings that profiles lack.
+func FromSupplicant(n SupplicantNetwork) (*WLANProfile, []Warning, error) {
+	w := &warnings{}
+	get := func(key string) string {
+		v, _ := n.Get(key)
+		return v
+	}
+
+	v, ok := n.Get("ssid")
+	if !ok {
+		return nil, nil, fmt.Errorf("profile: network has no ssid")
+	}
+	ssid, err := supplicantString(v)
+	if err != nil {
+		return nil, nil, err
+	}
+	b := Builder{SSID: ssid, Hidden: get("scan_ssid") == "1", Manual: get("disabled") == "1"}
+	if v, ok := n.Get("id_str"); ok {
+		b.Name, _ = supplicantString(v)
+	}
+	w.network = b.Name
+	if w.network == "" {
+		w.network = ssid
+	}
+	if mode := get("mode"); mode != "" && mode != "0" {
+		return nil, nil, fmt.Errorf("profile: network %s: mode %s is not an infrastructure network", w.network, mode)
+	}
+
+	keyMgmt := map[string]bool{}
+	for _, k := range strings.Fields(get("key_mgmt")) {
+		//Fast transition is negotiated by Windows with the BSS; it is not in the profile.
+		keyMgmt[strings.TrimPrefix(k, "FT-")] = true
+	}
+	if len(keyMgmt) == 0 {
+		//The default of wpa_supplicant is WPA-PSK WPA-EAP; the keys of the block tell which.
+		if _, ok := n.Get("eap"); ok {
+			keyMgmt["WPA-EAP"] = true
+		} else {
+			keyMgmt["WPA-PSK"] = true
+		}
+	}
+	wpa1 := get("proto") == "WPA" || get("pairwise") == "TKIP"
+	pmf := get("ieee80211w")
+	switch {
+	case keyMgmt["SAE"] && (keyMgmt["WPA-PSK"] || keyMgmt["WPA-PSK-SHA256"]):
+		b.Security = WPA3Transition
+	case keyMgmt["SAE"]:
+		b.Security = WPA3Personal
+	case keyMgmt["WPA-PSK"] || keyMgmt["WPA-PSK-SHA256"]:
+		b.Security = WPA2Personal
+		if wpa1 {
+			b.Security = WPAPersonal
+		}
+	case keyMgmt["OWE"]:
+		b.Security = OWE
+	case keyMgmt["WPA-EAP-SUITE-B-192"]:
+		b.Security = WPA3Enterprise192
+	case keyMgmt["WPA-EAP-SHA256"] || keyMgmt["WPA-EAP"] && pmf == "2":
+		b.Security = WPA3Enterprise
+	case keyMgmt["WPA-EAP"]:
+		b.Security = WPA2Enterprise
+		if wpa1 {
+			w.add("proto", "WPA-Enterprise is converted as WPA2-Enterprise")
+		}
+	case keyMgmt["NONE"]:
+		b.Security = Open
+		if _, ok := n.Get("wep_key0"); ok {
+			b.Security = WEP
+		}
+	default:
+		return nil, nil, fmt.Errorf("profile: network %s: unsupported key_mgmt %s", w.network, get("key_mgmt"))
+	}
+
+	if b.Security.Personal() {
+		v, ok := n.Get("sae_password")
+		if !ok {
+			v = get("psk")
+		}
+		if b.Security == WEP {
+			v = get("wep_key0")
+			if idx := get("wep_tx_keyidx"); idx != "" && idx != "0" {
+				w.add("wep_tx_keyidx", "only the first WEP key is converted")
+			}
+		}
+		if strings.HasPrefix(v, `"`) || strings.HasPrefix(v, `P"`) {
+			b.Key, err = supplicantString(v)
+		} else {
+			//Unquoted keys are the hexadecimal digits of a PSK or a WEP key, which profiles hold as is.
+			b.Key = v
+		}
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	if b.Security.Enterprise() {
+		if b.EAP, err = supplicantEAP(n, w); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	for _, f := range n {
+		switch {
+		case supplicantCredentials[f.Key]:
+			w.add(f.Key, "credentials and certificate files are not stored in profiles")
+		case strings.HasPrefix(f.Key, "wep_key") && f.Key != "wep_key0":
+			w.add(f.Key, "only the first WEP key is converted")
+		case f.Key == "auth_alg" && f.Value != "OPEN":
+			w.add(f.Key, "only open system authentication is converted")
+		case f.Key == "anonymous_identity" && b.EAP != nil && b.EAP.Method != EAPTTLS:
+			w.add(f.Key, "only TTLS profiles have an anonymous identity")
+		}
+		switch f.Key {
+		case "ssid", "scan_ssid", "disabled", "id_str", "mode", "key_mgmt", "proto", "pairwise", "group", "ieee80211w",
+			"psk", "sae_password", "wep_key0", "wep_key1", "wep_key2", "wep_key3", "wep_tx_keyidx", "auth_alg",
+			"eap", "phase2", "anonymous_identity", "domain_suffix_match", "domain_match":
+		default:
+			if !supplicantCredentials[f.Key] {
+				w.add(f.Key, "profiles have no such setting")
+			}
+		}
+	}
+	p, err := b.Build()
+	if err != nil {
+		return nil, nil, err
+	}
+	return p, w.list, nil
+}
+
+//supplicantEAP returns the EAP configuration of a network block.
+func supplicantEAP(n SupplicantNetwork, w *warnings) (*EAP, error) {
+	methods := strings.Fields(func() string { v, _ := n.Get("eap"); return v }())
+	if len(methods) == 0 {
+		w.add("eap", "the network has no EAP method")
+		return nil, nil
+	}
+	if len(methods) > 1 {
+		w.add("eap", "only the first of the EAP methods %s is converted", strings.Join(methods, " "))
+	}
+	e := &EAP{}
+	switch methods[0] {
+	case "PEAP":
+		e.Method = EAPPEAP
+	case "TTLS":
+		e.Method = EAPTTLS
+	case "TLS":
+		e.Method = EAPTLS
+	default:
+		return nil, fmt.Errorf("profile: unsupported EAP method %s", methods[0])
+	}
+
+	if v, ok := n.Get("phase2"); ok {
+		phase2, err := supplicantString(v)
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range strings.Fields(phase2) {
+			auth := strings.TrimPrefix(strings.TrimPrefix(p, "auth="), "autheap=")
+			switch strings.ToUpper(auth) {
+			case "PAP":
+				e.Phase2 = Phase2PAP
+			case "CHAP":
+				e.Phase2 = Phase2CHAP
+			case "MSCHAP":
+				e.Phase2 = Phase2MSCHAP
+			case "MSCHAPV2":
+				e.Phase2 = Phase2MSCHAPv2
+			default:
+				return nil, fmt.Errorf("profile: unsupported inner authentication %s", p)
+			}
+			if strings.HasPrefix(p, "autheap=") && e.Method == EAPTTLS {
+				w.add("phase2", "EAP-MSCHAPv2 is converted as MSCHAPv2")
+			}
+		}
+	}
+	switch {
+	case e.Method == EAPTLS:
+		e.Phase2 = ""
+	case e.Method == EAPPEAP && e.Phase2 == "":
+		e.Phase2 = Phase2MSCHAPv2
+	case e.Method == EAPTTLS && e.Phase2 == "":
+		return nil, fmt.Errorf("profile: TTLS network has no inner authentication")
+	}
+	if e.Method == EAPTTLS {
+		if v, ok := n.Get("anonymous_identity"); ok {
+			e.AnonymousIdentity, _ = supplicantString(v)
+		}
+	}
+	for _, key := range []string{"domain_suffix_match", "domain_match"} {
+		if v, ok := n.Get(key); ok {
+			names, err := supplicantString(v)
+			if err != nil {
+				return nil, err
+			}
+			e.ServerNames = append(e.ServerNames, splitNames(names)...)
+		}
+	}
+	return e, nil
+}
+
+//ToSupplicant returns the network block of a profile, with warnings for the settings that wpa_supplicant lacks.
+func ToSupplicant(p *WLANProfile) (SupplicantNetwork, []Warning, error) {
+	w := &warnings{}
+	b, err := p.export(w)
+	if err != nil {
+		return nil, nil, err
+	}
+	n := SupplicantNetwork{{"ssid", quoteSupplicant(b.SSID)}}
+	add := func(key, value string) { n = append(n, Field{key, value}) }
+	if b.Name != b.SSID {
+		add("id_str", quoteSupplicant(b.Name))
+	}
+	if b.Hidden {
+		add("scan_ssid", "1")
+	}
+	psk := func() {
+		if len(b.Key) == 64 {
+			add("psk", b.Key)
+		} else {
+			add("psk", quoteSupplicant(b.Key))
+		}
+	}
+	switch b.Security {
+	case Open:
+		add("key_mgmt", "NONE")
+	case WEP:
+		add("key_mgmt", "NONE")
+		if len(b.Key) == 5 || len(b.Key) == 13 {
+			add("wep_key0", quoteSupplicant(b.Key))
+		} else {
+			add("wep_key0", b.Key)
+		}
+		add("wep_tx_keyidx", "0")
+	case WPAPersonal:
+		add("key_mgmt", "WPA-PSK")
+		add("proto", "WPA")
+		add("pairwise", "TKIP")
+		psk()
+	case WPA2Personal:
+		add("key_mgmt", "WPA-PSK")
+		add("proto", "RSN")
+		add("pairwise", "CCMP")
+		psk()
+	case WPA3Personal:
+		if len(b.Key) == 64 {
+			return nil, nil, fmt.Errorf("profile: %s: SAE takes a passphrase rather than a PSK", b.Name)
+		}
+		add("key_mgmt", "SAE")
+		add("ieee80211w", "2")
+		add("sae_password", quoteSupplicant(b.Key))
+	case WPA3Transition:
+		add("key_mgmt", "WPA-PSK SAE")
+		add("ieee80211w", "1")
+		psk()
+	case OWE:
+		add("key_mgmt", "OWE")
+		add("ieee80211w", "2")
+	case WPA2Enterprise:
+		add("key_mgmt", "WPA-EAP")
+		add("proto", "RSN")
+		add("pairwise", "CCMP")
+	case WPA3Enterprise:
+		add("key_mgmt", "WPA-EAP WPA-EAP-SHA256")
+		add("ieee80211w", "2")
+	case WPA3Enterprise192:
+		add("key_mgmt", "WPA-EAP-SUITE-B-192")
+		add("pairwise", "GCMP-256")
+		add("group", "GCMP-256")
+		add("ieee80211w", "2")
+	}
+	if e := b.EAP; e != nil {
+		add("eap", strings.ToUpper(e.Method.String()))
+		if e.Phase2 != "" {
+			add("phase2", quoteSupplicant("auth="+strings.ToUpper(e.Phase2)))
+		}
+		if e.AnonymousIdentity != "" {
+			add("anonymous_identity", quoteSupplicant(e.AnonymousIdentity))
+		}
+		if len(e.ServerNames) > 0 {
+			add("domain_suffix_match", quoteSupplicant(strings.Join(e.ServerNames, ";")))
+		}
+		if len(e.TrustedRootCAs) > 0 {
+			w.add("TrustedRootCA", "wpa_supplicant trusts CAs by certificate file rather than thumbprint; set ca_cert")
+		}
+	}
+	if b.Manual {
+		add("disabled", "1")
+	}
+	return n, w.list, nil
+}
@@ -0,0 +1,79 @@
+package wlanapi
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+)
+
+const (
+	//rawDataListHeaderSize is the size of dwTotalSize and dwNumberOfItems of WLAN_RAW_DATA_LIST.
+	rawDataListHeaderSize = 8
+	//rawDataListEntrySize is the size of a DataList entry (dwDataOffset, dwDataSize) of WLAN_RAW_DATA_LIST.
+	rawDataListEntrySize = 8
+)
+
+//PSDIEList is a proximity service discovery (PSD) information element (IE) data list
+//as passed to WlanSetPsdIEDataList.
+type PSDIEList struct {
+	//Format is the format URI of the IEs in the list.
+	Format string
+	//IEs are the IE data blobs.
+	IEs [][]byte
+}
+
+//MarshalBinary serializes the IEs into the WLAN_RAW_DATA_LIST layout: the header, then a
+//table of data offsets and sizes relative to the start of the list, then the data blobs in order.
+//All fields are little-endian DWORDs.
+func (l *PSDIEList) MarshalBinary() ([]byte, error) {
+	size := rawDataListHeaderSize + rawDataListEntrySize*len(l.IEs)
+	for _, ie := range l.IEs {
+		size += len(ie)
+	}
+	if uint64(size) > math.MaxUint32 {
+		return nil, errors.New("wlanapi: PSD IE data list is too large")
+	}
+
+	b := make([]byte, size)
+	binary.LittleEndian.PutUint32(b[0:], uint32(size))
+	binary.LittleEndian.PutUint32(b[4:], uint32(len(l.IEs)))
+	offset := rawDataListHeaderSize + rawDataListEntrySize*len(l.IEs)
+	for n, ie := range l.IEs {
+		entry := b[rawDataListHeaderSize+rawDataListEntrySize*n:]
+		binary.LittleEndian.PutUint32(entry[0:], uint32(offset))
+		binary.LittleEndian.PutUint32(entry[4:], uint32(len(ie)))
+		offset += copy(b[offset:], ie)
+	}
+	return b, nil
+}
+
+//UnmarshalBinary parses a WLAN_RAW_DATA_LIST. The blobs may be in any order and may overlap;
+//each is copied. Format is left unchanged since it is not part of the layout.
+func (l *PSDIEList) UnmarshalBinary(b []byte) error {
+	if len(b) < rawDataListHeaderSize {
+		return errors.New("wlanapi: WLAN_RAW_DATA_LIST is shorter than its header")
+	}
+	total := binary.LittleEndian.Uint32(b[0:])
+	count := binary.LittleEndian.Uint32(b[4:])
+	if uint64(total) > uint64(len(b)) {
+		return fmt.Errorf("wlanapi: WLAN_RAW_DATA_LIST dwTotalSize %d exceeds %d bytes", total, len(b))
+	}
+	b = b[:total]
+	if uint64(count) > uint64(len(b)-rawDataListHeaderSize)/rawDataListEntrySize {
+		return fmt.Errorf("wlanapi: WLAN_RAW_DATA_LIST dwNumberOfItems %d exceeds dwTotalSize %d", count, total)
+	}
+
+	ies := make([][]byte, count)
+	for n := range ies {
+		entry := b[rawDataListHeaderSize+rawDataListEntrySize*n:]
+		offset := uint64(binary.LittleEndian.Uint32(entry[0:]))
+		size := uint64(binary.LittleEndian.Uint32(entry[4:]))
+		if offset+size > uint64(len(b)) {
+			return fmt.Errorf("wlanapi: WLAN_RAW_DATA_LIST item %d at %d+%d exceeds dwTotalSize %d", n, offset, size, total)
+		}
+		ies[n] = append([]byte{}, b[offset:offset+size]...)
+	}
+	l.IEs = ies
+	return nil
+}
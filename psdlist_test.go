@@ -0,0 +1,71 @@
+package wlanapi
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestPSDIEListMarshalBinary(t *testing.T) {
+	l := &PSDIEList{
+		Format: "urn:example:psd",
+		IEs:    [][]byte{{0xdd, 0x03, 0x50}, {}, {0x01, 0x02, 0x03, 0x04, 0x05}},
+	}
+	got, err := l.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []byte{
+		0x28, 0x00, 0x00, 0x00, // dwTotalSize = 40
+		0x03, 0x00, 0x00, 0x00, // dwNumberOfItems = 3
+		0x20, 0x00, 0x00, 0x00, 0x03, 0x00, 0x00, 0x00, // DataList[0] = {32, 3}
+		0x23, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, // DataList[1] = {35, 0}
+		0x23, 0x00, 0x00, 0x00, 0x05, 0x00, 0x00, 0x00, // DataList[2] = {35, 5}
+		0xdd, 0x03, 0x50,
+		0x01, 0x02, 0x03, 0x04, 0x05,
+	}
+	if !bytes.Equal(got, want) {
+		t.Fatalf("MarshalBinary =\n% x\nwant\n% x", got, want)
+	}
+
+	var parsed PSDIEList
+	if err := parsed.UnmarshalBinary(got); err != nil {
+		t.Fatal(err)
+	}
+	if len(parsed.IEs) != len(l.IEs) {
+		t.Fatalf("UnmarshalBinary returned %d IEs", len(parsed.IEs))
+	}
+	for n := range l.IEs {
+		if !bytes.Equal(parsed.IEs[n], l.IEs[n]) {
+			t.Errorf("IE %d = % x, want % x", n, parsed.IEs[n], l.IEs[n])
+		}
+	}
+}
+
+func TestPSDIEListMarshalBinaryEmpty(t *testing.T) {
+	got, err := (&PSDIEList{}).MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []byte{0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}
+	if !bytes.Equal(got, want) {
+		t.Fatalf("MarshalBinary = % x, want % x", got, want)
+	}
+}
+
+func TestPSDIEListUnmarshalBinaryErrors(t *testing.T) {
+	tests := map[string][]byte{
+		"short header": {0x08, 0x00, 0x00},
+		"total size":   {0x10, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00},
+		"item count":   {0x08, 0x00, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00},
+		"item bounds": {
+			0x10, 0x00, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00,
+			0x0f, 0x00, 0x00, 0x00, 0x02, 0x00, 0x00, 0x00,
+		},
+	}
+	for name, b := range tests {
+		var l PSDIEList
+		if err := l.UnmarshalBinary(b); err == nil {
+			t.Errorf("%s: UnmarshalBinary succeeded", name)
+		}
+	}
+}
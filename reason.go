@@ -0,0 +1,90 @@
+package wlanapi
+
+import "fmt"
+
+//The WLAN_REASON_CODE type explains why an operation failed or why a network is not connectable.
+//https://docs.microsoft.com/en-us/windows/win32/nativewifi/wlan-reason-code
+type WLAN_REASON_CODE uint32
+
+const (
+	WLAN_REASON_CODE_SUCCESS    WLAN_REASON_CODE = 0
+	WLAN_REASON_CODE_UNKNOWN    WLAN_REASON_CODE = 0x10001
+	WLAN_REASON_CODE_RANGE_SIZE WLAN_REASON_CODE = 0x10000
+
+	WLAN_REASON_CODE_BASE            WLAN_REASON_CODE = 0x20000
+	WLAN_REASON_CODE_AC_BASE         WLAN_REASON_CODE = 0x20000
+	WLAN_REASON_CODE_AC_CONNECT_BASE WLAN_REASON_CODE = WLAN_REASON_CODE_AC_BASE + WLAN_REASON_CODE_RANGE_SIZE/2
+	WLAN_REASON_CODE_PROFILE_BASE    WLAN_REASON_CODE = 0x80000
+	WLAN_REASON_CODE_MSM_BASE        WLAN_REASON_CODE = 0x30000
+	WLAN_REASON_CODE_MSMSEC_BASE     WLAN_REASON_CODE = 0x40000
+	WLAN_REASON_CODE_ONEX_BASE       WLAN_REASON_CODE = 0x50000
+
+	WLAN_REASON_CODE_NETWORK_NOT_COMPATIBLE WLAN_REASON_CODE = WLAN_REASON_CODE_AC_BASE + 1
+	WLAN_REASON_CODE_PROFILE_NOT_COMPATIBLE WLAN_REASON_CODE = WLAN_REASON_CODE_AC_BASE + 2
+
+	WLAN_REASON_CODE_NO_AUTO_CONNECTION                WLAN_REASON_CODE = WLAN_REASON_CODE_AC_CONNECT_BASE + 1
+	WLAN_REASON_CODE_NOT_VISIBLE                       WLAN_REASON_CODE = WLAN_REASON_CODE_AC_CONNECT_BASE + 2
+	WLAN_REASON_CODE_GP_DENIED                         WLAN_REASON_CODE = WLAN_REASON_CODE_AC_CONNECT_BASE + 3
+	WLAN_REASON_CODE_USER_DENIED                       WLAN_REASON_CODE = WLAN_REASON_CODE_AC_CONNECT_BASE + 4
+	WLAN_REASON_CODE_BSS_TYPE_NOT_ALLOWED              WLAN_REASON_CODE = WLAN_REASON_CODE_AC_CONNECT_BASE + 5
+	WLAN_REASON_CODE_IN_FAILED_LIST                    WLAN_REASON_CODE = WLAN_REASON_CODE_AC_CONNECT_BASE + 6
+	WLAN_REASON_CODE_IN_BLOCKED_LIST                   WLAN_REASON_CODE = WLAN_REASON_CODE_AC_CONNECT_BASE + 7
+	WLAN_REASON_CODE_SSID_LIST_TOO_LONG                WLAN_REASON_CODE = WLAN_REASON_CODE_AC_CONNECT_BASE + 8
+	WLAN_REASON_CODE_CONNECT_CALL_FAIL                 WLAN_REASON_CODE = WLAN_REASON_CODE_AC_CONNECT_BASE + 9
+	WLAN_REASON_CODE_SCAN_CALL_FAIL                    WLAN_REASON_CODE = WLAN_REASON_CODE_AC_CONNECT_BASE + 10
+	WLAN_REASON_CODE_NETWORK_NOT_AVAILABLE             WLAN_REASON_CODE = WLAN_REASON_CODE_AC_CONNECT_BASE + 11
+	WLAN_REASON_CODE_PROFILE_CHANGED_OR_DELETED        WLAN_REASON_CODE = WLAN_REASON_CODE_AC_CONNECT_BASE + 12
+	WLAN_REASON_CODE_KEY_MISMATCH                      WLAN_REASON_CODE = WLAN_REASON_CODE_AC_CONNECT_BASE + 13
+	WLAN_REASON_CODE_USER_NOT_RESPOND                  WLAN_REASON_CODE = WLAN_REASON_CODE_AC_CONNECT_BASE + 14
+	WLAN_REASON_CODE_AP_PROFILE_NOT_ALLOWED_FOR_CLIENT WLAN_REASON_CODE = WLAN_REASON_CODE_AC_CONNECT_BASE + 15
+	WLAN_REASON_CODE_AP_PROFILE_NOT_ALLOWED            WLAN_REASON_CODE = WLAN_REASON_CODE_AC_CONNECT_BASE + 16
+	WLAN_REASON_CODE_HOTSPOT2_PROFILE_DENIED           WLAN_REASON_CODE = WLAN_REASON_CODE_AC_CONNECT_BASE + 17
+)
+
+var reasonCodeStrings = map[WLAN_REASON_CODE]string{
+	WLAN_REASON_CODE_SUCCESS:                           "success",
+	WLAN_REASON_CODE_UNKNOWN:                           "unknown reason",
+	WLAN_REASON_CODE_NETWORK_NOT_COMPATIBLE:            "network not compatible",
+	WLAN_REASON_CODE_PROFILE_NOT_COMPATIBLE:            "profile not compatible",
+	WLAN_REASON_CODE_NO_AUTO_CONNECTION:                "no auto connection",
+	WLAN_REASON_CODE_NOT_VISIBLE:                       "network not visible",
+	WLAN_REASON_CODE_GP_DENIED:                         "denied by group policy",
+	WLAN_REASON_CODE_USER_DENIED:                       "denied by user",
+	WLAN_REASON_CODE_BSS_TYPE_NOT_ALLOWED:              "BSS type not allowed",
+	WLAN_REASON_CODE_IN_FAILED_LIST:                    "in failed list",
+	WLAN_REASON_CODE_IN_BLOCKED_LIST:                   "in blocked list",
+	WLAN_REASON_CODE_SSID_LIST_TOO_LONG:                "SSID list too long",
+	WLAN_REASON_CODE_CONNECT_CALL_FAIL:                 "connect call failed",
+	WLAN_REASON_CODE_SCAN_CALL_FAIL:                    "scan call failed",
+	WLAN_REASON_CODE_NETWORK_NOT_AVAILABLE:             "network not available",
+	WLAN_REASON_CODE_PROFILE_CHANGED_OR_DELETED:        "profile changed or deleted",
+	WLAN_REASON_CODE_KEY_MISMATCH:                      "key mismatch",
+	WLAN_REASON_CODE_USER_NOT_RESPOND:                  "user did not respond",
+	WLAN_REASON_CODE_AP_PROFILE_NOT_ALLOWED_FOR_CLIENT: "AP profile not allowed for client",
+	WLAN_REASON_CODE_AP_PROFILE_NOT_ALLOWED:            "AP profile not allowed",
+	WLAN_REASON_CODE_HOTSPOT2_PROFILE_DENIED:           "Hotspot 2.0 profile denied",
+}
+
+//String describes the reason code. Codes without a description are named after their range,
+//as WlanReasonCodeToString is only available on Windows.
+func (r WLAN_REASON_CODE) String() string {
+	if s, ok := reasonCodeStrings[r]; ok {
+		return s
+	}
+	var module string
+	switch {
+	case r >= WLAN_REASON_CODE_PROFILE_BASE && r < WLAN_REASON_CODE_PROFILE_BASE+WLAN_REASON_CODE_RANGE_SIZE:
+		module = "profile"
+	case r >= WLAN_REASON_CODE_ONEX_BASE && r < WLAN_REASON_CODE_ONEX_BASE+WLAN_REASON_CODE_RANGE_SIZE:
+		module = "802.1X"
+	case r >= WLAN_REASON_CODE_MSMSEC_BASE && r < WLAN_REASON_CODE_MSMSEC_BASE+WLAN_REASON_CODE_RANGE_SIZE:
+		module = "MSM security"
+	case r >= WLAN_REASON_CODE_MSM_BASE && r < WLAN_REASON_CODE_MSM_BASE+WLAN_REASON_CODE_RANGE_SIZE:
+		module = "MSM"
+	case r >= WLAN_REASON_CODE_AC_BASE && r < WLAN_REASON_CODE_AC_BASE+WLAN_REASON_CODE_RANGE_SIZE:
+		module = "auto config"
+	default:
+		return fmt.Sprintf("WLAN_REASON_CODE(0x%x)", uint32(r))
+	}
+	return fmt.Sprintf("%s reason 0x%x", module, uint32(r))
+}
@@ -0,0 +1,204 @@
+//Package roam reports the fast roaming support of the BSSes of each SSID: FT (802.11r) over the air
+//and over the DS, radio measurement neighbor reports (802.11k) and BSS transition management (802.11v),
+//and points out the APs whose configuration breaks roaming between them.
+package roam
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"sort"
+
+	"wlanapi"
+	"wlanapi/binary"
+	"wlanapi/ie"
+)
+
+//BSS is the roaming support of a BSS.
+type BSS struct {
+	BSSID    [6]byte
+	Channel  int
+	RSSI     int32
+	Security ie.Security
+	//MobilityDomain is nil without a Mobility Domain element.
+	MobilityDomain *ie.MobilityDomainElement
+	//FTAKM is set when the BSS advertises an FT AKM.
+	FTAKM bool
+	//FTOverAir and FTOverDS are set when the BSS advertises both an FT AKM and a mobility domain.
+	FTOverAir bool
+	FTOverDS  bool
+	//NeighborReport is 802.11k, BeaconReport the beacon measurements that 802.11k steering relies on.
+	NeighborReport bool
+	BeaconReport   bool
+	//BSSTransition is 802.11v BSS transition management; WNMSleepMode and WNMNotification are the other WNM services.
+	BSSTransition   bool
+	WNMSleepMode    bool
+	WNMNotification bool
+}
+
+//NewBSS decodes the roaming elements of a BSS entry.
+func NewBSS(e binary.BSSEntry) BSS {
+	b := BSS{BSSID: e.BSSID, Channel: wlanapi.ChannelOf(e.ChCenterFrequency), RSSI: e.RSSI}
+	es, _ := ie.Parse(e.IEs)
+	b.Security, _ = ie.ParseSecurity(e.CapabilityInformation, es)
+	for _, a := range b.Security.AKMs() {
+		b.FTAKM = b.FTAKM || a.FT()
+	}
+	if m, ok := es.Find(ie.MobilityDomain); ok {
+		b.MobilityDomain, _ = ie.ParseMobilityDomain(m.Data)
+	}
+	if b.FTAKM && b.MobilityDomain != nil {
+		b.FTOverAir = true
+		b.FTOverDS = b.MobilityDomain.OverDS()
+	}
+	rm := es.RMEnabledCapabilities()
+	b.NeighborReport = rm.Has(ie.RMNeighborReport)
+	b.BeaconReport = rm.Has(ie.RMBeaconPassive) || rm.Has(ie.RMBeaconActive) || rm.Has(ie.RMBeaconTable)
+	ext := es.ExtendedCapabilities()
+	b.BSSTransition = ext.Has(ie.ExtCapBSSTransition)
+	b.WNMSleepMode = ext.Has(ie.ExtCapWNMSleepMode)
+	b.WNMNotification = ext.Has(ie.ExtCapWNMNotification)
+	return b
+}
+
+//Problem is a configuration of a BSS that breaks or slows down roaming.
+type Problem struct {
+	BSSID   [6]byte
+	Message string
+}
+
+func (p Problem) String() string {
+	return fmt.Sprintf("%s: %s", net.HardwareAddr(p.BSSID[:]), p.Message)
+}
+
+//Report is the roaming support of the BSSes of an SSID.
+type Report struct {
+	SSID  []byte
+	BSSes []BSS
+	//MobilityDomains are the distinct MDIDs of the BSSes, in increasing order.
+	MobilityDomains []uint16
+	//FT, FTOverDS, NeighborReport and BSSTransition are set when every BSS supports them.
+	FT             bool
+	FTOverDS       bool
+	NeighborReport bool
+	BSSTransition  bool
+	Problems       []Problem
+}
+
+//Analyze returns the reports of the SSIDs of the infrastructure BSSes of a BSS list, ordered by SSID.
+//Hidden SSIDs are left out since their BSSes cannot be grouped.
+func Analyze(entries []binary.BSSEntry) []*Report {
+	bySSID := map[string]*Report{}
+	var reports []*Report
+	for _, e := range entries {
+		if len(bytes.Trim(e.SSID, "\x00")) == 0 || wlanapi.DOT11_BSS_TYPE(e.BssType) != wlanapi.Dot11BssTypeInfrastructure {
+			continue
+		}
+		r := bySSID[string(e.SSID)]
+		if r == nil {
+			r = &Report{SSID: e.SSID}
+			bySSID[string(e.SSID)] = r
+			reports = append(reports, r)
+		}
+		r.BSSes = append(r.BSSes, NewBSS(e))
+	}
+	sort.Slice(reports, func(i, j int) bool { return bytes.Compare(reports[i].SSID, reports[j].SSID) < 0 })
+	for _, r := range reports {
+		r.analyze()
+	}
+	return reports
+}
+
+func (r *Report) analyze() {
+	problem := func(b *BSS, format string, a ...interface{}) {
+		r.Problems = append(r.Problems, Problem{BSSID: b.BSSID, Message: fmt.Sprintf(format, a...)})
+	}
+
+	r.FT, r.FTOverDS, r.NeighborReport, r.BSSTransition = true, true, true, true
+	var ft, overDS, neighbor, transition int
+	domains := map[uint16]int{}
+	for n := range r.BSSes {
+		b := &r.BSSes[n]
+		r.FT = r.FT && b.FTOverAir
+		r.FTOverDS = r.FTOverDS && b.FTOverDS
+		r.NeighborReport = r.NeighborReport && b.NeighborReport
+		r.BSSTransition = r.BSSTransition && b.BSSTransition
+		if b.FTOverAir {
+			ft++
+		}
+		if b.FTOverDS {
+			overDS++
+		}
+		if b.NeighborReport {
+			neighbor++
+		}
+		if b.BSSTransition {
+			transition++
+		}
+		if b.MobilityDomain != nil {
+			if _, ok := domains[b.MobilityDomain.MDID]; !ok {
+				r.MobilityDomains = append(r.MobilityDomains, b.MobilityDomain.MDID)
+			}
+			domains[b.MobilityDomain.MDID]++
+		}
+	}
+	sort.Slice(r.MobilityDomains, func(i, j int) bool { return r.MobilityDomains[i] < r.MobilityDomains[j] })
+
+	//The mobility domain most BSSes use is taken as the intended one, the lowest MDID on ties.
+	var mdid uint16
+	for _, d := range r.MobilityDomains {
+		if domains[d] > domains[mdid] {
+			mdid = d
+		}
+	}
+	security := r.majoritySecurity()
+
+	total := len(r.BSSes)
+	for n := range r.BSSes {
+		b := &r.BSSes[n]
+		switch {
+		case b.FTAKM && b.MobilityDomain == nil:
+			problem(b, "advertises an FT AKM without a Mobility Domain element, so FT cannot be used")
+		case !b.FTAKM && b.MobilityDomain != nil:
+			problem(b, "advertises mobility domain 0x%04x without an FT AKM, so FT cannot be used", b.MobilityDomain.MDID)
+		case !b.FTOverAir && ft > 0:
+			problem(b, "does not support FT while %d of %d BSSes do; stations roaming to it do a full authentication", ft, total)
+		}
+		if b.MobilityDomain != nil && len(r.MobilityDomains) > 1 && b.MobilityDomain.MDID != mdid {
+			problem(b, "is in mobility domain 0x%04x while most BSSes are in 0x%04x; stations cannot fast-roam between them",
+				b.MobilityDomain.MDID, mdid)
+		}
+		if b.FTOverAir && !b.FTOverDS && overDS > 0 {
+			problem(b, "does not support FT over the DS while %d of %d BSSes do", overDS, total)
+		}
+		if !b.NeighborReport && neighbor > 0 {
+			problem(b, "does not support 802.11k neighbor reports while %d of %d BSSes do", neighbor, total)
+		}
+		if !b.BSSTransition && transition > 0 {
+			problem(b, "does not support 802.11v BSS transition management while %d of %d BSSes do", transition, total)
+		}
+		if s := b.Security.String(); s != security {
+			problem(b, "advertises %s while most BSSes advertise %s; stations may not roam to it", s, security)
+		}
+	}
+}
+
+//majoritySecurity returns the security most BSSes advertise, the first one on ties.
+func (r *Report) majoritySecurity() string {
+	counts := map[string]int{}
+	var order []string
+	for _, b := range r.BSSes {
+		s := b.Security.String()
+		if counts[s] == 0 {
+			order = append(order, s)
+		}
+		counts[s]++
+	}
+	var security string
+	for n, s := range order {
+		if n == 0 || counts[s] > counts[security] {
+			security = s
+		}
+	}
+	return security
+}
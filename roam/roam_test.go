@@ -0,0 +1,113 @@
+package roam
+
+import (
+	"strings"
+	"testing"
+
+	"wlanapi/binary"
+)
+
+var (
+	rsnFT  = []byte{48, 24, 1, 0, 0, 0x0f, 0xac, 4, 1, 0, 0, 0x0f, 0xac, 4, 2, 0, 0, 0x0f, 0xac, 1, 0, 0x0f, 0xac, 3, 0, 0}
+	rsnEAP = []byte{48, 20, 1, 0, 0, 0x0f, 0xac, 4, 1, 0, 0, 0x0f, 0xac, 4, 1, 0, 0, 0x0f, 0xac, 1, 0, 0}
+	rsnPSK = []byte{48, 20, 1, 0, 0, 0x0f, 0xac, 4, 1, 0, 0, 0x0f, 0xac, 4, 1, 0, 0, 0x0f, 0xac, 2, 0, 0}
+	//rm sets neighbor report and passive and active beacon measurements.
+	rm = []byte{70, 5, 0x32, 0, 0, 0, 0}
+	//ext sets BSS transition.
+	ext = []byte{127, 3, 0, 0, 0x08}
+)
+
+func md(mdid uint16, overDS bool) []byte {
+	e := []byte{54, 3, byte(mdid), byte(mdid >> 8), 0}
+	if overDS {
+		e[4] = 1
+	}
+	return e
+}
+
+func entry(ssid string, id byte, ies ...[]byte) binary.BSSEntry {
+	e := binary.BSSEntry{SSID: []byte(ssid), BSSID: [6]byte{0, 0x1a, 0x1e, 0, 0, id}, BssType: 1, RSSI: -60,
+		ChCenterFrequency: 5180000, CapabilityInformation: 0x0011}
+	for _, ie := range ies {
+		e.IEs = append(e.IEs, ie...)
+	}
+	return e
+}
+
+func TestAnalyze(t *testing.T) {
+	reports := Analyze([]binary.BSSEntry{
+		entry("corp", 1, rsnFT, md(0x1234, true), rm, ext),
+		entry("corp", 2, rsnFT, md(0x1234, true), rm, ext),
+		entry("corp", 3, rsnFT, md(0x1234, false), rm),
+		entry("corp", 4, rsnFT, md(0x9999, true), ext),
+		entry("corp", 5, rsnEAP, rm, ext),
+		entry("corp", 6, rsnFT, rm, ext),
+		entry("corp", 7, rsnEAP, md(0x1234, true), rm, ext),
+		entry("corp", 8, rsnPSK, rm, ext),
+		entry("home", 1, rsnPSK, rm, ext),
+		entry("home", 2, rsnPSK, rm, ext),
+		entry("", 3, rsnPSK),
+	})
+	if len(reports) != 2 || string(reports[0].SSID) != "corp" || string(reports[1].SSID) != "home" {
+		t.Fatalf("reports %v", reports)
+	}
+
+	corp := reports[0]
+	if len(corp.BSSes) != 8 || corp.FT || corp.NeighborReport || corp.BSSTransition {
+		t.Errorf("corp: %+v", corp)
+	}
+	if len(corp.MobilityDomains) != 2 || corp.MobilityDomains[0] != 0x1234 || corp.MobilityDomains[1] != 0x9999 {
+		t.Errorf("mobility domains %x", corp.MobilityDomains)
+	}
+	b := corp.BSSes[0]
+	if !b.FTAKM || !b.FTOverAir || !b.FTOverDS || !b.NeighborReport || !b.BeaconReport || !b.BSSTransition || b.WNMSleepMode {
+		t.Errorf("first BSS %+v", b)
+	}
+	if b := corp.BSSes[2]; !b.FTOverAir || b.FTOverDS || b.BSSTransition {
+		t.Errorf("third BSS %+v", b)
+	}
+
+	want := map[byte][]string{
+		3: {"FT over the DS", "BSS transition"},
+		4: {"mobility domain 0x9999 while most BSSes are in 0x1234", "neighbor reports"},
+		5: {"does not support FT while 4 of 8"},
+		6: {"FT AKM without a Mobility Domain element"},
+		7: {"mobility domain 0x1234 without an FT AKM"},
+		8: {"does not support FT", "advertises WPA2-Personal while most BSSes advertise WPA2-Enterprise"},
+	}
+	got := map[byte][]string{}
+	for _, p := range corp.Problems {
+		got[p.BSSID[5]] = append(got[p.BSSID[5]], p.Message)
+	}
+	for id, messages := range want {
+		if len(got[id]) != len(messages) {
+			t.Errorf("BSS %d problems %q, want %q", id, got[id], messages)
+			continue
+		}
+		for n, m := range messages {
+			if !strings.Contains(got[id][n], m) {
+				t.Errorf("BSS %d problem %q, want %q", id, got[id][n], m)
+			}
+		}
+	}
+	if len(got) != len(want) {
+		t.Errorf("problems on %d BSSes, want %d: %v", len(got), len(want), corp.Problems)
+	}
+
+	home := reports[1]
+	if home.FT || !home.NeighborReport || !home.BSSTransition || len(home.Problems) != 0 {
+		t.Errorf("home: %+v", home)
+	}
+}
+
+func TestMajoritySecurityTie(t *testing.T) {
+	reports := Analyze([]binary.BSSEntry{
+		entry("corp", 1, rsnPSK),
+		entry("corp", 2, rsnEAP),
+		entry("corp", 3, rsnEAP),
+		entry("corp", 4, rsnPSK),
+	})
+	if s := reports[0].majoritySecurity(); s != "WPA2-Personal" {
+		t.Errorf("majority of a tie %q, want the first security seen", s)
+	}
+}
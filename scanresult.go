@@ -0,0 +1,207 @@
+package wlanapi
+
+import (
+	"bytes"
+	"context"
+	"sort"
+
+	"wlanapi/binary"
+)
+
+//Flags of WLAN_AVAILABLE_NETWORK.dwFlags.
+const (
+	WLAN_AVAILABLE_NETWORK_CONNECTED              = 0x00000001
+	WLAN_AVAILABLE_NETWORK_HAS_PROFILE            = 0x00000002
+	WLAN_AVAILABLE_NETWORK_CONSOLE_USER_PROFILE   = 0x00000004
+	WLAN_AVAILABLE_NETWORK_INTERWORKING_SUPPORTED = 0x00000008
+	WLAN_AVAILABLE_NETWORK_HOTSPOT2_ENABLED       = 0x00000010
+	WLAN_AVAILABLE_NETWORK_ANQP_SUPPORTED         = 0x00000020
+	WLAN_AVAILABLE_NETWORK_HOTSPOT2_DOMAIN        = 0x00000040
+	WLAN_AVAILABLE_NETWORK_HOTSPOT2_ROAMING       = 0x00000080
+	WLAN_AVAILABLE_NETWORK_AUTO_CONNECT_FAILED    = 0x00000100
+)
+
+//capabilityPrivacy is the Privacy bit of the 802.11 Capability Information field.
+const capabilityPrivacy = 0x0010
+
+//BSS is a BSS entry of a Network.
+type BSS struct {
+	binary.BSSEntry
+	Band    Band
+	Channel int
+	//Privacy is the Privacy bit of the capability information, set when the BSS requires encryption.
+	Privacy bool
+}
+
+func newBSS(e binary.BSSEntry) BSS {
+	return BSS{
+		BSSEntry: e,
+		Band:     BandOf(e.ChCenterFrequency),
+		Channel:  ChannelOf(e.ChCenterFrequency),
+		Privacy:  e.CapabilityInformation&capabilityPrivacy != 0,
+	}
+}
+
+//Network is a network identified by its SSID, BSS type and security, with the BSSes that serve it.
+//A network whose BSSes were seen but that is missing from the available network list is not connectable.
+type Network struct {
+	SSID            []byte
+	BssType         DOT11_BSS_TYPE
+	SecurityEnabled bool
+	AuthAlgorithm   DOT11_AUTH_ALGORITHM
+	CipherAlgorithm DOT11_CIPHER_ALGORITHM
+	PhyTypes        []DOT11_PHY_TYPE
+	SignalQuality   uint32
+
+	Connectable          bool
+	NotConnectableReason WLAN_REASON_CODE
+	ProfileNames         []string
+	Connected            bool
+	HasProfile           bool
+	Flags                uint32
+
+	BSSes []BSS
+	//StrongestRSSI is the RSSI of the first BSS in dBm, or 0 without BSSes.
+	StrongestRSSI int32
+	Bands         BandSet
+
+	//listed is set once an entry of the available network list has been merged.
+	listed bool
+}
+
+//ScanResult is the networks seen by an interface, strongest first.
+type ScanResult struct {
+	Interface *Interface
+	Networks  []*Network
+}
+
+type networkKey struct {
+	ssid     string
+	bssType  DOT11_BSS_TYPE
+	security bool
+}
+
+//NewScanResult merges the available network list and the BSS list of an interface.
+//Available networks with the same SSID, BSS type and security are reported once per profile and
+//are merged into one Network; each BSS is assigned to the network with its SSID, BSS type and Privacy bit.
+func NewScanResult(networks []binary.AvailableNetwork, entries []binary.BSSEntry) *ScanResult {
+	r := &ScanResult{}
+	byKey := map[networkKey]*Network{}
+	lookup := func(k networkKey) *Network {
+		n, ok := byKey[k]
+		if !ok {
+			n = &Network{SSID: []byte(k.ssid), BssType: k.bssType, SecurityEnabled: k.security}
+			byKey[k] = n
+			r.Networks = append(r.Networks, n)
+		}
+		return n
+	}
+
+	for _, an := range networks {
+		n := lookup(networkKey{string(an.SSID), DOT11_BSS_TYPE(an.BssType), an.SecurityEnabled})
+		n.merge(an)
+	}
+	for _, e := range entries {
+		b := newBSS(e)
+		n := lookup(networkKey{string(e.SSID), DOT11_BSS_TYPE(e.BssType), b.Privacy})
+		n.BSSes = append(n.BSSes, b)
+	}
+
+	for _, n := range r.Networks {
+		sort.SliceStable(n.BSSes, func(i, j int) bool { return n.BSSes[i].RSSI > n.BSSes[j].RSSI })
+		if len(n.BSSes) > 0 {
+			n.StrongestRSSI = n.BSSes[0].RSSI
+		}
+		for _, b := range n.BSSes {
+			n.Bands |= BandSet(b.Band)
+		}
+	}
+	sort.SliceStable(r.Networks, func(i, j int) bool {
+		a, b := r.Networks[i], r.Networks[j]
+		if (len(a.BSSes) > 0) != (len(b.BSSes) > 0) {
+			return len(a.BSSes) > 0
+		}
+		if a.StrongestRSSI != b.StrongestRSSI {
+			return a.StrongestRSSI > b.StrongestRSSI
+		}
+		if a.SignalQuality != b.SignalQuality {
+			return a.SignalQuality > b.SignalQuality
+		}
+		return bytes.Compare(a.SSID, b.SSID) < 0
+	})
+	return r
+}
+
+//merge adds an entry of the available network list to the network.
+func (n *Network) merge(an binary.AvailableNetwork) {
+	if !n.listed {
+		n.listed = true
+		n.AuthAlgorithm = DOT11_AUTH_ALGORITHM(an.DefaultAuthAlgorithm)
+		n.CipherAlgorithm = DOT11_CIPHER_ALGORITHM(an.DefaultCipherAlgorithm)
+	}
+	if an.SignalQuality > n.SignalQuality {
+		n.SignalQuality = an.SignalQuality
+	}
+	for _, t := range an.PhyTypes {
+		if !containsPhyType(n.PhyTypes, DOT11_PHY_TYPE(t)) {
+			n.PhyTypes = append(n.PhyTypes, DOT11_PHY_TYPE(t))
+		}
+	}
+	if an.NetworkConnectable {
+		n.Connectable = true
+		n.NotConnectableReason = WLAN_REASON_CODE_SUCCESS
+	} else if !n.Connectable && n.NotConnectableReason == WLAN_REASON_CODE_SUCCESS {
+		n.NotConnectableReason = WLAN_REASON_CODE(an.NotConnectableReason)
+	}
+	if an.ProfileName != "" {
+		n.ProfileNames = append(n.ProfileNames, an.ProfileName)
+	}
+	n.Flags |= an.Flags
+	n.Connected = n.Flags&WLAN_AVAILABLE_NETWORK_CONNECTED != 0
+	n.HasProfile = n.Flags&WLAN_AVAILABLE_NETWORK_HAS_PROFILE != 0
+}
+
+func containsPhyType(types []DOT11_PHY_TYPE, t DOT11_PHY_TYPE) bool {
+	for _, v := range types {
+		if v == t {
+			return true
+		}
+	}
+	return false
+}
+
+//ScanResult retrieves the available networks and BSS entries of the interface and merges them.
+func (i *Interface) ScanResult() (*ScanResult, error) {
+	networks, err := i.AvailableNetworks()
+	if err != nil {
+		return nil, err
+	}
+	entries, err := i.BSSList()
+	if err != nil {
+		return nil, err
+	}
+	r := NewScanResult(networks, entries)
+	r.Interface = i
+	return r, nil
+}
+
+//ScanResultsAll retrieves the scan results of the interfaces chosen by sel, in interface order.
+//When some interfaces fail, the results of the others are returned with InterfaceErrors.
+func (c *Client) ScanResultsAll(ctx context.Context, sel InterfaceSelector) ([]*ScanResult, error) {
+	interfaces, err := c.SelectInterfaces(sel)
+	if err != nil {
+		return nil, err
+	}
+	results := make([]*ScanResult, len(interfaces))
+	err = c.forEach(ctx, interfaces, func(_ context.Context, n int, i *Interface) (err error) {
+		results[n], err = i.ScanResult()
+		return err
+	})
+	merged := results[:0]
+	for _, r := range results {
+		if r != nil {
+			merged = append(merged, r)
+		}
+	}
+	return merged, err
+}
@@ -0,0 +1,165 @@
+package wlanapi
+
+import (
+	"context"
+	"testing"
+
+	"wlanapi/binary"
+)
+
+func TestBandAndChannel(t *testing.T) {
+	tests := []struct {
+		khz     uint32
+		band    Band
+		channel int
+	}{
+		{2412000, Band2_4GHz, 1},
+		{2437000, Band2_4GHz, 6},
+		{2484000, Band2_4GHz, 14},
+		{5180000, Band5GHz, 36},
+		{5825000, Band5GHz, 165},
+		{5955000, Band6GHz, 1},
+		{5925000, Band6GHz, 0},
+		{5930000, Band6GHz, 0},
+		{5935000, Band6GHz, 2},
+		{5945000, Band6GHz, 0},
+		{7115000, Band6GHz, 233},
+		{5920000, Band5GHz, 184},
+		{6415000, Band6GHz, 93},
+		{58320000, Band60GHz, 1},
+		{900000, 0, 0},
+	}
+	for _, tt := range tests {
+		if b, c := BandOf(tt.khz), ChannelOf(tt.khz); b != tt.band || c != tt.channel {
+			t.Errorf("%d kHz: %v channel %d, want %v channel %d", tt.khz, b, c, tt.band, tt.channel)
+		}
+		if f := FrequencyOf(tt.band, tt.channel); tt.band != 0 && tt.channel != 0 && f != tt.khz {
+			t.Errorf("%v channel %d: %d kHz, want %d", tt.band, tt.channel, f, tt.khz)
+		}
+	}
+	if f := FrequencyOf(Band2_4GHz, 36); f != 0 {
+		t.Errorf("2.4GHz channel 36: %d kHz", f)
+	}
+	if s := (BandSet(Band2_4GHz) | BandSet(Band6GHz)).String(); s != "2.4GHz,6GHz" {
+		t.Errorf("band set %q", s)
+	}
+}
+
+func TestNewScanResult(t *testing.T) {
+	networks := []binary.AvailableNetwork{
+		{ProfileName: "Office", SSID: []byte("office"), BssType: 1, NetworkConnectable: true, SignalQuality: 70,
+			SecurityEnabled: true, DefaultAuthAlgorithm: 7, DefaultCipherAlgorithm: 4, PhyTypes: []uint32{7},
+			Flags: WLAN_AVAILABLE_NETWORK_CONNECTED | WLAN_AVAILABLE_NETWORK_HAS_PROFILE},
+		{SSID: []byte("office"), BssType: 1, NetworkConnectable: true, SignalQuality: 72,
+			SecurityEnabled: true, DefaultAuthAlgorithm: 7, DefaultCipherAlgorithm: 4, PhyTypes: []uint32{7, 8}},
+		{SSID: []byte("guest"), BssType: 1, SignalQuality: 40, DefaultAuthAlgorithm: 1,
+			NotConnectableReason: uint32(WLAN_REASON_CODE_GP_DENIED)},
+		{SSID: []byte("office"), BssType: 1, SignalQuality: 20, DefaultAuthAlgorithm: 1},
+	}
+	entries := []binary.BSSEntry{
+		{SSID: []byte("office"), BssType: 1, BSSID: [6]byte{1}, RSSI: -70, ChCenterFrequency: 2437000, CapabilityInformation: 0x0411},
+		{SSID: []byte("office"), BssType: 1, BSSID: [6]byte{2}, RSSI: -52, ChCenterFrequency: 5180000, CapabilityInformation: 0x0011},
+		{SSID: []byte("guest"), BssType: 1, BSSID: [6]byte{3}, RSSI: -80, ChCenterFrequency: 2412000, CapabilityInformation: 0x0401},
+		{SSID: []byte("hidden"), BssType: 1, BSSID: [6]byte{4}, RSSI: -90, ChCenterFrequency: 5955000, CapabilityInformation: 0x0011},
+	}
+	r := NewScanResult(networks, entries)
+	if len(r.Networks) != 4 {
+		t.Fatalf("got %d networks, want 4", len(r.Networks))
+	}
+
+	office := r.Networks[0]
+	if string(office.SSID) != "office" || !office.SecurityEnabled {
+		t.Fatalf("strongest network %q security %t, want secured office", office.SSID, office.SecurityEnabled)
+	}
+	if !office.Connectable || !office.Connected || !office.HasProfile || office.SignalQuality != 72 ||
+		office.AuthAlgorithm != DOT11_AUTH_ALGO_RSNA_PSK || office.CipherAlgorithm != DOT11_CIPHER_ALGO_CCMP {
+		t.Errorf("office: %+v", office)
+	}
+	if len(office.ProfileNames) != 1 || office.ProfileNames[0] != "Office" {
+		t.Errorf("office profiles %v, want [Office]", office.ProfileNames)
+	}
+	if len(office.PhyTypes) != 2 {
+		t.Errorf("office PHY types %v, want 2", office.PhyTypes)
+	}
+	if len(office.BSSes) != 2 || office.BSSes[0].BSSID[0] != 2 || office.StrongestRSSI != -52 {
+		t.Errorf("office BSSes %+v, strongest %d", office.BSSes, office.StrongestRSSI)
+	}
+	if !office.Bands.Has(Band2_4GHz) || !office.Bands.Has(Band5GHz) || office.Bands.Has(Band6GHz) {
+		t.Errorf("office bands %v", office.Bands)
+	}
+	if office.BSSes[0].Channel != 36 || office.BSSes[1].Channel != 6 {
+		t.Errorf("office channels %d, %d", office.BSSes[0].Channel, office.BSSes[1].Channel)
+	}
+
+	guest := r.Networks[1]
+	if string(guest.SSID) != "guest" || guest.Connectable || guest.NotConnectableReason != WLAN_REASON_CODE_GP_DENIED ||
+		len(guest.BSSes) != 1 || guest.HasProfile {
+		t.Errorf("guest: %+v", guest)
+	}
+	if s := guest.NotConnectableReason.String(); s != "denied by group policy" {
+		t.Errorf("guest reason %q", s)
+	}
+
+	hidden := r.Networks[2]
+	if string(hidden.SSID) != "hidden" || hidden.Connectable || len(hidden.BSSes) != 1 || hidden.Bands != BandSet(Band6GHz) {
+		t.Errorf("hidden: %+v", hidden)
+	}
+
+	//An open network with the SSID of a secured one is a different network, without BSSes here.
+	open := r.Networks[3]
+	if string(open.SSID) != "office" || open.SecurityEnabled || len(open.BSSes) != 0 {
+		t.Errorf("open office: %+v", open)
+	}
+}
+
+func TestScanResultFromSim(t *testing.T) {
+	sim := newFanOutSim()
+	c := NewClientWithBackend(sim)
+	results, err := c.ScanResultsAll(context.Background(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 3 {
+		t.Fatalf("got %d results, want 3", len(results))
+	}
+	for _, r := range results {
+		if r.Interface == nil || len(r.Networks) != 2 || string(r.Networks[0].SSID) != "shared" || len(r.Networks[0].BSSes) != 1 {
+			t.Errorf("result of %v: %+v", r.Interface, r.Networks)
+		}
+	}
+}
+
+func TestReasonCodeString(t *testing.T) {
+	tests := map[WLAN_REASON_CODE]string{
+		WLAN_REASON_CODE_SUCCESS:     "success",
+		WLAN_REASON_CODE_NOT_VISIBLE: "network not visible",
+		0x38001:                      "MSM reason 0x38001",
+		0x3c002:                      "MSM reason 0x3c002",
+		0x3ffff:                      "MSM reason 0x3ffff",
+		0x40000:                      "MSM security reason 0x40000",
+		0x4ffff:                      "MSM security reason 0x4ffff",
+		0x50000:                      "802.1X reason 0x50000",
+		0x80004:                      "profile reason 0x80004",
+		0x99999999:                   "WLAN_REASON_CODE(0x99999999)",
+	}
+	for code, want := range tests {
+		if got := code.String(); got != want {
+			t.Errorf("0x%x: %q, want %q", uint32(code), got, want)
+		}
+	}
+}
+
+func TestNotificationACMString(t *testing.T) {
+	tests := map[WLAN_NOTIFICATION_ACM]string{
+		WlanNotificationAcmAutoconfEnabled:        "autoconf enabled",
+		WlanNotificationAcmScanComplete:           "scan complete",
+		WlanNotificationAcmOperationalStateChange: "operational state change",
+		0:  "WLAN_NOTIFICATION_ACM(0)",
+		99: "WLAN_NOTIFICATION_ACM(99)",
+	}
+	for code, want := range tests {
+		if got := code.String(); got != want {
+			t.Errorf("%d: %q, want %q", uint32(code), got, want)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+//go:build windows
+// +build windows
+
+package wlanapi
+
+import (
+	"sync"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+//SecondaryStations returns the GUIDs of the secondary STA interfaces of the interface.
+func (i *Interface) SecondaryStations() ([]windows.GUID, error) {
+	_, ppData, _, err := WlanQueryInterface(i.client.handle, &i.GUID, WlanIntfOpcodeSecondaryStaInterfaces)
+	if err != nil {
+		return nil, err
+	}
+	defer WlanFreeMemory(PVOID(unsafe.Pointer(ppData)))
+
+	infos, err := (*WLAN_INTERFACE_INFO_LIST)(unsafe.Pointer(ppData)).Decode()
+	if err != nil {
+		return nil, err
+	}
+	stations := make([]windows.GUID, len(infos))
+	for n, info := range infos {
+		stations[n] = windows.GUID(info.InterfaceGuid)
+	}
+	return stations, nil
+}
+
+//SynchronizedConnections reports whether connections of the secondary STA interfaces follow the primary interface.
+func (i *Interface) SynchronizedConnections() (bool, error) {
+	_, ppData, _, err := WlanQueryInterface(i.client.handle, &i.GUID, WlanIntfOpcodeSecondaryStaSynchronizedConnections)
+	if err != nil {
+		return false, err
+	}
+	defer WlanFreeMemory(PVOID(unsafe.Pointer(ppData)))
+	return *(*BOOL)(unsafe.Pointer(ppData)) != FALSE, nil
+}
+
+//SetSynchronizedConnections sets whether connections of the secondary STA interfaces follow the primary interface.
+func (i *Interface) SetSynchronizedConnections(enabled bool) error {
+	value := FALSE
+	if enabled {
+		value = TRUE
+	}
+	return WlanSetInterface(i.client.handle, &i.GUID, WlanIntfOpcodeSecondaryStaSynchronizedConnections,
+		DWORD(unsafe.Sizeof(value)), (*BYTE)(unsafe.Pointer(&value)))
+}
+
+//VirtualStationNotifications registers the client for the notifications of the wireless Hosted Network on virtual
+//stations, as WlanRegisterVirtualStationNotification, and subscribes to them.
+//The returned function cancels the subscription, unregisters the client when no other subscription needs the
+//notifications and closes the channel.
+func (c *Client) VirtualStationNotifications() (<-chan VirtualStationNotification, func(), error) {
+	if err := c.addVirtualStations(1); err != nil {
+		return nil, nil, err
+	}
+	notifications, cancel, err := c.Subscribe(WLAN_NOTIFICATION_SOURCE_HNWK)
+	if err != nil {
+		c.addVirtualStations(-1)
+		return nil, nil, err
+	}
+
+	ch := make(chan VirtualStationNotification, notificationBuffer)
+	go func() {
+		defer close(ch)
+		for n := range notifications {
+			v, ok := n.VirtualStation()
+			if !ok {
+				continue
+			}
+			select {
+			case ch <- v:
+			default:
+			}
+		}
+	}()
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			cancel()
+			c.addVirtualStations(-1)
+		})
+	}, nil
+}
+
+//addVirtualStations adjusts the number of subscriptions to virtual station notifications by delta and
+//registers the client while there are any.
+func (c *Client) addVirtualStations(delta int) error {
+	c.registerMu.Lock()
+	defer c.registerMu.Unlock()
+	was := c.virtualStations > 0
+	c.virtualStations += delta
+	if now := c.virtualStations > 0; now != was {
+		register := FALSE
+		if now {
+			register = TRUE
+		}
+		if err := WlanRegisterVirtualStationNotification(c.handle, register); err != nil {
+			c.virtualStations -= delta
+			return err
+		}
+	}
+	return nil
+}
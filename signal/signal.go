@@ -0,0 +1,198 @@
+//Package signal converts between the signal scales of Native Wifi and smooths signal time series.
+//
+//WLAN_AVAILABLE_NETWORK.wlanSignalQuality and WLAN_BSS_ENTRY.uLinkQuality are percentages that Windows maps
+//linearly from RSSI: 0 is -100 dBm or less and 100 is -50 dBm or more.
+package signal
+
+import (
+	"fmt"
+	"math"
+)
+
+const (
+	//MinRSSI and MaxRSSI are the RSSIs in dBm of quality 0 and 100.
+	MinRSSI = -100
+	MaxRSSI = -50
+)
+
+//Quality returns the Windows signal quality (0-100) of an RSSI in dBm.
+func Quality(rssi int) int {
+	switch {
+	case rssi <= MinRSSI:
+		return 0
+	case rssi >= MaxRSSI:
+		return 100
+	}
+	return 2 * (rssi - MinRSSI)
+}
+
+//RSSI returns the RSSI in dBm of a Windows signal quality; qualities outside 0-100 are clamped.
+//Quality 0 and 100 cover RSSIs beyond MinRSSI and MaxRSSI, so the conversion is only exact between them.
+func RSSI(quality int) int {
+	switch {
+	case quality <= 0:
+		return MinRSSI
+	case quality >= 100:
+		return MaxRSSI
+	}
+	return quality/2 + MinRSSI
+}
+
+//SNR returns the signal to noise ratio in dB of an RSSI and a noise floor in dBm.
+func SNR(rssi, noiseFloor float64) float64 {
+	return rssi - noiseFloor
+}
+
+//Bars returns the number of bars (0-5) of a signal quality, one per 20 points started.
+func Bars(quality int) int {
+	switch {
+	case quality <= 0:
+		return 0
+	case quality < 20:
+		return 1
+	case quality < 40:
+		return 2
+	case quality < 60:
+		return 3
+	case quality < 80:
+		return 4
+	}
+	return 5
+}
+
+//Grade classifies an RSSI for the applications it supports.
+type Grade int
+
+const (
+	//Unusable signals drop connections.
+	Unusable Grade = iota
+	//Weak signals support browsing and mail at low rates.
+	Weak
+	//Fair signals support video streaming.
+	Fair
+	//Good signals support voice and video calls.
+	Good
+	//Excellent signals support the highest rates.
+	Excellent
+)
+
+func (g Grade) String() string {
+	switch g {
+	case Unusable:
+		return "unusable"
+	case Weak:
+		return "weak"
+	case Fair:
+		return "fair"
+	case Good:
+		return "good"
+	case Excellent:
+		return "excellent"
+	}
+	return fmt.Sprintf("Grade(%d)", int(g))
+}
+
+//GradeOf returns the grade of an RSSI in dBm.
+func GradeOf(rssi float64) Grade {
+	switch {
+	case rssi >= -50:
+		return Excellent
+	case rssi >= -67:
+		return Good
+	case rssi >= -75:
+		return Fair
+	case rssi >= -85:
+		return Weak
+	}
+	return Unusable
+}
+
+//EWMA is an exponentially weighted moving average. The zero value is not usable; set Alpha.
+type EWMA struct {
+	//Alpha is the weight of a new sample, between 0 and 1; higher values follow changes faster.
+	Alpha float64
+	value float64
+	valid bool
+}
+
+//Add adds a sample and returns the new average. The first sample is taken as is.
+func (e *EWMA) Add(x float64) float64 {
+	if !e.valid {
+		e.value, e.valid = x, true
+	} else {
+		e.value += e.Alpha * (x - e.value)
+	}
+	return e.value
+}
+
+//Value returns the average, and false before the first sample.
+func (e *EWMA) Value() (float64, bool) {
+	return e.value, e.valid
+}
+
+//Kalman is a one-dimensional Kalman filter for a signal that drifts slowly under noisy measurements.
+type Kalman struct {
+	//ProcessNoise is the variance of the change of the signal between samples, in dB².
+	ProcessNoise float64
+	//MeasurementNoise is the variance of the measurements, in dB².
+	MeasurementNoise float64
+	estimate         float64
+	covariance       float64
+	valid            bool
+}
+
+//NewKalman returns a filter with typical noise values for RSSI sampled every few seconds.
+func NewKalman() *Kalman {
+	return &Kalman{ProcessNoise: 0.5, MeasurementNoise: 16}
+}
+
+//Add adds a measurement and returns the new estimate. The first measurement is taken as is.
+func (k *Kalman) Add(z float64) float64 {
+	if !k.valid {
+		k.estimate, k.covariance, k.valid = z, k.MeasurementNoise, true
+		return z
+	}
+	k.covariance += k.ProcessNoise
+	gain := k.covariance / (k.covariance + k.MeasurementNoise)
+	k.estimate += gain * (z - k.estimate)
+	k.covariance *= 1 - gain
+	return k.estimate
+}
+
+//Value returns the estimate, and false before the first measurement.
+func (k *Kalman) Value() (float64, bool) {
+	return k.estimate, k.valid
+}
+
+//Path loss exponents of the log-distance model.
+const (
+	FreeSpace = 2.0
+	Office    = 3.0
+	Dense     = 4.0
+)
+
+//PathLoss is a log-distance path loss model: the loss is the free-space loss at 1 m
+//plus 10 * Exponent * log10(distance).
+type PathLoss struct {
+	//TxPower is the transmit power in dBm, antenna gains included.
+	TxPower float64
+	//FrequencyMHz is the channel center frequency.
+	FrequencyMHz float64
+	//Exponent is 2 in free space and 3 to 4 inside buildings.
+	Exponent float64
+}
+
+//reference returns the free-space path loss at 1 m in dB.
+func (m PathLoss) reference() float64 {
+	return 20*math.Log10(m.FrequencyMHz) - 27.55
+}
+
+//Distance estimates the distance in meters of a transmitter received at rssi dBm.
+func (m PathLoss) Distance(rssi float64) float64 {
+	return math.Pow(10, (m.TxPower-rssi-m.reference())/(10*m.Exponent))
+}
+
+//RSSI returns the expected RSSI in dBm at a distance in meters.
+func (m PathLoss) RSSI(distance float64) float64 {
+	return m.TxPower - m.reference() - 10*m.Exponent*math.Log10(distance)
+}
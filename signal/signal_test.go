@@ -0,0 +1,88 @@
+package signal
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestQuality(t *testing.T) {
+	tests := []struct{ rssi, quality int }{{-110, 0}, {-100, 0}, {-90, 20}, {-75, 50}, {-51, 98}, {-50, 100}, {-30, 100}}
+	for _, tt := range tests {
+		if q := Quality(tt.rssi); q != tt.quality {
+			t.Errorf("Quality(%d) = %d, want %d", tt.rssi, q, tt.quality)
+		}
+	}
+	for rssi := MinRSSI; rssi <= MaxRSSI; rssi++ {
+		if r := RSSI(Quality(rssi)); r != rssi {
+			t.Errorf("RSSI(Quality(%d)) = %d", rssi, r)
+		}
+	}
+	if RSSI(-5) != MinRSSI || RSSI(120) != MaxRSSI || RSSI(51) != -75 {
+		t.Error("RSSI does not clamp or round down")
+	}
+}
+
+func TestClassify(t *testing.T) {
+	for quality, bars := range map[int]int{0: 0, 1: 1, 19: 1, 20: 2, 59: 3, 79: 4, 80: 5, 100: 5} {
+		if b := Bars(quality); b != bars {
+			t.Errorf("Bars(%d) = %d, want %d", quality, b, bars)
+		}
+	}
+	for rssi, grade := range map[float64]Grade{-40: Excellent, -60: Good, -67: Good, -70: Fair, -80: Weak, -90: Unusable} {
+		if g := GradeOf(rssi); g != grade {
+			t.Errorf("GradeOf(%v) = %v, want %v", rssi, g, grade)
+		}
+	}
+	if snr := SNR(-60, -95); snr != 35 {
+		t.Errorf("SNR %v", snr)
+	}
+}
+
+func TestSmoothing(t *testing.T) {
+	e := EWMA{Alpha: 0.5}
+	if _, ok := e.Value(); ok {
+		t.Error("EWMA has a value before the first sample")
+	}
+	for _, x := range []float64{-60, -70, -70} {
+		e.Add(x)
+	}
+	if v, _ := e.Value(); v != -67.5 {
+		t.Errorf("EWMA %v", v)
+	}
+
+	//Both filters must reduce the error of noisy samples of a constant signal.
+	r := rand.New(rand.NewSource(1))
+	k := NewKalman()
+	e = EWMA{Alpha: 0.1}
+	var raw, kalman, ewma float64
+	for n := 0; n < 500; n++ {
+		z := -65 + 4*r.NormFloat64()
+		kv, ev := k.Add(z), e.Add(z)
+		if n >= 100 {
+			raw += (z + 65) * (z + 65)
+			kalman += (kv + 65) * (kv + 65)
+			ewma += (ev + 65) * (ev + 65)
+		}
+	}
+	if kalman > raw/4 || ewma > raw/4 {
+		t.Errorf("squared errors: raw %v, Kalman %v, EWMA %v", raw, kalman, ewma)
+	}
+}
+
+func TestPathLoss(t *testing.T) {
+	m := PathLoss{TxPower: 20, FrequencyMHz: 2437, Exponent: FreeSpace}
+	//The free-space path loss at 2437 MHz is about 40.2 dB at 1 m and 60.2 dB at 10 m.
+	if rssi := m.RSSI(10); math.Abs(rssi+40.2) > 0.1 {
+		t.Errorf("RSSI at 10 m %v", rssi)
+	}
+	for _, d := range []float64{1, 7.5, 30} {
+		if got := m.Distance(m.RSSI(d)); math.Abs(got-d) > 1e-9 {
+			t.Errorf("distance %v, want %v", got, d)
+		}
+	}
+	office := PathLoss{TxPower: 20, FrequencyMHz: 5180, Exponent: Office}
+	if office.Distance(-70) >= m.Distance(-70) {
+		t.Error("a higher exponent and frequency do not shorten the distance")
+	}
+}
@@ -0,0 +1,531 @@
+package wlanapi
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"sync"
+
+	"wlanapi/binary"
+)
+
+//Sim is an in-memory Backend for tests. Its interfaces and what they see are set by the test.
+type Sim struct {
+	mu            sync.Mutex
+	interfaces    []*simInterface
+	subscriptions map[chan Notification]struct{}
+
+	hosted         binary.HostedNetworkStatus
+	hostedSettings HostedNetworkSettings
+	hostedErr      error
+}
+
+type simInterface struct {
+	info     binary.InterfaceInfo
+	networks []binary.AvailableNetwork
+	bsses    []binary.BSSEntry
+	err      error
+	scans    int
+	//scanFailure is the reason reported by the scan fail notification, or 0 to report scan complete.
+	scanFailure WLAN_REASON_CODE
+	//profiles holds the profile XMLs in preference order.
+	profiles   []simProfile
+	connection *binary.ConnectionAttributes
+	statistics binary.Statistics
+}
+
+//NewSim returns a Sim without interfaces.
+func NewSim() *Sim {
+	return &Sim{}
+}
+
+//AddInterface adds an interface, or replaces the info of the interface with the same GUID.
+func (s *Sim) AddInterface(info binary.InterfaceInfo) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if i := s.lookup(GUID(info.InterfaceGuid)); i != nil {
+		i.info = info
+		return
+	}
+	s.interfaces = append(s.interfaces, &simInterface{info: info})
+}
+
+//RemoveInterface removes an interface, as when a USB adapter is unplugged.
+func (s *Sim) RemoveInterface(iface GUID) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for n, i := range s.interfaces {
+		if GUID(i.info.InterfaceGuid) == iface {
+			s.interfaces = append(s.interfaces[:n], s.interfaces[n+1:]...)
+			return
+		}
+	}
+}
+
+//SetNetworks sets the available networks reported for the interface.
+func (s *Sim) SetNetworks(iface GUID, networks []binary.AvailableNetwork) {
+	s.update(iface, func(i *simInterface) { i.networks = networks })
+}
+
+//SetBSSList sets the BSS entries reported for the interface.
+func (s *Sim) SetBSSList(iface GUID, entries []binary.BSSEntry) {
+	s.update(iface, func(i *simInterface) { i.bsses = entries })
+}
+
+//SetError makes every call on the interface fail with err; a nil err clears it.
+func (s *Sim) SetError(iface GUID, err error) {
+	s.update(iface, func(i *simInterface) { i.err = err })
+}
+
+//SetScanFailure makes the scans of the interface end with a scan fail notification carrying reason;
+//WLAN_REASON_CODE_SUCCESS makes them end with scan complete again.
+func (s *Sim) SetScanFailure(iface GUID, reason WLAN_REASON_CODE) {
+	s.update(iface, func(i *simInterface) { i.scanFailure = reason })
+}
+
+//Notify delivers n to the subscribers of Notifications. Subscribers that fall behind miss notifications.
+func (s *Sim) Notify(n Notification) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for ch := range s.subscriptions {
+		select {
+		case ch <- n:
+		default:
+		}
+	}
+}
+
+//Scans returns the number of scans requested on the interface.
+func (s *Sim) Scans(iface GUID) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if i := s.lookup(iface); i != nil {
+		return i.scans
+	}
+	return 0
+}
+
+func (s *Sim) lookup(iface GUID) *simInterface {
+	for _, i := range s.interfaces {
+		if GUID(i.info.InterfaceGuid) == iface {
+			return i
+		}
+	}
+	return nil
+}
+
+func (s *Sim) update(iface GUID, fn func(*simInterface)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if i := s.lookup(iface); i != nil {
+		fn(i)
+	}
+}
+
+//call runs fn on the interface unless it is unknown or set to fail.
+func (s *Sim) call(iface GUID, fn func(*simInterface)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	i := s.lookup(iface)
+	if i == nil {
+		return fmt.Errorf("wlanapi: sim has no interface %s", iface.String())
+	}
+	if i.err != nil {
+		return i.err
+	}
+	fn(i)
+	return nil
+}
+
+func (s *Sim) Interfaces() ([]binary.InterfaceInfo, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	infos := make([]binary.InterfaceInfo, len(s.interfaces))
+	for n, i := range s.interfaces {
+		infos[n] = i.info
+	}
+	return infos, nil
+}
+
+//Scan counts the scan and notifies its completion right away, with scan complete or scan fail.
+func (s *Sim) Scan(iface GUID) error {
+	var failure WLAN_REASON_CODE
+	err := s.call(iface, func(i *simInterface) {
+		i.scans++
+		failure = i.scanFailure
+	})
+	if err != nil {
+		return err
+	}
+	n := Notification{Source: WLAN_NOTIFICATION_SOURCE_ACM, Code: uint32(WlanNotificationAcmScanComplete), InterfaceGuid: iface}
+	if failure != WLAN_REASON_CODE_SUCCESS {
+		n.Code = uint32(WlanNotificationAcmScanFail)
+		n.Data = []byte{byte(failure), byte(failure >> 8), byte(failure >> 16), byte(failure >> 24)}
+	}
+	s.Notify(n)
+	return nil
+}
+
+func (s *Sim) AvailableNetworks(iface GUID) (networks []binary.AvailableNetwork, err error) {
+	err = s.call(iface, func(i *simInterface) {
+		networks = append([]binary.AvailableNetwork(nil), i.networks...)
+	})
+	return networks, err
+}
+
+func (s *Sim) BSSList(iface GUID) (entries []binary.BSSEntry, err error) {
+	err = s.call(iface, func(i *simInterface) {
+		entries = append([]binary.BSSEntry(nil), i.bsses...)
+	})
+	return entries, err
+}
+
+func (s *Sim) Notifications() (<-chan Notification, func(), error) {
+	ch := make(chan Notification, notificationBuffer)
+	s.mu.Lock()
+	if s.subscriptions == nil {
+		s.subscriptions = map[chan Notification]struct{}{}
+	}
+	s.subscriptions[ch] = struct{}{}
+	s.mu.Unlock()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			s.mu.Lock()
+			delete(s.subscriptions, ch)
+			close(ch)
+			s.mu.Unlock()
+		})
+	}, nil
+}
+
+func (s *Sim) Close() error {
+	return nil
+}
+
+//simProfile is a stored profile and the parts of its XML the Sim uses.
+type simProfile struct {
+	xml string
+	simProfileXML
+}
+
+//simProfileXML holds the elements of a WLANProfile the Sim connects with.
+type simProfileXML struct {
+	Name           string   `xml:"name"`
+	SSIDs          []string `xml:"SSIDConfig>SSID>name"`
+	ConnectionType string   `xml:"connectionType"`
+	Authentication string   `xml:"MSM>security>authEncryption>authentication"`
+	Encryption     string   `xml:"MSM>security>authEncryption>encryption"`
+	UseOneX        bool     `xml:"MSM>security>authEncryption>useOneX"`
+}
+
+func parseSimProfile(s string) (simProfileXML, error) {
+	var p simProfileXML
+	if err := xml.Unmarshal([]byte(s), &p); err != nil {
+		return p, fmt.Errorf("wlanapi: invalid profile XML: %v", err)
+	}
+	if p.Name == "" {
+		return p, errors.New("wlanapi: profile XML has no name")
+	}
+	return p, nil
+}
+
+//profileAuthAlgorithms maps the authentication of profile XML to the algorithm of the connection.
+var profileAuthAlgorithms = map[string]DOT11_AUTH_ALGORITHM{
+	"open":       DOT11_AUTH_ALGO_80211_OPEN,
+	"shared":     DOT11_AUTH_ALGO_80211_SHARED_KEY,
+	"WPA":        DOT11_AUTH_ALGO_WPA,
+	"WPAPSK":     DOT11_AUTH_ALGO_WPA_PSK,
+	"WPA2":       DOT11_AUTH_ALGO_RSNA,
+	"WPA2PSK":    DOT11_AUTH_ALGO_RSNA_PSK,
+	"WPA3":       DOT11_AUTH_ALGO_WPA3,
+	"WPA3ENT192": DOT11_AUTH_ALGO_WPA3,
+	"WPA3ENT":    DOT11_AUTH_ALGO_WPA3_ENT,
+	"WPA3SAE":    DOT11_AUTH_ALGO_WPA3_SAE,
+	"OWE":        DOT11_AUTH_ALGO_OWE,
+}
+
+//profileCipherAlgorithms maps the encryption of profile XML to the cipher of the connection.
+var profileCipherAlgorithms = map[string]DOT11_CIPHER_ALGORITHM{
+	"none":    DOT11_CIPHER_ALGO_NONE,
+	"WEP":     DOT11_CIPHER_ALGO_WEP,
+	"TKIP":    DOT11_CIPHER_ALGO_TKIP,
+	"AES":     DOT11_CIPHER_ALGO_CCMP,
+	"GCMP":    DOT11_CIPHER_ALGO_GCMP,
+	"GCMP256": DOT11_CIPHER_ALGO_GCMP_256,
+}
+
+func (i *simInterface) profile(name string) int {
+	for n, p := range i.profiles {
+		if p.Name == name {
+			return n
+		}
+	}
+	return -1
+}
+
+//Profiles lists the profiles set with SetProfile, in the order they were added.
+func (s *Sim) Profiles(iface GUID) (profiles []binary.ProfileInfo, err error) {
+	err = s.call(iface, func(i *simInterface) {
+		profiles = make([]binary.ProfileInfo, len(i.profiles))
+		for n, p := range i.profiles {
+			profiles[n] = binary.ProfileInfo{Name: p.Name}
+		}
+	})
+	return profiles, err
+}
+
+//Profile returns the XML of a profile as it was set; the Sim does not encrypt keys.
+func (s *Sim) Profile(iface GUID, name string, plaintextKey bool) (xml string, err error) {
+	callErr := s.call(iface, func(i *simInterface) {
+		if n := i.profile(name); n >= 0 {
+			xml = i.profiles[n].xml
+		} else {
+			err = ErrProfileNotFound
+		}
+	})
+	if callErr != nil {
+		return "", callErr
+	}
+	return xml, err
+}
+
+//SetProfile stores a profile for all users. The XML must have a name; the rest of it is only read by Connect.
+func (s *Sim) SetProfile(iface GUID, xml string, overwrite bool) error {
+	p, err := parseSimProfile(xml)
+	if err != nil {
+		return err
+	}
+	if callErr := s.call(iface, func(i *simInterface) {
+		switch n := i.profile(p.Name); {
+		case n < 0:
+			i.profiles = append(i.profiles, simProfile{xml: xml, simProfileXML: p})
+		case overwrite:
+			i.profiles[n] = simProfile{xml: xml, simProfileXML: p}
+		default:
+			err = fmt.Errorf("wlanapi: profile %q already exists", p.Name)
+		}
+	}); callErr != nil {
+		return callErr
+	}
+	return err
+}
+
+func (s *Sim) DeleteProfile(iface GUID, name string) error {
+	var err error
+	if callErr := s.call(iface, func(i *simInterface) {
+		if n := i.profile(name); n >= 0 {
+			i.profiles = append(i.profiles[:n], i.profiles[n+1:]...)
+		} else {
+			err = ErrProfileNotFound
+		}
+	}); callErr != nil {
+		return callErr
+	}
+	return err
+}
+
+//Connect connects the interface right away to the strongest BSS of the profile SSID in its BSS list and
+//notifies connection complete. Without such a BSS it notifies connection attempt fail and stays disconnected.
+//The notifications carry no data.
+func (s *Sim) Connect(iface GUID, p ConnectionParameters) error {
+	var err error
+	n := Notification{Source: WLAN_NOTIFICATION_SOURCE_ACM, InterfaceGuid: iface}
+	callErr := s.call(iface, func(i *simInterface) {
+		var profile simProfileXML
+		if p.ProfileXML != "" {
+			profile, err = parseSimProfile(p.ProfileXML)
+		} else if k := i.profile(p.Profile); k >= 0 {
+			profile = i.profiles[k].simProfileXML
+		} else {
+			err = ErrProfileNotFound
+		}
+		if err != nil {
+			return
+		}
+		ssid := p.SSID
+		if len(ssid) == 0 && len(profile.SSIDs) > 0 {
+			ssid = []byte(profile.SSIDs[0])
+		}
+		var best *binary.BSSEntry
+		for k := range i.bsses {
+			e := &i.bsses[k]
+			if string(e.SSID) == string(ssid) && allowedBSSID(p.BSSIDs, e.BSSID) && (best == nil || e.RSSI > best.RSSI) {
+				best = e
+			}
+		}
+		if best == nil {
+			n.Code = uint32(WlanNotificationAcmConnectionAttemptFail)
+			return
+		}
+		c := &binary.ConnectionAttributes{
+			State:           uint32(wlan_interface_state_connected),
+			Mode:            uint32(p.mode()),
+			ProfileName:     profile.Name,
+			SSID:            append([]byte(nil), ssid...),
+			BssType:         uint32(p.bssType()),
+			BSSID:           best.BSSID,
+			PhyType:         best.PhyType,
+			PhyIndex:        best.PhyID,
+			SignalQuality:   best.LinkQuality,
+			SecurityEnabled: profile.Authentication != "" && profile.Authentication != "open" || profile.Encryption != "" && profile.Encryption != "none",
+			OneXEnabled:     profile.UseOneX,
+			AuthAlgorithm:   uint32(profileAuthAlgorithms[profile.Authentication]),
+			CipherAlgorithm: uint32(profileCipherAlgorithms[profile.Encryption]),
+		}
+		for _, r := range best.Rates {
+			//Rates are in units of 500 kbps; the top bit marks basic rates.
+			if rate := uint32(r&0x7fff) * 500; rate > c.RxRate {
+				c.RxRate, c.TxRate = rate, rate
+			}
+		}
+		i.connection = c
+		i.info.State = c.State
+		n.Code = uint32(WlanNotificationAcmConnectionComplete)
+	})
+	if callErr != nil {
+		return callErr
+	}
+	if err != nil {
+		return err
+	}
+	s.Notify(n)
+	return nil
+}
+
+func allowedBSSID(allowed [][6]byte, bssid [6]byte) bool {
+	for _, a := range allowed {
+		if a == bssid {
+			return true
+		}
+	}
+	return len(allowed) == 0
+}
+
+//Disconnect disconnects the interface and notifies disconnected.
+func (s *Sim) Disconnect(iface GUID) error {
+	err := s.call(iface, func(i *simInterface) {
+		i.connection = nil
+		i.info.State = uint32(wlan_interface_state_disconnected)
+	})
+	if err != nil {
+		return err
+	}
+	s.Notify(Notification{Source: WLAN_NOTIFICATION_SOURCE_ACM, Code: uint32(WlanNotificationAcmDisconnected), InterfaceGuid: iface})
+	return nil
+}
+
+func (s *Sim) Connection(iface GUID) (c *binary.ConnectionAttributes, err error) {
+	callErr := s.call(iface, func(i *simInterface) {
+		if i.connection == nil {
+			err = ErrNotConnected
+			return
+		}
+		copied := *i.connection
+		copied.SSID = append([]byte(nil), copied.SSID...)
+		c = &copied
+	})
+	if callErr != nil {
+		return nil, callErr
+	}
+	return c, err
+}
+
+//SetStatistics sets the frame counters reported for the interface. The counters of a new interface are 0, without PHYs.
+func (s *Sim) SetStatistics(iface GUID, statistics binary.Statistics) {
+	s.update(iface, func(i *simInterface) { i.statistics = statistics })
+}
+
+func (s *Sim) Statistics(iface GUID) (statistics *binary.Statistics, err error) {
+	callErr := s.call(iface, func(i *simInterface) {
+		copied := i.statistics
+		copied.PhyCounters = append([]binary.PhyFrameStatistics(nil), copied.PhyCounters...)
+		statistics = &copied
+	})
+	if callErr != nil {
+		return nil, callErr
+	}
+	return statistics, nil
+}
+
+//SetHostedNetwork sets the status and settings of the Hosted Network. The Hosted Network of a new Sim is unavailable.
+func (s *Sim) SetHostedNetwork(status binary.HostedNetworkStatus, settings HostedNetworkSettings) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.hosted, s.hostedSettings = status, settings
+}
+
+//SetHostedNetworkError makes every call on the Hosted Network fail with err; a nil err clears it.
+func (s *Sim) SetHostedNetworkError(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.hostedErr = err
+}
+
+func (s *Sim) HostedNetworkStatus() (*binary.HostedNetworkStatus, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.hostedErr != nil {
+		return nil, s.hostedErr
+	}
+	status := s.hosted
+	status.Peers = append([]binary.HostedNetworkPeer(nil), status.Peers...)
+	return &status, nil
+}
+
+func (s *Sim) HostedNetworkSettings() (*HostedNetworkSettings, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.hostedErr != nil {
+		return nil, s.hostedErr
+	}
+	settings := s.hostedSettings
+	settings.SSID = append([]byte(nil), settings.SSID...)
+	return &settings, nil
+}
+
+func (s *Sim) SetHostedNetworkSettings(settings HostedNetworkSettings) error {
+	if len(settings.SSID) == 0 || len(settings.SSID) > 32 {
+		return fmt.Errorf("wlanapi: invalid hosted network SSID %q", settings.SSID)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.hostedErr != nil {
+		return s.hostedErr
+	}
+	s.hostedSettings = HostedNetworkSettings{SSID: append([]byte(nil), settings.SSID...), MaxPeers: settings.MaxPeers}
+	return nil
+}
+
+//StartHostedNetwork makes an idle Hosted Network active and notifies the state change.
+func (s *Sim) StartHostedNetwork() error {
+	return s.setHostedState(wlan_hosted_network_idle, wlan_hosted_network_active)
+}
+
+//StopHostedNetwork makes an active Hosted Network idle, dropping its peers, and notifies the state change.
+func (s *Sim) StopHostedNetwork() error {
+	return s.setHostedState(wlan_hosted_network_active, wlan_hosted_network_idle)
+}
+
+func (s *Sim) setHostedState(from, to WLAN_HOSTED_NETWORK_STATE) error {
+	s.mu.Lock()
+	if s.hostedErr != nil {
+		s.mu.Unlock()
+		return s.hostedErr
+	}
+	if state := WLAN_HOSTED_NETWORK_STATE(s.hosted.State); state != from {
+		s.mu.Unlock()
+		return fmt.Errorf("%w: hosted network is %v", ErrHostedNetworkState, state)
+	}
+	s.hosted.State = uint32(to)
+	if to != wlan_hosted_network_active {
+		s.hosted.Peers = nil
+	}
+	s.mu.Unlock()
+	//The data is WLAN_HOSTED_NETWORK_STATE_CHANGE, with wlan_hosted_network_reason_success as the reason.
+	data := make([]byte, 12)
+	data[0], data[4] = byte(from), byte(to)
+	s.Notify(Notification{Source: WLAN_NOTIFICATION_SOURCE_HNWK, Code: uint32(wlan_hosted_network_state_change), Data: data})
+	return nil
+}
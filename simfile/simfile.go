@@ -0,0 +1,216 @@
+//Package simfile loads a Sim from a JSON fixture, so tools and tests can run against canned interfaces,
+//networks, BSS lists, profiles, frame counters and a Hosted Network instead of the WLAN service.
+package simfile
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strings"
+
+	"wlanapi"
+	"wlanapi/binary"
+	"wlanapi/survey"
+)
+
+//File is a fixture.
+type File struct {
+	Interfaces    []Interface    `json:"interfaces"`
+	HostedNetwork *HostedNetwork `json:"hostedNetwork,omitempty"`
+}
+
+//Interface is an interface of a fixture with what it sees.
+type Interface struct {
+	GUID        string `json:"guid"`
+	Description string `json:"description"`
+	//State is a WLAN_INTERFACE_STATE; Connected overrides it.
+	State    uint32       `json:"state"`
+	Networks []Network    `json:"networks,omitempty"`
+	BSSes    []survey.BSS `json:"bss,omitempty"`
+	//Profiles holds the profile XMLs in preference order.
+	Profiles []string `json:"profiles,omitempty"`
+	//Connected names the profile the interface is connected with once loaded.
+	Connected string `json:"connected,omitempty"`
+	//Statistics are the frame counters of the interface, with the field names of binary.Statistics in camel case.
+	Statistics *binary.Statistics `json:"statistics,omitempty"`
+}
+
+//Network is an available network of a fixture, as WLAN_AVAILABLE_NETWORK.
+type Network struct {
+	ProfileName          string   `json:"profileName,omitempty"`
+	SSID                 string   `json:"ssid"`
+	BssType              uint32   `json:"bssType"`
+	NumberOfBssids       uint32   `json:"numberOfBssids"`
+	Connectable          bool     `json:"connectable"`
+	NotConnectableReason uint32   `json:"notConnectableReason,omitempty"`
+	PhyTypes             []uint32 `json:"phyTypes,omitempty"`
+	SignalQuality        uint32   `json:"signalQuality"`
+	SecurityEnabled      bool     `json:"securityEnabled"`
+	AuthAlgorithm        uint32   `json:"authAlgorithm"`
+	CipherAlgorithm      uint32   `json:"cipherAlgorithm"`
+	Flags                uint32   `json:"flags,omitempty"`
+}
+
+//Entry returns the available network list entry of the network.
+func (n Network) Entry() binary.AvailableNetwork {
+	return binary.AvailableNetwork{
+		ProfileName:            n.ProfileName,
+		SSID:                   []byte(n.SSID),
+		BssType:                n.BssType,
+		NumberOfBssids:         n.NumberOfBssids,
+		NetworkConnectable:     n.Connectable,
+		NotConnectableReason:   n.NotConnectableReason,
+		PhyTypes:               n.PhyTypes,
+		SignalQuality:          n.SignalQuality,
+		SecurityEnabled:        n.SecurityEnabled,
+		DefaultAuthAlgorithm:   n.AuthAlgorithm,
+		DefaultCipherAlgorithm: n.CipherAlgorithm,
+		Flags:                  n.Flags,
+	}
+}
+
+//HostedNetwork is the wireless Hosted Network of a fixture.
+type HostedNetwork struct {
+	//State is a WLAN_HOSTED_NETWORK_STATE.
+	State        uint32 `json:"state"`
+	SSID         string `json:"ssid,omitempty"`
+	MaxPeers     uint32 `json:"maxPeers,omitempty"`
+	BSSID        string `json:"bssid,omitempty"`
+	PhyType      uint32 `json:"phyType,omitempty"`
+	FrequencyKHz uint32 `json:"frequencyKHz,omitempty"`
+	Peers        []Peer `json:"peers,omitempty"`
+}
+
+//Peer is a peer of the Hosted Network.
+type Peer struct {
+	MAC string `json:"mac"`
+	//AuthState is a WLAN_HOSTED_NETWORK_PEER_AUTH_STATE.
+	AuthState uint32 `json:"authState"`
+}
+
+//Read decodes a fixture.
+func Read(r io.Reader) (*File, error) {
+	var f File
+	d := json.NewDecoder(r)
+	d.DisallowUnknownFields()
+	if err := d.Decode(&f); err != nil {
+		return nil, fmt.Errorf("simfile: %v", err)
+	}
+	return &f, nil
+}
+
+//Load reads the fixture at path and returns its Sim.
+func Load(path string) (*wlanapi.Sim, error) {
+	fd, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer fd.Close()
+	f, err := Read(fd)
+	if err != nil {
+		return nil, err
+	}
+	return f.Sim()
+}
+
+//UnknownBackendError is returned by Backend for a spec that names no backend.
+type UnknownBackendError string
+
+func (e UnknownBackendError) Error() string {
+	return fmt.Sprintf("unknown backend %q", string(e))
+}
+
+//Backend opens the client of a backend spec, as the --backend flag of the commands takes it: native for the
+//WLAN service, or sim:path for the Sim of the fixture at path.
+func Backend(spec string) (*wlanapi.Client, error) {
+	switch {
+	case spec == "native":
+		return wlanapi.NewClient()
+	case strings.HasPrefix(spec, "sim:"):
+		sim, err := Load(strings.TrimPrefix(spec, "sim:"))
+		if err != nil {
+			return nil, err
+		}
+		return wlanapi.NewClientWithBackend(sim), nil
+	}
+	return nil, UnknownBackendError(spec)
+}
+
+//Sim returns a Sim with the interfaces and Hosted Network of the fixture.
+func (f *File) Sim() (*wlanapi.Sim, error) {
+	sim := wlanapi.NewSim()
+	for _, fi := range f.Interfaces {
+		guid, err := wlanapi.ParseGUID(fi.GUID)
+		if err != nil {
+			return nil, fmt.Errorf("simfile: interface %q: %v", fi.Description, err)
+		}
+		sim.AddInterface(binary.InterfaceInfo{InterfaceGuid: binary.GUID(guid), Description: fi.Description, State: fi.State})
+
+		networks := make([]binary.AvailableNetwork, len(fi.Networks))
+		for n, network := range fi.Networks {
+			networks[n] = network.Entry()
+		}
+		sim.SetNetworks(guid, networks)
+		entries := make([]binary.BSSEntry, len(fi.BSSes))
+		for n, b := range fi.BSSes {
+			if entries[n], err = b.Entry(); err != nil {
+				return nil, fmt.Errorf("simfile: interface %s: %v", fi.GUID, err)
+			}
+		}
+		sim.SetBSSList(guid, entries)
+		if fi.Statistics != nil {
+			sim.SetStatistics(guid, *fi.Statistics)
+		}
+		for _, xml := range fi.Profiles {
+			if err := sim.SetProfile(guid, xml, false); err != nil {
+				return nil, fmt.Errorf("simfile: interface %s: %v", fi.GUID, err)
+			}
+		}
+		if fi.Connected != "" {
+			if err := sim.Connect(guid, wlanapi.ConnectionParameters{Profile: fi.Connected}); err != nil {
+				return nil, fmt.Errorf("simfile: interface %s: %v", fi.GUID, err)
+			}
+			if _, err := sim.Connection(guid); err != nil {
+				return nil, fmt.Errorf("simfile: interface %s: no BSS for profile %q", fi.GUID, fi.Connected)
+			}
+		}
+	}
+	if h := f.HostedNetwork; h != nil {
+		status, err := h.status()
+		if err != nil {
+			return nil, err
+		}
+		sim.SetHostedNetwork(status, wlanapi.HostedNetworkSettings{SSID: []byte(h.SSID), MaxPeers: h.MaxPeers})
+	}
+	return sim, nil
+}
+
+func (h *HostedNetwork) status() (binary.HostedNetworkStatus, error) {
+	status := binary.HostedNetworkStatus{State: h.State, PhyType: h.PhyType, ChannelFrequency: h.FrequencyKHz}
+	var err error
+	if h.BSSID != "" {
+		if status.BSSID, err = parseMAC(h.BSSID); err != nil {
+			return status, err
+		}
+	}
+	for _, p := range h.Peers {
+		peer := binary.HostedNetworkPeer{AuthState: p.AuthState}
+		if peer.MacAddress, err = parseMAC(p.MAC); err != nil {
+			return status, err
+		}
+		status.Peers = append(status.Peers, peer)
+	}
+	return status, nil
+}
+
+func parseMAC(s string) ([6]byte, error) {
+	var addr [6]byte
+	mac, err := net.ParseMAC(s)
+	if err != nil || len(mac) != len(addr) {
+		return addr, fmt.Errorf("simfile: invalid MAC address %q", s)
+	}
+	copy(addr[:], mac)
+	return addr, nil
+}
@@ -0,0 +1,85 @@
+package simfile
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"wlanapi"
+)
+
+func TestSim(t *testing.T) {
+	f, err := Read(strings.NewReader(`{
+		"interfaces": [{
+			"guid": "{01234567-89AB-CDEF-0123-456789ABCDEF}",
+			"description": "USB",
+			"state": 4,
+			"networks": [{"ssid": "home", "bssType": 1, "connectable": true, "signalQuality": 80}],
+			"bss": [{"bssid": "02:00:00:00:00:01", "ssid": "home", "bssType": 1, "rssi": -50, "frequencyKHz": 5180000}],
+			"profiles": ["<WLANProfile><name>home</name><SSIDConfig><SSID><name>home</name></SSID></SSIDConfig></WLANProfile>"],
+			"connected": "home",
+			"statistics": {"fourWayHandshakeFailures": 1, "phyCounters": [{"retryCount": 7}]}
+		}],
+		"hostedNetwork": {"state": 1, "ssid": "kiosk", "maxPeers": 4, "peers": [{"mac": "02:00:00:00:00:02", "authState": 1}]}
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sim, err := f.Sim()
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := wlanapi.NewClientWithBackend(sim)
+	interfaces, err := c.Interfaces()
+	if err != nil || len(interfaces) != 1 || interfaces[0].GUID.String() != "{01234567-89AB-CDEF-0123-456789ABCDEF}" {
+		t.Fatalf("interfaces %v, %v", interfaces, err)
+	}
+	i := interfaces[0]
+	if i.State.String() != "connected" {
+		t.Errorf("state %v", i.State)
+	}
+	if networks, err := i.AvailableNetworks(); err != nil || len(networks) != 1 || networks[0].SignalQuality != 80 {
+		t.Errorf("networks %+v, %v", networks, err)
+	}
+	if conn, err := i.Connection(); err != nil || conn.ProfileName != "home" || conn.BSSID != [6]byte{2, 0, 0, 0, 0, 1} {
+		t.Errorf("connection %+v, %v", conn, err)
+	}
+	if s, err := i.Statistics(); err != nil || s.FourWayHandshakeFailures != 1 || len(s.PhyCounters) != 1 || s.PhyCounters[0].RetryCount != 7 {
+		t.Errorf("statistics %+v, %v", s, err)
+	}
+	if status, err := c.HostedNetworkStatus(); err != nil || len(status.Peers) != 1 || status.Peers[0].MacAddress[5] != 2 {
+		t.Errorf("hosted network %+v, %v", status, err)
+	}
+	if settings, err := c.HostedNetworkSettings(); err != nil || string(settings.SSID) != "kiosk" || settings.MaxPeers != 4 {
+		t.Errorf("hosted network settings %+v, %v", settings, err)
+	}
+}
+
+func TestInvalid(t *testing.T) {
+	for _, doc := range []string{
+		`{"interfaces": [{"guid": "01234567-89AB-CDEF-0123-456789ABCDEF"}]}`,
+		`{"interfaces": [{"guid": "{01234567-89AB-CDEF-0123-456789ABCDEF}", "bss": [{"bssid": "02:00"}]}]}`,
+		`{"interfaces": [{"guid": "{01234567-89AB-CDEF-0123-456789ABCDEF}", "profiles": ["<WLANProfile/>"]}]}`,
+		`{"interfaces": [{"guid": "{01234567-89AB-CDEF-0123-456789ABCDEF}", "connected": "home"}]}`,
+		`{"hostedNetwork": {"bssid": "nope"}}`,
+		`{"interface": []}`,
+	} {
+		f, err := Read(strings.NewReader(doc))
+		if err == nil {
+			_, err = f.Sim()
+		}
+		if err == nil {
+			t.Errorf("no error for %s", doc)
+		}
+	}
+}
+
+func TestBackend(t *testing.T) {
+	var unknown UnknownBackendError
+	if _, err := Backend("dbus"); !errors.As(err, &unknown) || err.Error() != `unknown backend "dbus"` {
+		t.Errorf("dbus backend: %v", err)
+	}
+	if _, err := Backend("sim:testdata/missing.json"); err == nil || errors.As(err, &unknown) {
+		t.Errorf("missing fixture: %v", err)
+	}
+}
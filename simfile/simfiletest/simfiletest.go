@@ -0,0 +1,41 @@
+//Package simfiletest provides the fixture shared by the tests of the commands and servers built on a Sim:
+//an Intel interface connected to corp among four BSSes, with a profile and frame counters, a disconnected
+//Realtek interface, and an idle Hosted Network.
+package simfiletest
+
+import (
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"wlanapi"
+	"wlanapi/simfile"
+)
+
+//The GUIDs of the interfaces of the fixture.
+const (
+	Intel   = "{11111111-2222-3333-4444-555555555555}"
+	Realtek = "{AAAAAAAA-BBBB-CCCC-DDDD-EEEEEEEEEEEE}"
+)
+
+//Path is the path of the fixture.
+var Path = func() string {
+	_, file, _, _ := runtime.Caller(0)
+	return filepath.Join(filepath.Dir(file), "..", "testdata", "fixture.json")
+}()
+
+//Sim returns a new Sim of the fixture and fails the test when it does not load.
+func Sim(t testing.TB) *wlanapi.Sim {
+	t.Helper()
+	sim, err := simfile.Load(Path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sim
+}
+
+//Client returns a client of a new Sim of the fixture.
+func Client(t testing.TB) *wlanapi.Client {
+	t.Helper()
+	return wlanapi.NewClientWithBackend(Sim(t))
+}
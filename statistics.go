@@ -0,0 +1,19 @@
+package wlanapi
+
+import "wlanapi/binary"
+
+//StatisticsBackend is implemented by backends that report the frame counters of their interfaces.
+type StatisticsBackend interface {
+	//Statistics retrieves the counters of the interface, as WlanQueryInterface with wlan_intf_opcode_statistics.
+	//It returns ErrNotConnected when the driver only counts frames of a connection.
+	Statistics(iface GUID) (*binary.Statistics, error)
+}
+
+//Statistics retrieves the MAC and per-PHY frame counters of the interface.
+func (i *Interface) Statistics() (*binary.Statistics, error) {
+	b, ok := i.client.backend.(StatisticsBackend)
+	if !ok {
+		return nil, ErrBackendNotSupported
+	}
+	return b.Statistics(i.GUID)
+}
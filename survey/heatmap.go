@@ -0,0 +1,267 @@
+package survey
+
+import (
+	"encoding/json"
+	"errors"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"math"
+	"net"
+	"strings"
+)
+
+//Filter selects the BSSes a heatmap shows.
+type Filter func(b *BSS) bool
+
+//ByBSSID selects a BSS by its BSSID.
+func ByBSSID(bssid string) Filter {
+	mac, err := net.ParseMAC(bssid)
+	if err == nil {
+		bssid = mac.String()
+	}
+	return func(b *BSS) bool { return strings.EqualFold(b.BSSID, bssid) }
+}
+
+//BySSID selects the BSSes of an SSID; the heatmap then shows the best of them at each position.
+func BySSID(ssid string) Filter {
+	return func(b *BSS) bool { return b.SSID == ssid }
+}
+
+//Options configures the interpolation of a heatmap.
+type Options struct {
+	//Cell is the side of a grid cell, in floor plan units, or in meters for geographic samples.
+	Cell float64
+	//Power is the exponent of the inverse distance weights; it defaults to 2.
+	Power float64
+	//Radius leaves out the samples farther than it from a cell, in the units of Cell.
+	//Cells without samples in range have no value. 0 uses every sample.
+	Radius float64
+	//Floor selects the samples of a floor.
+	Floor string
+	//Missing is the RSSI of samples where no BSS matches the filter; it defaults to -100 dBm.
+	Missing float64
+}
+
+//Grid is a heatmap of RSSIs in dBm, interpolated with inverse distance weighting.
+type Grid struct {
+	Columns, Rows int
+	//Values holds the RSSIs row by row, from the row of the smallest Y or latitude; NaN is a cell without a value.
+	Values []float64
+	//Geographic grids are laid out in meters around the origin of a local projection.
+	Geographic bool
+	//MinX and MinY are the projected coordinates of the corner of the first cell, and Cell the side of the cells.
+	MinX, MinY, Cell float64
+	lat0, lon0       float64
+}
+
+const (
+	metersPerDegreeLat = 110574.0
+	metersPerDegreeLon = 111320.0
+)
+
+//project returns the planar coordinates of a position, in meters for geographic positions.
+func (g *Grid) project(p Position) (float64, float64) {
+	if !g.Geographic {
+		return p.X, p.Y
+	}
+	return (p.Lon - g.lon0) * math.Cos(g.lat0*math.Pi/180) * metersPerDegreeLon, (p.Lat - g.lat0) * metersPerDegreeLat
+}
+
+//unproject returns the coordinates of a projected point: x and y, or longitude and latitude.
+func (g *Grid) unproject(x, y float64) (float64, float64) {
+	if !g.Geographic {
+		return x, y
+	}
+	return g.lon0 + x/(math.Cos(g.lat0*math.Pi/180)*metersPerDegreeLon), g.lat0 + y/metersPerDegreeLat
+}
+
+//Value returns the RSSI of a cell, NaN if it has none.
+func (g *Grid) Value(column, row int) float64 {
+	return g.Values[row*g.Columns+column]
+}
+
+//Bounds returns the corners of a cell as x and y, or as longitude and latitude.
+func (g *Grid) Bounds(column, row int) (x0, y0, x1, y1 float64) {
+	x0, y0 = g.unproject(g.MinX+float64(column)*g.Cell, g.MinY+float64(row)*g.Cell)
+	x1, y1 = g.unproject(g.MinX+float64(column+1)*g.Cell, g.MinY+float64(row+1)*g.Cell)
+	return
+}
+
+type point struct {
+	x, y, rssi float64
+}
+
+//Heatmap interpolates the RSSI of the BSSes selected by filter over a grid covering the samples.
+func Heatmap(samples []Sample, filter Filter, opts Options) (*Grid, error) {
+	if opts.Cell <= 0 {
+		return nil, errors.New("survey: heatmap cell size must be positive")
+	}
+	if opts.Power == 0 {
+		opts.Power = 2
+	}
+	if opts.Missing == 0 {
+		opts.Missing = -100
+	}
+
+	var floor []Sample
+	for _, s := range samples {
+		if s.Position.Floor == opts.Floor {
+			floor = append(floor, s)
+		}
+	}
+	if len(floor) == 0 {
+		return nil, errors.New("survey: no samples on the floor")
+	}
+	g := &Grid{Geographic: floor[0].Position.Geographic, Cell: opts.Cell}
+	if g.Geographic {
+		g.lat0, g.lon0 = floor[0].Position.Lat, floor[0].Position.Lon
+	}
+
+	points := make([]point, len(floor))
+	minX, minY, maxX, maxY := math.Inf(1), math.Inf(1), math.Inf(-1), math.Inf(-1)
+	for n, s := range floor {
+		if s.Position.Geographic != g.Geographic {
+			return nil, errors.New("survey: heatmap mixes floor plan and geographic positions")
+		}
+		p := point{rssi: math.Inf(-1)}
+		p.x, p.y = g.project(s.Position)
+		for i := range s.BSSes {
+			if b := &s.BSSes[i]; filter(b) && float64(b.RSSI) > p.rssi {
+				p.rssi = float64(b.RSSI)
+			}
+		}
+		if math.IsInf(p.rssi, -1) {
+			p.rssi = opts.Missing
+		}
+		points[n] = p
+		minX, minY = math.Min(minX, p.x), math.Min(minY, p.y)
+		maxX, maxY = math.Max(maxX, p.x), math.Max(maxY, p.y)
+	}
+
+	g.MinX, g.MinY = minX, minY
+	g.Columns = int(math.Floor((maxX-minX)/opts.Cell)) + 1
+	g.Rows = int(math.Floor((maxY-minY)/opts.Cell)) + 1
+	g.Values = make([]float64, g.Columns*g.Rows)
+	for row := 0; row < g.Rows; row++ {
+		for column := 0; column < g.Columns; column++ {
+			x, y := g.MinX+(float64(column)+0.5)*g.Cell, g.MinY+(float64(row)+0.5)*g.Cell
+			g.Values[row*g.Columns+column] = idw(points, x, y, opts.Power, opts.Radius)
+		}
+	}
+	return g, nil
+}
+
+//idw returns the inverse distance weighted RSSI at x, y, or NaN without points in the radius.
+func idw(points []point, x, y, power, radius float64) float64 {
+	var sum, weights float64
+	for _, p := range points {
+		d := math.Hypot(p.x-x, p.y-y)
+		if radius > 0 && d > radius {
+			continue
+		}
+		if d == 0 {
+			return p.rssi
+		}
+		w := 1 / math.Pow(d, power)
+		sum += w * p.rssi
+		weights += w
+	}
+	if weights == 0 {
+		return math.NaN()
+	}
+	return sum / weights
+}
+
+//colorStops map RSSIs to colors: red for unusable signals, yellow for fair ones, green for good ones.
+var colorStops = []struct {
+	rssi  float64
+	color color.NRGBA
+}{
+	{-90, color.NRGBA{0xd7, 0x19, 0x1c, 0xff}},
+	{-75, color.NRGBA{0xfd, 0xae, 0x61, 0xff}},
+	{-67, color.NRGBA{0xff, 0xff, 0x66, 0xff}},
+	{-50, color.NRGBA{0x1a, 0x96, 0x41, 0xff}},
+}
+
+func colorOf(rssi float64) color.NRGBA {
+	if math.IsNaN(rssi) {
+		return color.NRGBA{}
+	}
+	if rssi <= colorStops[0].rssi {
+		return colorStops[0].color
+	}
+	for n := 1; n < len(colorStops); n++ {
+		a, b := colorStops[n-1], colorStops[n]
+		if rssi <= b.rssi {
+			f := (rssi - a.rssi) / (b.rssi - a.rssi)
+			mix := func(x, y uint8) uint8 { return uint8(math.Round(float64(x) + f*(float64(y)-float64(x)))) }
+			return color.NRGBA{mix(a.color.R, b.color.R), mix(a.color.G, b.color.G), mix(a.color.B, b.color.B), 0xff}
+		}
+	}
+	return colorStops[len(colorStops)-1].color
+}
+
+//Image renders the grid with scale pixels per cell, north or increasing Y up. Cells without a value are transparent.
+func (g *Grid) Image(scale int) image.Image {
+	if scale < 1 {
+		scale = 1
+	}
+	img := image.NewNRGBA(image.Rect(0, 0, g.Columns*scale, g.Rows*scale))
+	for row := 0; row < g.Rows; row++ {
+		for column := 0; column < g.Columns; column++ {
+			c := colorOf(g.Value(column, row))
+			top := (g.Rows - 1 - row) * scale
+			for y := top; y < top+scale; y++ {
+				for x := column * scale; x < (column+1)*scale; x++ {
+					img.SetNRGBA(x, y, c)
+				}
+			}
+		}
+	}
+	return img
+}
+
+//WritePNG writes the image of the grid as a PNG.
+func (g *Grid) WritePNG(w io.Writer, scale int) error {
+	return png.Encode(w, g.Image(scale))
+}
+
+type geoJSONFeature struct {
+	Type       string             `json:"type"`
+	Geometry   geoJSONGeometry    `json:"geometry"`
+	Properties map[string]float64 `json:"properties"`
+}
+
+type geoJSONGeometry struct {
+	Type        string         `json:"type"`
+	Coordinates [][][2]float64 `json:"coordinates"`
+}
+
+//GeoJSON returns the cells with a value as a FeatureCollection of polygons with an "rssi" property.
+//Geographic grids use longitude and latitude; floor plan grids use their x and y as coordinates.
+func (g *Grid) GeoJSON() ([]byte, error) {
+	features := []geoJSONFeature{}
+	for row := 0; row < g.Rows; row++ {
+		for column := 0; column < g.Columns; column++ {
+			v := g.Value(column, row)
+			if math.IsNaN(v) {
+				continue
+			}
+			x0, y0, x1, y1 := g.Bounds(column, row)
+			features = append(features, geoJSONFeature{
+				Type: "Feature",
+				Geometry: geoJSONGeometry{
+					Type:        "Polygon",
+					Coordinates: [][][2]float64{{{x0, y0}, {x1, y0}, {x1, y1}, {x0, y1}, {x0, y0}}},
+				},
+				Properties: map[string]float64{"rssi": math.Round(v*10) / 10},
+			})
+		}
+	}
+	return json.Marshal(struct {
+		Type     string           `json:"type"`
+		Features []geoJSONFeature `json:"features"`
+	}{"FeatureCollection", features})
+}
@@ -0,0 +1,270 @@
+//Package survey records site surveys: BSS lists taken at known positions, stored as JSON Lines,
+//and interpolates them into signal heatmaps.
+package survey
+
+import (
+	"bufio"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"wlanapi"
+	"wlanapi/binary"
+)
+
+//Position is where a sample was taken: floor plan coordinates, or a latitude and longitude when Geographic is set.
+type Position struct {
+	X, Y       float64
+	Lat, Lon   float64
+	Geographic bool
+	//Floor names the floor plan or level; heatmaps combine the samples of one floor.
+	Floor string
+}
+
+//At returns a floor plan position.
+func At(x, y float64) Position {
+	return Position{X: x, Y: y}
+}
+
+//AtGeo returns a geographic position in WGS84 degrees.
+func AtGeo(lat, lon float64) Position {
+	return Position{Lat: lat, Lon: lon, Geographic: true}
+}
+
+type jsonPosition struct {
+	X     *float64 `json:"x,omitempty"`
+	Y     *float64 `json:"y,omitempty"`
+	Lat   *float64 `json:"lat,omitempty"`
+	Lon   *float64 `json:"lon,omitempty"`
+	Floor string   `json:"floor,omitempty"`
+}
+
+func (p Position) MarshalJSON() ([]byte, error) {
+	j := jsonPosition{Floor: p.Floor}
+	if p.Geographic {
+		j.Lat, j.Lon = &p.Lat, &p.Lon
+	} else {
+		j.X, j.Y = &p.X, &p.Y
+	}
+	return json.Marshal(j)
+}
+
+func (p *Position) UnmarshalJSON(b []byte) error {
+	var j jsonPosition
+	if err := json.Unmarshal(b, &j); err != nil {
+		return err
+	}
+	switch {
+	case j.Lat != nil && j.Lon != nil && j.X == nil && j.Y == nil:
+		*p = AtGeo(*j.Lat, *j.Lon)
+	case j.X != nil && j.Y != nil && j.Lat == nil && j.Lon == nil:
+		*p = At(*j.X, *j.Y)
+	default:
+		return errors.New("survey: a position needs either x and y or lat and lon")
+	}
+	p.Floor = j.Floor
+	return nil
+}
+
+//BSS is a BSS entry as stored in a survey file.
+type BSS struct {
+	BSSID string `json:"bssid"`
+	//SSID holds the bytes of the SSID, which JSON can only carry when they are valid UTF-8.
+	SSID string `json:"ssid"`
+	//SSIDHex is the SSID in hexadecimal when it is not valid UTF-8; it then takes precedence over SSID.
+	SSIDHex       string   `json:"ssidHex,omitempty"`
+	PhyID         uint32   `json:"phyId"`
+	BssType       uint32   `json:"bssType"`
+	PhyType       uint32   `json:"phyType"`
+	RSSI          int32    `json:"rssi"`
+	LinkQuality   uint32   `json:"linkQuality"`
+	InRegDomain   bool     `json:"inRegDomain"`
+	BeaconPeriod  uint16   `json:"beaconPeriod"`
+	Timestamp     uint64   `json:"timestamp"`
+	HostTimestamp uint64   `json:"hostTimestamp"`
+	Capability    uint16   `json:"capability"`
+	FrequencyKHz  uint32   `json:"frequencyKHz"`
+	Rates         []uint16 `json:"rates,omitempty"`
+	IEs           []byte   `json:"ies,omitempty"`
+}
+
+//NewBSS returns the record of a BSS entry.
+func NewBSS(e binary.BSSEntry) BSS {
+	b := BSS{
+		BSSID:         net.HardwareAddr(e.BSSID[:]).String(),
+		SSID:          string(e.SSID),
+		PhyID:         e.PhyID,
+		BssType:       e.BssType,
+		PhyType:       e.PhyType,
+		RSSI:          e.RSSI,
+		LinkQuality:   e.LinkQuality,
+		InRegDomain:   e.InRegDomain,
+		BeaconPeriod:  e.BeaconPeriod,
+		Timestamp:     e.Timestamp,
+		HostTimestamp: e.HostTimestamp,
+		Capability:    e.CapabilityInformation,
+		FrequencyKHz:  e.ChCenterFrequency,
+		Rates:         e.Rates,
+		IEs:           e.IEs,
+	}
+	if !utf8.Valid(e.SSID) {
+		b.SSIDHex = hex.EncodeToString(e.SSID)
+	}
+	return b
+}
+
+//Entry returns the BSS entry of the record.
+func (b BSS) Entry() (binary.BSSEntry, error) {
+	e := binary.BSSEntry{
+		SSID:                  []byte(b.SSID),
+		PhyID:                 b.PhyID,
+		BssType:               b.BssType,
+		PhyType:               b.PhyType,
+		RSSI:                  b.RSSI,
+		LinkQuality:           b.LinkQuality,
+		InRegDomain:           b.InRegDomain,
+		BeaconPeriod:          b.BeaconPeriod,
+		Timestamp:             b.Timestamp,
+		HostTimestamp:         b.HostTimestamp,
+		CapabilityInformation: b.Capability,
+		ChCenterFrequency:     b.FrequencyKHz,
+		Rates:                 b.Rates,
+		IEs:                   b.IEs,
+	}
+	if b.SSIDHex != "" {
+		ssid, err := hex.DecodeString(b.SSIDHex)
+		if err != nil {
+			return e, fmt.Errorf("survey: invalid SSID %q", b.SSIDHex)
+		}
+		e.SSID = ssid
+	}
+	mac, err := net.ParseMAC(b.BSSID)
+	if err != nil || len(mac) != len(e.BSSID) {
+		return e, fmt.Errorf("survey: invalid BSSID %q", b.BSSID)
+	}
+	copy(e.BSSID[:], mac)
+	return e, nil
+}
+
+//Sample is the BSS list of an interface at a position.
+type Sample struct {
+	Time     time.Time `json:"time"`
+	Position Position  `json:"position"`
+	//Interface is the GUID of the interface that took the sample.
+	Interface string `json:"interface,omitempty"`
+	BSSes     []BSS  `json:"bss"`
+}
+
+//Entries returns the BSS entries of the sample.
+func (s *Sample) Entries() ([]binary.BSSEntry, error) {
+	entries := make([]binary.BSSEntry, len(s.BSSes))
+	for n, b := range s.BSSes {
+		var err error
+		if entries[n], err = b.Entry(); err != nil {
+			return nil, err
+		}
+	}
+	return entries, nil
+}
+
+//Recorder writes samples to a survey file, one JSON object per line.
+type Recorder struct {
+	//Now timestamps the samples taken with Take; it defaults to time.Now.
+	Now func() time.Time
+
+	mu  sync.Mutex
+	enc *json.Encoder
+}
+
+//NewRecorder returns a Recorder writing to w.
+func NewRecorder(w io.Writer) *Recorder {
+	return &Recorder{Now: time.Now, enc: json.NewEncoder(w)}
+}
+
+//Record writes a sample.
+func (r *Recorder) Record(s Sample) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.enc.Encode(s)
+}
+
+//Take reads the BSS list of an interface, as WlanGetNetworkBssList, and records it at the position.
+//Callers scan before, or keep a Monitor running, for the list to be fresh.
+func (r *Recorder) Take(i *wlanapi.Interface, position Position) (Sample, error) {
+	entries, err := i.BSSList()
+	if err != nil {
+		return Sample{}, err
+	}
+	s := Sample{Time: r.Now(), Position: position, Interface: i.GUID.String(), BSSes: make([]BSS, len(entries))}
+	for n, e := range entries {
+		s.BSSes[n] = NewBSS(e)
+	}
+	return s, r.Record(s)
+}
+
+//Read reads the samples of a survey file. Empty lines are skipped.
+func Read(r io.Reader) ([]Sample, error) {
+	var samples []Sample
+	s := bufio.NewScanner(r)
+	s.Buffer(nil, 16<<20)
+	for line := 1; s.Scan(); line++ {
+		if len(s.Bytes()) == 0 {
+			continue
+		}
+		var sample Sample
+		if err := json.Unmarshal(s.Bytes(), &sample); err != nil {
+			return samples, fmt.Errorf("survey: line %d: %v", line, err)
+		}
+		samples = append(samples, sample)
+	}
+	return samples, s.Err()
+}
+
+//Player replays samples through a Sim, so code using a Client sees the recorded BSS lists.
+type Player struct {
+	sim     *wlanapi.Sim
+	iface   wlanapi.GUID
+	samples []Sample
+	next    int
+}
+
+//replayInterface is the GUID of the interface of a Player.
+var replayInterface = binary.GUID{Data1: 0x5eed5eed, Data2: 0x5eed, Data3: 0x5eed}
+
+//NewPlayer returns a Player of the samples, before the first one.
+func NewPlayer(samples []Sample) *Player {
+	sim := wlanapi.NewSim()
+	sim.AddInterface(binary.InterfaceInfo{InterfaceGuid: replayInterface, Description: "Survey replay", State: 1})
+	return &Player{sim: sim, iface: wlanapi.GUID(replayInterface), samples: samples}
+}
+
+//Sim returns the Sim that reports the BSS list of the current sample.
+func (p *Player) Sim() *wlanapi.Sim {
+	return p.sim
+}
+
+//Interface returns the GUID of the interface of the Sim.
+func (p *Player) Interface() wlanapi.GUID {
+	return p.iface
+}
+
+//Next makes the Sim report the BSS list of the next sample and returns the sample, or false after the last one.
+func (p *Player) Next() (Sample, bool, error) {
+	if p.next >= len(p.samples) {
+		return Sample{}, false, nil
+	}
+	s := p.samples[p.next]
+	p.next++
+	entries, err := s.Entries()
+	if err != nil {
+		return s, false, err
+	}
+	p.sim.SetBSSList(p.iface, entries)
+	return s, true, nil
+}
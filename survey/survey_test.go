@@ -0,0 +1,216 @@
+package survey
+
+import (
+	"bytes"
+	"encoding/json"
+	"image/png"
+	"math"
+	"os"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"wlanapi"
+	"wlanapi/binary"
+)
+
+func readFixture(t *testing.T) ([]byte, []Sample) {
+	t.Helper()
+	b, err := os.ReadFile("testdata/office.jsonl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	samples, err := Read(bytes.NewReader(b))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b, samples
+}
+
+//TestReplay replays a recorded survey through a client and records it again, which must give the same file.
+func TestReplay(t *testing.T) {
+	fixture, samples := readFixture(t)
+	if len(samples) != 6 || samples[4].Position != (Position{X: 10, Y: 10, Floor: "2"}) || len(samples[0].BSSes) != 3 {
+		t.Fatalf("samples %+v", samples)
+	}
+
+	player := NewPlayer(samples)
+	client := wlanapi.NewClientWithBackend(player.Sim())
+	interfaces, err := client.Interfaces()
+	if err != nil || len(interfaces) != 1 || interfaces[0].GUID != player.Interface() {
+		t.Fatalf("interfaces %v, %v", interfaces, err)
+	}
+	var out bytes.Buffer
+	recorder := NewRecorder(&out)
+	for {
+		s, ok, err := player.Next()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !ok {
+			break
+		}
+		recorder.Now = func() time.Time { return s.Time }
+		taken, err := recorder.Take(interfaces[0], s.Position)
+		if err != nil {
+			t.Fatal(err)
+		}
+		entries, _ := taken.Entries()
+		if len(entries) != len(s.BSSes) || entries[0].BSSID != [6]byte{0x00, 0x1a, 0x1e, 0, 0, 1} || string(entries[0].IEs[2:]) != "corp" {
+			t.Fatalf("entries %+v", entries)
+		}
+	}
+	if out.String() != string(fixture) {
+		t.Errorf("recorded survey differs from the replayed one:\n%s", out.String())
+	}
+}
+
+//TestBSSJSON checks that an SSID that is not valid UTF-8 survives a survey file.
+func TestBSSJSON(t *testing.T) {
+	for _, ssid := range []string{"corp", "caf\xe9", "\xff\x00"} {
+		b := NewBSS(binary.BSSEntry{BSSID: [6]byte{0x00, 0x1a, 0x1e, 0, 0, 1}, SSID: []byte(ssid)})
+		j, err := json.Marshal(b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if strings.Contains(string(j), "ssidHex") == utf8.ValidString(ssid) {
+			t.Errorf("%q: %s", ssid, j)
+		}
+		var read BSS
+		if err := json.Unmarshal(j, &read); err != nil {
+			t.Fatal(err)
+		}
+		if e, err := read.Entry(); err != nil || string(e.SSID) != ssid {
+			t.Errorf("%q: %s gives %q, %v", ssid, j, e.SSID, err)
+		}
+	}
+	if _, err := (BSS{BSSID: "00:1a:1e:00:00:01", SSIDHex: "zz"}).Entry(); err == nil {
+		t.Error("invalid ssidHex")
+	}
+}
+
+func TestPositionJSON(t *testing.T) {
+	for _, p := range []Position{At(1.5, -2), AtGeo(48.8584, 2.2945), {X: 3, Y: 4, Floor: "B1"}} {
+		b, err := json.Marshal(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var q Position
+		if err := json.Unmarshal(b, &q); err != nil || q != p {
+			t.Errorf("%s: %+v, %v", b, q, err)
+		}
+	}
+	for _, s := range []string{`{}`, `{"x":1}`, `{"x":1,"y":2,"lat":3,"lon":4}`} {
+		var p Position
+		if err := json.Unmarshal([]byte(s), &p); err == nil {
+			t.Errorf("%s parsed", s)
+		}
+	}
+	if _, err := Read(strings.NewReader("{\"time\":\"2020-03-02T09:00:00Z\",\"position\":{},\"bss\":[]}\n")); err == nil ||
+		!strings.Contains(err.Error(), "line 1") {
+		t.Errorf("invalid position: %v", err)
+	}
+}
+
+func TestHeatmap(t *testing.T) {
+	_, samples := readFixture(t)
+	g, err := Heatmap(samples, BySSID("corp"), Options{Cell: 5, Floor: "2"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g.Columns != 5 || g.Rows != 3 || g.Geographic {
+		t.Fatalf("grid %dx%d", g.Columns, g.Rows)
+	}
+	//Both corp APs are strong near them and the signal is weakest in between.
+	near1, near2, middle := g.Value(0, 0), g.Value(4, 2), g.Value(2, 0)
+	if near1 < -50 || near2 < -50 || middle > near1 || middle > near2 {
+		t.Errorf("near the APs %.1f and %.1f, in between %.1f", near1, near2, middle)
+	}
+	x0, y0, x1, y1 := g.Bounds(4, 2)
+	if x0 != 20 || y0 != 10 || x1 != 25 || y1 != 15 {
+		t.Errorf("bounds %v %v %v %v", x0, y0, x1, y1)
+	}
+
+	guest, err := Heatmap(samples, ByBSSID("00-1A-1E-00-00-03"), Options{Cell: 2, Floor: "2", Radius: 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v := guest.Value(5, 5); v != -40 {
+		t.Errorf("guest at its AP %.1f", v)
+	}
+	if v := guest.Value(1, 3); !math.IsNaN(v) {
+		t.Errorf("cell out of the radius has value %.1f", v)
+	}
+
+	var b bytes.Buffer
+	if err := guest.WritePNG(&b, 4); err != nil {
+		t.Fatal(err)
+	}
+	img, err := png.Decode(&b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if size := img.Bounds().Size(); size.X != 44 || size.Y != 24 {
+		t.Errorf("image size %v", size)
+	}
+	//The row of the largest Y is on top.
+	if _, _, _, a := img.At(21, 1).RGBA(); a == 0 {
+		t.Error("the AP cell is transparent")
+	}
+	if _, _, _, a := img.At(5, 9).RGBA(); a != 0 {
+		t.Error("a cell without value is opaque")
+	}
+
+	if _, err := Heatmap(samples, BySSID("corp"), Options{Cell: 5}); err == nil {
+		t.Error("heatmap of a floor without samples")
+	}
+	if _, err := Heatmap(append(samples, Sample{Position: Position{Lat: 1, Lon: 1, Geographic: true, Floor: "2"}}), BySSID("corp"), Options{Cell: 5, Floor: "2"}); err == nil {
+		t.Error("heatmap mixing position kinds")
+	}
+}
+
+func TestHeatmapGeoJSON(t *testing.T) {
+	samples := []Sample{
+		{Position: AtGeo(48.85800, 2.29400), BSSes: []BSS{{BSSID: "02:00:00:00:00:01", SSID: "park", RSSI: -45}}},
+		{Position: AtGeo(48.85828, 2.29442), BSSes: []BSS{{BSSID: "02:00:00:00:00:01", SSID: "park", RSSI: -80}}},
+		{Position: AtGeo(48.85800, 2.29442)},
+	}
+	g, err := Heatmap(samples, BySSID("park"), Options{Cell: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	//The samples are 30 m apart both ways.
+	if !g.Geographic || g.Columns != 4 || g.Rows != 4 {
+		t.Fatalf("grid %dx%d", g.Columns, g.Rows)
+	}
+	b, err := g.GeoJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fc struct {
+		Type     string
+		Features []struct {
+			Geometry struct {
+				Type        string
+				Coordinates [][][2]float64
+			}
+			Properties map[string]float64
+		}
+	}
+	if err := json.Unmarshal(b, &fc); err != nil {
+		t.Fatal(err)
+	}
+	if fc.Type != "FeatureCollection" || len(fc.Features) != 16 {
+		t.Fatalf("%s with %d features", fc.Type, len(fc.Features))
+	}
+	first := fc.Features[0]
+	corner := first.Geometry.Coordinates[0][0]
+	if first.Geometry.Type != "Polygon" || len(first.Geometry.Coordinates[0]) != 5 ||
+		math.Abs(corner[0]-2.294) > 1e-9 || math.Abs(corner[1]-48.858) > 1e-9 {
+		t.Errorf("first cell %+v", first.Geometry)
+	}
+	if rssi := first.Properties["rssi"]; rssi > -45 || rssi < -60 {
+		t.Errorf("first cell RSSI %v", rssi)
+	}
+}
@@ -1,3 +1,6 @@
+//go:build windows
+// +build windows
+
 package wlanapi
 
 import (
@@ -8,6 +11,8 @@ import (
 const (
 	MAX_INDEX = 1000
 	S_OK      = 0
+
+	WLAN_MAX_PHY_INDEX = 64
 )
 
 //A DOT11_SSID structure contains the SSID of an interface.
@@ -19,6 +24,12 @@ type DOT11_SSID struct {
 
 type DOT11_MAC_ADDRESS [6]UCHAR
 
+//Values of the NDIS_OBJECT_HEADER of a DOT11_BSSID_LIST.
+const (
+	NDIS_OBJECT_TYPE_DEFAULT    = 0x80
+	DOT11_BSSID_LIST_REVISION_1 = 1
+)
+
 //The DOT11_BSSID_LIST structure contains a list of basic service set (BSS) identifiers.
 //https://docs.microsoft.com/en-us/windows/win32/nativewifi/dot11-bssid-list
 type DOT11_BSSID_LIST struct {
@@ -40,7 +51,7 @@ type DOT11_NETWORK struct {
 type DOT11_NETWORK_LIST struct {
 	dwNumberOfItems DWORD
 	dwIndex         DWORD
-	Network         [MAX_INDEX + 1]DOT11_NETWORK
+	Network         [1]DOT11_NETWORK
 }
 
 type NDIS_OBJECT_HEADER struct {
@@ -54,7 +65,7 @@ type NDIS_OBJECT_HEADER struct {
 type WLAN_AVAILABLE_NETWORK_LIST struct {
 	dwNumberOfItems uint32
 	dwIndex         uint32
-	Network         [MAX_INDEX + 1]WLAN_AVAILABLE_NETWORK
+	Network         [1]WLAN_AVAILABLE_NETWORK
 }
 
 //The WLAN_AVAILABLE_NETWORK structure contains information about an available wireless network.
@@ -84,7 +95,7 @@ func (avn WLAN_AVAILABLE_NETWORK) GetStrProfileName() (profileName string) {
 type WLAN_BSS_LIST struct {
 	dwTotalSize     uint32
 	dwNumberOfItems uint32
-	wlanBssEntries  [MAX_INDEX + 1]WLAN_BSS_ENTRY
+	wlanBssEntries  [1]WLAN_BSS_ENTRY
 }
 
 type WLAN_BSS_ENTRY struct {
@@ -95,7 +106,7 @@ type WLAN_BSS_ENTRY struct {
 	dot11BssPhyType         uint32
 	lRssi                   int32
 	uLinkQuality            uint32
-	bInRegDomain            int32
+	bInRegDomain            BOOLEAN
 	usBeaconPeriod          uint16
 	ullTimestamp            uint64
 	ullHostTimestamp        uint64
@@ -120,18 +131,22 @@ type WLAN_INTERFACE_INFO struct {
 type WLAN_INTERFACE_INFO_LIST struct {
 	dwNumberOfItems uint32
 	dwIndex         uint32
-	InterfaceInfo   [MAX_INDEX + 1]WLAN_INTERFACE_INFO
+	InterfaceInfo   [1]WLAN_INTERFACE_INFO
 }
 
+//The WLAN_PROFILE_INFO structure contains basic information about a profile.
+//https://docs.microsoft.com/en-us/windows/win32/api/wlanapi/ns-wlanapi-wlan_profile_info
 type WLAN_PROFILE_INFO struct {
-	ProfileName uint8
-	Flags       uint32
+	strProfileName [256]uint16
+	dwFlags        uint32
 }
 
+//The WLAN_PROFILE_INFO_LIST structure contains a list of wireless profile information.
+//https://docs.microsoft.com/en-us/windows/win32/api/wlanapi/ns-wlanapi-wlan_profile_info_list
 type WLAN_PROFILE_INFO_LIST struct {
-	NumberOfItems uint32
-	Index         uint32
-	ProfileInfo   WLAN_PROFILE_INFO
+	dwNumberOfItems uint32
+	dwIndex         uint32
+	ProfileInfo     [1]WLAN_PROFILE_INFO
 }
 
 type WLAN_RAW_DATA struct {
@@ -141,11 +156,12 @@ type WLAN_RAW_DATA struct {
 
 //The WLAN_CONNECTION_PARAMETERS structure specifies the parameters used when using the WlanConnect function.
 //https://docs.microsoft.com/en-us/windows/win32/api/wlanapi/ns-wlanapi-wlan_connection_parameters
+//strProfile is a profile name or, for wlan_connection_mode_temporary_profile, a profile XML.
 type WLAN_CONNECTION_PARAMETERS struct {
 	wlanConnectionMode WLAN_CONNECTION_MODE
-	strProfile         string
-	pDot11Ssid         DOT11_SSID
-	pDesiredBssidList  DOT11_BSSID_LIST
+	strProfile         *uint16
+	pDot11Ssid         *DOT11_SSID
+	pDesiredBssidList  *DOT11_BSSID_LIST
 	dot11BssType       DOT11_BSS_TYPE
 	dwFlags            DWORD
 }
@@ -158,7 +174,32 @@ type WLAN_INTERFACE_CAPABILITY struct {
 	dwMaxDesiredSsidListSize  DWORD
 	dwMaxDesiredBssidListSize DWORD
 	dwNumberOfSupportedPhys   DWORD
-	dot11PhyTypes             [MAX_INDEX + 1]DOT11_PHY_TYPE
+	dot11PhyTypes             [WLAN_MAX_PHY_INDEX]DOT11_PHY_TYPE
+}
+
+//The DOT11_AUTH_CIPHER_PAIR structure defines a pair of 802.11 authentication and cipher algorithms that can be enabled at the same time on the 802.11 station.
+//https://docs.microsoft.com/en-us/windows/win32/nativewifi/dot11-auth-cipher-pair
+type DOT11_AUTH_CIPHER_PAIR struct {
+	AuthAlgoId   DOT11_AUTH_ALGORITHM
+	CipherAlgoId DOT11_CIPHER_ALGORITHM
+}
+
+//The WLAN_AUTH_CIPHER_PAIR_LIST structure contains a list of authentication and cipher algorithm pairs.
+//https://docs.microsoft.com/en-us/windows/win32/api/wlanapi/ns-wlanapi-wlan_auth_cipher_pair_list
+type WLAN_AUTH_CIPHER_PAIR_LIST struct {
+	dwNumberOfItems     DWORD
+	pAuthCipherPairList [1]DOT11_AUTH_CIPHER_PAIR
+}
+
+//The DOT11_COUNTRY_OR_REGION_STRING type is a 3-byte country or region string as defined by 802.11d.
+//https://docs.microsoft.com/en-us/windows/win32/nativewifi/dot11-country-or-region-string
+type DOT11_COUNTRY_OR_REGION_STRING [3]UCHAR
+
+//The WLAN_COUNTRY_OR_REGION_STRING_LIST structure contains a list of supported country or region strings.
+//https://docs.microsoft.com/en-us/windows/win32/api/wlanapi/ns-wlanapi-wlan_country_or_region_string_list
+type WLAN_COUNTRY_OR_REGION_STRING_LIST struct {
+	dwNumberOfItems            DWORD
+	pCountryOrRegionStringList [1]DOT11_COUNTRY_OR_REGION_STRING
 }
 
 //WLAN_DEVICE_SERVICE_GUID_LIST Contains an array of device service GUIDs.
@@ -169,6 +210,25 @@ type WLAN_DEVICE_SERVICE_GUID_LIST struct {
 	DeviceService   [MAX_INDEX + 1]syscall.GUID
 }
 
+//WLAN_DEVICE_SERVICE_NOTIFICATION_DATA A structure that represents a device service notification.
+//https://docs.microsoft.com/en-us/windows/win32/api/wlanapi/ns-wlanapi-wlan_device_service_notification_data
+type WLAN_DEVICE_SERVICE_NOTIFICATION_DATA struct {
+	DeviceService syscall.GUID
+	dwOpCode      DWORD
+	dwDataSize    DWORD
+	DataBlob      [1]BYTE
+}
+
+//The WLAN_NOTIFICATION_DATA structure contains information provided when receiving notifications.
+//https://docs.microsoft.com/en-us/windows/win32/api/wlanapi/ns-wlanapi-wlan_notification_data
+type WLAN_NOTIFICATION_DATA struct {
+	NotificationSource DWORD
+	NotificationCode   DWORD
+	InterfaceGuid      windows.GUID
+	dwDataSize         DWORD
+	pData              PVOID
+}
+
 //The WLAN_HOSTED_NETWORK_PEER_STATE structure contains information about the peer state for a peer on the wireless Hosted Network.
 //https://docs.microsoft.com/en-us/windows/win32/api/wlanapi/ns-wlanapi-wlan_hosted_network_peer_state
 type WLAN_HOSTED_NETWORK_PEER_STATE struct {
@@ -185,7 +245,14 @@ type WLAN_HOSTED_NETWORK_STATUS struct {
 	dot11PhyType           DOT11_PHY_TYPE
 	ulChannelFrequency     ULONG
 	dwNumberOfPeers        DWORD
-	PeerList               [MAX_INDEX + 1]WLAN_HOSTED_NETWORK_PEER_STATE
+	PeerList               [1]WLAN_HOSTED_NETWORK_PEER_STATE
+}
+
+//The WLAN_HOSTED_NETWORK_CONNECTION_SETTINGS structure contains information about the connection settings on the wireless Hosted Network.
+//https://docs.microsoft.com/en-us/windows/win32/api/wlanapi/ns-wlanapi-wlan_hosted_network_connection_settings
+type WLAN_HOSTED_NETWORK_CONNECTION_SETTINGS struct {
+	hostedNetworkSSID  DOT11_SSID
+	dwMaxNumberOfPeers DWORD
 }
 
 //The EAP_TYPE structure contains type and vendor identification information for an EAP method.
@@ -205,10 +272,15 @@ type EAP_METHOD_TYPE struct {
 
 //The WLAN_RAW_DATA_LIST structure contains raw data in the form of an array of data blobs that are used by some Native Wifi functions.
 //https://docs.microsoft.com/en-us/windows/win32/api/wlanapi/ns-wlanapi-wlan_raw_data_list
-//TODO:
+//The offsets of DataList are relative to the start of the structure; PSDIEList.MarshalBinary builds this layout.
 type WLAN_RAW_DATA_LIST struct {
 	dwTotalSize     DWORD
 	dwNumberOfItems DWORD
+	DataList        [1]WLAN_RAW_DATA_LIST_ENTRY
 }
 
-type WLAN_REASON_CODE uint32
+//WLAN_RAW_DATA_LIST_ENTRY is an entry of WLAN_RAW_DATA_LIST.DataList.
+type WLAN_RAW_DATA_LIST_ENTRY struct {
+	dwDataOffset DWORD
+	dwDataSize   DWORD
+}
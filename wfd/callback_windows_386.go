@@ -0,0 +1,13 @@
+package wfd
+
+import (
+	"unsafe"
+)
+
+//openSessionComplete is the WFD_OPEN_SESSION_COMPLETE_CALLBACK.
+//On 386 the guidSessionInterface argument is pushed on the stack as four words.
+func openSessionComplete(hSessionHandle, pvContext, guid0, guid1, guid2, guid3, dwError, dwReasonCode uintptr) uintptr {
+	guid := [4]uintptr{guid0, guid1, guid2, guid3}
+	completeSession(pvContext, *(*GUID)(unsafe.Pointer(&guid)), dwError, dwReasonCode)
+	return 0
+}
@@ -0,0 +1,8 @@
+package wfd
+
+//openSessionComplete is the WFD_OPEN_SESSION_COMPLETE_CALLBACK.
+//On amd64 the guidSessionInterface argument is passed by reference.
+func openSessionComplete(hSessionHandle, pvContext uintptr, guidSessionInterface *GUID, dwError, dwReasonCode uintptr) uintptr {
+	completeSession(pvContext, *guidSessionInterface, dwError, dwReasonCode)
+	return 0
+}
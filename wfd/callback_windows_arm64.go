@@ -0,0 +1,13 @@
+package wfd
+
+import (
+	"unsafe"
+)
+
+//openSessionComplete is the WFD_OPEN_SESSION_COMPLETE_CALLBACK.
+//On arm64 the guidSessionInterface argument is passed in two registers.
+func openSessionComplete(hSessionHandle, pvContext, guid0, guid1, dwError, dwReasonCode uintptr) uintptr {
+	guid := [2]uintptr{guid0, guid1}
+	completeSession(pvContext, *(*GUID)(unsafe.Pointer(&guid)), dwError, dwReasonCode)
+	return 0
+}
@@ -0,0 +1,177 @@
+package wfd
+
+import (
+	"sync"
+	"syscall"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+var (
+	wlanapi = syscall.NewLazyDLL("wlanapi.dll")
+
+	procWFDOpenHandle             = wlanapi.NewProc("WFDOpenHandle")
+	procWFDCloseHandle            = wlanapi.NewProc("WFDCloseHandle")
+	procWFDStartOpenSession       = wlanapi.NewProc("WFDStartOpenSession")
+	procWFDCancelOpenSession      = wlanapi.NewProc("WFDCancelOpenSession")
+	procWFDCloseSession           = wlanapi.NewProc("WFDCloseSession")
+	procWFDUpdateDeviceVisibility = wlanapi.NewProc("WFDUpdateDeviceVisibility")
+)
+
+//The WFDOpenHandle function opens a handle to the Wi-Fi Direct service and negotiates a version of the Wi-fi Direct API to use.
+//https://docs.microsoft.com/en-us/windows/win32/api/wlanapi/nf-wlanapi-wfdopenhandle
+func WFDOpenHandle(dwClientVersion uint32) (dwNegotiatedVersion uint32, handle windows.Handle, err error) {
+	r1, _, _ := procWFDOpenHandle.Call(
+		uintptr(dwClientVersion),
+		// out
+		uintptr(unsafe.Pointer(&dwNegotiatedVersion)),
+		uintptr(unsafe.Pointer(&handle)),
+	)
+	if r1 != 0 {
+		err = syscall.Errno(r1)
+	}
+	return
+}
+
+//The WFDCloseHandle function closes a handle to the Wi-Fi Direct service.
+//https://docs.microsoft.com/en-us/windows/win32/api/wlanapi/nf-wlanapi-wfdclosehandle
+func WFDCloseHandle(handle windows.Handle) (err error) {
+	r1, _, _ := procWFDCloseHandle.Call(
+		uintptr(handle),
+	)
+	if r1 != 0 {
+		err = syscall.Errno(r1)
+	}
+	return
+}
+
+//The WFDStartOpenSession function starts an on-demand connection to a specific Wi-Fi Direct device, which has been previously paired through the Windows Pairing experience.
+//https://docs.microsoft.com/en-us/windows/win32/api/wlanapi/nf-wlanapi-wfdstartopensession
+//pfnCallback is a function pointer created with windows.NewCallback.
+func WFDStartOpenSession(handle windows.Handle, pDeviceAddress *MACAddress, pvContext uintptr, pfnCallback uintptr) (
+	hSessionHandle windows.Handle, err error) {
+
+	r1, _, _ := procWFDStartOpenSession.Call(
+		uintptr(handle),
+		uintptr(unsafe.Pointer(pDeviceAddress)),
+		pvContext,
+		pfnCallback,
+		// out
+		uintptr(unsafe.Pointer(&hSessionHandle)),
+	)
+	if r1 != 0 {
+		err = syscall.Errno(r1)
+	}
+	return
+}
+
+//The WFDCancelOpenSession function indicates that the application wants to cancel a pending WFDStartOpenSession function that has not completed.
+//https://docs.microsoft.com/en-us/windows/win32/api/wlanapi/nf-wlanapi-wfdcancelopensession
+func WFDCancelOpenSession(hSessionHandle windows.Handle) (err error) {
+	r1, _, _ := procWFDCancelOpenSession.Call(
+		uintptr(hSessionHandle),
+	)
+	if r1 != 0 {
+		err = syscall.Errno(r1)
+	}
+	return
+}
+
+//The WFDCloseSession function closes a session after a previously successful call to the WFDStartOpenSession function.
+//https://docs.microsoft.com/en-us/windows/win32/api/wlanapi/nf-wlanapi-wfdclosesession
+func WFDCloseSession(hSessionHandle windows.Handle) (err error) {
+	r1, _, _ := procWFDCloseSession.Call(
+		uintptr(hSessionHandle),
+	)
+	if r1 != 0 {
+		err = syscall.Errno(r1)
+	}
+	return
+}
+
+//The WFDUpdateDeviceVisibility function updates device visibility for the Wi-Fi Direct device address for a given installed Wi-Fi Direct device node.
+//https://docs.microsoft.com/en-us/windows/win32/api/wlanapi/nf-wlanapi-wfdupdatedevicevisibility
+func WFDUpdateDeviceVisibility(pDeviceAddress *MACAddress) (err error) {
+	r1, _, _ := procWFDUpdateDeviceVisibility.Call(
+		uintptr(unsafe.Pointer(pDeviceAddress)),
+	)
+	if r1 != 0 {
+		err = syscall.Errno(r1)
+	}
+	return
+}
+
+var (
+	openSessionCallbackOnce sync.Once
+	openSessionCallback     uintptr
+
+	completionsMu sync.Mutex
+	completions   = map[uintptr]func(Completion){}
+	completionID  uintptr
+)
+
+//completeSession is called by the arch specific WFD_OPEN_SESSION_COMPLETE_CALLBACK.
+func completeSession(context uintptr, sessionInterface GUID, dwError, dwReasonCode uintptr) {
+	completionsMu.Lock()
+	complete := completions[context]
+	delete(completions, context)
+	completionsMu.Unlock()
+	if complete != nil {
+		complete(Completion{
+			SessionInterface: sessionInterface,
+			Error:            uint32(dwError),
+			ReasonCode:       uint32(dwReasonCode),
+		})
+	}
+}
+
+//native is the Backend of the Wi-Fi Direct service in wlanapi.dll.
+type native struct {
+	handle windows.Handle
+}
+
+//Open opens a Client on the Wi-Fi Direct service.
+func Open() (*Client, error) {
+	_, handle, err := WFDOpenHandle(WFD_API_VERSION_1_0)
+	if err != nil {
+		return nil, err
+	}
+	return NewClient(&native{handle: handle}), nil
+}
+
+func (n *native) StartOpenSession(device MACAddress, complete func(Completion)) (SessionHandle, error) {
+	openSessionCallbackOnce.Do(func() {
+		openSessionCallback = windows.NewCallback(openSessionComplete)
+	})
+	completionsMu.Lock()
+	completionID++
+	context := completionID
+	completions[context] = complete
+	completionsMu.Unlock()
+
+	session, err := WFDStartOpenSession(n.handle, &device, context, openSessionCallback)
+	if err != nil {
+		completionsMu.Lock()
+		delete(completions, context)
+		completionsMu.Unlock()
+		return 0, err
+	}
+	return SessionHandle(session), nil
+}
+
+func (n *native) CancelOpenSession(session SessionHandle) error {
+	return WFDCancelOpenSession(windows.Handle(session))
+}
+
+func (n *native) CloseSession(session SessionHandle) error {
+	return WFDCloseSession(windows.Handle(session))
+}
+
+func (n *native) UpdateDeviceVisibility(device MACAddress) error {
+	return WFDUpdateDeviceVisibility(&device)
+}
+
+func (n *native) Close() error {
+	return WFDCloseHandle(n.handle)
+}
@@ -0,0 +1,153 @@
+package wfd
+
+import (
+	"sync"
+)
+
+//Sim is an in-memory Backend that simulates the Wi-Fi Direct service.
+//Sessions stay pending until they are completed with Accept or Reject, which makes
+//the session state machine deterministic in tests.
+type Sim struct {
+	mu         sync.Mutex
+	paired     map[MACAddress]GUID
+	visibility map[MACAddress]int
+	sessions   map[SessionHandle]*simSession
+	next       SessionHandle
+	closed     bool
+}
+
+type simSession struct {
+	device   MACAddress
+	complete func(Completion)
+	open     bool
+}
+
+//NewSim returns a Sim without paired devices.
+func NewSim() *Sim {
+	return &Sim{
+		paired:     map[MACAddress]GUID{},
+		visibility: map[MACAddress]int{},
+		sessions:   map[SessionHandle]*simSession{},
+	}
+}
+
+//Pair pairs device with the simulated service. Sessions with device report sessionInterface once accepted.
+func (s *Sim) Pair(device MACAddress, sessionInterface GUID) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.paired[device] = sessionInterface
+}
+
+//Accept completes the pending sessions with device successfully and returns how many were completed.
+func (s *Sim) Accept(device MACAddress) int {
+	s.mu.Lock()
+	c := Completion{SessionInterface: s.paired[device]}
+	pending := s.pendingLocked(device, true)
+	s.mu.Unlock()
+	for _, p := range pending {
+		p.complete(c)
+	}
+	return len(pending)
+}
+
+//Reject fails the pending sessions with device and returns how many were completed.
+func (s *Sim) Reject(device MACAddress, code, reasonCode uint32) int {
+	s.mu.Lock()
+	pending := s.pendingLocked(device, false)
+	for h, ss := range s.sessions {
+		if ss.device == device && !ss.open {
+			delete(s.sessions, h)
+		}
+	}
+	s.mu.Unlock()
+	for _, p := range pending {
+		p.complete(Completion{Error: code, ReasonCode: reasonCode})
+	}
+	return len(pending)
+}
+
+//pendingLocked returns the pending sessions with device and marks them open if open is set.
+func (s *Sim) pendingLocked(device MACAddress, open bool) []*simSession {
+	var pending []*simSession
+	for _, ss := range s.sessions {
+		if ss.device == device && !ss.open {
+			ss.open = open
+			pending = append(pending, ss)
+		}
+	}
+	return pending
+}
+
+//OpenSessions returns the number of open sessions with device.
+func (s *Sim) OpenSessions(device MACAddress) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	n := 0
+	for _, ss := range s.sessions {
+		if ss.device == device && ss.open {
+			n++
+		}
+	}
+	return n
+}
+
+//VisibilityUpdates returns how many times the visibility of device was updated.
+func (s *Sim) VisibilityUpdates(device MACAddress) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.visibility[device]
+}
+
+func (s *Sim) StartOpenSession(device MACAddress, complete func(Completion)) (SessionHandle, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return 0, ErrClosed
+	}
+	if _, ok := s.paired[device]; !ok {
+		return 0, &Error{Code: ERROR_NOT_FOUND}
+	}
+	s.next++
+	s.sessions[s.next] = &simSession{device: device, complete: complete}
+	return s.next, nil
+}
+
+func (s *Sim) CancelOpenSession(session SessionHandle) error {
+	s.mu.Lock()
+	ss, ok := s.sessions[session]
+	if !ok || ss.open {
+		s.mu.Unlock()
+		return &Error{Code: ERROR_NOT_FOUND}
+	}
+	delete(s.sessions, session)
+	s.mu.Unlock()
+	ss.complete(Completion{Error: ERROR_CANCELLED})
+	return nil
+}
+
+func (s *Sim) CloseSession(session SessionHandle) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.sessions[session]; !ok {
+		return &Error{Code: ERROR_NOT_FOUND}
+	}
+	delete(s.sessions, session)
+	return nil
+}
+
+func (s *Sim) UpdateDeviceVisibility(device MACAddress) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.paired[device]; !ok {
+		return &Error{Code: ERROR_NOT_FOUND}
+	}
+	s.visibility[device]++
+	return nil
+}
+
+func (s *Sim) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	return nil
+}
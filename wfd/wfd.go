@@ -0,0 +1,288 @@
+//Package wfd binds the Wi-Fi Direct (WFD) functions of wlanapi.dll.
+//It opens sessions with paired Wi-Fi Direct devices and reports their completion on channels.
+//https://docs.microsoft.com/en-us/windows/win32/nativewifi/wi-fi-direct-functions
+package wfd
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+)
+
+//WFD_API_VERSION_1_0 is the client version passed to WFDOpenHandle.
+const WFD_API_VERSION_1_0 = 0x00000001
+
+const (
+	//ERROR_CANCELLED is reported to the open session callback after WFDCancelOpenSession.
+	ERROR_CANCELLED = 1223
+	//ERROR_NOT_FOUND is returned for devices that are not paired.
+	ERROR_NOT_FOUND = 1168
+)
+
+//MACAddress is the 802.11 address of a Wi-Fi Direct device.
+type MACAddress [6]byte
+
+//ParseMAC parses an address in the aa:bb:cc:dd:ee:ff or aa-bb-cc-dd-ee-ff form.
+func ParseMAC(s string) (MACAddress, error) {
+	var mac MACAddress
+	hw, err := net.ParseMAC(s)
+	if err != nil || len(hw) != len(mac) {
+		return mac, fmt.Errorf("wfd: invalid MAC address %q", s)
+	}
+	copy(mac[:], hw)
+	return mac, nil
+}
+
+func (mac MACAddress) String() string {
+	return fmt.Sprintf("%02x:%02x:%02x:%02x:%02x:%02x", mac[0], mac[1], mac[2], mac[3], mac[4], mac[5])
+}
+
+//GUID has the layout of windows.GUID so both convert to each other.
+type GUID struct {
+	Data1 uint32
+	Data2 uint16
+	Data3 uint16
+	Data4 [8]byte
+}
+
+func (g GUID) String() string {
+	return fmt.Sprintf("{%08X-%04X-%04X-%02X%02X-%02X%02X%02X%02X%02X%02X}",
+		g.Data1, g.Data2, g.Data3, g.Data4[0], g.Data4[1],
+		g.Data4[2], g.Data4[3], g.Data4[4], g.Data4[5], g.Data4[6], g.Data4[7])
+}
+
+//SessionHandle identifies a session of a Backend.
+type SessionHandle uintptr
+
+//Completion is what the WFD_OPEN_SESSION_COMPLETE_CALLBACK reports for a session.
+type Completion struct {
+	SessionInterface GUID
+	Error            uint32
+	ReasonCode       uint32
+}
+
+//Backend is the Wi-Fi Direct service behind a Client.
+//The native backend calls wlanapi.dll; Sim is an in-memory backend for tests.
+type Backend interface {
+	//StartOpenSession starts opening a session with device and calls complete exactly once when it finishes.
+	StartOpenSession(device MACAddress, complete func(Completion)) (SessionHandle, error)
+	//CancelOpenSession cancels a pending session; complete is then called with ERROR_CANCELLED.
+	CancelOpenSession(session SessionHandle) error
+	CloseSession(session SessionHandle) error
+	UpdateDeviceVisibility(device MACAddress) error
+	Close() error
+}
+
+//SessionState is the state of a Session.
+type SessionState int
+
+const (
+	SessionPending SessionState = iota
+	SessionOpen
+	SessionFailed
+	SessionCanceled
+	SessionClosed
+)
+
+func (s SessionState) String() string {
+	switch s {
+	case SessionPending:
+		return "pending"
+	case SessionOpen:
+		return "open"
+	case SessionFailed:
+		return "failed"
+	case SessionCanceled:
+		return "canceled"
+	case SessionClosed:
+		return "closed"
+	}
+	return fmt.Sprintf("SessionState(%d)", int(s))
+}
+
+//ErrCanceled is the Result error of a canceled session.
+var ErrCanceled = errors.New("wfd: open session canceled")
+
+//ErrClosed is returned by a Client that has been closed.
+var ErrClosed = errors.New("wfd: client closed")
+
+//ErrSessionState is returned by Cancel on a finished session and by Close on a pending one.
+var ErrSessionState = errors.New("wfd: operation not valid in the session state")
+
+//Error is the Result error of a session that failed to open.
+type Error struct {
+	Code       uint32
+	ReasonCode uint32
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("wfd: open session failed with error %d (reason code %d)", e.Code, e.ReasonCode)
+}
+
+//Result is delivered on Session.Done when the session has finished opening.
+type Result struct {
+	SessionInterface GUID
+	Err              error
+}
+
+//Session is a Wi-Fi Direct session with a paired device.
+type Session struct {
+	Device MACAddress
+
+	client *Client
+	handle SessionHandle
+	done   chan Result
+
+	mu    sync.Mutex
+	state SessionState
+}
+
+//Done returns a channel that receives the Result once the session is open, failed or canceled.
+func (s *Session) Done() <-chan Result {
+	return s.done
+}
+
+//State returns the current state of the session.
+func (s *Session) State() SessionState {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.state
+}
+
+//Cancel cancels a pending session. The Result is delivered with ErrCanceled.
+func (s *Session) Cancel() error {
+	s.mu.Lock()
+	state, handle := s.state, s.handle
+	s.mu.Unlock()
+	if state != SessionPending {
+		return ErrSessionState
+	}
+	return s.client.backend.CancelOpenSession(handle)
+}
+
+//Close closes an open session. Closing a session in any other finished state is a no-op.
+func (s *Session) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	switch s.state {
+	case SessionPending:
+		return ErrSessionState
+	case SessionOpen:
+		if err := s.client.backend.CloseSession(s.handle); err != nil {
+			return err
+		}
+	}
+	s.state = SessionClosed
+	s.client.forget(s)
+	return nil
+}
+
+func (s *Session) complete(c Completion) {
+	r := Result{SessionInterface: c.SessionInterface}
+	s.mu.Lock()
+	if s.state != SessionPending {
+		s.mu.Unlock()
+		return
+	}
+	switch c.Error {
+	case 0:
+		s.state = SessionOpen
+	case ERROR_CANCELLED:
+		s.state = SessionCanceled
+		r.Err = ErrCanceled
+	default:
+		s.state = SessionFailed
+		r.Err = &Error{Code: c.Error, ReasonCode: c.ReasonCode}
+	}
+	s.mu.Unlock()
+	if r.Err != nil {
+		s.client.forget(s)
+	}
+	s.done <- r
+}
+
+//Client is a Wi-Fi Direct client handle.
+type Client struct {
+	backend Backend
+
+	mu       sync.Mutex
+	sessions map[*Session]struct{}
+	closed   bool
+}
+
+//NewClient returns a Client using backend.
+func NewClient(backend Backend) *Client {
+	return &Client{backend: backend, sessions: map[*Session]struct{}{}}
+}
+
+//StartSession starts opening a session with the paired device.
+//The outcome is delivered on the Done channel of the returned Session.
+func (c *Client) StartSession(device MACAddress) (*Session, error) {
+	s := &Session{Device: device, client: c, done: make(chan Result, 1)}
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		return nil, ErrClosed
+	}
+	c.sessions[s] = struct{}{}
+	c.mu.Unlock()
+
+	//The backend may call s.complete before StartOpenSession returns.
+	handle, err := c.backend.StartOpenSession(device, s.complete)
+	if err != nil {
+		c.forget(s)
+		return nil, err
+	}
+	s.mu.Lock()
+	s.handle = handle
+	s.mu.Unlock()
+	return s, nil
+}
+
+//UpdateDeviceVisibility updates the visibility of the paired device in the Devices and Printers folder.
+func (c *Client) UpdateDeviceVisibility(device MACAddress) error {
+	return c.backend.UpdateDeviceVisibility(device)
+}
+
+//Sessions returns the sessions that are pending or open.
+func (c *Client) Sessions() []*Session {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	sessions := make([]*Session, 0, len(c.sessions))
+	for s := range c.sessions {
+		sessions = append(sessions, s)
+	}
+	return sessions
+}
+
+func (c *Client) forget(s *Session) {
+	c.mu.Lock()
+	delete(c.sessions, s)
+	c.mu.Unlock()
+}
+
+//Close cancels pending sessions, closes open sessions and closes the backend.
+func (c *Client) Close() error {
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		return nil
+	}
+	c.closed = true
+	sessions := make([]*Session, 0, len(c.sessions))
+	for s := range c.sessions {
+		sessions = append(sessions, s)
+	}
+	c.mu.Unlock()
+
+	for _, s := range sessions {
+		switch s.State() {
+		case SessionPending:
+			s.Cancel()
+		case SessionOpen:
+			s.Close()
+		}
+	}
+	return c.backend.Close()
+}
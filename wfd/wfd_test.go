@@ -0,0 +1,149 @@
+package wfd
+
+import (
+	"errors"
+	"testing"
+)
+
+var (
+	testDevice    = MACAddress{0x02, 0x11, 0x22, 0x33, 0x44, 0x55}
+	testInterface = GUID{Data1: 0x12345678, Data2: 0x9abc, Data3: 0xdef0, Data4: [8]byte{1, 2, 3, 4, 5, 6, 7, 8}}
+)
+
+func newTestClient() (*Client, *Sim) {
+	sim := NewSim()
+	sim.Pair(testDevice, testInterface)
+	return NewClient(sim), sim
+}
+
+func TestParseMAC(t *testing.T) {
+	for _, s := range []string{"02:11:22:33:44:55", "02-11-22-33-44-55"} {
+		mac, err := ParseMAC(s)
+		if err != nil || mac != testDevice {
+			t.Errorf("ParseMAC(%q) = %v, %v", s, mac, err)
+		}
+	}
+	for _, s := range []string{"", "02:11:22:33:44", "02:11:22:33:44:55:66", "zz:11:22:33:44:55"} {
+		if _, err := ParseMAC(s); err == nil {
+			t.Errorf("ParseMAC(%q) succeeded", s)
+		}
+	}
+}
+
+func TestSessionOpen(t *testing.T) {
+	client, sim := newTestClient()
+	session, err := client.StartSession(testDevice)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if session.State() != SessionPending {
+		t.Fatalf("state = %v, want pending", session.State())
+	}
+	if err := session.Close(); err != ErrSessionState {
+		t.Errorf("Close on pending session = %v", err)
+	}
+
+	if n := sim.Accept(testDevice); n != 1 {
+		t.Fatalf("Accept completed %d sessions", n)
+	}
+	r := <-session.Done()
+	if r.Err != nil || r.SessionInterface != testInterface {
+		t.Fatalf("result = %+v", r)
+	}
+	if session.State() != SessionOpen || sim.OpenSessions(testDevice) != 1 {
+		t.Fatalf("state = %v, open sessions = %d", session.State(), sim.OpenSessions(testDevice))
+	}
+	if err := session.Cancel(); err != ErrSessionState {
+		t.Errorf("Cancel on open session = %v", err)
+	}
+
+	if err := session.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if session.State() != SessionClosed || sim.OpenSessions(testDevice) != 0 || len(client.Sessions()) != 0 {
+		t.Fatalf("state = %v after Close", session.State())
+	}
+}
+
+func TestSessionCancel(t *testing.T) {
+	client, sim := newTestClient()
+	session, err := client.StartSession(testDevice)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := session.Cancel(); err != nil {
+		t.Fatal(err)
+	}
+	r := <-session.Done()
+	if r.Err != ErrCanceled || session.State() != SessionCanceled {
+		t.Fatalf("result = %+v, state = %v", r, session.State())
+	}
+	if n := sim.Accept(testDevice); n != 0 {
+		t.Errorf("Accept completed %d canceled sessions", n)
+	}
+	if len(client.Sessions()) != 0 {
+		t.Errorf("canceled session is still tracked")
+	}
+}
+
+func TestSessionRejected(t *testing.T) {
+	client, sim := newTestClient()
+	session, err := client.StartSession(testDevice)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sim.Reject(testDevice, 1460, 7)
+	r := <-session.Done()
+	var e *Error
+	if !errors.As(r.Err, &e) || e.Code != 1460 || e.ReasonCode != 7 {
+		t.Fatalf("result = %+v", r)
+	}
+	if session.State() != SessionFailed {
+		t.Fatalf("state = %v, want failed", session.State())
+	}
+}
+
+func TestStartSessionUnpaired(t *testing.T) {
+	client, _ := newTestClient()
+	_, err := client.StartSession(MACAddress{0x02})
+	var e *Error
+	if !errors.As(err, &e) || e.Code != ERROR_NOT_FOUND {
+		t.Fatalf("StartSession = %v", err)
+	}
+}
+
+func TestUpdateDeviceVisibility(t *testing.T) {
+	client, sim := newTestClient()
+	if err := client.UpdateDeviceVisibility(testDevice); err != nil {
+		t.Fatal(err)
+	}
+	if n := sim.VisibilityUpdates(testDevice); n != 1 {
+		t.Fatalf("VisibilityUpdates = %d", n)
+	}
+	if err := client.UpdateDeviceVisibility(MACAddress{0x02}); err == nil {
+		t.Fatal("UpdateDeviceVisibility of unpaired device succeeded")
+	}
+}
+
+func TestClientClose(t *testing.T) {
+	client, sim := newTestClient()
+	pending, _ := client.StartSession(testDevice)
+	open, _ := client.StartSession(testDevice)
+	sim.Accept(testDevice)
+	<-pending.Done()
+	<-open.Done()
+
+	third, _ := client.StartSession(testDevice)
+	if err := client.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if r := <-third.Done(); r.Err != ErrCanceled {
+		t.Errorf("pending session result = %+v", r)
+	}
+	if sim.OpenSessions(testDevice) != 0 {
+		t.Errorf("open sessions left after Close")
+	}
+	if _, err := client.StartSession(testDevice); err != ErrClosed {
+		t.Errorf("StartSession after Close = %v", err)
+	}
+}
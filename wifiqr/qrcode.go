@@ -0,0 +1,496 @@
+package wifiqr
+
+import (
+	"errors"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+)
+
+//Level is the error correction level of a QR code.
+type Level int
+
+const (
+	//Low recovers 7% of the codewords.
+	Low Level = iota
+	//Medium recovers 15% of the codewords.
+	Medium
+	//Quartile recovers 25% of the codewords.
+	Quartile
+	//High recovers 30% of the codewords.
+	High
+)
+
+//eccCodewordsPerBlock and eccBlocks are the error correction codewords of each block and the number of blocks,
+//by level and version, from table 9 of ISO/IEC 18004.
+var eccCodewordsPerBlock = [4][41]int{
+	{0, 7, 10, 15, 20, 26, 18, 20, 24, 30, 18, 20, 24, 26, 30, 22, 24, 28, 30, 28, 28, 28, 28, 30, 30, 26, 28, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30},
+	{0, 10, 16, 26, 18, 24, 16, 18, 22, 22, 26, 30, 22, 22, 24, 24, 28, 28, 26, 26, 26, 26, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28},
+	{0, 13, 22, 18, 26, 18, 24, 18, 22, 20, 24, 28, 26, 24, 20, 30, 24, 28, 28, 26, 30, 28, 30, 30, 30, 30, 28, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30},
+	{0, 17, 28, 22, 16, 22, 28, 26, 26, 24, 28, 24, 28, 22, 24, 24, 30, 28, 28, 26, 28, 30, 24, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30},
+}
+
+var eccBlocks = [4][41]int{
+	{0, 1, 1, 1, 1, 1, 2, 2, 2, 2, 4, 4, 4, 4, 4, 6, 6, 6, 6, 7, 8, 8, 9, 9, 10, 12, 12, 12, 13, 14, 15, 16, 17, 18, 19, 19, 20, 21, 22, 24, 25},
+	{0, 1, 1, 1, 2, 2, 4, 4, 4, 5, 5, 5, 8, 9, 9, 10, 10, 11, 13, 14, 16, 17, 17, 18, 20, 21, 23, 25, 26, 28, 29, 31, 33, 35, 37, 38, 40, 43, 45, 47, 49},
+	{0, 1, 1, 2, 2, 4, 4, 6, 6, 8, 8, 8, 10, 12, 16, 12, 17, 16, 18, 21, 20, 23, 23, 25, 27, 29, 34, 34, 35, 38, 40, 43, 45, 48, 51, 53, 56, 59, 62, 65, 68},
+	{0, 1, 1, 2, 4, 4, 4, 5, 6, 8, 8, 11, 11, 16, 16, 18, 16, 19, 21, 25, 25, 25, 34, 30, 32, 35, 37, 40, 42, 45, 48, 51, 54, 57, 60, 63, 66, 70, 74, 77, 81},
+}
+
+//formatLevels are the error correction bits of the format information of each level.
+var formatLevels = [4]int{Low: 1, Medium: 0, Quartile: 3, High: 2}
+
+//rawModules returns the number of modules of a version that hold codewords, with the remainder bits.
+func rawModules(version int) int {
+	n := (16*version+128)*version + 64
+	if version >= 2 {
+		alignments := version/7 + 2
+		n -= (25*alignments-10)*alignments - 55
+		if version >= 7 {
+			n -= 36
+		}
+	}
+	return n
+}
+
+//dataCodewords returns the number of data codewords of a version at a level.
+func dataCodewords(version int, level Level) int {
+	return rawModules(version)/8 - eccCodewordsPerBlock[level][version]*eccBlocks[level][version]
+}
+
+//QRCode is the matrix of modules of a QR code.
+type QRCode struct {
+	Version int
+	Level   Level
+	//Size is the number of modules of a side, 17 + 4 × Version.
+	Size     int
+	modules  []bool
+	function []bool
+}
+
+//Dark reports whether the module at column x and row y is dark.
+func (q *QRCode) Dark(x, y int) bool {
+	return q.modules[y*q.Size+x]
+}
+
+func (q *QRCode) set(x, y int, dark bool) {
+	q.modules[y*q.Size+x] = dark
+	q.function[y*q.Size+x] = true
+}
+
+//Encode encodes data in byte mode, in the smallest version that holds it at the level.
+func Encode(data []byte, level Level) (*QRCode, error) {
+	if level < Low || level > High {
+		return nil, errors.New("wifiqr: unknown error correction level")
+	}
+	version := 1
+	for ; version <= 40; version++ {
+		countBits := 8
+		if version >= 10 {
+			countBits = 16
+		}
+		if 4+countBits+8*len(data) <= 8*dataCodewords(version, level) {
+			break
+		}
+	}
+	if version > 40 {
+		return nil, errors.New("wifiqr: data too long for a QR code")
+	}
+
+	//The byte mode indicator, the character count, the data, a terminator of up to 4 bits and pad codewords.
+	var w bitWriter
+	w.write(4, 4)
+	if version >= 10 {
+		w.write(len(data), 16)
+	} else {
+		w.write(len(data), 8)
+	}
+	for _, b := range data {
+		w.write(int(b), 8)
+	}
+	capacity := 8 * dataCodewords(version, level)
+	if n := capacity - w.n; n < 4 {
+		w.write(0, n)
+	} else {
+		w.write(0, 4)
+	}
+	if w.n%8 != 0 {
+		w.write(0, 8-w.n%8)
+	}
+	for pad := 0xec; w.n < capacity; pad ^= 0xec ^ 0x11 {
+		w.write(pad, 8)
+	}
+
+	q := &QRCode{Version: version, Level: level, Size: 17 + 4*version}
+	q.modules = make([]bool, q.Size*q.Size)
+	q.function = make([]bool, q.Size*q.Size)
+	q.drawFunctionPatterns()
+	q.drawCodewords(interleave(w.bytes, version, level))
+
+	best, penalty := 0, -1
+	for mask := 0; mask < 8; mask++ {
+		q.applyMask(mask)
+		q.drawFormat(mask)
+		if p := q.penalty(); penalty < 0 || p < penalty {
+			best, penalty = mask, p
+		}
+		q.applyMask(mask)
+	}
+	q.applyMask(best)
+	q.drawFormat(best)
+	return q, nil
+}
+
+//bitWriter appends bits to bytes, most significant first.
+type bitWriter struct {
+	bytes []byte
+	n     int
+}
+
+func (w *bitWriter) write(v, bits int) {
+	for i := bits - 1; i >= 0; i-- {
+		if w.n%8 == 0 {
+			w.bytes = append(w.bytes, 0)
+		}
+		if v>>uint(i)&1 != 0 {
+			w.bytes[w.n/8] |= 0x80 >> uint(w.n%8)
+		}
+		w.n++
+	}
+}
+
+//interleave splits the data codewords into blocks, adds the error correction codewords of each block, and
+//interleaves the codewords of the blocks.
+func interleave(data []byte, version int, level Level) []byte {
+	blocks, eccLen := eccBlocks[level][version], eccCodewordsPerBlock[level][version]
+	raw := rawModules(version) / 8
+	short := blocks - raw%blocks
+	shortLen := raw / blocks
+	generator := rsGenerator(eccLen)
+
+	var split [][]byte
+	for i, k := 0, 0; i < blocks; i++ {
+		n := shortLen - eccLen
+		if i >= short {
+			n++
+		}
+		block := make([]byte, 0, shortLen+1)
+		block = append(block, data[k:k+n]...)
+		k += n
+		ecc := rsRemainder(block, generator)
+		if i < short {
+			//Short blocks skip a data codeword so the codewords of all blocks line up.
+			block = append(block, 0)
+		}
+		split = append(split, append(block, ecc...))
+	}
+
+	out := make([]byte, 0, raw)
+	for i := 0; i <= shortLen; i++ {
+		for j, block := range split {
+			if i != shortLen-eccLen || j >= short {
+				out = append(out, block[i])
+			}
+		}
+	}
+	return out
+}
+
+//gfMultiply multiplies in GF(256) modulo x^8 + x^4 + x^3 + x^2 + 1.
+func gfMultiply(x, y byte) byte {
+	var z int
+	for i := 7; i >= 0; i-- {
+		z = z<<1 ^ (z>>7)*0x11d
+		z ^= int(y>>uint(i)&1) * int(x)
+	}
+	return byte(z)
+}
+
+//rsGenerator returns the coefficients of the Reed-Solomon generator polynomial of a degree, highest first and
+//without the leading 1.
+func rsGenerator(degree int) []byte {
+	g := make([]byte, degree)
+	g[degree-1] = 1
+	root := byte(1)
+	for i := 0; i < degree; i++ {
+		for j := range g {
+			g[j] = gfMultiply(g[j], root)
+			if j+1 < len(g) {
+				g[j] ^= g[j+1]
+			}
+		}
+		root = gfMultiply(root, 2)
+	}
+	return g
+}
+
+//rsRemainder returns the error correction codewords of data.
+func rsRemainder(data, generator []byte) []byte {
+	r := make([]byte, len(generator))
+	for _, b := range data {
+		factor := b ^ r[0]
+		copy(r, r[1:])
+		r[len(r)-1] = 0
+		for i, g := range generator {
+			r[i] ^= gfMultiply(g, factor)
+		}
+	}
+	return r
+}
+
+//alignmentPositions returns the centers of the alignment patterns of a version, on either axis.
+func alignmentPositions(version int) []int {
+	if version == 1 {
+		return nil
+	}
+	n := version/7 + 2
+	step := (version*8 + n*3 + 5) / (n*4 - 4) * 2
+	p := make([]int, n)
+	p[0] = 6
+	for i, pos := n-1, 17+4*version-7; i >= 1; i, pos = i-1, pos-step {
+		p[i] = pos
+	}
+	return p
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+//drawFunctionPatterns draws the timing, finder and alignment patterns, the version information and reserves the
+//modules of the format information.
+func (q *QRCode) drawFunctionPatterns() {
+	for i := 0; i < q.Size; i++ {
+		q.set(6, i, i%2 == 0)
+		q.set(i, 6, i%2 == 0)
+	}
+	//The finder patterns, with their separators.
+	for _, c := range [][2]int{{3, 3}, {q.Size - 4, 3}, {3, q.Size - 4}} {
+		for dy := -4; dy <= 4; dy++ {
+			for dx := -4; dx <= 4; dx++ {
+				x, y := c[0]+dx, c[1]+dy
+				if x >= 0 && x < q.Size && y >= 0 && y < q.Size {
+					d := max(abs(dx), abs(dy))
+					q.set(x, y, d != 2 && d != 4)
+				}
+			}
+		}
+	}
+	p := alignmentPositions(q.Version)
+	for i := range p {
+		for j := range p {
+			if i == 0 && j == 0 || i == 0 && j == len(p)-1 || i == len(p)-1 && j == 0 {
+				continue
+			}
+			for dy := -2; dy <= 2; dy++ {
+				for dx := -2; dx <= 2; dx++ {
+					q.set(p[i]+dx, p[j]+dy, max(abs(dx), abs(dy)) != 1)
+				}
+			}
+		}
+	}
+	q.drawFormat(0)
+	if q.Version >= 7 {
+		r := q.Version
+		for i := 0; i < 12; i++ {
+			r = r<<1 ^ (r>>11)*0x1f25
+		}
+		bits := q.Version<<12 | r
+		for i := 0; i < 18; i++ {
+			dark := bits>>uint(i)&1 != 0
+			a, b := q.Size-11+i%3, i/3
+			q.set(a, b, dark)
+			q.set(b, a, dark)
+		}
+	}
+}
+
+//drawFormat draws both copies of the format information of the level and a mask, and the dark module.
+func (q *QRCode) drawFormat(mask int) {
+	data := formatLevels[q.Level]<<3 | mask
+	r := data
+	for i := 0; i < 10; i++ {
+		r = r<<1 ^ (r>>9)*0x537
+	}
+	bits := (data<<10 | r) ^ 0x5412
+	bit := func(i int) bool { return bits>>uint(i)&1 != 0 }
+
+	for i := 0; i <= 5; i++ {
+		q.set(8, i, bit(i))
+	}
+	q.set(8, 7, bit(6))
+	q.set(8, 8, bit(7))
+	q.set(7, 8, bit(8))
+	for i := 9; i < 15; i++ {
+		q.set(14-i, 8, bit(i))
+	}
+	for i := 0; i < 8; i++ {
+		q.set(q.Size-1-i, 8, bit(i))
+	}
+	for i := 8; i < 15; i++ {
+		q.set(8, q.Size-15+i, bit(i))
+	}
+	q.set(8, q.Size-8, true)
+}
+
+//drawCodewords places the codewords in the zigzag of two module wide columns, from the bottom right corner.
+func (q *QRCode) drawCodewords(codewords []byte) {
+	i := 0
+	for right := q.Size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5
+		}
+		upward := (right+1)&2 == 0
+		for vert := 0; vert < q.Size; vert++ {
+			y := vert
+			if upward {
+				y = q.Size - 1 - vert
+			}
+			for j := 0; j < 2; j++ {
+				x := right - j
+				if !q.function[y*q.Size+x] && i < 8*len(codewords) {
+					q.modules[y*q.Size+x] = codewords[i/8]>>uint(7-i%8)&1 != 0
+					i++
+				}
+			}
+		}
+	}
+}
+
+//applyMask flips the modules that are not function patterns where a mask pattern holds; applying it twice undoes it.
+func (q *QRCode) applyMask(mask int) {
+	for y := 0; y < q.Size; y++ {
+		for x := 0; x < q.Size; x++ {
+			var flip bool
+			switch mask {
+			case 0:
+				flip = (x+y)%2 == 0
+			case 1:
+				flip = y%2 == 0
+			case 2:
+				flip = x%3 == 0
+			case 3:
+				flip = (x+y)%3 == 0
+			case 4:
+				flip = (x/3+y/2)%2 == 0
+			case 5:
+				flip = x*y%2+x*y%3 == 0
+			case 6:
+				flip = (x*y%2+x*y%3)%2 == 0
+			case 7:
+				flip = ((x+y)%2+x*y%3)%2 == 0
+			}
+			if flip && !q.function[y*q.Size+x] {
+				q.modules[y*q.Size+x] = !q.modules[y*q.Size+x]
+			}
+		}
+	}
+}
+
+//finderLike are the patterns of penalty rule 3, a 1:1:3:1:1 finder pattern with four light modules on one side.
+var finderLike = [2][11]bool{
+	{true, false, true, true, true, false, true, false, false, false, false},
+	{false, false, false, false, true, false, true, true, true, false, true},
+}
+
+//penalty scores the masked code by the four rules of ISO/IEC 18004; the mask with the lowest score is used.
+func (q *QRCode) penalty() int {
+	score, dark := 0, 0
+	line := make([]bool, q.Size)
+	for _, column := range []bool{false, true} {
+		for a := 0; a < q.Size; a++ {
+			for b := range line {
+				if column {
+					line[b] = q.Dark(a, b)
+				} else {
+					line[b] = q.Dark(b, a)
+				}
+			}
+			//Rule 1: runs of five or more modules of a color.
+			run := 1
+			for b := 1; b <= q.Size; b++ {
+				if b < q.Size && line[b] == line[b-1] {
+					run++
+					continue
+				}
+				if run >= 5 {
+					score += run - 2
+				}
+				run = 1
+			}
+			//Rule 3: finder-like patterns.
+			for b := 0; b+11 <= q.Size; b++ {
+				for _, pattern := range finderLike {
+					match := true
+					for k, d := range pattern {
+						if line[b+k] != d {
+							match = false
+							break
+						}
+					}
+					if match {
+						score += 40
+					}
+				}
+			}
+		}
+	}
+	for y := 0; y < q.Size; y++ {
+		for x := 0; x < q.Size; x++ {
+			d := q.Dark(x, y)
+			if d {
+				dark++
+			}
+			//Rule 2: blocks of 2×2 modules of a color.
+			if x+1 < q.Size && y+1 < q.Size && d == q.Dark(x+1, y) && d == q.Dark(x, y+1) && d == q.Dark(x+1, y+1) {
+				score += 3
+			}
+		}
+	}
+	//Rule 4: 10 for every 5% that the proportion of dark modules is off 50%.
+	total := q.Size * q.Size
+	score += abs(dark*20-total*10) / total * 10
+	return score
+}
+
+//Image returns the code with scale pixels per module and the quiet zone of four modules around it.
+func (q *QRCode) Image(scale int) image.Image {
+	if scale < 1 {
+		scale = 1
+	}
+	const quiet = 4
+	side := (q.Size + 2*quiet) * scale
+	img := image.NewGray(image.Rect(0, 0, side, side))
+	for y := 0; y < side; y++ {
+		for x := 0; x < side; x++ {
+			mx, my := x/scale-quiet, y/scale-quiet
+			if mx >= 0 && mx < q.Size && my >= 0 && my < q.Size && q.Dark(mx, my) {
+				img.SetGray(x, y, color.Gray{})
+			} else {
+				img.SetGray(x, y, color.Gray{Y: 0xff})
+			}
+		}
+	}
+	return img
+}
+
+//WritePNG writes the image of the code as a PNG.
+func (q *QRCode) WritePNG(w io.Writer, scale int) error {
+	return png.Encode(w, q.Image(scale))
+}
+
+//QRCode returns the QR code of the URI of the network.
+func (n Network) QRCode(level Level) (*QRCode, error) {
+	return Encode([]byte(n.String()), level)
+}
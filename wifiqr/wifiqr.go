@@ -0,0 +1,248 @@
+//Package wifiqr parses and prints the WIFI: URIs of Wi-Fi QR codes, converts them to and from profiles, and
+//renders them as QR codes.
+//
+//A URI is a list of fields ended by an empty field, such as WIFI:T:WPA;S:home;P:correct horse;H:true;;
+//The fields are those of the WPA3 specification, which adds the transition disable (R), password identifier (I)
+//and public key (K) fields to the original T, S, P and H fields.
+package wifiqr
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"wlanapi/profile"
+)
+
+//The values of the T field.
+const (
+	TypeNone = "nopass"
+	TypeWEP  = "WEP"
+	TypeWPA  = "WPA"
+	//TypeSAE is printed by some devices for WPA3-Personal networks, which the WPA3 specification writes as WPA with R:1.
+	TypeSAE = "SAE"
+)
+
+//TransitionDisableWPA3Personal is the bit of the R field that disables the WPA2-Personal transition mode, so the
+//network is connected to with SAE only.
+const TransitionDisableWPA3Personal = 0x01
+
+//Network is the network of a WIFI: URI.
+type Network struct {
+	//Type is the T field, a Type value; empty is an open network.
+	Type     string
+	SSID     string
+	Password string
+	Hidden   bool
+	//TransitionDisable is the R field, a bitmap of the transition modes the network has disabled.
+	TransitionDisable uint8
+	//PasswordID is the I field, the SAE password identifier.
+	PasswordID string
+	//PublicKey is the K field, the DER SubjectPublicKeyInfo of the DPP public key of the network.
+	PublicKey []byte
+}
+
+//escape escapes the special characters of a field value with a backslash.
+func escape(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch r {
+		case '\\', ';', ',', ':', '"':
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+//String returns the WIFI: URI of the network.
+func (n Network) String() string {
+	var b strings.Builder
+	b.WriteString("WIFI:")
+	field := func(name, value string) {
+		b.WriteString(name + ":" + escape(value) + ";")
+	}
+	if n.Type != "" {
+		field("T", n.Type)
+	}
+	if n.TransitionDisable != 0 {
+		field("R", fmt.Sprintf("%X", n.TransitionDisable))
+	}
+	field("S", n.SSID)
+	if n.Password != "" {
+		field("P", n.Password)
+	}
+	if n.PasswordID != "" {
+		field("I", n.PasswordID)
+	}
+	if len(n.PublicKey) > 0 {
+		field("K", base64.StdEncoding.EncodeToString(n.PublicKey))
+	}
+	if n.Hidden {
+		field("H", "true")
+	}
+	b.WriteByte(';')
+	return b.String()
+}
+
+//fields splits the fields of a URI after the WIFI: scheme, up to the empty field that ends it. The fields are
+//still escaped.
+func fields(s string) ([]string, error) {
+	var list []string
+	start := 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			if i++; i == len(s) {
+				return nil, errors.New("wifiqr: URI ends in an escape")
+			}
+		case ';':
+			if i == start {
+				if rest := s[i+1:]; strings.TrimSpace(rest) != "" {
+					return nil, fmt.Errorf("wifiqr: text %q after the end of the URI", rest)
+				}
+				return list, nil
+			}
+			list = append(list, s[start:i])
+			start = i + 1
+		}
+	}
+	//Some encoders omit the empty field that ends the URI.
+	if start < len(s) {
+		list = append(list, s[start:])
+	}
+	return list, nil
+}
+
+//unescape removes the backslashes of an escaped value. Double quotes around the value, which some encoders add
+//to an SSID or password that would read as hexadecimal, are removed unless they are escaped.
+func unescape(s string) string {
+	if len(s) >= 2 && s[0] == '"' && s[len(s)-1] == '"' {
+		backslashes := len(s) - 1 - len(strings.TrimRight(s[:len(s)-1], `\\`))
+		if backslashes%2 == 0 {
+			s = s[1 : len(s)-1]
+		}
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) {
+			i++
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
+
+//Parse parses a WIFI: URI. Unknown fields are skipped, as the specification requires.
+func Parse(uri string) (Network, error) {
+	var n Network
+	if len(uri) < 5 || !strings.EqualFold(uri[:5], "WIFI:") {
+		return n, fmt.Errorf("wifiqr: %q is not a WIFI: URI", uri)
+	}
+	list, err := fields(uri[5:])
+	if err != nil {
+		return n, err
+	}
+	ssid := false
+	for _, f := range list {
+		i := strings.IndexByte(f, ':')
+		if i < 0 {
+			return n, fmt.Errorf("wifiqr: field %q has no name", f)
+		}
+		value := unescape(f[i+1:])
+		switch f[:i] {
+		case "T":
+			n.Type = value
+		case "S":
+			n.SSID, ssid = value, true
+		case "P":
+			n.Password = value
+		case "H":
+			n.Hidden = strings.EqualFold(value, "true")
+		case "R":
+			r, err := strconv.ParseUint(value, 16, 8)
+			if err != nil {
+				return n, fmt.Errorf("wifiqr: invalid transition disable %q", value)
+			}
+			n.TransitionDisable = uint8(r)
+		case "I":
+			n.PasswordID = value
+		case "K":
+			if n.PublicKey, err = base64.StdEncoding.DecodeString(value); err != nil {
+				return n, fmt.Errorf("wifiqr: invalid public key: %v", err)
+			}
+		}
+	}
+	if !ssid {
+		return n, errors.New("wifiqr: URI has no SSID")
+	}
+	return n, nil
+}
+
+//FromBuilder returns the network of a profile builder. Only open, WEP and personal networks have a URI.
+func FromBuilder(b profile.Builder) (Network, error) {
+	n := Network{SSID: b.SSID, Password: b.Key, Hidden: b.Hidden}
+	switch b.Security {
+	case profile.Open:
+		n.Type = TypeNone
+	case profile.WEP:
+		n.Type = TypeWEP
+	case profile.WPAPersonal, profile.WPA2Personal, profile.WPA3Transition:
+		n.Type = TypeWPA
+	case profile.WPA3Personal:
+		n.Type, n.TransitionDisable = TypeWPA, TransitionDisableWPA3Personal
+	default:
+		return n, fmt.Errorf("wifiqr: %v networks have no WIFI: URI", b.Security)
+	}
+	return n, nil
+}
+
+//FromProfile returns the network of a profile, which must have a plain text key.
+func FromProfile(p *profile.WLANProfile) (Network, error) {
+	b, err := p.Builder()
+	if err != nil {
+		return Network{}, err
+	}
+	return FromBuilder(b)
+}
+
+//Builder returns the profile builder of the network.
+//
+//A WPA network is WPA2-Personal, unless it has disabled the transition mode and is WPA3-Personal, or has an SAE
+//password identifier or a public key and is WPA3-Personal in transition mode. Profiles have no password
+//identifier or public key, so they are dropped.
+func (n Network) Builder() (profile.Builder, error) {
+	b := profile.Builder{SSID: n.SSID, Hidden: n.Hidden, Key: n.Password}
+	switch {
+	case n.Type == "" || strings.EqualFold(n.Type, TypeNone):
+		b.Security = profile.Open
+		b.Key = ""
+	case strings.EqualFold(n.Type, TypeWEP):
+		b.Security = profile.WEP
+	case strings.EqualFold(n.Type, TypeSAE), strings.EqualFold(n.Type, "WPA3"):
+		b.Security = profile.WPA3Personal
+	case strings.EqualFold(n.Type, TypeWPA), strings.EqualFold(n.Type, "WPA2"):
+		switch {
+		case n.TransitionDisable&TransitionDisableWPA3Personal != 0:
+			b.Security = profile.WPA3Personal
+		case n.PasswordID != "" || len(n.PublicKey) > 0:
+			b.Security = profile.WPA3Transition
+		default:
+			b.Security = profile.WPA2Personal
+		}
+	default:
+		return b, fmt.Errorf("wifiqr: unknown network type %q", n.Type)
+	}
+	return b, nil
+}
+
+//Profile returns the profile of the network.
+func (n Network) Profile() (*profile.WLANProfile, error) {
+	b, err := n.Builder()
+	if err != nil {
+		return nil, err
+	}
+	return b.Build()
+}
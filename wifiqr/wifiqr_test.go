@@ -0,0 +1,185 @@
+package wifiqr
+
+import (
+	"bytes"
+	"image/png"
+	"os"
+	"strings"
+	"testing"
+
+	"wlanapi/profile"
+)
+
+func TestParse(t *testing.T) {
+	for _, c := range []struct {
+		uri  string
+		want Network
+	}{
+		{`WIFI:T:WPA;S:home;P:correct horse;H:true;;`, Network{Type: TypeWPA, SSID: "home", Password: "correct horse", Hidden: true}},
+		{`WIFI:S:cafe;;`, Network{SSID: "cafe"}},
+		{`wifi:T:nopass;S:cafe`, Network{Type: TypeNone, SSID: "cafe"}},
+		{`WIFI:T:WPA;S:a\;b\:c\\d\,e\";P:"123456789";;`, Network{Type: TypeWPA, SSID: `a;b:c\d,e"`, Password: "123456789"}},
+		{`WIFI:T:WPA;S:\"quoted\";;`, Network{Type: TypeWPA, SSID: `"quoted"`}},
+		{`WIFI:T:WPA;R:1;S:home;P:correct horse;I:alice;K:MDkwEwYHKoZIzj0CAQ==;X:future;;`, Network{
+			Type: TypeWPA, TransitionDisable: 1, SSID: "home", Password: "correct horse", PasswordID: "alice",
+			PublicKey: []byte{0x30, 0x39, 0x30, 0x13, 0x06, 0x07, 0x2a, 0x86, 0x48, 0xce, 0x3d, 0x02, 0x01},
+		}},
+	} {
+		n, err := Parse(c.uri)
+		if err != nil || n.String() != c.want.String() || !bytes.Equal(n.PublicKey, c.want.PublicKey) {
+			t.Errorf("Parse(%q) = %+v %v, want %+v", c.uri, n, err, c.want)
+		}
+	}
+	for _, uri := range []string{
+		`MECARD:N:home;;`,
+		`WIFI:T:WPA;P:correct horse;;`,
+		`WIFI:T:WPA;S:home;P:x\`,
+		`WIFI:T:WPA;R:xyz;S:home;;`,
+		`WIFI:T:WPA;S:home;K:!!;;`,
+		`WIFI:T:WPA;home;;`,
+		`WIFI:S:home;;S:other;;`,
+	} {
+		if n, err := Parse(uri); err == nil {
+			t.Errorf("parsed %q as %+v", uri, n)
+		}
+	}
+}
+
+func TestString(t *testing.T) {
+	n := Network{Type: TypeWPA, SSID: `a;b:c\d,e"`, Password: "pass;word", Hidden: true, TransitionDisable: 1, PasswordID: "id"}
+	const want = `WIFI:T:WPA;R:1;S:a\;b\:c\\d\,e\";P:pass\;word;I:id;H:true;;`
+	if s := n.String(); s != want {
+		t.Errorf("String() = %s, want %s", s, want)
+	}
+	if back, err := Parse(want); err != nil || back.String() != want {
+		t.Errorf("Parse(String()) = %+v %v", back, err)
+	}
+}
+
+func TestProfiles(t *testing.T) {
+	for _, c := range []struct {
+		b   profile.Builder
+		uri string
+	}{
+		{profile.Builder{SSID: "cafe", Security: profile.Open}, `WIFI:T:nopass;S:cafe;;`},
+		{profile.Builder{SSID: "lab", Security: profile.WEP, Key: "0123456789"}, `WIFI:T:WEP;S:lab;P:0123456789;;`},
+		{profile.Builder{SSID: "home", Security: profile.WPA2Personal, Key: "correct horse", Hidden: true}, `WIFI:T:WPA;S:home;P:correct horse;H:true;;`},
+		{profile.Builder{SSID: "home", Security: profile.WPA3Personal, Key: "correct horse"}, `WIFI:T:WPA;R:1;S:home;P:correct horse;;`},
+	} {
+		p, err := c.b.Build()
+		if err != nil {
+			t.Fatal(err)
+		}
+		n, err := FromProfile(p)
+		if err != nil || n.String() != c.uri {
+			t.Errorf("FromProfile(%v) = %s %v, want %s", c.b.Security, n, err, c.uri)
+		}
+		n, _ = Parse(c.uri)
+		b, err := n.Builder()
+		if back, _ := n.Profile(); err != nil || b != c.b || back == nil || back.Name != c.b.SSID {
+			t.Errorf("Builder(%s) = %+v %v, want %+v", c.uri, b, err, c.b)
+		}
+	}
+
+	//WPA3-Personal in transition mode prints as a WPA network, which reads back as WPA2-Personal.
+	if n, err := FromBuilder(profile.Builder{SSID: "home", Security: profile.WPA3Transition, Key: "correct horse"}); err != nil ||
+		n.String() != `WIFI:T:WPA;S:home;P:correct horse;;` {
+		t.Errorf("transition mode %s %v", n, err)
+	}
+	for uri, want := range map[string]profile.SecurityType{
+		`WIFI:T:WPA;S:home;P:correct horse;;`:         profile.WPA2Personal,
+		`WIFI:T:WPA;S:home;P:correct horse;I:alice;;`: profile.WPA3Transition,
+		`WIFI:T:SAE;S:home;P:correct horse;;`:         profile.WPA3Personal,
+		`WIFI:T:nopass;S:home;P:ignored;;`:            profile.Open,
+	} {
+		n, _ := Parse(uri)
+		if b, err := n.Builder(); err != nil || b.Security != want {
+			t.Errorf("Builder(%s) = %v %v, want %v", uri, b.Security, err, want)
+		}
+	}
+
+	if _, err := FromBuilder(profile.Builder{SSID: "corp", Security: profile.WPA2Enterprise}); err == nil {
+		t.Error("URI of an enterprise network")
+	}
+	if _, err := (Network{Type: "WPA4", SSID: "home"}).Builder(); err == nil {
+		t.Error("builder of an unknown type")
+	}
+	if _, err := (Network{Type: TypeWPA, SSID: "home", Password: "short"}).Profile(); err == nil {
+		t.Error("profile with a short passphrase")
+	}
+}
+
+//matrix prints the modules of a code, one row a line.
+func matrix(q *QRCode) string {
+	var b strings.Builder
+	for y := 0; y < q.Size; y++ {
+		for x := 0; x < q.Size; x++ {
+			if q.Dark(x, y) {
+				b.WriteByte('#')
+			} else {
+				b.WriteByte('.')
+			}
+		}
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+func TestQRCode(t *testing.T) {
+	n := Network{Type: TypeWPA, SSID: "guest", Password: "welcome to the lobby"}
+	q, err := n.QRCode(Medium)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if q.Version != 4 || q.Size != 33 {
+		t.Errorf("version %d size %d", q.Version, q.Size)
+	}
+	got := matrix(q)
+	want, err := os.ReadFile("testdata/guest.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != string(want) {
+		t.Errorf("matrix\n%s\nwant\n%s", got, want)
+	}
+
+	var b bytes.Buffer
+	if err := q.WritePNG(&b, 4); err != nil {
+		t.Fatal(err)
+	}
+	img, err := png.Decode(&b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if side := (33 + 8) * 4; img.Bounds().Dx() != side || img.Bounds().Dy() != side {
+		t.Errorf("image bounds %v", img.Bounds())
+	}
+	//The top left module of the finder pattern, after the quiet zone, is dark.
+	if r, _, _, _ := img.At(16, 16).RGBA(); r != 0 {
+		t.Errorf("finder module is not dark")
+	}
+}
+
+func TestEncodeCapacity(t *testing.T) {
+	for _, c := range []struct {
+		n       int
+		level   Level
+		version int
+	}{
+		{17, Low, 1},
+		{18, Low, 2},
+		{7, High, 1},
+		{8, High, 2},
+		{271, Low, 10},
+		{2953, Low, 40},
+		{1273, High, 40},
+	} {
+		q, err := Encode(make([]byte, c.n), c.level)
+		if err != nil || q.Version != c.version {
+			t.Errorf("Encode(%d bytes, %d) = %v %v, want version %d", c.n, c.level, q, err, c.version)
+		}
+	}
+	if _, err := Encode(make([]byte, 2954), Low); err == nil {
+		t.Error("encoded more than the capacity of version 40")
+	}
+}
@@ -1,3 +1,6 @@
+//go:build windows
+// +build windows
+
 package wlanapi
 
 import (
@@ -38,4 +41,11 @@ var (
 	wlanHostedNetworkStartUsing              = wlanapi.NewProc("WlanHostedNetworkStartUsing")
 	wlanHostedNetworkStopUsing               = wlanapi.NewProc("WlanHostedNetworkStopUsing")
 	wlanIhvControl                           = wlanapi.NewProc("WlanIhvControl")
+	wlanDeviceServiceCommand                 = wlanapi.NewProc("WlanDeviceServiceCommand")
+	wlanRegisterDeviceServiceNotification    = wlanapi.NewProc("WlanRegisterDeviceServiceNotification")
+	wlanRegisterNotification                 = wlanapi.NewProc("WlanRegisterNotification")
+	wlanRegisterVirtualStationNotification   = wlanapi.NewProc("WlanRegisterVirtualStationNotification")
+	wlanSetInterface                         = wlanapi.NewProc("WlanSetInterface")
+	wlanSetProfile                           = wlanapi.NewProc("WlanSetProfile")
+	wlanSetPsdIEDataList                     = wlanapi.NewProc("WlanSetPsdIEDataList")
 )